@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"bab-insa-api/config"
 	"bab-insa-api/migrations"
@@ -19,15 +20,7 @@ func main() {
 	}
 
 	config.ConnectDatabase()
-	migrator := migrations.NewMigrator(config.DB)
-
-	// Ajouter toutes les migrations
-	for _, migration := range migrations.GetAuthMigrations() {
-		migrator.AddMigration(migration)
-	}
-	for _, migration := range migrations.GetAllMigrations() {
-		migrator.AddMigration(migration)
-	}
+	migrator := migrations.NewAppMigrator(config.DB)
 
 	if len(os.Args) < 2 {
 		printUsage()
@@ -38,6 +31,13 @@ func main() {
 
 	switch command {
 	case "migrate":
+		if len(os.Args) > 2 && strings.HasPrefix(os.Args[2], "--phase=") {
+			phase := migrations.MigrationPhase(strings.TrimPrefix(os.Args[2], "--phase="))
+			if err := migrator.MigratePhase(phase); err != nil {
+				log.Fatal("Migration failed:", err)
+			}
+			return
+		}
 		if err := migrator.Migrate(); err != nil {
 			log.Fatal("Migration failed:", err)
 		}
@@ -53,6 +53,11 @@ func main() {
 		}
 	case "status":
 		showStatus(config.DB)
+		if len(os.Args) > 2 && os.Args[2] == "--verify" {
+			if !verifySchema(migrator) {
+				os.Exit(1)
+			}
+		}
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		printUsage()
@@ -62,8 +67,11 @@ func main() {
 func printUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  go run cmd/migrate.go migrate          - Run pending migrations")
+	fmt.Println("  go run cmd/migrate.go migrate --phase=pre  - Run pending pre-deploy migrations")
+	fmt.Println("  go run cmd/migrate.go migrate --phase=post - Run pending post-deploy migrations")
 	fmt.Println("  go run cmd/migrate.go rollback [steps] - Rollback migrations (default: 1)")
 	fmt.Println("  go run cmd/migrate.go status           - Show migration status")
+	fmt.Println("  go run cmd/migrate.go status --verify  - Show migration status and check the live schema for drift")
 }
 
 func showStatus(db *gorm.DB) {
@@ -83,3 +91,28 @@ func showStatus(db *gorm.DB) {
 		fmt.Printf("%-5d | %s\n", migration.Batch, migration.Name)
 	}
 }
+
+func verifySchema(migrator *migrations.Migrator) bool {
+	fmt.Println()
+	fmt.Println("Verifying live schema against migrations...")
+
+	drifts, err := migrator.VerifySchema()
+	if err != nil {
+		log.Fatal("Schema verification failed:", err)
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("Schema matches the migration set.")
+		return true
+	}
+
+	fmt.Printf("Schema drift detected (%d difference(s)):\n", len(drifts))
+	fmt.Print(migrations.FormatDrifts(drifts))
+
+	for _, drift := range drifts {
+		if drift.IsMissing() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"bab-insa-api/openapi"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		return
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := flags.String("dir", ".", "Source root, where main.go is")
+	_ = flags.Parse(os.Args[2:])
+
+	spec, err := openapi.Generate(*dir)
+	if err != nil {
+		log.Fatalf("Failed to generate the OpenAPI spec: %v", err)
+	}
+	path := filepath.Join(*dir, openapi.File)
+
+	switch os.Args[1] {
+	case "generate":
+		if err := os.WriteFile(path, spec, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		fmt.Printf("✅ OpenAPI spec written to %s\n", path)
+	case "check":
+		current, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if !bytes.Equal(current, spec) {
+			fmt.Printf("❌ %s is out of date with the handler annotations, run `make openapi`\n", path)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s matches the handler annotations\n", path)
+	default:
+		fmt.Printf("Unknown command: %s\n", os.Args[1])
+		printUsage()
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run ./cmd/openapi generate [-dir .]  - Write the OpenAPI spec of the handlers to openapi/openapi.json")
+	fmt.Println("  go run ./cmd/openapi check [-dir .]     - Compare openapi/openapi.json with the handlers (exit 1 when out of date)")
+}
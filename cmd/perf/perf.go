@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	authModels "auth/models"
+	"bab-insa-api/config"
+	"core/models"
+
+	"github.com/joho/godotenv"
+)
+
+// Scenarios covered by the budgets, the load run and the k6 script
+const (
+	scenarioPlayersTop        = "players_top"
+	scenarioMatchesList       = "matches_list"
+	scenarioMatchConfirmation = "match_confirmation"
+)
+
+// Budget is the latency and error target of a scenario, see perf/budgets.json
+type Budget struct {
+	P95Ms        float64 `json:"p95_ms"`
+	MaxErrorRate float64 `json:"max_error_rate"`
+}
+
+// fixtureUser is a fixture account able to log in with the fixture password
+type fixtureUser struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	if len(os.Args) < 2 {
+		printUsage()
+		return
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "Base URL of the API under test")
+	budgetsPath := flags.String("budgets", "perf/budgets.json", "Budget file")
+	output := flags.String("out", "perf/k6/scenario.js", "Generated k6 script")
+	concurrency := flags.Int("c", 20, "Concurrent virtual users")
+	duration := flags.Duration("d", 30*time.Second, "Duration of each scenario")
+	password := flags.String("password", "password123", "Password of the fixture users")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+
+	budgets, err := loadBudgets(*budgetsPath)
+	if err != nil {
+		log.Fatalf("Failed to load budgets: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "k6":
+		config.ConnectDatabase()
+		if err := generateK6Script(*output, *password, budgets); err != nil {
+			log.Fatalf("Failed to generate k6 script: %v", err)
+		}
+		fmt.Printf("✅ k6 script written to %s (run: k6 run -e BASE_URL=%s %s)\n", *output, *baseURL, *output)
+	case "run":
+		config.ConnectDatabase()
+		users, err := loadFixtureUsers()
+		if err != nil {
+			log.Fatalf("Failed to load fixture users: %v", err)
+		}
+		if !runLoad(*baseURL, users, *password, *concurrency, *duration, budgets) {
+			fmt.Println("❌ Performance budget exceeded")
+			os.Exit(1)
+		}
+		fmt.Println("✅ All scenarios within budget")
+	default:
+		fmt.Printf("Unknown command: %s\n", os.Args[1])
+		printUsage()
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run ./cmd/perf k6 [-out file]        - Generate a k6 scenario from the fixtures")
+	fmt.Println("  go run ./cmd/perf run [-url -c -d]      - Load the running API and enforce perf/budgets.json")
+	fmt.Println()
+	fmt.Println("Run against a database loaded with `make fixtures` and a server started with MATCH_DAILY_LIMIT=0:")
+	fmt.Println("the confirmation scenario creates and confirms real matches.")
+	fmt.Println("The Go benchmarks of the hot paths are run by `make perf-bench`.")
+}
+
+func loadBudgets(path string) (map[string]Budget, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- Path given by the operator
+	if err != nil {
+		return nil, err
+	}
+
+	var budgets map[string]Budget
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		return nil, err
+	}
+
+	for _, scenario := range []string{scenarioPlayersTop, scenarioMatchesList, scenarioMatchConfirmation} {
+		if _, ok := budgets[scenario]; !ok {
+			return nil, fmt.Errorf("missing budget for scenario %s", scenario)
+		}
+	}
+	return budgets, nil
+}
+
+// loadFixtureUsers returns the enabled fixture accounts that have a player
+func loadFixtureUsers() ([]fixtureUser, error) {
+	var users []fixtureUser
+	if err := config.DB.Model(&authModels.User{}).
+		Select("users.id, users.email").
+		Joins("JOIN players ON players.id = users.id").
+		Where("users.email LIKE ? AND users.enabled = ?", "%@bab-insa.fr", true).
+		Order("users.id").
+		Scan(&users).Error; err != nil {
+		return nil, err
+	}
+
+	if len(users) < 2 {
+		return nil, fmt.Errorf("at least 2 fixture users are required, run `make fixtures` first")
+	}
+	return users, nil
+}
+
+// result collects the latencies of one scenario
+type result struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    int
+}
+
+func (r *result) add(latency time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if !ok {
+		r.errors++
+	}
+}
+
+func (r *result) p95() time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)*95+99)/100-1]
+}
+
+func (r *result) errorRate() float64 {
+	if len(r.latencies) == 0 {
+		return 1
+	}
+	return float64(r.errors) / float64(len(r.latencies))
+}
+
+// runLoad runs every scenario against the API and reports whether they all fit their budget
+func runLoad(baseURL string, users []fixtureUser, password string, concurrency int, duration time.Duration, budgets map[string]Budget) bool {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	tokens := make(map[uint]string, len(users))
+	for _, user := range users {
+		token, err := login(client, baseURL, user.Email, password)
+		if err != nil {
+			log.Fatalf("Failed to log in as %s: %v", user.Email, err)
+		}
+		tokens[user.ID] = token
+	}
+
+	scenarios := []struct {
+		name string
+		run  func(worker, iteration int) (time.Duration, bool)
+	}{
+		{scenarioPlayersTop, func(_, _ int) (time.Duration, bool) {
+			return timedRequest(client, http.MethodGet, baseURL+"/players/top?limit=10", "", nil)
+		}},
+		{scenarioMatchesList, func(_, _ int) (time.Duration, bool) {
+			return timedRequest(client, http.MethodGet, baseURL+"/matches?page=1&per_page=20", "", nil)
+		}},
+		{scenarioMatchConfirmation, func(worker, iteration int) (time.Duration, bool) {
+			// Creating the match is setup, only the confirmation by the opponent is measured
+			player1 := users[(worker+iteration)%len(users)]
+			player2 := users[(worker+iteration+1)%len(users)]
+			matchID, err := createMatch(client, baseURL, tokens[player1.ID], player1.ID, player2.ID)
+			if err != nil {
+				return 0, false
+			}
+			url := fmt.Sprintf("%s/matches/%d", baseURL, matchID)
+			return timedRequest(client, http.MethodPatch, url, tokens[player2.ID], map[string]string{"status": "confirmed"})
+		}},
+	}
+
+	withinBudget := true
+	for _, scenario := range scenarios {
+		res := &result{}
+		deadline := time.Now().Add(duration)
+
+		var wg sync.WaitGroup
+		for worker := 0; worker < concurrency; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for iteration := 0; time.Now().Before(deadline); iteration++ {
+					latency, ok := scenario.run(worker, iteration)
+					res.add(latency, ok)
+				}
+			}(worker)
+		}
+		wg.Wait()
+
+		budget := budgets[scenario.name]
+		p95 := float64(res.p95().Microseconds()) / 1000
+		status := "OK"
+		if p95 > budget.P95Ms || res.errorRate() > budget.MaxErrorRate {
+			status = "OVER BUDGET"
+			withinBudget = false
+		}
+		fmt.Printf("%-20s %6d req  p95 %8.1fms (budget %.0fms)  errors %5.2f%% (max %.2f%%)  %s\n",
+			scenario.name, len(res.latencies), p95, budget.P95Ms, res.errorRate()*100, budget.MaxErrorRate*100, status)
+	}
+
+	return withinBudget
+}
+
+// timedRequest sends a JSON request and returns its latency and whether it succeeded
+func timedRequest(client *http.Client, method, url, token string, body interface{}) (time.Duration, bool) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, false
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return 0, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, false
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return latency, resp.StatusCode < http.StatusBadRequest
+}
+
+func login(client *http.Client, baseURL, email, password string) (string, error) {
+	payload, _ := json.Marshal(authModels.LoginRequest{Email: email, Password: password})
+	resp, err := client.Post(baseURL+"/auth/login", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("login returned %d", resp.StatusCode)
+	}
+
+	var tokens authModels.TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return "", err
+	}
+	return tokens.AccessToken, nil
+}
+
+func createMatch(client *http.Client, baseURL, token string, player1ID, player2ID uint) (uint, error) {
+	payload, _ := json.Marshal(models.CreateMatchRequest{Player1ID: player1ID, Player2ID: player2ID, WinnerID: player1ID})
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/matches", bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return 0, fmt.Errorf("match creation returned %d", resp.StatusCode)
+	}
+
+	var match models.Match
+	if err := json.NewDecoder(resp.Body).Decode(&match); err != nil {
+		return 0, err
+	}
+	return match.ID, nil
+}
+
+// generateK6Script writes a k6 scenario using the fixture accounts, with the budgets as thresholds
+func generateK6Script(path, password string, budgets map[string]Budget) error {
+	users, err := loadFixtureUsers()
+	if err != nil {
+		return err
+	}
+
+	usersJSON, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+
+	var thresholds []string
+	for _, scenario := range []string{scenarioPlayersTop, scenarioMatchesList, scenarioMatchConfirmation} {
+		budget := budgets[scenario]
+		thresholds = append(thresholds,
+			fmt.Sprintf("    'http_req_duration{scenario:%s}': ['p(95)<%g'],", scenario, budget.P95Ms),
+			fmt.Sprintf("    'http_req_failed{scenario:%s}': ['rate<%g'],", scenario, budget.MaxErrorRate))
+	}
+
+	script := strings.NewReplacer(
+		"{{USERS}}", string(usersJSON),
+		"{{PASSWORD}}", password,
+		"{{THRESHOLDS}}", strings.Join(thresholds, "\n"),
+	).Replace(k6Template)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(script), 0o600)
+}
+
+const k6Template = `// Generated by go run ./cmd/perf k6, do not edit
+import http from 'k6/http';
+import { check } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const USERS = {{USERS}};
+const PASSWORD = '{{PASSWORD}}';
+
+export const options = {
+  scenarios: {
+    players_top: { executor: 'constant-vus', vus: 20, duration: '30s', exec: 'playersTop' },
+    matches_list: { executor: 'constant-vus', vus: 20, duration: '30s', exec: 'matchesList', startTime: '30s' },
+    match_confirmation: { executor: 'constant-vus', vus: 10, duration: '30s', exec: 'matchConfirmation', startTime: '60s' },
+  },
+  thresholds: {
+{{THRESHOLDS}}
+  },
+};
+
+const JSON_HEADERS = { 'Content-Type': 'application/json' };
+
+export function setup() {
+  const tokens = {};
+  for (const user of USERS) {
+    const res = http.post(BASE_URL + '/auth/login', JSON.stringify({ email: user.email, password: PASSWORD }), { headers: JSON_HEADERS, tags: { scenario: 'setup' } });
+    tokens[user.id] = res.json('access_token');
+  }
+  return { tokens };
+}
+
+export function playersTop() {
+  check(http.get(BASE_URL + '/players/top?limit=10'), { 'status 200': (r) => r.status === 200 });
+}
+
+export function matchesList() {
+  check(http.get(BASE_URL + '/matches?page=1&per_page=20'), { 'status 200': (r) => r.status === 200 });
+}
+
+export function matchConfirmation(data) {
+  const i = (__VU + __ITER) % USERS.length;
+  const player1 = USERS[i];
+  const player2 = USERS[(i + 1) % USERS.length];
+
+  const created = http.post(BASE_URL + '/matches',
+    JSON.stringify({ player1_id: player1.id, player2_id: player2.id, winner_id: player1.id }),
+    { headers: Object.assign({ Authorization: 'Bearer ' + data.tokens[player1.id] }, JSON_HEADERS), tags: { scenario: 'setup' } });
+  if (created.status !== 201) {
+    return;
+  }
+
+  const confirmed = http.patch(BASE_URL + '/matches/' + created.json('id'),
+    JSON.stringify({ status: 'confirmed' }),
+    { headers: Object.assign({ Authorization: 'Bearer ' + data.tokens[player2.id] }, JSON_HEADERS) });
+  check(confirmed, { 'status 200': (r) => r.status === 200 });
+}
+`
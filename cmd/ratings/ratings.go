@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"bab-insa-api/config"
+	"core/models"
+	"core/services"
+
+	"github.com/joho/godotenv"
+)
+
+// maxDriftListed caps the drifting players printed, the count is always complete
+const maxDriftListed = 20
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	if len(os.Args) < 2 {
+		printUsage()
+		return
+	}
+
+	config.ConnectDatabase()
+	ratingService := services.NewRatingService(config.DB)
+
+	switch os.Args[1] {
+	case "backfill":
+		dryRun := len(os.Args) > 2 && os.Args[2] == "--dry-run"
+		if !backfill(ratingService, dryRun) {
+			os.Exit(1)
+		}
+	case "verify":
+		if !verify(ratingService) {
+			os.Exit(1)
+		}
+	default:
+		fmt.Printf("Unknown command: %s\n", os.Args[1])
+		printUsage()
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run ./cmd/ratings backfill            - Copy the team columns of the players into the team ladder, then verify")
+	fmt.Println("  go run ./cmd/ratings backfill --dry-run  - Show what the backfill would copy")
+	fmt.Println("  go run ./cmd/ratings verify              - Compare the team columns with the team ladder (exit 1 on drift)")
+}
+
+func backfill(ratingService *services.RatingService, dryRun bool) bool {
+	drift, err := ratingService.TeamLadderDrift()
+	if err != nil {
+		log.Fatalf("Failed to compare the team ladder: %v", err)
+	}
+	printDrift(drift)
+
+	if dryRun {
+		fmt.Printf("Dry run: %d team rating(s) would be written\n", len(drift))
+		return true
+	}
+
+	written, err := ratingService.BackfillTeamLadder()
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+	fmt.Printf("Backfill: %d team rating(s) written\n", written)
+
+	return verify(ratingService)
+}
+
+func verify(ratingService *services.RatingService) bool {
+	drift, err := ratingService.TeamLadderDrift()
+	if err != nil {
+		log.Fatalf("Failed to compare the team ladder: %v", err)
+	}
+	if len(drift) > 0 {
+		printDrift(drift)
+		fmt.Println("❌ Team ladder out of sync with the team columns, run the backfill")
+		return false
+	}
+
+	var players, ratings int64
+	config.DB.Model(&models.Player{}).Unscoped().Count(&players)
+	config.DB.Model(&models.Rating{}).Where("ladder = ?", models.LadderTeam).Count(&ratings)
+	fmt.Printf("✅ Team ladder in sync: %d player(s), %d team rating(s)\n", players, ratings)
+	return true
+}
+
+func printDrift(drift []models.RatingDrift) {
+	fmt.Printf("%d player(s) with a missing or different team rating\n", len(drift))
+	for i, d := range drift {
+		if i == maxDriftListed {
+			fmt.Printf("  ... and %d more\n", len(drift)-maxDriftListed)
+			break
+		}
+		if d.RatingEloRating == nil {
+			fmt.Printf("  #%d %s: no team rating (columns: elo %.1f, %d matches, %d-%d)\n",
+				d.PlayerID, d.Username, d.TeamEloRating, d.TeamTotalMatches, d.TeamWins, d.TeamLosses)
+			continue
+		}
+		fmt.Printf("  #%d %s: columns elo %.1f, %d matches, %d-%d / rating elo %.1f, %d matches, %d-%d\n",
+			d.PlayerID, d.Username, d.TeamEloRating, d.TeamTotalMatches, d.TeamWins, d.TeamLosses,
+			*d.RatingEloRating, *d.RatingTotalMatches, *d.RatingWins, *d.RatingLosses)
+	}
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bab-insa-api/config"
+	"core/models"
+	"core/utils"
+
+	"github.com/joho/godotenv"
+)
+
+// productionK is the K-factor of the rating engine, see utils.CalculateEloChange
+const productionK = 32.0
+
+// Glicko parameters: a new player starts at the base rating with the maximum deviation, which grows
+// back from about 50 to the maximum over a year without playing
+const (
+	glickoMaxRD = 350.0
+	glickoC     = 18.0
+)
+
+// replayMatch is a confirmed solo match, in confirmation order
+type replayMatch struct {
+	ID           uint
+	Player1ID    uint
+	Player2ID    uint
+	WinnerID     uint
+	TournamentID *uint
+	ConfirmedAt  time.Time
+}
+
+// engine is a rating system replayed over the match history
+type engine interface {
+	name() string
+	// predict returns the probability that player1 wins, before the match is applied
+	predict(m replayMatch) float64
+	apply(m replayMatch)
+	ratings() map[uint]float64
+}
+
+// result is the evaluation of an engine over the history
+type result struct {
+	engine  engine
+	logLoss float64
+	correct float64
+	drift   float64
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	kValues := flags.String("k", "16,24,32,40", "ELO K-factors to compare, comma separated")
+	floorModes := flags.String("floor", strings.Join(utils.FloorModes, ","), "ELO floor modes to compare, comma separated")
+	glicko := flags.Bool("glicko", true, "Also replay the history with Glicko")
+	top := flags.Int("top", 10, "Players listed in each leaderboard")
+	since := flags.String("since", "", "Only replay the matches confirmed from this date (YYYY-MM-DD), everybody starting at the base rating")
+	flags.Usage = printUsage
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+
+	engines, err := buildEngines(*kValues, *floorModes, *glicko)
+	if err != nil {
+		log.Fatalf("Invalid parameters: %v", err)
+	}
+
+	var from *time.Time
+	if *since != "" {
+		date, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			log.Fatalf("Invalid -since date: %v", err)
+		}
+		from = &date
+	}
+
+	config.ConnectDatabase()
+	matches, err := loadMatches(from)
+	if err != nil {
+		log.Fatalf("Failed to load the match history: %v", err)
+	}
+	if len(matches) == 0 {
+		fmt.Println("No confirmed match to replay")
+		return
+	}
+	weights, err := loadTournamentWeights()
+	if err != nil {
+		log.Fatalf("Failed to load the tournament weights: %v", err)
+	}
+	for _, e := range engines {
+		if elo, ok := e.(*eloEngine); ok {
+			elo.weights = weights
+		}
+	}
+
+	fmt.Printf("Replaying %d confirmed solo matches, from %s to %s\n\n", len(matches),
+		matches[0].ConfirmedAt.Format("2006-01-02"), matches[len(matches)-1].ConfirmedAt.Format("2006-01-02"))
+
+	results := make([]result, 0, len(engines))
+	for _, e := range engines {
+		results = append(results, replay(e, matches))
+	}
+	printResults(results, len(matches))
+
+	players, err := loadPlayers()
+	if err != nil {
+		log.Fatalf("Failed to load the players: %v", err)
+	}
+	for _, r := range results {
+		printLeaderboard(r.engine, players, *top)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  go run ./cmd/simulate [-k 16,24,32,40] [-floor clamp,zero_sum,none] [-glicko=true] [-top 10] [-since 2025-09-01]")
+	fmt.Println()
+	fmt.Println("Replays the confirmed solo matches under each rating configuration and compares their prediction")
+	fmt.Println("of every match before it is applied (log-loss, lower is better, and accuracy), the rating created")
+	fmt.Println("or destroyed by the floor (drift) and their leaderboards. Nothing is written to the database.")
+	fmt.Println("Floor exemptions, rating overrides and resets are not replayed, nor ELO_CALIBRATION_MATCHES: only")
+	fmt.Println("the default calibration of the zero_sum mode is.")
+}
+
+// buildEngines returns the configurations to compare, ELO ones first
+func buildEngines(kValues, floorModes string, glicko bool) ([]engine, error) {
+	var engines []engine
+	for _, rawK := range strings.Split(kValues, ",") {
+		k, err := strconv.ParseFloat(strings.TrimSpace(rawK), 64)
+		if err != nil || k <= 0 {
+			return nil, fmt.Errorf("invalid K-factor %q", rawK)
+		}
+		for _, mode := range strings.Split(floorModes, ",") {
+			mode = strings.TrimSpace(mode)
+			if !slices.Contains(utils.FloorModes, mode) {
+				return nil, fmt.Errorf("invalid floor mode %q", mode)
+			}
+			engines = append(engines, newEloEngine(k, mode))
+		}
+	}
+	if glicko {
+		engines = append(engines, newGlickoEngine())
+	}
+	if len(engines) == 0 {
+		return nil, fmt.Errorf("no configuration to compare")
+	}
+	return engines, nil
+}
+
+func loadMatches(from *time.Time) ([]replayMatch, error) {
+	query := config.DB.Model(&models.Match{}).
+		Select("id, player1_id, player2_id, winner_id, tournament_id, confirmed_at").
+		Where("status = ? AND confirmed_at IS NOT NULL", "confirmed")
+	if from != nil {
+		query = query.Where("confirmed_at >= ?", *from)
+	}
+
+	var matches []replayMatch
+	if err := query.Order("confirmed_at ASC, id ASC").Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// loadTournamentWeights returns the ELO weight of the tournaments that do not use the default weight
+func loadTournamentWeights() (map[uint]float64, error) {
+	var tournaments []models.Tournament
+	if err := config.DB.Select("id, elo_weight").Where("elo_weight > 0 AND elo_weight <> 1").Find(&tournaments).Error; err != nil {
+		return nil, err
+	}
+
+	weights := make(map[uint]float64, len(tournaments))
+	for _, t := range tournaments {
+		weights[t.ID] = t.EloWeight
+	}
+	return weights, nil
+}
+
+// loadPlayers returns the active players, by current solo rank
+func loadPlayers() ([]models.Player, error) {
+	var players []models.Player
+	if err := config.DB.Select("id, username, elo_rating").Order("elo_rating DESC, id ASC").Find(&players).Error; err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// replay predicts then applies every match in order
+func replay(e engine, matches []replayMatch) result {
+	r := result{engine: e}
+	for _, m := range matches {
+		p := e.predict(m)
+		if m.WinnerID != m.Player1ID {
+			p = 1 - p
+		}
+
+		r.logLoss -= math.Log(math.Min(math.Max(p, 1e-6), 1-1e-6))
+		switch {
+		case p > 0.5:
+			r.correct++
+		case p == 0.5:
+			r.correct += 0.5
+		}
+
+		e.apply(m)
+	}
+
+	r.logLoss /= float64(len(matches))
+	for _, rating := range e.ratings() {
+		r.drift += rating - models.BaseEloRating
+	}
+	return r
+}
+
+func printResults(results []result, matches int) {
+	fmt.Printf("%-28s %9s %9s %10s\n", "Configuration", "Log-loss", "Accuracy", "Drift")
+	fmt.Printf("%-28s %9.4f %8.1f%% %10s\n", "coin flip (reference)", math.Log(2), 50.0, "-")
+	for _, r := range results {
+		fmt.Printf("%-28s %9.4f %8.1f%% %+10.0f\n", r.engine.name(), r.logLoss, 100*r.correct/float64(matches), r.drift)
+	}
+	fmt.Println()
+}
+
+func printLeaderboard(e engine, players []models.Player, top int) {
+	ratings := e.ratings()
+
+	currentRank := make(map[uint]int, len(players))
+	var ranked []models.Player
+	for i, p := range players {
+		currentRank[p.ID] = i + 1
+		if _, played := ratings[p.ID]; played {
+			ranked = append(ranked, p)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ratings[ranked[i].ID] > ratings[ranked[j].ID]
+	})
+
+	fmt.Printf("Top %d - %s\n", top, e.name())
+	for i, p := range ranked {
+		if i == top {
+			break
+		}
+		fmt.Printf("  #%-3d %-24s %7.0f   (current #%d, %.0f)\n", i+1, p.Username, ratings[p.ID], currentRank[p.ID], p.EloRating)
+	}
+	fmt.Println()
+}
+
+// eloEngine is the production rating engine with another K-factor or floor mode
+type eloEngine struct {
+	k         float64
+	floorMode string
+	weights   map[uint]float64
+	values    map[uint]float64
+	// Matches replayed per player, for the calibration of the zero_sum mode
+	played map[uint]int
+}
+
+func newEloEngine(k float64, floorMode string) *eloEngine {
+	return &eloEngine{k: k, floorMode: floorMode, values: make(map[uint]float64), played: make(map[uint]int)}
+}
+
+func (e *eloEngine) name() string {
+	name := fmt.Sprintf("elo k=%g %s", e.k, e.floorMode)
+	currentMode := os.Getenv("ELO_FLOOR_MODE")
+	if currentMode == "" {
+		currentMode = utils.FloorModeClamp
+	}
+	if e.k == productionK && e.floorMode == currentMode {
+		name += " (current)"
+	}
+	return name
+}
+
+func (e *eloEngine) rating(playerID uint) float64 {
+	if rating, ok := e.values[playerID]; ok {
+		return rating
+	}
+	return models.BaseEloRating
+}
+
+func (e *eloEngine) predict(m replayMatch) float64 {
+	return utils.ExpectedScore(e.rating(m.Player1ID), e.rating(m.Player2ID))
+}
+
+// floorExempt reports whether the floor does not apply to the player, the zero_sum mode calibrating
+// the first utils.ZeroSumCalibrationMatches matches like the production default
+func (e *eloEngine) floorExempt(playerID uint) bool {
+	switch e.floorMode {
+	case utils.FloorModeNone:
+		return true
+	case utils.FloorModeZeroSum:
+		return e.played[playerID] < utils.ZeroSumCalibrationMatches
+	}
+	return false
+}
+
+func (e *eloEngine) apply(m replayMatch) {
+	rating1, rating2 := e.rating(m.Player1ID), e.rating(m.Player2ID)
+
+	weight := 1.0
+	if m.TournamentID != nil {
+		if w, ok := e.weights[*m.TournamentID]; ok {
+			weight = w
+		}
+	}
+	kMultiplier := e.k / productionK * weight
+	change1, change2 := utils.CalculateWeightedEloChange(rating1, rating2, m.WinnerID, m.Player1ID,
+		kMultiplier, kMultiplier, e.floorExempt(m.Player1ID), e.floorExempt(m.Player2ID))
+	if e.floorMode == utils.FloorModeZeroSum {
+		change1, change2 = utils.ZeroSumEloChange(change1, change2)
+	}
+
+	e.values[m.Player1ID] = rating1 + change1
+	e.values[m.Player2ID] = rating2 + change2
+	e.played[m.Player1ID]++
+	e.played[m.Player2ID]++
+}
+
+func (e *eloEngine) ratings() map[uint]float64 {
+	return e.values
+}
+
+// glickoEngine is Glicko-1, updated after every match rather than by rating period
+type glickoEngine struct {
+	values     map[uint]float64
+	deviations map[uint]float64
+	lastPlayed map[uint]time.Time
+}
+
+func newGlickoEngine() *glickoEngine {
+	return &glickoEngine{
+		values:     make(map[uint]float64),
+		deviations: make(map[uint]float64),
+		lastPlayed: make(map[uint]time.Time),
+	}
+}
+
+func (e *glickoEngine) name() string {
+	return "glicko"
+}
+
+var glickoQ = math.Ln10 / 400
+
+func glickoG(deviation float64) float64 {
+	return 1 / math.Sqrt(1+3*glickoQ*glickoQ*deviation*deviation/(math.Pi*math.Pi))
+}
+
+func glickoExpected(rating, opponentRating, opponentDeviation float64) float64 {
+	return 1 / (1 + math.Pow(10, -glickoG(opponentDeviation)*(rating-opponentRating)/400))
+}
+
+// state returns the rating and deviation of a player at a date, the deviation growing with inactivity
+func (e *glickoEngine) state(playerID uint, at time.Time) (float64, float64) {
+	rating, ok := e.values[playerID]
+	if !ok {
+		return models.BaseEloRating, glickoMaxRD
+	}
+	days := at.Sub(e.lastPlayed[playerID]).Hours() / 24
+	deviation := math.Sqrt(e.deviations[playerID]*e.deviations[playerID] + glickoC*glickoC*math.Max(days, 0))
+	return rating, math.Min(deviation, glickoMaxRD)
+}
+
+func (e *glickoEngine) predict(m replayMatch) float64 {
+	rating1, deviation1 := e.state(m.Player1ID, m.ConfirmedAt)
+	rating2, deviation2 := e.state(m.Player2ID, m.ConfirmedAt)
+	return glickoExpected(rating1, rating2, math.Sqrt(deviation1*deviation1+deviation2*deviation2))
+}
+
+func (e *glickoEngine) apply(m replayMatch) {
+	rating1, deviation1 := e.state(m.Player1ID, m.ConfirmedAt)
+	rating2, deviation2 := e.state(m.Player2ID, m.ConfirmedAt)
+
+	score1 := 0.0
+	if m.WinnerID == m.Player1ID {
+		score1 = 1
+	}
+	e.update(m.Player1ID, rating1, deviation1, rating2, deviation2, score1, m.ConfirmedAt)
+	e.update(m.Player2ID, rating2, deviation2, rating1, deviation1, 1-score1, m.ConfirmedAt)
+}
+
+func (e *glickoEngine) update(playerID uint, rating, deviation, opponentRating, opponentDeviation, score float64, at time.Time) {
+	g := glickoG(opponentDeviation)
+	expected := glickoExpected(rating, opponentRating, opponentDeviation)
+	dSquared := 1 / (glickoQ * glickoQ * g * g * expected * (1 - expected))
+	precision := 1/(deviation*deviation) + 1/dSquared
+
+	e.values[playerID] = rating + glickoQ/precision*g*(score-expected)
+	e.deviations[playerID] = math.Sqrt(1 / precision)
+	e.lastPlayed[playerID] = at
+}
+
+func (e *glickoEngine) ratings() map[uint]float64 {
+	return e.values
+}
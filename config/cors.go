@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+)
+
+// CORSPolicy is the cross-origin policy of the API. Origins are exact (https://app.bab-insa.fr)
+// or a wildcard subdomain (https://*.bab-insa.fr), which matches any subdomain but not the domain itself.
+type CORSPolicy struct {
+	Profile          string
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+	RequireHTTPS     bool // Refuse plain http origins, localhost included
+}
+
+// CORS profiles, chosen with CORS_PROFILE
+const (
+	CORSProfileDev     = "dev"
+	CORSProfileStaging = "staging"
+	CORSProfileProd    = "prod"
+)
+
+var (
+	corsMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	corsHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Kiosk-Token", "X-Client-Version"}
+
+	// Headers the frontend may read: request ID, rate limit delay, export file name, pagination and pending consent
+	corsExposeHeaders = []string{"X-Request-ID", "Retry-After", "Content-Disposition", "X-Total-Count", "X-Total-Pages", "Link", "X-Consent-Required"}
+)
+
+// corsProfiles are the defaults of each environment. Staging and prod have no default origin,
+// CORS_ALLOWED_ORIGINS must list them.
+var corsProfiles = map[string]CORSPolicy{
+	CORSProfileDev: {
+		AllowOrigins:     []string{"http://127.0.0.1:5173", "http://localhost:5173"},
+		AllowCredentials: true,
+	},
+	CORSProfileStaging: {
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+		RequireHTTPS:     true,
+	},
+	CORSProfileProd: {
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+		RequireHTTPS:     true,
+	},
+}
+
+// LoadCORSPolicy builds the policy of the CORS_PROFILE profile (dev by default), with the origins of
+// CORS_ALLOWED_ORIGINS and the extra exposed headers of CORS_EXPOSE_HEADERS, and validates it
+func LoadCORSPolicy() (*CORSPolicy, error) {
+	profile := strings.ToLower(strings.TrimSpace(os.Getenv("CORS_PROFILE")))
+	if profile == "" {
+		profile = CORSProfileDev
+	}
+
+	defaults, ok := corsProfiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown CORS_PROFILE %q, use dev, staging or prod", profile)
+	}
+
+	policy := defaults
+	policy.Profile = profile
+	policy.AllowMethods = corsMethods
+	policy.AllowHeaders = corsHeaders
+	policy.ExposeHeaders = append(append([]string{}, corsExposeHeaders...), splitList(os.Getenv("CORS_EXPOSE_HEADERS"))...)
+	if origins := splitList(os.Getenv("CORS_ALLOWED_ORIGINS")); len(origins) > 0 {
+		policy.AllowOrigins = origins
+	}
+	if credentials := os.Getenv("CORS_ALLOW_CREDENTIALS"); credentials != "" {
+		policy.AllowCredentials = credentials == "true"
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate rejects the policies a browser would refuse or that would open the API too wide: no
+// origin, malformed origins, plain http under a https-only profile, and any wildcard origin
+// ("*" or a wildcard subdomain) combined with credentials
+func (p *CORSPolicy) Validate() error {
+	if len(p.AllowOrigins) == 0 {
+		return fmt.Errorf("CORS profile %s has no allowed origin, set CORS_ALLOWED_ORIGINS", p.Profile)
+	}
+
+	for _, origin := range p.AllowOrigins {
+		if origin == "*" {
+			if p.AllowCredentials {
+				return fmt.Errorf("CORS origin * cannot be combined with credentials, list the origins or set CORS_ALLOW_CREDENTIALS=false")
+			}
+			continue
+		}
+
+		parsed, err := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			(parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" {
+			return fmt.Errorf("invalid CORS origin %q, expected scheme://host[:port]", origin)
+		}
+		if p.RequireHTTPS && parsed.Scheme != "https" {
+			return fmt.Errorf("CORS origin %q must use https in the %s profile", origin, p.Profile)
+		}
+
+		if strings.Contains(origin, "*") {
+			if !isWildcardSubdomain(origin) {
+				return fmt.Errorf("invalid CORS origin %q, a wildcard must be a whole subdomain like https://*.example.org", origin)
+			}
+			if p.AllowCredentials {
+				return fmt.Errorf("CORS origin %q: wildcard origins cannot be combined with credentials, list the origins or set CORS_ALLOW_CREDENTIALS=false", origin)
+			}
+		}
+	}
+	return nil
+}
+
+// Config returns the gin-contrib configuration of the policy, wildcard subdomains are matched by AllowOriginFunc
+func (p *CORSPolicy) Config() cors.Config {
+	config := cors.Config{
+		AllowMethods:     p.AllowMethods,
+		AllowHeaders:     p.AllowHeaders,
+		ExposeHeaders:    p.ExposeHeaders,
+		AllowCredentials: p.AllowCredentials,
+		MaxAge:           p.MaxAge,
+	}
+
+	var wildcards []string
+	for _, origin := range p.AllowOrigins {
+		switch {
+		case origin == "*":
+			config.AllowAllOrigins = true
+		case strings.Contains(origin, "*"):
+			wildcards = append(wildcards, origin)
+		default:
+			config.AllowOrigins = append(config.AllowOrigins, origin)
+		}
+	}
+	if config.AllowAllOrigins {
+		config.AllowOrigins = nil
+		return config
+	}
+
+	if len(wildcards) > 0 {
+		config.AllowOriginFunc = func(origin string) bool {
+			for _, wildcard := range wildcards {
+				if matchWildcardOrigin(wildcard, origin) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return config
+}
+
+// isWildcardSubdomain accepts scheme://*.domain.tld[:port]: one wildcard, as the first label, before
+// at least two labels, so that a whole TLD cannot be allowed
+func isWildcardSubdomain(origin string) bool {
+	scheme, host, found := strings.Cut(origin, "://")
+	if !found || strings.Count(origin, "*") != 1 || !strings.HasPrefix(host, "*.") {
+		return false
+	}
+	hostname, _, _ := strings.Cut(strings.TrimPrefix(host, "*."), ":")
+	return (scheme == "http" || scheme == "https") && strings.Count(hostname, ".") >= 1
+}
+
+// matchWildcardOrigin reports whether origin is a subdomain of the wildcard, same scheme and port
+func matchWildcardOrigin(wildcard, origin string) bool {
+	prefix, suffix, _ := strings.Cut(wildcard, "*")
+	if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	subdomain := strings.TrimSuffix(strings.TrimPrefix(origin, prefix), suffix)
+	if subdomain == "" {
+		return false
+	}
+	for _, label := range strings.Split(subdomain, ".") {
+		if label == "" || strings.Trim(label, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// splitList splits a comma separated variable, trimming each entry and dropping the empty ones
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
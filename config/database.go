@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -13,7 +14,15 @@ import (
 
 var DB *gorm.DB
 
+// ConnectDatabase opens DB from the DB_* variables and stops the process when it cannot
 func ConnectDatabase() {
+	if err := OpenDatabase(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// OpenDatabase opens DB from the DB_* variables, for callers that must clean up before exiting
+func OpenDatabase() error {
 	host := os.Getenv("DB_HOST")
 	if host == "" {
 		host = "localhost"
@@ -31,12 +40,12 @@ func ConnectDatabase() {
 
 	password := os.Getenv("DB_PASSWORD")
 	if password == "" {
-		log.Fatal("DB_PASSWORD environment variable is required")
+		return errors.New("DB_PASSWORD environment variable is required")
 	}
 
 	dbname := os.Getenv("DB_NAME")
 	if dbname == "" {
-		log.Fatal("DB_NAME environment variable is required")
+		return errors.New("DB_NAME environment variable is required")
 	}
 
 	sslmode := os.Getenv("DB_SSLMODE")
@@ -47,14 +56,15 @@ func ConnectDatabase() {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s statement_timeout=30000",
 		host, user, password, dbname, port, sslmode)
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError maps unique violations to gorm.ErrDuplicatedKey for the services to detect them
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	sqlDB, err := database.DB()
 	if err != nil {
-		log.Fatal("Failed to get database instance:", err)
+		return fmt.Errorf("failed to get database instance: %w", err)
 	}
 
 	maxOpenConns := getEnvAsInt("DB_MAX_OPEN_CONNS", 10)
@@ -69,6 +79,7 @@ func ConnectDatabase() {
 	log.Printf("Database connected successfully with pool: max_open=%d, max_idle=%d, max_lifetime=%ds",
 		maxOpenConns, maxIdleConns, maxLifetime)
 	DB = database
+	return nil
 }
 
 func getEnvAsInt(name string, defaultValue int) int {
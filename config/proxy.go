@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ProxyConfig tells gin which peers are reverse proxies: only for them is the client IP read from the
+// forwarding headers, other peers are the client themselves. Rate limits, audit logs and the email
+// webhook all rely on gin's ClientIP.
+type ProxyConfig struct {
+	TrustedProxies  []string // IPs or CIDRs, e.g. 127.0.0.1 or 172.18.0.0/16 for a Docker network
+	ClientIPHeaders []string // Headers holding the client IP, by priority
+}
+
+// defaultClientIPHeaders are the headers set by our nginx (proxy_set_header X-Forwarded-For
+// $proxy_add_x_forwarded_for and X-Real-IP $remote_addr)
+var defaultClientIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+
+// LoadProxyConfig reads TRUSTED_PROXIES (comma separated IPs or CIDRs, loopback by default) and
+// CLIENT_IP_HEADERS. APACHE_PROXY_IP, the former single proxy setting, is still added when set.
+func LoadProxyConfig() (*ProxyConfig, error) {
+	proxies := splitList(os.Getenv("TRUSTED_PROXIES"))
+	if len(proxies) == 0 {
+		proxies = []string{"127.0.0.1", "::1"}
+	}
+	if apacheIP := os.Getenv("APACHE_PROXY_IP"); apacheIP != "" {
+		log.Println("⚠️  APACHE_PROXY_IP is deprecated, list the proxy in TRUSTED_PROXIES")
+		proxies = append(proxies, apacheIP)
+	}
+
+	headers := splitList(os.Getenv("CLIENT_IP_HEADERS"))
+	if len(headers) == 0 {
+		headers = append([]string{}, defaultClientIPHeaders...)
+	}
+	for i, header := range headers {
+		headers[i] = http.CanonicalHeaderKey(header)
+	}
+
+	config := &ProxyConfig{TrustedProxies: proxies, ClientIPHeaders: headers}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate rejects malformed entries and proxies trusting the whole internet, which would let any
+// client choose its IP with a forged X-Forwarded-For and dodge the rate limits
+func (p *ProxyConfig) Validate() error {
+	for _, proxy := range p.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			_, network, err := net.ParseCIDR(proxy)
+			if err != nil {
+				return fmt.Errorf("invalid trusted proxy %q: %v", proxy, err)
+			}
+			if ones, _ := network.Mask.Size(); ones == 0 {
+				return fmt.Errorf("trusted proxy %q trusts every address, list the proxy networks instead", proxy)
+			}
+			continue
+		}
+		if net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid trusted proxy %q, expected an IP or a CIDR", proxy)
+		}
+	}
+	return nil
+}
@@ -244,7 +244,8 @@ func (f *Fixtures) generateEloHistoryAndStreaks(users []authModels.User, matches
 		// Create ELO history entries
 		eloHistory1 := models.EloHistory{
 			PlayerID:   match.Player1ID,
-			MatchID:    match.ID,
+			MatchID:    &match.ID,
+			Kind:       models.EloHistoryKindMatch,
 			EloBefore:  player1Elo,
 			EloAfter:   player1Elo + player1Change,
 			EloChange:  player1Change,
@@ -254,7 +255,8 @@ func (f *Fixtures) generateEloHistoryAndStreaks(users []authModels.User, matches
 
 		eloHistory2 := models.EloHistory{
 			PlayerID:   match.Player2ID,
-			MatchID:    match.ID,
+			MatchID:    &match.ID,
+			Kind:       models.EloHistoryKindMatch,
 			EloBefore:  player2Elo,
 			EloAfter:   player2Elo + player2Change,
 			EloChange:  player2Change,
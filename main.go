@@ -8,9 +8,12 @@ import (
 	"syscall"
 
 	"auth"
+	authModels "auth/models"
 	"bab-insa-api/config"
-	_ "bab-insa-api/docs" // Swagger docs
+	"bab-insa-api/migrations"
+	"bab-insa-api/openapi"
 	"core"
+	"core/cron"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -39,58 +42,114 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey  PublicAPIKey
+// @in header
+// @name X-API-Key
+// @description Scoped token of the public API, issued by an admin.
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	config.ConnectDatabase()
+	// --check-routes prints the auth policy of every route and exits, see route_policy.go
+	if isCheckRoutes() {
+		os.Exit(runCheckRoutes())
+	}
 
-	r := gin.Default()
+	// --sandbox runs on a disposable database loaded with the fixtures, see sandbox.go
+	if isSandbox() {
+		applySandboxEnv()
+		stop, err := startSandbox()
+		if err != nil {
+			log.Fatalf("Failed to start the sandbox: %v", err)
+		}
+		stopSandbox = stop
+	} else {
+		config.ConnectDatabase()
+	}
 
-	// Configure trusted proxies for security
-	trustedProxies := []string{"127.0.0.1", "::1"}
-	if apacheIP := os.Getenv("APACHE_PROXY_IP"); apacheIP != "" {
-		trustedProxies = append(trustedProxies, apacheIP)
+	// Detect schema drift between the live database and the migration set, opt-in as it reads the whole
+	// catalog on every start; `make migration-status-verify` runs the same check on demand
+	if os.Getenv("SCHEMA_VERIFY") == "true" {
+		checkSchemaDrift()
 	}
-	if err := r.SetTrustedProxies(trustedProxies); err != nil {
-		log.Fatal("Failed to set trusted proxies:", err)
+
+	// Verify the dependencies now rather than failing on the first request
+	runStartupChecks("dependency", dependencyChecks())
+
+	// Panics are recovered by the core module, with a JSON body and the request ID
+	r := gin.New()
+	r.Use(routeProbe())
+	r.Use(gin.Logger())
+
+	// Read the client IP from the forwarding headers of the reverse proxy only, see config/proxy.go
+	proxyConfig, err := config.LoadProxyConfig()
+	if err != nil {
+		fatalf("Invalid proxy configuration: %v", err)
 	}
+	r.RemoteIPHeaders = proxyConfig.ClientIPHeaders
+	if err := r.SetTrustedProxies(proxyConfig.TrustedProxies); err != nil {
+		fatalf("Failed to set trusted proxies: %v", err)
+	}
+	log.Printf("Trusted proxies: %s (client IP from %s)", strings.Join(proxyConfig.TrustedProxies, ", "), strings.Join(proxyConfig.ClientIPHeaders, ", "))
 
-	// Get CORS allowed origins from environment
-	corsOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
-	var allowedOrigins []string
-	if corsOrigins != "" {
-		allowedOrigins = strings.Split(corsOrigins, ",")
-		// Trim whitespace from each origin
-		for i, origin := range allowedOrigins {
-			allowedOrigins[i] = strings.TrimSpace(origin)
-		}
-	} else {
-		// Default origins for development
-		allowedOrigins = []string{"http://127.0.0.1:5173", "http://localhost:5173"}
+	// CORS policy of the CORS_PROFILE environment, an unsafe policy stops the server, see config/cors.go
+	corsPolicy, err := config.LoadCORSPolicy()
+	if err != nil {
+		fatalf("Invalid CORS configuration: %v", err)
+	}
+	log.Printf("CORS profile %s, allowed origins: %s", corsPolicy.Profile, strings.Join(corsPolicy.AllowOrigins, ", "))
+	r.Use(cors.New(corsPolicy.Config()))
+
+	// Setup core module (players, matches, etc.)
+	coreModule := core.NewModule(config.DB)
+	if err := coreModule.EnableSlowQueryLogging(); err != nil {
+		log.Printf("Failed to enable slow query logging: %v", err)
 	}
 
-	// CORS middleware
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		AllowCredentials: true,
-	}))
+	// Tag each request with an ID returned in X-Request-ID and in the body of server errors
+	r.Use(coreModule.RequestID())
+
+	// Report server error spikes to the admin console
+	r.Use(coreModule.ErrorRateMonitor())
+
+	// Recover panics with a JSON 500, after the error-rate monitor so that they are counted
+	r.Use(coreModule.Recovery())
+
+	// Turn away the app builds that are too old or known to be broken
+	r.Use(coreModule.ClientVersionGate())
+
+	// Compress the large JSON payloads (match lists) for the clubroom wifi
+	r.Use(coreModule.Compression())
+
+	// Let the reverse proxy and browsers cache static assets and anonymous reads
+	r.Use(coreModule.CacheControl())
+
+	// Capture sanitized bodies of the routes an admin is debugging
+	r.Use(coreModule.DebugLogger())
+
+	// Count the requests per user and endpoint group, see GET /admin/usage
+	r.Use(coreModule.UsageTracker())
+	coreModule.StartUsageTracking()
 
 	// Setup auth module (includes all refresh token routes)
 	authModule := auth.NewModule(config.DB)
-	authModule.SetupRoutes(r)
+	authModule.Handler.Events = coreModule.Events
 
-	// Setup core module (players, matches, etc.)
-	coreModule := core.NewModule(config.DB)
-	coreModule.SetupRoutes(r)
+	// Ask the signed-in members to accept the terms again when TERMS_VERSION changes
+	r.Use(authModule.ConsentGate())
+	registerRoutes(r, authModule, coreModule)
 
-	// Start the scheduler for auto-validation
+	// Refuse to serve a route that lost its auth middleware, see route_policy.go
+	runStartupChecks("route", routeChecks(r))
+
+	// Start the scheduler. If it fails the API still serves traffic without the background jobs while
+	// the scheduler retries, the degraded mode is reported by /readyz and on the admin console
 	if err := coreModule.StartScheduler(); err != nil {
-		log.Fatalf("Failed to start scheduler: %v", err)
+		log.Printf("⚠️  SERVING DEGRADED: scheduler failed to start, retrying in the background: %v", err)
 	}
+	runStartupChecks("scheduler", schedulerChecks(coreModule))
 
 	// Setup graceful shutdown
 	c := make(chan os.Signal, 1)
@@ -100,9 +159,28 @@ func main() {
 		<-c
 		log.Println("Shutting down gracefully...")
 		coreModule.StopScheduler()
+		coreModule.FlushUsage()
+		stopSandbox()
 		os.Exit(0)
 	}()
 
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	log.Printf("Server starting on port %s", port)
+	if err := r.Run(":" + port); err != nil {
+		fatalf("Failed to start server: %v", err)
+	}
+}
+
+// registerRoutes mounts the routes of the modules and of the server itself
+func registerRoutes(r *gin.Engine, authModule *auth.Module, coreModule *core.Module) {
+	authModule.SetupRoutes(r)
+
+	coreModule.SetupRoutes(r)
+
 	// Users routes (protected)
 	users := r.Group("/users")
 	users.Use(auth.JWTMiddleware())
@@ -113,10 +191,13 @@ func main() {
 		users.PATCH("/:id", authModule.Handler.PatchUser)
 	}
 
-	// Swagger endpoint
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// OpenAPI spec of the running binary, also read by the Swagger UI, see openapi/openapi.go
+	spec, source := openapi.Load(".")
+	r.GET("/openapi.json", openAPIHandler(spec, source))
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
 
 	r.GET("/health", healthHandler)
+	r.GET("/readyz", readyzHandler(coreModule))
 
 	protected := r.Group("/protected")
 	protected.Use(auth.JWTMiddleware())
@@ -132,17 +213,35 @@ func main() {
 			coreModule.RunAutoValidationNow()
 			c.JSON(200, gin.H{"message": "Auto-validation triggered manually"})
 		})
+
+		adminOnly := auth.RequireAnyRole(config.DB, authModels.RoleAdmin, authModels.RoleSuperAdmin)
+		admin.POST("/users/bulk", adminOnly, authModule.Handler.BulkUsers)
+		admin.POST("/users/batch-generate", adminOnly, authModule.Handler.BatchGenerateUsers)
+		admin.POST("/users/:id/resend-email", adminOnly, authModule.Handler.ResendEmail)
+		admin.GET("/emails", adminOnly, authModule.Handler.GetEmailLogs)
+		admin.GET("/consents/export", adminOnly, authModule.Handler.ExportConsents)
+		admin.GET("/password-resets/metrics", adminOnly, authModule.Handler.GetPasswordResetMetrics)
 	}
+}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// checkSchemaDrift logs loudly when the live schema diverges from the migrations
+func checkSchemaDrift() {
+	drifts, err := migrations.NewAppMigrator(config.DB).VerifySchema()
+	if err != nil {
+		log.Printf("⚠️  Schema verification could not run: %v", err)
+		return
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	if len(drifts) == 0 {
+		log.Println("Schema verification passed")
+		return
 	}
+
+	log.Printf("⚠️  SCHEMA DRIFT DETECTED: %d difference(s) between the database and the migrations", len(drifts))
+	for _, line := range strings.Split(strings.TrimRight(migrations.FormatDrifts(drifts), "\n"), "\n") {
+		log.Printf("⚠️  %s", strings.TrimSpace(line))
+	}
+	log.Println("⚠️  Run `make migration-status-verify` for details and fix the schema before deploying")
 }
 
 // HealthResponse represents the health check response
@@ -164,6 +263,51 @@ func healthHandler(c *gin.Context) {
 	})
 }
 
+// ReadinessResponse represents the readiness check response
+type ReadinessResponse struct {
+	Status    string      `json:"status" example:"ready"` // ready, degraded or unavailable
+	Database  string      `json:"database" example:"connected"`
+	Scheduler cron.Health `json:"scheduler"`
+}
+
+// @Summary Readiness Check
+// @Description Check whether the server can serve traffic. Without its database it cannot (503, unavailable). When the scheduler failed to start, the API keeps serving but the background jobs (auto-validation, notifications, exports...) do not run until one of its retries succeeds: the status is degraded, with the scheduler's last error and next retry
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse
+// @Failure 503 {object} ReadinessResponse
+// @Router /readyz [get]
+func readyzHandler(coreModule *core.Module) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := ReadinessResponse{
+			Status:    "ready",
+			Database:  "connected",
+			Scheduler: coreModule.SchedulerHealth(),
+		}
+
+		if err := checkDatabase(); err != nil {
+			response.Status = "unavailable"
+			response.Database = "unreachable"
+			c.JSON(503, response)
+			return
+		}
+		if response.Scheduler.Status != cron.HealthRunning {
+			response.Status = "degraded"
+		}
+
+		c.JSON(200, response)
+	}
+}
+
+// openAPIHandler serves the OpenAPI spec, X-OpenAPI-Source tells whether it was generated on start
+// (runtime) or when the binary was built (embedded)
+func openAPIHandler(spec []byte, source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("X-OpenAPI-Source", source)
+		c.Data(200, "application/json; charset=utf-8", spec)
+	}
+}
+
 // ProtectedResponse represents the protected endpoint response
 type ProtectedResponse struct {
 	Message string `json:"message" example:"Protected route accessed"`
@@ -58,5 +58,117 @@ func GetAuthMigrations() []MigrationDefinition {
 				return db.Exec("DROP TABLE IF EXISTS refresh_tokens CASCADE").Error
 			},
 		},
+		{
+			Name: "2026_10_16_000100_add_email_verification_to_users",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE users
+					ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP NULL,
+					ADD COLUMN IF NOT EXISTS email_verification_token VARCHAR(255) NULL;
+					CREATE INDEX IF NOT EXISTS idx_users_email_verification_token ON users(email_verification_token);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_users_email_verification_token;
+					ALTER TABLE users
+					DROP COLUMN IF EXISTS email_verification_token,
+					DROP COLUMN IF EXISTS email_verified_at;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_000200_create_audit_logs_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS audit_logs (
+						id BIGSERIAL PRIMARY KEY,
+						actor_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						action VARCHAR(100) NOT NULL,
+						target_type VARCHAR(50) NOT NULL,
+						target_id BIGINT NOT NULL,
+						details JSONB DEFAULT '{}'::jsonb,
+						ip_address VARCHAR(45) NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id);
+					CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs(action);
+					CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs(target_type, target_id);
+					CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS audit_logs CASCADE").Error
+			},
+		},
+		{
+			Name: "2026_10_16_000300_add_email_status_to_users",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE users
+					ADD COLUMN IF NOT EXISTS email_status VARCHAR(20) DEFAULT 'active',
+					ADD COLUMN IF NOT EXISTS email_status_reason TEXT NULL,
+					ADD COLUMN IF NOT EXISTS email_status_updated_at TIMESTAMP NULL;
+					CREATE INDEX IF NOT EXISTS idx_users_email_status ON users(email_status);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_users_email_status;
+					ALTER TABLE users
+					DROP COLUMN IF EXISTS email_status_updated_at,
+					DROP COLUMN IF EXISTS email_status_reason,
+					DROP COLUMN IF EXISTS email_status;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_000400_create_email_logs_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS email_logs (
+						id BIGSERIAL PRIMARY KEY,
+						user_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						recipient VARCHAR(255) NOT NULL,
+						template VARCHAR(50) NOT NULL,
+						subject VARCHAR(255) NULL,
+						provider VARCHAR(50) NOT NULL,
+						status VARCHAR(20) NOT NULL,
+						provider_response TEXT NULL,
+						error TEXT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_email_logs_user_id ON email_logs(user_id);
+					CREATE INDEX IF NOT EXISTS idx_email_logs_recipient ON email_logs(recipient);
+					CREATE INDEX IF NOT EXISTS idx_email_logs_status ON email_logs(status);
+					CREATE INDEX IF NOT EXISTS idx_email_logs_created_at ON email_logs(created_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS email_logs CASCADE").Error
+			},
+		},
+		{
+			Name: "2026_10_16_000500_create_consents_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS consents (
+						id BIGSERIAL PRIMARY KEY,
+						user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+						kind VARCHAR(30) NOT NULL,
+						version VARCHAR(50) NOT NULL,
+						granted BOOLEAN NOT NULL DEFAULT FALSE,
+						ip_address VARCHAR(45) NULL,
+						user_agent TEXT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_consents_user_kind_created_at ON consents(user_id, kind, created_at);
+					CREATE INDEX IF NOT EXISTS idx_consents_created_at ON consents(created_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS consents CASCADE").Error
+			},
+		},
 	}
 }
@@ -382,5 +382,1430 @@ func GetCoreMigrations() []MigrationDefinition {
 				return db.Exec("DROP TABLE IF EXISTS team_elo_history CASCADE").Error
 			},
 		},
+		{
+			Name:               "2026_10_16_000000_add_match_history_lookup_indexes",
+			Phase:              PhasePostDeploy,
+			DisableTransaction: true,
+			Up: func(db *gorm.DB) error {
+				// Used by auto-validation (pending matches older than 24h)
+				if err := CreateIndexConcurrently(db, "idx_matches_status_created_at", "matches", "status, created_at"); err != nil {
+					return err
+				}
+				if err := CreateIndexConcurrently(db, "idx_team_matches_status_created_at", "team_matches", "status, created_at"); err != nil {
+					return err
+				}
+				// Used by player ELO history charts
+				return CreateIndexConcurrently(db, "idx_elo_history_player_id_created_at", "elo_history", "player_id, created_at")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := DropIndexConcurrently(db, "idx_elo_history_player_id_created_at"); err != nil {
+					return err
+				}
+				if err := DropIndexConcurrently(db, "idx_team_matches_status_created_at"); err != nil {
+					return err
+				}
+				return DropIndexConcurrently(db, "idx_matches_status_created_at")
+			},
+		},
+		{
+			Name: "2026_10_16_000500_create_notifications_tables",
+			Up: func(db *gorm.DB) error {
+				if err := db.Exec(`
+					CREATE TABLE IF NOT EXISTS notifications (
+						id BIGSERIAL PRIMARY KEY,
+						user_id BIGINT NOT NULL,
+						type VARCHAR(50) NOT NULL,
+						message TEXT NOT NULL,
+						match_id BIGINT NULL,
+						team_match_id BIGINT NULL,
+						status VARCHAR(20) DEFAULT 'pending',
+						created_at TIMESTAMP DEFAULT NOW(),
+						sent_at TIMESTAMP NULL,
+						FOREIGN KEY (user_id) REFERENCES players(id) ON DELETE CASCADE,
+						FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,
+						FOREIGN KEY (team_match_id) REFERENCES team_matches(id) ON DELETE CASCADE
+					);
+					CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id);
+					CREATE INDEX IF NOT EXISTS idx_notifications_status_created_at ON notifications(status, created_at);
+				`).Error; err != nil {
+					return err
+				}
+
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS notification_preferences (
+						user_id BIGINT PRIMARY KEY,
+						quiet_hours_enabled BOOLEAN DEFAULT true,
+						quiet_hours_start INT DEFAULT 22,
+						quiet_hours_end INT DEFAULT 8,
+						timezone VARCHAR(64) DEFAULT 'Europe/Paris',
+						batch_window_minutes INT DEFAULT 15,
+						created_at TIMESTAMP DEFAULT NOW(),
+						updated_at TIMESTAMP DEFAULT NOW(),
+						FOREIGN KEY (user_id) REFERENCES players(id) ON DELETE CASCADE
+					);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("DROP TABLE IF EXISTS notification_preferences CASCADE").Error; err != nil {
+					return err
+				}
+				return db.Exec("DROP TABLE IF EXISTS notifications CASCADE").Error
+			},
+		},
+		{
+			Name: "2026_10_16_000600_create_live_matches_tables",
+			Up: func(db *gorm.DB) error {
+				if err := db.Exec(`
+					CREATE TABLE IF NOT EXISTS live_matches (
+						id BIGSERIAL PRIMARY KEY,
+						player1_id BIGINT NOT NULL,
+						player2_id BIGINT NOT NULL,
+						player1_score INT DEFAULT 0,
+						player2_score INT DEFAULT 0,
+						status VARCHAR(20) DEFAULT 'in_progress',
+						started_by BIGINT NULL,
+						tournament_id BIGINT NULL,
+						match_id BIGINT NULL,
+						started_at TIMESTAMP DEFAULT NOW(),
+						finished_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT NOW(),
+						updated_at TIMESTAMP DEFAULT NOW(),
+						FOREIGN KEY (player1_id) REFERENCES players(id) ON DELETE CASCADE,
+						FOREIGN KEY (player2_id) REFERENCES players(id) ON DELETE CASCADE,
+						FOREIGN KEY (started_by) REFERENCES players(id) ON DELETE SET NULL,
+						FOREIGN KEY (tournament_id) REFERENCES tournaments(id) ON DELETE SET NULL,
+						FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE SET NULL
+					);
+					CREATE INDEX IF NOT EXISTS idx_live_matches_status ON live_matches(status);
+					CREATE INDEX IF NOT EXISTS idx_live_matches_match_id ON live_matches(match_id);
+				`).Error; err != nil {
+					return err
+				}
+
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS match_goals (
+						id BIGSERIAL PRIMARY KEY,
+						live_match_id BIGINT NOT NULL,
+						match_id BIGINT NULL,
+						scorer_id BIGINT NOT NULL,
+						player1_score INT NOT NULL,
+						player2_score INT NOT NULL,
+						scored_at TIMESTAMP DEFAULT NOW(),
+						FOREIGN KEY (live_match_id) REFERENCES live_matches(id) ON DELETE CASCADE,
+						FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,
+						FOREIGN KEY (scorer_id) REFERENCES players(id) ON DELETE CASCADE
+					);
+					CREATE INDEX IF NOT EXISTS idx_match_goals_live_match_id ON match_goals(live_match_id);
+					CREATE INDEX IF NOT EXISTS idx_match_goals_match_id ON match_goals(match_id);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("DROP TABLE IF EXISTS match_goals CASCADE").Error; err != nil {
+					return err
+				}
+				return db.Exec("DROP TABLE IF EXISTS live_matches CASCADE").Error
+			},
+		},
+		{
+			Name: "2026_10_16_000700_add_tournament_match_rules",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournaments
+					ADD COLUMN IF NOT EXISTS match_time_limit_seconds INT NULL,
+					ADD COLUMN IF NOT EXISTS golden_goal BOOLEAN DEFAULT false;
+
+					ALTER TABLE live_matches
+					ADD COLUMN IF NOT EXISTS time_limit_seconds INT NULL,
+					ADD COLUMN IF NOT EXISTS golden_goal BOOLEAN DEFAULT false,
+					ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP NULL,
+					ADD COLUMN IF NOT EXISTS paused_seconds INT DEFAULT 0;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE live_matches
+					DROP COLUMN IF EXISTS paused_seconds,
+					DROP COLUMN IF EXISTS paused_at,
+					DROP COLUMN IF EXISTS golden_goal,
+					DROP COLUMN IF EXISTS time_limit_seconds;
+
+					ALTER TABLE tournaments
+					DROP COLUMN IF EXISTS golden_goal,
+					DROP COLUMN IF EXISTS match_time_limit_seconds;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_000800_add_referee_to_matches",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS referee_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL;
+					CREATE INDEX IF NOT EXISTS idx_matches_referee_id ON matches(referee_id);
+
+					ALTER TABLE team_matches ADD COLUMN IF NOT EXISTS referee_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL;
+					CREATE INDEX IF NOT EXISTS idx_team_matches_referee_id ON team_matches(referee_id);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_team_matches_referee_id;
+					ALTER TABLE team_matches DROP COLUMN IF EXISTS referee_id;
+
+					DROP INDEX IF EXISTS idx_matches_referee_id;
+					ALTER TABLE matches DROP COLUMN IF EXISTS referee_id;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_000900_add_tournament_group_and_knockout_stages",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournaments
+					ADD COLUMN IF NOT EXISTS format VARCHAR(20) NOT NULL DEFAULT 'single',
+					ADD COLUMN IF NOT EXISTS stage VARCHAR(20) NULL,
+					ADD COLUMN IF NOT EXISTS pool_count INTEGER NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS qualifiers_per_pool INTEGER NOT NULL DEFAULT 0;
+
+					ALTER TABLE tournament_teams ADD COLUMN IF NOT EXISTS pool VARCHAR(10) NULL;
+					CREATE INDEX IF NOT EXISTS idx_tournament_teams_pool ON tournament_teams(tournament_id, pool);
+
+					ALTER TABLE team_matches ADD COLUMN IF NOT EXISTS stage VARCHAR(20) NULL;
+
+					CREATE TABLE IF NOT EXISTS tournament_bracket_matches (
+						id BIGSERIAL PRIMARY KEY,
+						tournament_id BIGINT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+						round INTEGER NOT NULL,
+						position INTEGER NOT NULL,
+						team1_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL,
+						team2_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL,
+						winner_team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL,
+						team_match_id BIGINT NULL REFERENCES team_matches(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_tournament_bracket_matches_slot ON tournament_bracket_matches(tournament_id, round, position);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS tournament_bracket_matches CASCADE;
+
+					ALTER TABLE team_matches DROP COLUMN IF EXISTS stage;
+
+					DROP INDEX IF EXISTS idx_tournament_teams_pool;
+					ALTER TABLE tournament_teams DROP COLUMN IF EXISTS pool;
+
+					ALTER TABLE tournaments
+					DROP COLUMN IF EXISTS qualifiers_per_pool,
+					DROP COLUMN IF EXISTS pool_count,
+					DROP COLUMN IF EXISTS stage,
+					DROP COLUMN IF EXISTS format;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001000_create_tournament_templates_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS tournament_templates (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						description TEXT,
+						type VARCHAR(20) NOT NULL DEFAULT 'team',
+						format VARCHAR(20) NOT NULL DEFAULT 'single',
+						pool_count INTEGER NOT NULL DEFAULT 0,
+						qualifiers_per_pool INTEGER NOT NULL DEFAULT 0,
+						max_participants INTEGER NULL,
+						match_time_limit_seconds INTEGER NULL,
+						golden_goal BOOLEAN NOT NULL DEFAULT false,
+						elo_weight DOUBLE PRECISION NOT NULL DEFAULT 1,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						deleted_at TIMESTAMP NULL
+					);
+					CREATE INDEX IF NOT EXISTS idx_tournament_templates_deleted_at ON tournament_templates(deleted_at);
+
+					ALTER TABLE tournaments
+					ADD COLUMN IF NOT EXISTS max_participants INTEGER NULL,
+					ADD COLUMN IF NOT EXISTS elo_weight DOUBLE PRECISION NOT NULL DEFAULT 1,
+					ADD COLUMN IF NOT EXISTS template_id BIGINT NULL REFERENCES tournament_templates(id) ON DELETE SET NULL;
+					CREATE INDEX IF NOT EXISTS idx_tournaments_template_id ON tournaments(template_id);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_tournaments_template_id;
+					ALTER TABLE tournaments
+					DROP COLUMN IF EXISTS template_id,
+					DROP COLUMN IF EXISTS elo_weight,
+					DROP COLUMN IF EXISTS max_participants;
+
+					DROP TABLE IF EXISTS tournament_templates CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001100_create_tournament_recurrences_tables",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS tournament_recurrences (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						tournament_id BIGINT NULL REFERENCES tournaments(id) ON DELETE SET NULL,
+						frequency VARCHAR(20) NOT NULL DEFAULT 'weekly',
+						weekday INTEGER NOT NULL,
+						hour INTEGER NOT NULL DEFAULT 18,
+						timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Paris',
+						edition INTEGER NOT NULL DEFAULT 1,
+						next_run_at TIMESTAMP NOT NULL,
+						active BOOLEAN NOT NULL DEFAULT true,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						deleted_at TIMESTAMP NULL
+					);
+					CREATE INDEX IF NOT EXISTS idx_tournament_recurrences_next_run_at ON tournament_recurrences(active, next_run_at);
+					CREATE INDEX IF NOT EXISTS idx_tournament_recurrences_deleted_at ON tournament_recurrences(deleted_at);
+
+					CREATE TABLE IF NOT EXISTS tournament_recurrence_subscriptions (
+						id BIGSERIAL PRIMARY KEY,
+						recurrence_id BIGINT NOT NULL REFERENCES tournament_recurrences(id) ON DELETE CASCADE,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_tournament_recurrence_subscriptions_unique ON tournament_recurrence_subscriptions(recurrence_id, player_id);
+
+					ALTER TABLE tournaments
+					ADD COLUMN IF NOT EXISTS recurrence_id BIGINT NULL REFERENCES tournament_recurrences(id) ON DELETE SET NULL,
+					ADD COLUMN IF NOT EXISTS edition INTEGER NULL;
+					CREATE INDEX IF NOT EXISTS idx_tournaments_recurrence_id ON tournaments(recurrence_id);
+
+					ALTER TABLE notifications ADD COLUMN IF NOT EXISTS tournament_id BIGINT NULL REFERENCES tournaments(id) ON DELETE CASCADE;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE notifications DROP COLUMN IF EXISTS tournament_id;
+
+					DROP INDEX IF EXISTS idx_tournaments_recurrence_id;
+					ALTER TABLE tournaments
+					DROP COLUMN IF EXISTS edition,
+					DROP COLUMN IF EXISTS recurrence_id;
+
+					DROP TABLE IF EXISTS tournament_recurrence_subscriptions CASCADE;
+					DROP TABLE IF EXISTS tournament_recurrences CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001200_create_tournament_waitlist_entries_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS registration_deadline TIMESTAMP NULL;
+
+					CREATE TABLE IF NOT EXISTS tournament_waitlist_entries (
+						id BIGSERIAL PRIMARY KEY,
+						tournament_id BIGINT NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+						team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_tournament_waitlist_entries_unique ON tournament_waitlist_entries(tournament_id, team_id);
+					CREATE INDEX IF NOT EXISTS idx_tournament_waitlist_entries_order ON tournament_waitlist_entries(tournament_id, created_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS tournament_waitlist_entries CASCADE;
+					ALTER TABLE tournaments DROP COLUMN IF EXISTS registration_deadline;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001300_add_tournament_entry_fees",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournaments
+					ADD COLUMN IF NOT EXISTS entry_fee_cents INTEGER NULL,
+					ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'EUR';
+
+					ALTER TABLE tournament_teams
+					ADD COLUMN IF NOT EXISTS payment_status VARCHAR(20) NOT NULL DEFAULT 'unpaid',
+					ADD COLUMN IF NOT EXISTS paid_at TIMESTAMP NULL,
+					ADD COLUMN IF NOT EXISTS payment_note TEXT NULL,
+					ADD COLUMN IF NOT EXISTS payment_updated_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournament_teams
+					DROP COLUMN IF EXISTS payment_updated_by,
+					DROP COLUMN IF EXISTS payment_note,
+					DROP COLUMN IF EXISTS paid_at,
+					DROP COLUMN IF EXISTS payment_status;
+
+					ALTER TABLE tournaments
+					DROP COLUMN IF EXISTS currency,
+					DROP COLUMN IF EXISTS entry_fee_cents;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001400_create_trophies_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS trophy_image_url TEXT NULL;
+
+					CREATE TABLE IF NOT EXISTS trophies (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						image_url TEXT NULL,
+						season VARCHAR(9) NOT NULL,
+						tournament_id BIGINT NULL REFERENCES tournaments(id) ON DELETE SET NULL,
+						winner_team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL,
+						winner_player_id BIGINT NULL REFERENCES players(id) ON DELETE SET NULL,
+						awarded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						deleted_at TIMESTAMP NULL
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_trophies_tournament ON trophies(tournament_id) WHERE tournament_id IS NOT NULL AND deleted_at IS NULL;
+					CREATE INDEX IF NOT EXISTS idx_trophies_winner_team ON trophies(winner_team_id);
+					CREATE INDEX IF NOT EXISTS idx_trophies_winner_player ON trophies(winner_player_id);
+					CREATE INDEX IF NOT EXISTS idx_trophies_season ON trophies(season);
+					CREATE INDEX IF NOT EXISTS idx_trophies_deleted_at ON trophies(deleted_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS trophies CASCADE;
+					ALTER TABLE tournaments DROP COLUMN IF EXISTS trophy_image_url;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001500_create_public_api_tokens_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE players
+					ADD COLUMN IF NOT EXISTS public_api_consent BOOLEAN NOT NULL DEFAULT FALSE,
+					ADD COLUMN IF NOT EXISTS public_api_consent_at TIMESTAMP NULL;
+
+					CREATE TABLE IF NOT EXISTS public_api_tokens (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						token_hash VARCHAR(64) NOT NULL UNIQUE,
+						prefix VARCHAR(12) NOT NULL,
+						scopes VARCHAR(255) NOT NULL,
+						created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						last_used_at TIMESTAMP NULL,
+						expires_at TIMESTAMP NULL,
+						revoked_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_matches_confirmed_at ON matches(confirmed_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_matches_confirmed_at;
+					DROP TABLE IF EXISTS public_api_tokens CASCADE;
+					ALTER TABLE players
+					DROP COLUMN IF EXISTS public_api_consent_at,
+					DROP COLUMN IF EXISTS public_api_consent;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001600_create_external_matches_tables",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS player_external_ids (
+						id BIGSERIAL PRIMARY KEY,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						source VARCHAR(50) NOT NULL,
+						external_id VARCHAR(255) NOT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_player_external_ids_source_external ON player_external_ids(source, external_id);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_player_external_ids_source_player ON player_external_ids(source, player_id);
+
+					CREATE TABLE IF NOT EXISTS import_sources (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(50) NOT NULL UNIQUE,
+						connector VARCHAR(20) NOT NULL,
+						url TEXT NULL,
+						enabled BOOLEAN NOT NULL DEFAULT TRUE,
+						last_import_at TIMESTAMP NULL,
+						last_import_count INTEGER NOT NULL DEFAULT 0,
+						last_error TEXT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+
+					CREATE TABLE IF NOT EXISTS external_matches (
+						id BIGSERIAL PRIMARY KEY,
+						source_id BIGINT NOT NULL REFERENCES import_sources(id) ON DELETE CASCADE,
+						external_id VARCHAR(255) NOT NULL,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						opponent_player_id BIGINT NULL REFERENCES players(id) ON DELETE SET NULL,
+						opponent_external_id VARCHAR(255),
+						opponent_name VARCHAR(255),
+						won BOOLEAN NOT NULL,
+						score_for INTEGER NULL,
+						score_against INTEGER NULL,
+						played_at TIMESTAMP NOT NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_external_matches_unique ON external_matches(source_id, external_id, player_id);
+					CREATE INDEX IF NOT EXISTS idx_external_matches_player ON external_matches(player_id, played_at DESC);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS external_matches CASCADE;
+					DROP TABLE IF EXISTS import_sources CASCADE;
+					DROP TABLE IF EXISTS player_external_ids CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001700_create_kiosk_devices_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS kiosk_devices (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						pin_hash VARCHAR(64) NULL,
+						pin_expires_at TIMESTAMP NULL,
+						token_hash VARCHAR(64) NULL UNIQUE,
+						paired_at TIMESTAMP NULL,
+						last_seen_at TIMESTAMP NULL,
+						revoked_at TIMESTAMP NULL,
+						created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_kiosk_devices_pin_hash ON kiosk_devices(pin_hash);
+
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS kiosk_device_id BIGINT NULL REFERENCES kiosk_devices(id) ON DELETE SET NULL;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE matches DROP COLUMN IF EXISTS kiosk_device_id;
+					DROP TABLE IF EXISTS kiosk_devices CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001800_add_players_card_uid_hash",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS card_uid_hash VARCHAR(64) NULL;
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_players_card_uid_hash ON players(card_uid_hash);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_players_card_uid_hash;
+					ALTER TABLE players DROP COLUMN IF EXISTS card_uid_hash;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_001900_create_match_anomalies_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS match_anomalies (
+						id BIGSERIAL PRIMARY KEY,
+						match_id BIGINT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						kind VARCHAR(30) NOT NULL,
+						details TEXT NULL,
+						status VARCHAR(20) NOT NULL DEFAULT 'open',
+						reviewed_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						reviewed_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_match_anomalies_unique ON match_anomalies(kind, match_id, player_id);
+					CREATE INDEX IF NOT EXISTS idx_match_anomalies_status ON match_anomalies(status, created_at DESC);
+
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS on_hold BOOLEAN NOT NULL DEFAULT FALSE;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE matches DROP COLUMN IF EXISTS on_hold;
+					DROP TABLE IF EXISTS match_anomalies CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002000_create_season_settings_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS season_settings (
+						season VARCHAR(9) PRIMARY KEY,
+						confirmation_mode VARCHAR(20) NOT NULL DEFAULT 'single',
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS requires_both_confirmations BOOLEAN NOT NULL DEFAULT FALSE;
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS player1_confirmed_at TIMESTAMP NULL;
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS player2_confirmed_at TIMESTAMP NULL;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE matches DROP COLUMN IF EXISTS player2_confirmed_at;
+					ALTER TABLE matches DROP COLUMN IF EXISTS player1_confirmed_at;
+					ALTER TABLE matches DROP COLUMN IF EXISTS requires_both_confirmations;
+					DROP TABLE IF EXISTS season_settings CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002100_add_players_away_window",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS away_from TIMESTAMP NULL;
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS away_until TIMESTAMP NULL;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE players DROP COLUMN IF EXISTS away_until;
+					ALTER TABLE players DROP COLUMN IF EXISTS away_from;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002200_create_rating_overrides_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS rating_overrides (
+						id BIGSERIAL PRIMARY KEY,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						k_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1,
+						adjustment DOUBLE PRECISION NOT NULL DEFAULT 0,
+						reason TEXT NOT NULL,
+						expires_at TIMESTAMP NOT NULL,
+						revoked_at TIMESTAMP NULL,
+						created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_rating_overrides_player ON rating_overrides(player_id, created_at DESC);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS rating_overrides CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002300_create_rating_reset_requests_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS rating_reset_requests (
+						id BIGSERIAL PRIMARY KEY,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						reason TEXT NOT NULL,
+						status VARCHAR(20) NOT NULL DEFAULT 'pending',
+						reviewed_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						reviewed_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_rating_reset_requests_status ON rating_reset_requests(status, created_at);
+
+					ALTER TABLE elo_history ALTER COLUMN match_id DROP NOT NULL;
+					ALTER TABLE elo_history ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'match';
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DELETE FROM elo_history WHERE match_id IS NULL;
+					ALTER TABLE elo_history DROP COLUMN IF EXISTS kind;
+					ALTER TABLE elo_history ALTER COLUMN match_id SET NOT NULL;
+					DROP TABLE IF EXISTS rating_reset_requests CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002400_create_debug_log_tables",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS debug_log_rules (
+						id BIGSERIAL PRIMARY KEY,
+						method VARCHAR(10) NOT NULL,
+						path VARCHAR(255) NOT NULL,
+						expires_at TIMESTAMP NOT NULL,
+						created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_debug_log_rules_expires_at ON debug_log_rules(expires_at);
+
+					CREATE TABLE IF NOT EXISTS debug_log_entries (
+						id BIGSERIAL PRIMARY KEY,
+						rule_id BIGINT NOT NULL REFERENCES debug_log_rules(id) ON DELETE CASCADE,
+						method VARCHAR(10) NOT NULL,
+						path VARCHAR(255) NOT NULL,
+						status INTEGER NOT NULL,
+						duration_ms BIGINT NOT NULL,
+						user_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+						request_body TEXT,
+						response_body TEXT,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_debug_log_entries_rule ON debug_log_entries(rule_id, created_at DESC);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS debug_log_entries CASCADE;
+					DROP TABLE IF EXISTS debug_log_rules CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002500_create_slow_queries_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS slow_queries (
+						id BIGSERIAL PRIMARY KEY,
+						fingerprint TEXT NOT NULL,
+						source VARCHAR(255) NOT NULL,
+						handler VARCHAR(255) NOT NULL,
+						duration_ms DOUBLE PRECISION NOT NULL,
+						rows_affected BIGINT NOT NULL DEFAULT 0,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_slow_queries_created_at ON slow_queries(created_at);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS slow_queries CASCADE;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002600_add_win_rate_columns",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS win_rate DOUBLE PRECISION
+						GENERATED ALWAYS AS (CASE WHEN total_matches > 0 THEN wins::double precision / total_matches ELSE 0 END) STORED;
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS team_win_rate DOUBLE PRECISION
+						GENERATED ALWAYS AS (CASE WHEN team_total_matches > 0 THEN team_wins::double precision / team_total_matches ELSE 0 END) STORED;
+					ALTER TABLE teams ADD COLUMN IF NOT EXISTS win_rate DOUBLE PRECISION
+						GENERATED ALWAYS AS (CASE WHEN total_matches > 0 THEN wins::double precision / total_matches ELSE 0 END) STORED;
+
+					CREATE INDEX IF NOT EXISTS idx_players_win_rate ON players(win_rate DESC);
+					CREATE INDEX IF NOT EXISTS idx_players_team_win_rate ON players(team_win_rate DESC);
+					CREATE INDEX IF NOT EXISTS idx_teams_win_rate ON teams(win_rate DESC);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_teams_win_rate;
+					DROP INDEX IF EXISTS idx_players_team_win_rate;
+					DROP INDEX IF EXISTS idx_players_win_rate;
+					ALTER TABLE teams DROP COLUMN IF EXISTS win_rate;
+					ALTER TABLE players DROP COLUMN IF EXISTS team_win_rate;
+					ALTER TABLE players DROP COLUMN IF EXISTS win_rate;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002700_create_leaderboard_view",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE MATERIALIZED VIEW IF NOT EXISTS leaderboard AS
+					WITH results AS (
+						SELECT player1_id AS player_id, id, created_at, winner_id = player1_id AS won
+						FROM matches WHERE status = 'confirmed' AND deleted_at IS NULL
+						UNION ALL
+						SELECT player2_id AS player_id, id, created_at, winner_id = player2_id AS won
+						FROM matches WHERE status = 'confirmed' AND deleted_at IS NULL
+					), runs AS (
+						SELECT player_id, created_at, won,
+							ROW_NUMBER() OVER (PARTITION BY player_id ORDER BY created_at, id) -
+							ROW_NUMBER() OVER (PARTITION BY player_id, won ORDER BY created_at, id) AS run
+						FROM results
+					), run_lengths AS (
+						SELECT player_id, won, COUNT(*) AS length, MAX(created_at) AS ended_at
+						FROM runs
+						GROUP BY player_id, won, run
+					), best_streaks AS (
+						SELECT player_id, MAX(length) AS best_streak
+						FROM run_lengths WHERE won
+						GROUP BY player_id
+					), current_streaks AS (
+						SELECT DISTINCT ON (player_id) player_id, CASE WHEN won THEN length ELSE 0 END AS current_streak
+						FROM run_lengths
+						ORDER BY player_id, ended_at DESC
+					), last_matches AS (
+						SELECT player_id, MAX(created_at) AS last_match_at
+						FROM results
+						GROUP BY player_id
+					)
+					SELECT players.id AS player_id, players.username, players.elo_rating, players.rank,
+						players.total_matches, players.wins, players.losses, players.win_rate,
+						COALESCE(current_streaks.current_streak, 0) AS current_streak,
+						COALESCE(best_streaks.best_streak, 0) AS best_streak,
+						last_matches.last_match_at, players.away_from, players.away_until
+					FROM players
+					LEFT JOIN current_streaks ON current_streaks.player_id = players.id
+					LEFT JOIN best_streaks ON best_streaks.player_id = players.id
+					LEFT JOIN last_matches ON last_matches.player_id = players.id
+					WHERE players.deleted_at IS NULL;
+
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_leaderboard_player ON leaderboard(player_id);
+					CREATE INDEX IF NOT EXISTS idx_leaderboard_elo ON leaderboard(elo_rating DESC, player_id);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP MATERIALIZED VIEW IF EXISTS leaderboard;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002800_create_elo_history_archive",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS elo_history_archive (
+						id BIGINT PRIMARY KEY,
+						player_id BIGINT NOT NULL,
+						match_id BIGINT,
+						kind VARCHAR(20) NOT NULL DEFAULT 'match',
+						elo_before FLOAT NOT NULL,
+						elo_after FLOAT NOT NULL,
+						elo_change FLOAT NOT NULL,
+						opponent_id BIGINT,
+						opponent_team_id BIGINT,
+						match_type VARCHAR(20) DEFAULT 'solo',
+						created_at TIMESTAMP,
+						updated_at TIMESTAMP,
+						deleted_at TIMESTAMP,
+						archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+					);
+
+					CREATE INDEX IF NOT EXISTS idx_elo_history_archive_player_id ON elo_history_archive(player_id);
+					CREATE INDEX IF NOT EXISTS idx_elo_history_archive_match_id ON elo_history_archive(match_id);
+					CREATE INDEX IF NOT EXISTS idx_elo_history_archive_created_at ON elo_history_archive(created_at);
+
+					CREATE OR REPLACE VIEW elo_history_all AS
+					SELECT id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+						opponent_id, opponent_team_id, match_type, created_at, updated_at, deleted_at
+					FROM elo_history
+					UNION ALL
+					SELECT id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+						opponent_id, opponent_team_id, match_type, created_at, updated_at, deleted_at
+					FROM elo_history_archive;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP VIEW IF EXISTS elo_history_all;
+					DROP TABLE IF EXISTS elo_history_archive;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_002900_create_client_version_settings",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS client_version_settings (
+						id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+						min_version VARCHAR(20) NOT NULL DEFAULT '',
+						blocked_versions JSONB NOT NULL DEFAULT '[]'::jsonb,
+						message VARCHAR(255) NOT NULL DEFAULT '',
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS client_version_settings;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003000_create_offline_match_submissions",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS offline_match_submissions (
+						id BIGSERIAL PRIMARY KEY,
+						client_uuid VARCHAR(36) NOT NULL UNIQUE,
+						kiosk_device_id BIGINT NULL REFERENCES kiosk_devices(id) ON DELETE SET NULL,
+						player1_id BIGINT NOT NULL,
+						player2_id BIGINT NOT NULL,
+						winner_id BIGINT NOT NULL,
+						played_at TIMESTAMP NOT NULL,
+						status VARCHAR(20) NOT NULL,
+						match_id BIGINT NULL REFERENCES matches(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS offline_match_submissions;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003100_add_matches_client_uuid",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS client_uuid VARCHAR(36) NULL;
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_client_uuid ON matches(client_uuid);
+
+					ALTER TABLE team_matches ADD COLUMN IF NOT EXISTS client_uuid VARCHAR(36) NULL;
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_team_matches_client_uuid ON team_matches(client_uuid);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_team_matches_client_uuid;
+					ALTER TABLE team_matches DROP COLUMN IF EXISTS client_uuid;
+
+					DROP INDEX IF EXISTS idx_matches_client_uuid;
+					ALTER TABLE matches DROP COLUMN IF EXISTS client_uuid;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003200_add_scoreboard_photo_policy",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS photo_policy VARCHAR(20) NOT NULL DEFAULT 'none';
+					ALTER TABLE tournament_templates ADD COLUMN IF NOT EXISTS photo_policy VARCHAR(20) NOT NULL DEFAULT 'none';
+
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS photo_url TEXT NULL;
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS disputed BOOLEAN NOT NULL DEFAULT FALSE;
+					ALTER TABLE team_matches ADD COLUMN IF NOT EXISTS photo_url TEXT NULL;
+					ALTER TABLE team_matches ADD COLUMN IF NOT EXISTS disputed BOOLEAN NOT NULL DEFAULT FALSE;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE team_matches DROP COLUMN IF EXISTS disputed;
+					ALTER TABLE team_matches DROP COLUMN IF EXISTS photo_url;
+					ALTER TABLE matches DROP COLUMN IF EXISTS disputed;
+					ALTER TABLE matches DROP COLUMN IF EXISTS photo_url;
+
+					ALTER TABLE tournament_templates DROP COLUMN IF EXISTS photo_policy;
+					ALTER TABLE tournaments DROP COLUMN IF EXISTS photo_policy;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003300_create_titles",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS titles (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL UNIQUE,
+						description TEXT NULL,
+						tournament_id BIGINT NULL REFERENCES tournaments(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+
+					CREATE TABLE IF NOT EXISTS player_titles (
+						id BIGSERIAL PRIMARY KEY,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						title_id BIGINT NOT NULL REFERENCES titles(id) ON DELETE CASCADE,
+						awarded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_player_titles_player_title ON player_titles(player_id, title_id);
+					CREATE INDEX IF NOT EXISTS idx_player_titles_title ON player_titles(title_id);
+
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS flair_title_id BIGINT NULL REFERENCES titles(id) ON DELETE SET NULL;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE players DROP COLUMN IF EXISTS flair_title_id;
+					DROP TABLE IF EXISTS player_titles;
+					DROP TABLE IF EXISTS titles;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003400_create_ratings",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS ratings (
+						id BIGSERIAL PRIMARY KEY,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						ladder VARCHAR(30) NOT NULL,
+						elo_rating DOUBLE PRECISION NOT NULL DEFAULT 1200,
+						total_matches INTEGER NOT NULL DEFAULT 0,
+						wins INTEGER NOT NULL DEFAULT 0,
+						losses INTEGER NOT NULL DEFAULT 0,
+						win_rate DOUBLE PRECISION
+							GENERATED ALWAYS AS (CASE WHEN total_matches > 0 THEN wins::double precision / total_matches ELSE 0 END) STORED,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_ratings_player_ladder ON ratings(player_id, ladder);
+					CREATE INDEX IF NOT EXISTS idx_ratings_ladder_elo ON ratings(ladder, elo_rating DESC);
+
+					-- The solo and team ladders mirror the rating columns of players
+					CREATE OR REPLACE FUNCTION sync_player_ratings() RETURNS TRIGGER AS $$
+					BEGIN
+						INSERT INTO ratings (player_id, ladder, elo_rating, total_matches, wins, losses)
+						VALUES
+							(NEW.id, 'solo', NEW.elo_rating, NEW.total_matches, NEW.wins, NEW.losses),
+							(NEW.id, 'team', NEW.team_elo_rating, NEW.team_total_matches, NEW.team_wins, NEW.team_losses)
+						ON CONFLICT (player_id, ladder) DO UPDATE SET
+							elo_rating = EXCLUDED.elo_rating,
+							total_matches = EXCLUDED.total_matches,
+							wins = EXCLUDED.wins,
+							losses = EXCLUDED.losses,
+							updated_at = CURRENT_TIMESTAMP;
+						RETURN NULL;
+					END;
+					$$ LANGUAGE plpgsql;
+
+					DROP TRIGGER IF EXISTS players_sync_ratings ON players;
+					CREATE TRIGGER players_sync_ratings
+						AFTER INSERT OR UPDATE OF elo_rating, total_matches, wins, losses,
+							team_elo_rating, team_total_matches, team_wins, team_losses
+						ON players
+						FOR EACH ROW EXECUTE FUNCTION sync_player_ratings();
+
+					INSERT INTO ratings (player_id, ladder, elo_rating, total_matches, wins, losses)
+					SELECT id, 'solo', elo_rating, total_matches, wins, losses FROM players
+					UNION ALL
+					SELECT id, 'team', team_elo_rating, team_total_matches, team_wins, team_losses FROM players
+					ON CONFLICT (player_id, ladder) DO NOTHING;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TRIGGER IF EXISTS players_sync_ratings ON players;
+					DROP FUNCTION IF EXISTS sync_player_ratings();
+					DROP TABLE IF EXISTS ratings;
+				`).Error
+			},
+		},
+		{
+			// The team ladder is now written to ratings; the team_* columns of players stay in sync
+			// both ways during the transition (binaries still writing them, readers of the columns)
+			Name: "2026_10_16_003500_sync_team_ratings_both_ways",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE OR REPLACE FUNCTION sync_player_ratings() RETURNS TRIGGER AS $$
+					BEGIN
+						INSERT INTO ratings (player_id, ladder, elo_rating, total_matches, wins, losses)
+						VALUES
+							(NEW.id, 'solo', NEW.elo_rating, NEW.total_matches, NEW.wins, NEW.losses),
+							(NEW.id, 'team', NEW.team_elo_rating, NEW.team_total_matches, NEW.team_wins, NEW.team_losses)
+						ON CONFLICT (player_id, ladder) DO UPDATE SET
+							elo_rating = EXCLUDED.elo_rating,
+							total_matches = EXCLUDED.total_matches,
+							wins = EXCLUDED.wins,
+							losses = EXCLUDED.losses,
+							updated_at = CURRENT_TIMESTAMP
+						WHERE (ratings.elo_rating, ratings.total_matches, ratings.wins, ratings.losses)
+							IS DISTINCT FROM (EXCLUDED.elo_rating, EXCLUDED.total_matches, EXCLUDED.wins, EXCLUDED.losses);
+						RETURN NULL;
+					END;
+					$$ LANGUAGE plpgsql;
+
+					CREATE OR REPLACE FUNCTION sync_player_team_columns() RETURNS TRIGGER AS $$
+					BEGIN
+						UPDATE players SET
+							team_elo_rating = NEW.elo_rating,
+							team_total_matches = NEW.total_matches,
+							team_wins = NEW.wins,
+							team_losses = NEW.losses,
+							updated_at = CURRENT_TIMESTAMP
+						WHERE id = NEW.player_id
+							AND (team_elo_rating, team_total_matches, team_wins, team_losses)
+								IS DISTINCT FROM (NEW.elo_rating, NEW.total_matches, NEW.wins, NEW.losses);
+						RETURN NULL;
+					END;
+					$$ LANGUAGE plpgsql;
+
+					DROP TRIGGER IF EXISTS ratings_sync_team_columns ON ratings;
+					CREATE TRIGGER ratings_sync_team_columns
+						AFTER INSERT OR UPDATE ON ratings
+						FOR EACH ROW WHEN (NEW.ladder = 'team')
+						EXECUTE FUNCTION sync_player_team_columns();
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TRIGGER IF EXISTS ratings_sync_team_columns ON ratings;
+					DROP FUNCTION IF EXISTS sync_player_team_columns();
+
+					CREATE OR REPLACE FUNCTION sync_player_ratings() RETURNS TRIGGER AS $$
+					BEGIN
+						INSERT INTO ratings (player_id, ladder, elo_rating, total_matches, wins, losses)
+						VALUES
+							(NEW.id, 'solo', NEW.elo_rating, NEW.total_matches, NEW.wins, NEW.losses),
+							(NEW.id, 'team', NEW.team_elo_rating, NEW.team_total_matches, NEW.team_wins, NEW.team_losses)
+						ON CONFLICT (player_id, ladder) DO UPDATE SET
+							elo_rating = EXCLUDED.elo_rating,
+							total_matches = EXCLUDED.total_matches,
+							wins = EXCLUDED.wins,
+							losses = EXCLUDED.losses,
+							updated_at = CURRENT_TIMESTAMP;
+						RETURN NULL;
+					END;
+					$$ LANGUAGE plpgsql;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003600_create_team_rating_history",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS team_rating_history (
+						id BIGSERIAL PRIMARY KEY,
+						team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+						team_match_id BIGINT NOT NULL REFERENCES team_matches(id) ON DELETE CASCADE,
+						elo_before DOUBLE PRECISION NOT NULL,
+						elo_after DOUBLE PRECISION NOT NULL,
+						elo_change DOUBLE PRECISION NOT NULL,
+						opponent_team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_team_rating_history_team ON team_rating_history(team_id, created_at);
+
+					-- Rebuild the past changes: a team moves by the average change of its two players,
+					-- accumulated from the base rating
+					INSERT INTO team_rating_history (team_id, team_match_id, elo_before, elo_after, elo_change, opponent_team_id, created_at)
+					SELECT team_id, team_match_id,
+						1200 + SUM(elo_change) OVER team_changes - elo_change,
+						1200 + SUM(elo_change) OVER team_changes,
+						elo_change, opponent_team_id, created_at
+					FROM (
+						SELECT team_matches.id AS team_match_id, teams.id AS team_id,
+							CASE WHEN teams.id = team_matches.team1_id THEN team_matches.team2_id ELSE team_matches.team1_id END AS opponent_team_id,
+							AVG(team_elo_history.elo_change) AS elo_change,
+							MIN(team_elo_history.created_at) AS created_at
+						FROM team_matches
+						JOIN teams ON teams.id IN (team_matches.team1_id, team_matches.team2_id)
+						JOIN team_elo_history ON team_elo_history.team_match_id = team_matches.id
+							AND team_elo_history.player_id IN (teams.player1_id, teams.player2_id)
+							AND team_elo_history.deleted_at IS NULL
+						GROUP BY team_matches.id, teams.id
+					) changes
+					WINDOW team_changes AS (PARTITION BY team_id ORDER BY created_at, team_match_id);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS team_rating_history;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003700_create_monthly_awards_table",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					-- Awards given at the end of each month, at most one per kind and month
+					CREATE TABLE IF NOT EXISTS monthly_awards (
+						id BIGSERIAL PRIMARY KEY,
+						month VARCHAR(7) NOT NULL,
+						kind VARCHAR(30) NOT NULL,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						trophy_id BIGINT NULL REFERENCES trophies(id) ON DELETE SET NULL,
+						value DOUBLE PRECISION NOT NULL DEFAULT 0,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_monthly_awards_month_kind ON monthly_awards(month, kind);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS monthly_awards;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003800_add_floor_exempt_to_matches_and_elo_history",
+			Up: func(db *gorm.DB) error {
+				return db.Exec(`
+					ALTER TABLE matches ADD COLUMN IF NOT EXISTS floor_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+					ALTER TABLE elo_history ADD COLUMN IF NOT EXISTS floor_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+					ALTER TABLE elo_history_archive ADD COLUMN IF NOT EXISTS floor_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+
+					-- New columns can only be appended to a replaced view
+					CREATE OR REPLACE VIEW elo_history_all AS
+					SELECT id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+						opponent_id, opponent_team_id, match_type, created_at, updated_at, deleted_at, floor_exempt
+					FROM elo_history
+					UNION ALL
+					SELECT id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+						opponent_id, opponent_team_id, match_type, created_at, updated_at, deleted_at, floor_exempt
+					FROM elo_history_archive;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP VIEW IF EXISTS elo_history_all;
+					CREATE VIEW elo_history_all AS
+					SELECT id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+						opponent_id, opponent_team_id, match_type, created_at, updated_at, deleted_at
+					FROM elo_history
+					UNION ALL
+					SELECT id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+						opponent_id, opponent_team_id, match_type, created_at, updated_at, deleted_at
+					FROM elo_history_archive;
+
+					ALTER TABLE elo_history_archive DROP COLUMN IF EXISTS floor_exempt;
+					ALTER TABLE elo_history DROP COLUMN IF EXISTS floor_exempt;
+					ALTER TABLE matches DROP COLUMN IF EXISTS floor_exempt;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_003900_add_campus_to_players",
+			Up: func(db *gorm.DB) error {
+				// Optional campus and department, the ladder spans two INSA campuses
+				return db.Exec(`
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS campus VARCHAR(50);
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS department VARCHAR(100);
+					CREATE INDEX IF NOT EXISTS idx_players_campus ON players(campus);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_players_campus;
+					ALTER TABLE players DROP COLUMN IF EXISTS department;
+					ALTER TABLE players DROP COLUMN IF EXISTS campus;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004000_add_display_name_to_players",
+			Up: func(db *gorm.DB) error {
+				// Name shown on public payloads, separate from the username used to log in and for slugs
+				return db.Exec(`ALTER TABLE players ADD COLUMN IF NOT EXISTS display_name VARCHAR(50);`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`ALTER TABLE players DROP COLUMN IF EXISTS display_name;`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004100_create_export_jobs_table",
+			Up: func(db *gorm.DB) error {
+				// Exports produced in the background, the file is kept until expires_at
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS export_jobs (
+						id BIGSERIAL PRIMARY KEY,
+						kind VARCHAR(30) NOT NULL,
+						status VARCHAR(20) NOT NULL DEFAULT 'pending',
+						requested_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+						player_id BIGINT NULL REFERENCES players(id) ON DELETE CASCADE,
+						match_status VARCHAR(20) NULL,
+						date_from TIMESTAMP NULL,
+						date_to TIMESTAMP NULL,
+						file_name VARCHAR(100) NOT NULL DEFAULT '',
+						file_path VARCHAR(500) NOT NULL DEFAULT '',
+						size BIGINT NOT NULL DEFAULT 0,
+						rows INTEGER NOT NULL DEFAULT 0,
+						error TEXT NULL,
+						started_at TIMESTAMP NULL,
+						finished_at TIMESTAMP NULL,
+						expires_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE INDEX IF NOT EXISTS idx_export_jobs_status ON export_jobs(status);
+					CREATE INDEX IF NOT EXISTS idx_export_jobs_requested_by ON export_jobs(requested_by);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS export_jobs;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004200_create_webhook_tables",
+			Up: func(db *gorm.DB) error {
+				// Integrator endpoints receiving signed events, and the deliveries queued for them
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS webhook_endpoints (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						url VARCHAR(500) NOT NULL,
+						events VARCHAR(255) NOT NULL,
+						secret VARCHAR(100) NOT NULL,
+						previous_secret VARCHAR(100) NULL,
+						previous_secret_expires_at TIMESTAMP NULL,
+						cursor TIMESTAMP NULL,
+						created_by BIGINT NULL REFERENCES users(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+
+					CREATE TABLE IF NOT EXISTS webhook_deliveries (
+						id BIGSERIAL PRIMARY KEY,
+						delivery_id VARCHAR(40) NOT NULL,
+						endpoint_id BIGINT NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+						event VARCHAR(50) NOT NULL,
+						payload TEXT NOT NULL,
+						status VARCHAR(20) NOT NULL DEFAULT 'pending',
+						attempts INTEGER NOT NULL DEFAULT 0,
+						response_status INTEGER NULL,
+						last_error TEXT NULL,
+						next_attempt_at TIMESTAMP NULL,
+						delivered_at TIMESTAMP NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+					);
+					CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_delivery_id ON webhook_deliveries(delivery_id);
+					CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint_id ON webhook_deliveries(endpoint_id);
+					CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS webhook_deliveries;
+					DROP TABLE IF EXISTS webhook_endpoints;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004300_create_api_usage_table",
+			Up: func(db *gorm.DB) error {
+				// Daily request counters per user (0 when anonymous) and endpoint group
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS api_usage (
+						day DATE NOT NULL,
+						user_id BIGINT NOT NULL,
+						endpoint_group VARCHAR(50) NOT NULL,
+						request_count BIGINT NOT NULL DEFAULT 0,
+						last_activity_at TIMESTAMP NOT NULL,
+						PRIMARY KEY (day, user_id, endpoint_group)
+					);
+					CREATE INDEX IF NOT EXISTS idx_api_usage_user_id ON api_usage(user_id, day);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`DROP TABLE IF EXISTS api_usage;`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004400_create_event_leaderboards_tables",
+			Up: func(db *gorm.DB) error {
+				// Temporary ladders computed from the matches of a time window, with their opt-in participants
+				return db.Exec(`
+					CREATE TABLE IF NOT EXISTS event_leaderboards (
+						id BIGSERIAL PRIMARY KEY,
+						name VARCHAR(255) NOT NULL,
+						description TEXT NULL,
+						starts_at TIMESTAMP NOT NULL,
+						ends_at TIMESTAMP NOT NULL,
+						opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+						created_by BIGINT NULL REFERENCES users(id) ON DELETE SET NULL,
+						created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						CHECK (ends_at > starts_at)
+					);
+					CREATE INDEX IF NOT EXISTS idx_event_leaderboards_window ON event_leaderboards(starts_at, ends_at);
+
+					CREATE TABLE IF NOT EXISTS event_leaderboard_participants (
+						event_leaderboard_id BIGINT NOT NULL REFERENCES event_leaderboards(id) ON DELETE CASCADE,
+						player_id BIGINT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+						joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+						PRIMARY KEY (event_leaderboard_id, player_id)
+					);
+					CREATE INDEX IF NOT EXISTS idx_event_leaderboard_participants_player ON event_leaderboard_participants(player_id);
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP TABLE IF EXISTS event_leaderboard_participants;
+					DROP TABLE IF EXISTS event_leaderboards;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004500_add_archived_at_to_players",
+			Up: func(db *gorm.DB) error {
+				// Players who left the school, left out of the leaderboards
+				return db.Exec(`
+					ALTER TABLE players ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP NULL;
+					CREATE INDEX IF NOT EXISTS idx_players_archived_at ON players(archived_at) WHERE archived_at IS NOT NULL;
+				`).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(`
+					DROP INDEX IF EXISTS idx_players_archived_at;
+					ALTER TABLE players DROP COLUMN IF EXISTS archived_at;
+				`).Error
+			},
+		},
+		{
+			Name: "2026_10_16_004600_order_leaderboard_streaks_by_confirmation",
+			Up: func(db *gorm.DB) error {
+				// Streaks follow the order results were confirmed in, a match entered late counts when confirmed
+				return db.Exec(leaderboardViewSQL("COALESCE(confirmed_at, created_at)")).Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec(leaderboardViewSQL("created_at")).Error
+			},
+		},
 	}
 }
+
+// leaderboardViewSQL recreates the leaderboard view with the streaks computed in the order of resultAt
+func leaderboardViewSQL(resultAt string) string {
+	return `
+		DROP MATERIALIZED VIEW IF EXISTS leaderboard;
+
+		CREATE MATERIALIZED VIEW leaderboard AS
+		WITH results AS (
+			SELECT player1_id AS player_id, id, created_at, ` + resultAt + ` AS result_at, winner_id = player1_id AS won
+			FROM matches WHERE status = 'confirmed' AND deleted_at IS NULL
+			UNION ALL
+			SELECT player2_id AS player_id, id, created_at, ` + resultAt + ` AS result_at, winner_id = player2_id AS won
+			FROM matches WHERE status = 'confirmed' AND deleted_at IS NULL
+		), runs AS (
+			SELECT player_id, result_at, won,
+				ROW_NUMBER() OVER (PARTITION BY player_id ORDER BY result_at, id) -
+				ROW_NUMBER() OVER (PARTITION BY player_id, won ORDER BY result_at, id) AS run
+			FROM results
+		), run_lengths AS (
+			SELECT player_id, won, COUNT(*) AS length, MAX(result_at) AS ended_at
+			FROM runs
+			GROUP BY player_id, won, run
+		), best_streaks AS (
+			SELECT player_id, MAX(length) AS best_streak
+			FROM run_lengths WHERE won
+			GROUP BY player_id
+		), current_streaks AS (
+			SELECT DISTINCT ON (player_id) player_id, CASE WHEN won THEN length ELSE 0 END AS current_streak
+			FROM run_lengths
+			ORDER BY player_id, ended_at DESC
+		), last_matches AS (
+			SELECT player_id, MAX(created_at) AS last_match_at
+			FROM results
+			GROUP BY player_id
+		)
+		SELECT players.id AS player_id, players.username, players.elo_rating, players.rank,
+			players.total_matches, players.wins, players.losses, players.win_rate,
+			COALESCE(current_streaks.current_streak, 0) AS current_streak,
+			COALESCE(best_streaks.best_streak, 0) AS best_streak,
+			last_matches.last_match_at, players.away_from, players.away_until
+		FROM players
+		LEFT JOIN current_streaks ON current_streaks.player_id = players.id
+		LEFT JOIN best_streaks ON best_streaks.player_id = players.id
+		LEFT JOIN last_matches ON last_matches.player_id = players.id
+		WHERE players.deleted_at IS NULL;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_leaderboard_player ON leaderboard(player_id);
+		CREATE INDEX IF NOT EXISTS idx_leaderboard_elo ON leaderboard(elo_rating DESC, player_id);
+	`
+}
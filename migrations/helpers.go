@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// verifyModeKey est positionné sur la session GORM lorsque les migrations sont rejouées par VerifySchema
+const verifyModeKey = "migrations:verify"
+
+// isVerifying indique si la migration est rejouée dans la transaction de vérification du schéma
+func isVerifying(db *gorm.DB) bool {
+	verifying, ok := db.Get(verifyModeKey)
+	return ok && verifying == true
+}
+
+// CreateIndexConcurrently crée un index sans verrouiller la table en écriture.
+// La migration appelante doit déclarer DisableTransaction: CREATE INDEX CONCURRENTLY
+// est interdit dans une transaction. Un index invalide laissé par une tentative
+// précédente interrompue est supprimé avant d'être recréé.
+func CreateIndexConcurrently(db *gorm.DB, name, table, columns string) error {
+	if isVerifying(db) {
+		return db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", name, table, columns)).Error
+	}
+
+	var invalid int64
+	if err := db.Raw(`
+		SELECT COUNT(*)
+		FROM pg_index
+		JOIN pg_class ON pg_class.oid = pg_index.indexrelid
+		WHERE pg_class.relname = ? AND NOT pg_index.indisvalid
+	`, name).Scan(&invalid).Error; err != nil {
+		return err
+	}
+
+	if invalid > 0 {
+		if err := db.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", name)).Error; err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s (%s)", name, table, columns)).Error
+}
+
+// DropIndexConcurrently supprime un index sans verrouiller la table en écriture
+func DropIndexConcurrently(db *gorm.DB, name string) error {
+	if isVerifying(db) {
+		return db.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s", name)).Error
+	}
+	return db.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", name)).Error
+}
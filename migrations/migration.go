@@ -16,10 +16,31 @@ type Migration struct {
 
 type MigrationFunc func(*gorm.DB) error
 
+// MigrationPhase indique à quel moment du déploiement une migration doit être exécutée
+type MigrationPhase string
+
+const (
+	// PhasePreDeploy : migrations additives exécutées avant la mise en ligne du nouveau binaire (défaut)
+	PhasePreDeploy MigrationPhase = "pre"
+	// PhasePostDeploy : migrations exécutées une fois le nouveau binaire en ligne (index concurrents, nettoyage)
+	PhasePostDeploy MigrationPhase = "post"
+)
+
 type MigrationDefinition struct {
-	Name string
-	Up   MigrationFunc
-	Down MigrationFunc
+	Name  string
+	Up    MigrationFunc
+	Down  MigrationFunc
+	Phase MigrationPhase
+	// DisableTransaction exécute la migration hors transaction, requis par CREATE INDEX CONCURRENTLY
+	DisableTransaction bool
+}
+
+// GetPhase retourne la phase de la migration (pré-déploiement par défaut)
+func (md MigrationDefinition) GetPhase() MigrationPhase {
+	if md.Phase == "" {
+		return PhasePreDeploy
+	}
+	return md.Phase
 }
 
 type Migrator struct {
@@ -41,6 +62,7 @@ func (m *Migrator) AddMigration(migration MigrationDefinition) {
 	m.migrations = append(m.migrations, migration)
 }
 
+// Migrate exécute toutes les migrations en attente, quelle que soit leur phase
 func (m *Migrator) Migrate() error {
 	fmt.Println("Running database migrations...")
 
@@ -51,30 +73,84 @@ func (m *Migrator) Migrate() error {
 			continue
 		}
 
-		fmt.Printf("Migrating: %s\n", migration.Name)
+		if err := m.runUp(migration, batch); err != nil {
+			return err
+		}
+	}
 
-		tx := m.db.Begin()
+	fmt.Println("Migration completed successfully")
+	return nil
+}
 
-		if err := migration.Up(tx); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("migration %s failed: %w", migration.Name, err)
+// MigratePhase exécute uniquement les migrations en attente de la phase donnée.
+// La phase post-déploiement refuse de s'exécuter tant que des migrations pré-déploiement sont en attente.
+func (m *Migrator) MigratePhase(phase MigrationPhase) error {
+	if phase != PhasePreDeploy && phase != PhasePostDeploy {
+		return fmt.Errorf("unknown migration phase: %s", phase)
+	}
+
+	fmt.Printf("Running %s-deploy database migrations...\n", phase)
+
+	if phase == PhasePostDeploy {
+		for _, migration := range m.migrations {
+			if migration.GetPhase() == PhasePreDeploy && !m.hasRun(migration.Name) {
+				return fmt.Errorf("pre-deploy migration %s is pending, run the pre-deploy phase first", migration.Name)
+			}
+		}
+	}
+
+	batch := m.getNextBatch()
+
+	for _, migration := range m.migrations {
+		if migration.GetPhase() != phase || m.hasRun(migration.Name) {
+			continue
 		}
 
-		migrationRecord := Migration{
-			Name:  migration.Name,
-			Batch: batch,
+		if err := m.runUp(migration, batch); err != nil {
+			return err
 		}
+	}
 
-		if err := tx.Create(&migrationRecord).Error; err != nil {
-			tx.Rollback()
+	fmt.Println("Migration completed successfully")
+	return nil
+}
+
+// runUp exécute une migration et enregistre son passage, dans une transaction sauf si elle l'interdit
+func (m *Migrator) runUp(migration MigrationDefinition, batch int) error {
+	fmt.Printf("Migrating: %s\n", migration.Name)
+
+	migrationRecord := Migration{
+		Name:  migration.Name,
+		Batch: batch,
+	}
+
+	if migration.DisableTransaction {
+		if err := migration.Up(m.db); err != nil {
+			return fmt.Errorf("migration %s failed: %w", migration.Name, err)
+		}
+
+		if err := m.db.Create(&migrationRecord).Error; err != nil {
 			return fmt.Errorf("failed to record migration %s: %w", migration.Name, err)
 		}
 
-		tx.Commit()
 		fmt.Printf("Migrated: %s\n", migration.Name)
+		return nil
 	}
 
-	fmt.Println("Migration completed successfully")
+	tx := m.db.Begin()
+
+	if err := migration.Up(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %s failed: %w", migration.Name, err)
+	}
+
+	if err := tx.Create(&migrationRecord).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record migration %s: %w", migration.Name, err)
+	}
+
+	tx.Commit()
+	fmt.Printf("Migrated: %s\n", migration.Name)
 	return nil
 }
 
@@ -103,6 +179,17 @@ func (m *Migrator) Rollback(steps int) error {
 
 			fmt.Printf("Rolling back: %s\n", migrationRecord.Name)
 
+			if migration.DisableTransaction {
+				if err := migration.Down(m.db); err != nil {
+					return fmt.Errorf("rollback failed for %s: %w", migrationRecord.Name, err)
+				}
+				if err := m.db.Delete(&migrationRecord).Error; err != nil {
+					return fmt.Errorf("failed to remove migration record %s: %w", migrationRecord.Name, err)
+				}
+				fmt.Printf("Rolled back: %s\n", migrationRecord.Name)
+				continue
+			}
+
 			tx := m.db.Begin()
 
 			if err := migration.Down(tx); err != nil {
@@ -126,6 +213,25 @@ func (m *Migrator) Rollback(steps int) error {
 	return nil
 }
 
+// Pending retourne les noms des migrations de la phase donnée pas encore exécutées, dans l'ordre
+func (m *Migrator) Pending(phase MigrationPhase) []string {
+	var ran []string
+	m.db.Model(&Migration{}).Pluck("name", &ran)
+
+	done := make(map[string]bool, len(ran))
+	for _, name := range ran {
+		done[name] = true
+	}
+
+	var pending []string
+	for _, migration := range m.migrations {
+		if migration.GetPhase() == phase && !done[migration.Name] {
+			pending = append(pending, migration.Name)
+		}
+	}
+	return pending
+}
+
 func (m *Migrator) hasRun(name string) bool {
 	var count int64
 	m.db.Model(&Migration{}).Where("name = ?", name).Count(&count)
@@ -1,5 +1,7 @@
 package migrations
 
+import "gorm.io/gorm"
+
 func GetAllMigrations() []MigrationDefinition {
 	migrations := []MigrationDefinition{}
 
@@ -10,3 +12,15 @@ func GetAllMigrations() []MigrationDefinition {
 
 	return migrations
 }
+
+// NewAppMigrator crée un migrator contenant toutes les migrations de l'application (auth puis core)
+func NewAppMigrator(db *gorm.DB) *Migrator {
+	migrator := NewMigrator(db)
+	for _, migration := range GetAuthMigrations() {
+		migrator.AddMigration(migration)
+	}
+	for _, migration := range GetAllMigrations() {
+		migrator.AddMigration(migration)
+	}
+	return migrator
+}
@@ -0,0 +1,239 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationsTable est la table de suivi créée par le Migrator, ignorée lors de la comparaison
+const migrationsTable = "migrations"
+
+// errVerifyRollback est utilisé pour annuler la transaction de vérification
+var errVerifyRollback = errors.New("schema verification rollback")
+
+// DriftKind décrit le type de divergence détectée
+type DriftKind string
+
+const (
+	DriftMissingTable     DriftKind = "missing_table"
+	DriftMissingColumn    DriftKind = "missing_column"
+	DriftColumnType       DriftKind = "column_type"
+	DriftUnexpectedColumn DriftKind = "unexpected_column"
+	DriftMissingIndex     DriftKind = "missing_index"
+	DriftUnexpectedIndex  DriftKind = "unexpected_index"
+)
+
+// SchemaDrift représente une divergence entre le schéma live et les migrations
+type SchemaDrift struct {
+	Kind     DriftKind
+	Table    string
+	Object   string
+	Expected string
+	Actual   string
+}
+
+func (d SchemaDrift) String() string {
+	switch d.Kind {
+	case DriftMissingTable:
+		return fmt.Sprintf("table %s is missing", d.Table)
+	case DriftMissingColumn:
+		return fmt.Sprintf("column %s.%s is missing (expected %s)", d.Table, d.Object, d.Expected)
+	case DriftColumnType:
+		return fmt.Sprintf("column %s.%s has type %s (expected %s)", d.Table, d.Object, d.Actual, d.Expected)
+	case DriftUnexpectedColumn:
+		return fmt.Sprintf("column %s.%s is not declared by any migration", d.Table, d.Object)
+	case DriftMissingIndex:
+		return fmt.Sprintf("index %s on %s is missing", d.Object, d.Table)
+	case DriftUnexpectedIndex:
+		return fmt.Sprintf("index %s on %s is not declared by any migration", d.Object, d.Table)
+	}
+	return fmt.Sprintf("%s %s.%s", d.Kind, d.Table, d.Object)
+}
+
+// IsMissing indique si la divergence correspond à un objet attendu mais absent
+func (d SchemaDrift) IsMissing() bool {
+	return d.Kind == DriftMissingTable || d.Kind == DriftMissingColumn ||
+		d.Kind == DriftMissingIndex || d.Kind == DriftColumnType
+}
+
+type schemaSnapshot struct {
+	columns map[string]map[string]string // table -> colonne -> type
+	indexes map[string]map[string]bool   // table -> index
+}
+
+// VerifySchema compare le schéma live avec celui produit par les migrations déjà exécutées.
+// Les migrations sont rejouées dans un schéma temporaire à l'intérieur d'une transaction
+// qui est toujours annulée, ce qui évite de maintenir une description du schéma à la main.
+func (m *Migrator) VerifySchema() ([]SchemaDrift, error) {
+	var liveSchema string
+	if err := m.db.Raw("SELECT current_schema()").Scan(&liveSchema).Error; err != nil {
+		return nil, err
+	}
+
+	live, err := m.snapshot(m.db, liveSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read live schema: %w", err)
+	}
+
+	var expected *schemaSnapshot
+	scratchSchema := fmt.Sprintf("schema_verify_%d", time.Now().UnixNano())
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("CREATE SCHEMA %s", scratchSchema)).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL search_path TO %s", scratchSchema)).Error; err != nil {
+			return err
+		}
+
+		verifyTx := tx.Set(verifyModeKey, true)
+		for _, migration := range m.migrations {
+			if !m.hasRun(migration.Name) {
+				continue
+			}
+			if err := migration.Up(verifyTx); err != nil {
+				return fmt.Errorf("migration %s failed during verification: %w", migration.Name, err)
+			}
+		}
+
+		snapshot, err := m.snapshot(tx, scratchSchema)
+		if err != nil {
+			return err
+		}
+		expected = snapshot
+
+		return errVerifyRollback
+	})
+	if err != nil && !errors.Is(err, errVerifyRollback) {
+		return nil, err
+	}
+
+	return diffSchemas(expected, live), nil
+}
+
+func (m *Migrator) snapshot(db *gorm.DB, schema string) (*schemaSnapshot, error) {
+	snapshot := &schemaSnapshot{
+		columns: map[string]map[string]string{},
+		indexes: map[string]map[string]bool{},
+	}
+
+	type columnRow struct {
+		TableName  string
+		ColumnName string
+		DataType   string
+	}
+	var columns []columnRow
+	if err := db.Raw(`
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = ?
+	`, schema).Scan(&columns).Error; err != nil {
+		return nil, err
+	}
+
+	for _, column := range columns {
+		if column.TableName == migrationsTable {
+			continue
+		}
+		if snapshot.columns[column.TableName] == nil {
+			snapshot.columns[column.TableName] = map[string]string{}
+		}
+		snapshot.columns[column.TableName][column.ColumnName] = column.DataType
+	}
+
+	type indexRow struct {
+		Tablename string
+		Indexname string
+	}
+	var indexes []indexRow
+	if err := db.Raw(`
+		SELECT tablename, indexname
+		FROM pg_indexes
+		WHERE schemaname = ?
+	`, schema).Scan(&indexes).Error; err != nil {
+		return nil, err
+	}
+
+	for _, index := range indexes {
+		if index.Tablename == migrationsTable {
+			continue
+		}
+		if snapshot.indexes[index.Tablename] == nil {
+			snapshot.indexes[index.Tablename] = map[string]bool{}
+		}
+		snapshot.indexes[index.Tablename][index.Indexname] = true
+	}
+
+	return snapshot, nil
+}
+
+func diffSchemas(expected, live *schemaSnapshot) []SchemaDrift {
+	var drifts []SchemaDrift
+
+	for _, table := range sortedKeys(expected.columns) {
+		liveColumns, ok := live.columns[table]
+		if !ok {
+			drifts = append(drifts, SchemaDrift{Kind: DriftMissingTable, Table: table})
+			continue
+		}
+
+		for _, column := range sortedKeys(expected.columns[table]) {
+			expectedType := expected.columns[table][column]
+			actualType, ok := liveColumns[column]
+			if !ok {
+				drifts = append(drifts, SchemaDrift{Kind: DriftMissingColumn, Table: table, Object: column, Expected: expectedType})
+				continue
+			}
+			if actualType != expectedType {
+				drifts = append(drifts, SchemaDrift{Kind: DriftColumnType, Table: table, Object: column, Expected: expectedType, Actual: actualType})
+			}
+		}
+
+		for _, column := range sortedKeys(liveColumns) {
+			if _, ok := expected.columns[table][column]; !ok {
+				drifts = append(drifts, SchemaDrift{Kind: DriftUnexpectedColumn, Table: table, Object: column, Actual: liveColumns[column]})
+			}
+		}
+
+		for _, index := range sortedKeys(expected.indexes[table]) {
+			if !live.indexes[table][index] {
+				drifts = append(drifts, SchemaDrift{Kind: DriftMissingIndex, Table: table, Object: index})
+			}
+		}
+
+		for _, index := range sortedKeys(live.indexes[table]) {
+			if !expected.indexes[table][index] {
+				drifts = append(drifts, SchemaDrift{Kind: DriftUnexpectedIndex, Table: table, Object: index})
+			}
+		}
+	}
+
+	return drifts
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatDrifts retourne un rapport lisible des divergences détectées
+func FormatDrifts(drifts []SchemaDrift) string {
+	var builder strings.Builder
+	for _, drift := range drifts {
+		level := "WARN"
+		if drift.IsMissing() {
+			level = "MISSING"
+		}
+		fmt.Fprintf(&builder, "  [%s] %s\n", level, drift)
+	}
+	return builder.String()
+}
@@ -0,0 +1,87 @@
+// Package openapi builds the OpenAPI (Swagger 2.0) spec of the API from the swag annotations of the
+// handlers. The spec embedded in the binary is generated by `go generate ./openapi`, which `make build`
+// runs first; a server started from its source tree outside release mode generates it again on start,
+// so that /openapi.json always describes the handlers it serves.
+package openapi
+
+//go:generate go run ../cmd/openapi generate -dir ..
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+// File is where the generated spec is written, relative to the source root
+const File = "openapi/openapi.json"
+
+// Sources of the served spec
+const (
+	SourceEmbedded = "embedded" // Generated when the binary was built
+	SourceRuntime  = "runtime"  // Generated from the source tree on start
+)
+
+//go:embed openapi.json
+var embedded []byte
+
+// excludedDirs hold no handler, skipping them keeps the generation fast
+var excludedDirs = []string{"cmd", "fixtures", "migrations", "perf", "storage"}
+
+// Generate parses the annotations of the source tree whose main.go is in dir and returns the spec as
+// indented JSON. It needs the Go toolchain to resolve the packages.
+func Generate(dir string) ([]byte, error) {
+	excludes := ""
+	for _, excluded := range excludedDirs {
+		excludes += filepath.Join(dir, excluded) + ","
+	}
+
+	parser := swag.New(
+		swag.SetDebugger(log.New(io.Discard, "", 0)),
+		swag.SetExcludedDirsAndFiles(excludes),
+	)
+	if err := parser.ParseAPI(dir, "main.go", 100); err != nil {
+		return nil, err
+	}
+
+	spec, err := json.MarshalIndent(parser.GetSwagger(), "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(spec, '\n'), nil
+}
+
+// Embedded returns the spec generated when the binary was built
+func Embedded() []byte {
+	return embedded
+}
+
+// Load returns the spec to serve. From the source tree (main.go and openapi/ in dir) outside release
+// mode, it is generated again unless OPENAPI_RUNTIME=false; otherwise, or if the generation fails, the
+// embedded spec is served.
+func Load(dir string) ([]byte, string) {
+	if gin.Mode() == gin.ReleaseMode || os.Getenv("OPENAPI_RUNTIME") == "false" || !isSourceTree(dir) {
+		return embedded, SourceEmbedded
+	}
+
+	spec, err := Generate(dir)
+	if err != nil {
+		log.Printf("⚠️  OpenAPI generation from the source failed, serving the spec embedded at build: %v", err)
+		return embedded, SourceEmbedded
+	}
+	return spec, SourceRuntime
+}
+
+func isSourceTree(dir string) bool {
+	for _, path := range []string{"main.go", File} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			return false
+		}
+	}
+	return true
+}
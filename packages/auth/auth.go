@@ -30,8 +30,26 @@ func (m *Module) SetupRoutes(r *gin.Engine) {
 		auth.POST("/logout-all", middleware.JWTMiddleware(), m.Handler.LogoutAll)
 		auth.POST("/reset-password/send-link", m.Handler.SendPasswordResetLink)
 		auth.POST("/reset-password/confirm", m.Handler.ConfirmPasswordReset)
+		auth.POST("/verify-email/confirm", m.Handler.ConfirmEmailVerification)
 		auth.POST("/change-password", middleware.JWTMiddleware(), m.Handler.ChangePassword)
 	}
+
+	consents := r.Group("/consents")
+	consents.Use(middleware.JWTMiddleware())
+	{
+		consents.GET("/me", m.Handler.GetMyConsents)
+		consents.POST("", m.Handler.RecordConsent)
+	}
+
+	webhooks := r.Group("/webhooks")
+	{
+		webhooks.POST("/email/events", m.Handler.HandleEmailEvent)
+	}
+}
+
+// ConsentGate répond 428 aux membres qui n'ont pas accepté les conditions en vigueur, à enregistrer avant les routes
+func (m *Module) ConsentGate() gin.HandlerFunc {
+	return middleware.RequireConsent(m.Handler.Consents)
 }
 
 func JWTMiddleware() gin.HandlerFunc {
@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth/models"
+	"auth/services"
+	"auth/utils"
+	"core/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Nombre maximum de renvois d'un même type d'email pour un utilisateur sur la fenêtre
+	resendEmailLimit  = 3
+	resendEmailWindow = time.Hour
+)
+
+// @Summary Resend Verification or Reset Email
+// @Description Re-trigger the verification or password reset email for a user (admin only). Rate limited per user and email type, every attempt is audited.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path uint true "User ID"
+// @Param type query string true "Email type" Enums(verification, reset)
+// @Param request body models.ResendEmailRequest true "Frontend callback URL ([token] is replaced)"
+// @Success 200 {object} models.ResendEmailResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /admin/users/{id}/resend-email [post]
+func (h *AuthHandler) ResendEmail(c *gin.Context) {
+	if h.workshop {
+		c.JSON(http.StatusForbidden, gin.H{"error": errWorkshopEmailDisabled.Error()})
+		return
+	}
+
+	emailType := c.Query("type")
+	if emailType != models.ResendEmailTypeVerification && emailType != models.ResendEmailTypeReset {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type parameter, expected verification or reset"})
+		return
+	}
+
+	var req models.ResendEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, targetID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if emailType == models.ResendEmailTypeVerification && user.IsEmailVerified() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already verified"})
+		return
+	}
+
+	if allowed, retryAfter := h.resendLimiter.Allow(fmt.Sprintf("%d:%s", user.ID, emailType)); !allowed {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Too many emails sent to this user, try again later",
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	action := models.AuditActionResendVerificationEmail
+	if emailType == models.ResendEmailTypeReset {
+		action = models.AuditActionResendResetEmail
+		err = h.issuePasswordReset(c, &user, req.CallBackUrl)
+	} else {
+		err = h.issueEmailVerification(c, &user, req.CallBackUrl)
+	}
+
+	details := models.AuditDetails{"email": user.Email, "status": "sent"}
+	if err != nil {
+		details["status"] = "failed"
+		details["error"] = err.Error()
+	}
+	h.AuditService.Log(adminID.(uint), action, models.AuditTargetUser, user.ID, details, c.ClientIP())
+
+	if errors.Is(err, services.ErrEmailSuppressed) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email address is suppressed after a bounce or complaint", "email_status": user.EmailStatus})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResendEmailResponse{Success: true, Type: emailType})
+}
+
+// @Summary Bulk User Operations
+// @Description Apply one action (disable, add_role, send_email) to a list of users and report the outcome for each of them (admin only). Every change is audited; send_email shares the per-user rate limit of resend-email.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.BulkUserRequest true "Users and action to apply"
+// @Success 200 {object} models.BulkUserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/users/bulk [post]
+func (h *AuthHandler) BulkUsers(c *gin.Context) {
+	var req models.BulkUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	switch req.Action {
+	case models.BulkUserActionAddRole:
+		if !models.IsValidRole(req.Role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid role: %s", req.Role)})
+			return
+		}
+	case models.BulkUserActionSendEmail:
+		if h.workshop {
+			c.JSON(http.StatusForbidden, gin.H{"error": errWorkshopEmailDisabled.Error()})
+			return
+		}
+		if req.EmailType != models.ResendEmailTypeVerification && req.EmailType != models.ResendEmailTypeReset {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email_type, expected verification or reset"})
+			return
+		}
+		if req.CallBackUrl == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "callBackUrl is required for send_email"})
+			return
+		}
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	response := models.BulkUserResponse{Action: req.Action, Results: make([]models.BulkUserResult, 0, len(req.UserIDs))}
+	seen := make(map[uint]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		// Un même identifiant présent plusieurs fois n'est traité qu'une fois
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		status, err := h.applyBulkUserAction(c, adminID.(uint), userID, req)
+		result := models.BulkUserResult{UserID: userID, Status: status}
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		switch status {
+		case models.BulkUserStatusOK:
+			response.Succeeded++
+		case models.BulkUserStatusSkipped:
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+	response.Total = len(response.Results)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// applyBulkUserAction applique l'action à un utilisateur et retourne son statut dans le rapport.
+// Une erreur accompagne les statuts skipped (raison) et failed.
+func (h *AuthHandler) applyBulkUserAction(c *gin.Context, adminID, userID uint, req models.BulkUserRequest) (string, error) {
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		return models.BulkUserStatusFailed, errors.New("user not found")
+	}
+
+	switch req.Action {
+	case models.BulkUserActionDisable:
+		if user.ID == adminID {
+			return models.BulkUserStatusSkipped, errors.New("cannot disable your own account")
+		}
+		if !user.Enabled {
+			return models.BulkUserStatusSkipped, errors.New("already disabled")
+		}
+		if err := h.DB.Model(&user).Update("enabled", false).Error; err != nil {
+			return models.BulkUserStatusFailed, err
+		}
+		// Un compte désactivé ne doit pas pouvoir rafraîchir ses sessions en cours
+		if err := utils.RevokeAllUserTokens(h.DB, user.ID); err != nil {
+			return models.BulkUserStatusFailed, err
+		}
+		h.AuditService.Log(adminID, models.AuditActionUserDisabled, models.AuditTargetUser, user.ID, models.AuditDetails{"bulk": true}, c.ClientIP())
+
+	case models.BulkUserActionAddRole:
+		if user.HasRole(req.Role) {
+			return models.BulkUserStatusSkipped, errors.New("role already granted")
+		}
+		user.AddRole(req.Role)
+		if err := h.DB.Model(&user).Update("roles", user.Roles).Error; err != nil {
+			return models.BulkUserStatusFailed, err
+		}
+		h.AuditService.Log(adminID, models.AuditActionRoleAdded, models.AuditTargetUser, user.ID, models.AuditDetails{"role": req.Role, "bulk": true}, c.ClientIP())
+
+	case models.BulkUserActionSendEmail:
+		if req.EmailType == models.ResendEmailTypeVerification && user.IsEmailVerified() {
+			return models.BulkUserStatusSkipped, errors.New("email already verified")
+		}
+		if user.IsEmailSuppressed() {
+			return models.BulkUserStatusSkipped, services.ErrEmailSuppressed
+		}
+		if allowed, _ := h.resendLimiter.Allow(fmt.Sprintf("%d:%s", user.ID, req.EmailType)); !allowed {
+			return models.BulkUserStatusSkipped, errors.New("too many emails sent to this user, try again later")
+		}
+
+		var err error
+		action := models.AuditActionResendVerificationEmail
+		if req.EmailType == models.ResendEmailTypeReset {
+			action = models.AuditActionResendResetEmail
+			err = h.issuePasswordReset(c, &user, req.CallBackUrl)
+		} else {
+			err = h.issueEmailVerification(c, &user, req.CallBackUrl)
+		}
+
+		details := models.AuditDetails{"email": user.Email, "status": "sent", "bulk": true}
+		if err != nil {
+			details["status"] = "failed"
+			details["error"] = err.Error()
+		}
+		h.AuditService.Log(adminID, action, models.AuditTargetUser, user.ID, details, c.ClientIP())
+		if err != nil {
+			return models.BulkUserStatusFailed, err
+		}
+	}
+
+	return models.BulkUserStatusOK, nil
+}
+
+// EmailLogListResponse represents the paginated email delivery log
+type EmailLogListResponse struct {
+	Emails     []models.EmailLog `json:"emails"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PerPage    int               `json:"per_page"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// @Summary Get Email Delivery Log
+// @Description Get paginated list of outgoing emails with their delivery status (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param per_page query int false "Items per page (default: 20, max: 100)" default(20)
+// @Param recipient query string false "Search in recipient address"
+// @Param user_id query int false "Filter by user ID"
+// @Param template query string false "Filter by template" Enums(password_reset, verification, notification_digest, data_quality_report)
+// @Param status query string false "Filter by status" Enums(sent, failed, suppressed)
+// @Param from query string false "Only emails sent after this date (YYYY-MM-DD)"
+// @Param to query string false "Only emails sent before the end of this date (YYYY-MM-DD)"
+// @Success 200 {object} EmailLogListResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/emails [get]
+func (h *AuthHandler) GetEmailLogs(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if err != nil || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid per_page parameter"})
+		return
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	query := h.DB.Model(&models.EmailLog{})
+
+	if recipient := c.Query("recipient"); recipient != "" {
+		query = query.Where("recipient ILIKE ?", "%"+recipient+"%")
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id parameter"})
+			return
+		}
+		query = query.Where("user_id = ?", userID)
+	}
+	if template := c.Query("template"); template != "" {
+		query = query.Where("template = ?", template)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter, expected YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("created_at >= ?", from)
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter, expected YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("created_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count emails"})
+		return
+	}
+
+	var emails []models.EmailLog
+	if err := query.Order("created_at DESC").Offset((page - 1) * perPage).Limit(perPage).Find(&emails).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve emails"})
+		return
+	}
+
+	c.JSON(http.StatusOK, EmailLogListResponse{
+		Emails:     emails,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	})
+}
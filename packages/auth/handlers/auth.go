@@ -3,6 +3,7 @@ package handlers
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,7 +14,9 @@ import (
 	"auth/models"
 	"auth/services"
 	"auth/utils"
+	"core/events"
 	coreServices "core/services"
+	"core/validation"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -22,14 +25,25 @@ import (
 type AuthHandler struct {
 	DB            *gorm.DB
 	EmailService  services.EmailService
+	AuditService  *services.AuditService
+	Consents      *services.ConsentService // Conditions d'utilisation et publication des photos, versions TERMS_VERSION/PHOTO_CONSENT_VERSION
 	PlayerService *coreServices.PlayerService
+	Events        *events.Bus // Flux temps réel de la console admin, optionnel
+	resendLimiter *utils.RateLimiter
+	resetLimits   *passwordResetLimits
+	workshop      bool // AUTH_MODE=workshop : aucun email, connexion par identifiant, comptes générés par lot
 }
 
 func NewAuthHandler(db *gorm.DB, playerService *coreServices.PlayerService) *AuthHandler {
 	return &AuthHandler{
 		DB:            db,
-		EmailService:  services.NewEmailService(), // Service email automatique (SMTP si configuré, sinon log)
+		EmailService:  services.NewEmailService(db), // Provider selon MAIL_PROVIDER/MAIL_DSN ; adresses en bounce ignorées, envois journalisés
+		AuditService:  services.NewAuditService(db),
+		Consents:      services.NewConsentService(db),
 		PlayerService: playerService,
+		resendLimiter: utils.NewRateLimiter(resendEmailLimit, resendEmailWindow),
+		resetLimits:   newPasswordResetLimits(),
+		workshop:      workshopModeFromEnv(),
 	}
 }
 
@@ -66,6 +80,10 @@ func (h *AuthHandler) CreateUserAndPlayerWithTx(req models.RegisterRequest) (*mo
 			return err
 		}
 
+		if req.Campus != nil || req.Department != nil {
+			return h.PlayerService.SetCampusWithTx(tx, user.ID, req.Campus, req.Department)
+		}
+
 		return nil
 	})
 
@@ -77,7 +95,7 @@ func (h *AuthHandler) CreateUserAndPlayerWithTx(req models.RegisterRequest) (*mo
 }
 
 // @Summary User Registration
-// @Description Register a new user and get JWT tokens
+// @Description Register a new user and get JWT tokens. In workshop mode (AUTH_MODE=workshop) no verification email is sent. When terms of use are configured, terms_version must be the version in force; it is recorded as accepted along with the optional photo publication answer
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -90,7 +108,14 @@ func (h *AuthHandler) CreateUserAndPlayerWithTx(req models.RegisterRequest) (*mo
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	// Les conditions en vigueur doivent être acceptées à l'inscription, si elles sont configurées
+	termsVersion := h.Consents.CurrentVersion(models.ConsentKindTerms)
+	if termsVersion != "" && req.TermsVersion != termsVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Terms must be accepted", "terms_version": termsVersion})
 		return
 	}
 
@@ -110,6 +135,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	h.recordRegistrationConsents(c, user.ID, req)
+
+	// L'email de vérification n'est envoyé que si le front fournit le lien de confirmation, jamais en mode atelier
+	if req.VerifyCallBackUrl != "" && !h.workshop {
+		if err := h.issueEmailVerification(c, user, req.VerifyCallBackUrl); err != nil {
+			log.Printf("Warning: Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
 	tokenPair, err := utils.GenerateTokenPair(h.DB, *user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
@@ -128,7 +162,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 }
 
 // @Summary User Login
-// @Description Login with email and password to get JWT tokens
+// @Description Login with email and password to get JWT tokens. In workshop mode (AUTH_MODE=workshop) the username can replace the email
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -140,12 +174,21 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
+	query := h.DB.Where("email = ?", req.Email)
+	if req.Email == "" {
+		if !h.workshop {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Login by username is only available in workshop mode"})
+			return
+		}
+		query = h.DB.Where("LOWER(username) = ?", strings.ToLower(req.Username))
+	}
+
 	var user models.User
-	if err := h.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+	if err := query.First(&user).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
@@ -156,7 +199,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Mettre à jour lastLogin et nbConnexion (seulement si différent jour)
-	now := time.Now()
+	now := utils.Now()
 	shouldIncrementConnexion := true
 
 	if user.LastLogin != nil {
@@ -230,7 +273,7 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req models.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -242,7 +285,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// Mettre à jour lastLogin et nbConnexion (seulement si différent jour)
-	now := time.Now()
+	now := utils.Now()
 	user := refreshToken.User
 	shouldIncrementConnexion := true
 
@@ -283,7 +326,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) Logout(c *gin.Context) {
 	var req models.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -329,18 +372,24 @@ func generateConfirmationToken() (string, error) {
 }
 
 // @Summary Send Password Reset Link
-// @Description Send password reset link to user email
+// @Description Send password reset link to user email. Emails are capped per IP and globally per hour (PASSWORD_RESET_IP_LIMIT, PASSWORD_RESET_GLOBAL_LIMIT); a capped request gets the same response but no email
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body models.PasswordResetRequest true "Password reset request"
 // @Success 200 {object} models.PasswordResetResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /auth/reset-password/send-link [post]
 func (h *AuthHandler) SendPasswordResetLink(c *gin.Context) {
+	if h.workshop {
+		c.JSON(http.StatusForbidden, gin.H{"error": errWorkshopEmailDisabled.Error()})
+		return
+	}
+
 	var req models.PasswordResetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -360,11 +409,40 @@ func (h *AuthHandler) SendPasswordResetLink(c *gin.Context) {
 		return
 	}
 
+	// Plafonds par IP et global : même réponse, aucun email envoyé
+	if !h.resetLimits.allow(c.ClientIP(), h.Events) {
+		c.JSON(http.StatusOK, models.PasswordResetResponse{Success: true})
+		return
+	}
+
+	if err := h.issuePasswordReset(c, &user, req.CallBackUrl); err != nil {
+		// Adresse bloquée (bounce/plainte) : même réponse pour éviter l'énumération
+		if errors.Is(err, services.ErrEmailSuppressed) {
+			c.JSON(http.StatusOK, models.PasswordResetResponse{Success: true})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PasswordResetResponse{Success: true})
+}
+
+// buildCallbackURL construit le lien envoyé par email à partir de l'origine de la requête
+func buildCallbackURL(c *gin.Context, callBackUrl, token string) string {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		origin = "http://localhost:3030" // URL par défaut pour le développement
+	}
+	return fmt.Sprintf("%s%s", origin, strings.ReplaceAll(callBackUrl, "[token]", token))
+}
+
+// issuePasswordReset génère un nouveau token de reset, le sauvegarde et envoie l'email
+func (h *AuthHandler) issuePasswordReset(c *gin.Context, user *models.User, callBackUrl string) error {
 	// Générer un nouveau token de confirmation
 	token, err := generateConfirmationToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate confirmation token"})
-		return
+		return errors.New("Failed to generate confirmation token")
 	}
 
 	// Mettre à jour l'utilisateur avec le nouveau token
@@ -372,25 +450,77 @@ func (h *AuthHandler) SendPasswordResetLink(c *gin.Context) {
 	user.ConfirmationToken = &token
 	user.PasswordRequestedAt = &now
 
-	if err := h.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save password reset request"})
+	if err := h.DB.Save(user).Error; err != nil {
+		return errors.New("Failed to save password reset request")
+	}
+
+	// Envoyer l'email de reset
+	if err := h.EmailService.SendPasswordResetEmail(user.Email, buildCallbackURL(c, callBackUrl, token)); err != nil {
+		if errors.Is(err, services.ErrEmailSuppressed) {
+			return err
+		}
+		return errors.New("Failed to send password reset email")
+	}
+
+	return nil
+}
+
+// issueEmailVerification génère un nouveau token de vérification, le sauvegarde et envoie l'email
+func (h *AuthHandler) issueEmailVerification(c *gin.Context, user *models.User, callBackUrl string) error {
+	token, err := generateConfirmationToken()
+	if err != nil {
+		return errors.New("Failed to generate verification token")
+	}
+
+	user.EmailVerificationToken = &token
+
+	if err := h.DB.Save(user).Error; err != nil {
+		return errors.New("Failed to save verification token")
+	}
+
+	if err := h.EmailService.SendVerificationEmail(user.Email, buildCallbackURL(c, callBackUrl, token)); err != nil {
+		if errors.Is(err, services.ErrEmailSuppressed) {
+			return err
+		}
+		return errors.New("Failed to send verification email")
+	}
+
+	return nil
+}
+
+// @Summary Confirm Email Verification
+// @Description Confirm the user email address with the token received by email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.EmailVerificationConfirmRequest true "Email verification confirmation"
+// @Success 200 {object} models.EmailVerificationConfirmResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/verify-email/confirm [post]
+func (h *AuthHandler) ConfirmEmailVerification(c *gin.Context) {
+	var req models.EmailVerificationConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
-	// Construire l'URL de reset avec le token
-	origin := c.GetHeader("Origin")
-	if origin == "" {
-		origin = "http://localhost:3030" // URL par défaut pour le développement
+	var user models.User
+	if err := h.DB.Where("email_verification_token = ?", req.Token).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid or expired token"})
+		return
 	}
-	resetURL := fmt.Sprintf("%s%s", origin, strings.ReplaceAll(req.CallBackUrl, "[token]", token))
 
-	// Envoyer l'email de reset
-	if err := h.EmailService.SendPasswordResetEmail(user.Email, resetURL); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send password reset email"})
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user.EmailVerificationToken = nil
+
+	if err := h.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.PasswordResetResponse{Success: true})
+	c.JSON(http.StatusOK, models.EmailVerificationConfirmResponse{Success: true})
 }
 
 // @Summary Confirm Password Reset
@@ -406,7 +536,7 @@ func (h *AuthHandler) SendPasswordResetLink(c *gin.Context) {
 func (h *AuthHandler) ConfirmPasswordReset(c *gin.Context) {
 	var req models.PasswordResetConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -464,7 +594,7 @@ func (h *AuthHandler) ConfirmPasswordReset(c *gin.Context) {
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	var req models.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -521,7 +651,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 func (h *AuthHandler) UpdateUser(c *gin.Context) {
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -582,7 +712,7 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 }
 
 // @Summary Patch User Roles and Status
-// @Description Update user email, roles and enabled status (admin only)
+// @Description Update user email, roles, enabled status and email delivery status (admin only)
 // @Tags user
 // @Security BearerAuth
 // @Accept json
@@ -599,7 +729,7 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 func (h *AuthHandler) PatchUser(c *gin.Context) {
 	var req models.PatchUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -666,11 +796,27 @@ func (h *AuthHandler) PatchUser(c *gin.Context) {
 		targetUser.Enabled = *req.Enabled
 	}
 
+	// Update email delivery status if provided (e.g. reactivate an address after a bounce was fixed)
+	previousEmailStatus := targetUser.EmailStatus
+	if req.EmailStatus != nil && *req.EmailStatus != targetUser.EmailStatus {
+		now := time.Now()
+		targetUser.EmailStatus = *req.EmailStatus
+		targetUser.EmailStatusUpdatedAt = &now
+		targetUser.EmailStatusReason = nil
+	}
+
 	if err := h.DB.Save(&targetUser).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
 		return
 	}
 
+	if targetUser.EmailStatus != previousEmailStatus {
+		h.AuditService.Log(currentUser.ID, models.AuditActionEmailStatusChanged, models.AuditTargetUser, targetUser.ID, models.AuditDetails{
+			"from": previousEmailStatus,
+			"to":   targetUser.EmailStatus,
+		}, c.ClientIP())
+	}
+
 	c.JSON(http.StatusOK, targetUser)
 }
 
@@ -691,6 +837,7 @@ type UserListResponse struct {
 // @Param page query int false "Page number (default: 1)" default(1)
 // @Param per_page query int false "Items per page (default: 10, max: 100)" default(10)
 // @Param search query string false "Search in username or email"
+// @Param email_status query string false "Filter by email delivery status" Enums(active, bounced, complained)
 // @Success 200 {object} UserListResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -707,6 +854,7 @@ func (h *AuthHandler) GetUsers(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	perPageStr := c.DefaultQuery("per_page", "10")
 	search := c.Query("search")
+	emailStatus := c.Query("email_status")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
@@ -734,6 +882,9 @@ func (h *AuthHandler) GetUsers(c *gin.Context) {
 		searchPattern := "%" + search + "%"
 		query = query.Where("username ILIKE ? OR email ILIKE ?", searchPattern, searchPattern)
 	}
+	if emailStatus != "" {
+		query = query.Where("email_status = ?", emailStatus)
+	}
 
 	// Get total count with search filter
 	var total int64
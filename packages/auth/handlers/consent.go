@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth/middleware"
+	"auth/models"
+	"core/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get my consents
+// @Description Get the current user's answers to the terms of use and to the publication of tournament photos, with the versions in force. up_to_date is false when the user must answer again after a version change
+// @Tags consents
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ConsentStatusResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /consents/me [get]
+func (h *AuthHandler) GetMyConsents(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	status, err := h.Consents.Status(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary Record a consent
+// @Description Accept the terms of use or answer the photo publication consent for the version in force. Every answer is kept with its timestamp, IP address and user agent; the latest one per kind applies. The terms can only be accepted
+// @Tags consents
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param consent body models.RecordConsentRequest true "Consent kind, version and answer"
+// @Success 201 {object} models.Consent
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /consents [post]
+func (h *AuthHandler) RecordConsent(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.RecordConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	consent, err := h.Consents.Record(userID, req.Kind, req.Version, *req.Granted, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		switch err.Error() {
+		case "no consent version configured", "terms must be accepted":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "consent version is not current":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "current_version": h.Consents.CurrentVersion(req.Kind)})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, consent)
+}
+
+// @Summary Export consents
+// @Description Export the recorded consents as CSV with the user, version, answer, timestamp, IP address and user agent, oldest first. With current=true only the latest answer of each user per kind is kept, e.g. to check who agreed to photo publication before publishing tournament photos (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce text/csv
+// @Param kind query string false "Filter by consent kind" Enums(terms, photo_publication)
+// @Param current query bool false "Only the latest answer of each user per kind"
+// @Param from query string false "Only consents given after this date (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/consents/export [get]
+func (h *AuthHandler) ExportConsents(c *gin.Context) {
+	kind := c.Query("kind")
+	if kind != "" && kind != models.ConsentKindTerms && kind != models.ConsentKindPhoto {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kind parameter, expected terms or photo_publication"})
+		return
+	}
+
+	latestOnly := false
+	if currentStr := c.Query("current"); currentStr != "" {
+		current, err := strconv.ParseBool(currentStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid current parameter"})
+			return
+		}
+		latestOnly = current
+	}
+
+	var since *time.Time
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter, expected YYYY-MM-DD"})
+			return
+		}
+		since = &from
+	}
+
+	rows, err := h.Consents.Export(kind, latestOnly, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("consents-%s.csv", time.Now().Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"user_id", "email", "username", "kind", "version", "granted", "ip_address", "user_agent", "created_at"})
+	for _, row := range rows {
+		w.Write([]string{
+			strconv.FormatUint(uint64(row.UserID), 10),
+			row.Email,
+			row.Username,
+			row.Kind,
+			row.Version,
+			strconv.FormatBool(row.Granted),
+			row.IPAddress,
+			row.UserAgent,
+			row.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
+
+// recordRegistrationConsents enregistre les consentements donnés à l'inscription. Un échec n'annule pas
+// l'inscription : le membre sera invité à répondre à nouveau par RequireConsent.
+func (h *AuthHandler) recordRegistrationConsents(c *gin.Context, userID uint, req models.RegisterRequest) {
+	// Register a déjà vérifié que terms_version est la version en vigueur
+	if termsVersion := h.Consents.CurrentVersion(models.ConsentKindTerms); termsVersion != "" {
+		if _, err := h.Consents.Record(userID, models.ConsentKindTerms, termsVersion, true, c.ClientIP(), c.Request.UserAgent()); err != nil {
+			log.Printf("Warning: Failed to record terms consent of user %d: %v", userID, err)
+		}
+	}
+
+	photoVersion := h.Consents.CurrentVersion(models.ConsentKindPhoto)
+	if req.PhotoConsent != nil && photoVersion != "" {
+		if _, err := h.Consents.Record(userID, models.ConsentKindPhoto, photoVersion, *req.PhotoConsent, c.ClientIP(), c.Request.UserAgent()); err != nil {
+			log.Printf("Warning: Failed to record photo consent of user %d: %v", userID, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"time"
+
+	"auth/models"
+	"core/events"
+	"core/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Email Bounce/Complaint Webhook
+// @Description Receive bounce and complaint notifications from the mail provider. Hard bounces and complaints suppress further sends to the address. Requires the X-Webhook-Secret header matching EMAIL_WEBHOOK_SECRET.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Secret header string true "Shared webhook secret"
+// @Param event body models.EmailEventRequest true "Delivery event"
+// @Success 200 {object} models.EmailEventResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /webhooks/email/events [post]
+func (h *AuthHandler) HandleEmailEvent(c *gin.Context) {
+	secret := os.Getenv("EMAIL_WEBHOOK_SECRET")
+	if secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email webhook is not configured"})
+		return
+	}
+
+	provided := c.GetHeader("X-Webhook-Secret")
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook secret"})
+		return
+	}
+
+	var req models.EmailEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	// Les échecs de délivrance remontent dans la console admin
+	if req.Type == models.EmailEventBounce {
+		h.Events.Publish(events.TypeDeliveryFailed, map[string]interface{}{
+			"channel":     "email_webhook",
+			"email":       req.Email,
+			"bounce_type": req.BounceType,
+			"reason":      req.Reason,
+		})
+	}
+
+	var user models.User
+	if err := h.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	// Les bounces temporaires (boîte pleine, serveur indisponible) ne bloquent pas l'adresse
+	if req.Type == models.EmailEventBounce && req.BounceType == models.BounceTypeSoft {
+		c.JSON(http.StatusOK, models.EmailEventResponse{Success: true, EmailStatus: user.EmailStatus})
+		return
+	}
+
+	status := models.EmailStatusBounced
+	if req.Type == models.EmailEventComplaint {
+		status = models.EmailStatusComplained
+	}
+
+	now := time.Now()
+	user.EmailStatus = status
+	user.EmailStatusUpdatedAt = &now
+	if req.Reason != "" {
+		user.EmailStatusReason = &req.Reason
+	}
+
+	if err := h.DB.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email status"})
+		return
+	}
+
+	h.AuditService.Log(0, models.AuditActionEmailSuppressed, models.AuditTargetUser, user.ID, models.AuditDetails{
+		"email":  user.Email,
+		"status": status,
+		"reason": req.Reason,
+	}, c.ClientIP())
+
+	c.JSON(http.StatusOK, models.EmailEventResponse{Success: true, EmailStatus: status})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"auth/utils"
+	"core/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Plafonds par défaut des emails de réinitialisation par heure, 0 pour désactiver
+	defaultPasswordResetIPLimit     = 5
+	defaultPasswordResetGlobalLimit = 50
+	passwordResetWindow             = time.Hour
+
+	passwordResetGlobalKey = "global"
+)
+
+// PasswordResetMetrics compte les emails de réinitialisation envoyés et retenus depuis le démarrage
+type PasswordResetMetrics struct {
+	IPLimit       int       `json:"ip_limit" example:"5"`      // Emails par heure et par IP, 0 si désactivé
+	GlobalLimit   int       `json:"global_limit" example:"50"` // Emails par heure pour toute l'API, 0 si désactivé
+	Sent          int64     `json:"sent"`
+	LimitedIP     int64     `json:"limited_ip"`     // Demandes retenues par le plafond par IP
+	LimitedGlobal int64     `json:"limited_global"` // Demandes retenues par le plafond global
+	Since         time.Time `json:"since"`
+}
+
+// passwordResetLimits plafonne l'envoi des emails de réinitialisation par IP et globalement, pour que
+// l'endpoint public ne serve pas à inonder les boîtes des membres. Le plafond est silencieux : la
+// réponse reste la même pour ne pas révéler quelles adresses existent.
+type passwordResetLimits struct {
+	ipLimit     int
+	globalLimit int
+	perIP       *utils.RateLimiter // nil si désactivé
+	global      *utils.RateLimiter // nil si désactivé
+	alert       *utils.RateLimiter // Au plus une alerte console par fenêtre
+
+	sent          atomic.Int64
+	limitedIP     atomic.Int64
+	limitedGlobal atomic.Int64
+	since         time.Time
+}
+
+// newPasswordResetLimits lit PASSWORD_RESET_IP_LIMIT et PASSWORD_RESET_GLOBAL_LIMIT
+func newPasswordResetLimits() *passwordResetLimits {
+	limits := &passwordResetLimits{
+		ipLimit:     passwordResetLimitFromEnv("PASSWORD_RESET_IP_LIMIT", defaultPasswordResetIPLimit),
+		globalLimit: passwordResetLimitFromEnv("PASSWORD_RESET_GLOBAL_LIMIT", defaultPasswordResetGlobalLimit),
+		alert:       utils.NewRateLimiter(1, passwordResetWindow),
+		since:       time.Now(),
+	}
+	if limits.ipLimit > 0 {
+		limits.perIP = utils.NewRateLimiter(limits.ipLimit, passwordResetWindow)
+	}
+	if limits.globalLimit > 0 {
+		limits.global = utils.NewRateLimiter(limits.globalLimit, passwordResetWindow)
+	}
+	return limits
+}
+
+func passwordResetLimitFromEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Printf("Invalid %s %q, using %d", name, value, fallback)
+		return fallback
+	}
+	return limit
+}
+
+// allow enregistre un envoi pour l'IP si aucun plafond n'est atteint, et publie une alerte sur la
+// console admin quand le plafond global est atteint
+func (l *passwordResetLimits) allow(ip string, bus *events.Bus) bool {
+	if l.perIP != nil {
+		if allowed, _ := l.perIP.Allow(ip); !allowed {
+			l.limitedIP.Add(1)
+			log.Printf("Email de réinitialisation retenu : plafond de %d/h atteint pour l'IP %s", l.ipLimit, ip)
+			return false
+		}
+	}
+
+	if l.global != nil {
+		if allowed, _ := l.global.Allow(passwordResetGlobalKey); !allowed {
+			l.limitedGlobal.Add(1)
+			log.Printf("Email de réinitialisation retenu : plafond global de %d/h atteint", l.globalLimit)
+			if allowed, _ := l.alert.Allow(passwordResetGlobalKey); allowed && bus != nil {
+				bus.Publish(events.TypePasswordResetCapped, map[string]interface{}{
+					"global_limit": l.globalLimit,
+					"ip":           ip,
+				})
+			}
+			return false
+		}
+	}
+
+	l.sent.Add(1)
+	return true
+}
+
+func (l *passwordResetLimits) metrics() PasswordResetMetrics {
+	return PasswordResetMetrics{
+		IPLimit:       l.ipLimit,
+		GlobalLimit:   l.globalLimit,
+		Sent:          l.sent.Load(),
+		LimitedIP:     l.limitedIP.Load(),
+		LimitedGlobal: l.limitedGlobal.Load(),
+		Since:         l.since,
+	}
+}
+
+// @Summary Password Reset Email Metrics
+// @Description Get the password reset emails sent and held back by the per-IP and global hourly caps (PASSWORD_RESET_IP_LIMIT, PASSWORD_RESET_GLOBAL_LIMIT) since the server started (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} PasswordResetMetrics
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/password-resets/metrics [get]
+func (h *AuthHandler) GetPasswordResetMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.resetLimits.metrics())
+}
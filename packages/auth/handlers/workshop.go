@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"auth/models"
+	"core/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// AuthModeWorkshop désactive les emails et permet de générer des comptes par lot, pour les
+	// événements (semaine d'intégration...) où des dizaines de personnes s'inscrivent sans email fiable
+	AuthModeWorkshop = "workshop"
+
+	defaultWorkshopPrefix  = "atelier"
+	workshopPasswordLength = 10
+	// Alphabet sans caractères ambigus (0/o, 1/l/i) : les mots de passe sont recopiés depuis un écran ou une feuille
+	workshopPasswordAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var errWorkshopEmailDisabled = errors.New("Email flows are disabled in workshop mode, ask an organizer to reset the password")
+
+// workshopModeFromEnv lit AUTH_MODE, le mode normal (emails actifs) par défaut
+func workshopModeFromEnv() bool {
+	if strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_MODE"))) != AuthModeWorkshop {
+		return false
+	}
+	log.Println("⚠️  AUTH_MODE=workshop: email flows are disabled and accounts can be generated in batch")
+	return true
+}
+
+// @Summary Batch Generate Users
+// @Description Workshop mode only (AUTH_MODE=workshop): create up to 200 accounts with generated usernames (prefix + number) and passwords, for events where participants cannot rely on email. Accounts get a placeholder address that never receives email and log in with their username. Passwords are only returned in this response (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.BatchGenerateUsersRequest true "Number of accounts, username prefix and campus"
+// @Success 201 {object} models.BatchGenerateUsersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/batch-generate [post]
+func (h *AuthHandler) BatchGenerateUsers(c *gin.Context) {
+	if !h.workshop {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Batch generation is only available in workshop mode"})
+		return
+	}
+
+	var req models.BatchGenerateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	prefix := strings.ToLower(req.Prefix)
+	if prefix == "" {
+		prefix = defaultWorkshopPrefix
+	}
+
+	// Les numéros déjà pris par un précédent lot du même préfixe sont sautés
+	var taken []string
+	if err := h.DB.Model(&models.User{}).Where("username LIKE ?", prefix+"%").Pluck("username", &taken).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read existing usernames"})
+		return
+	}
+	takenSet := make(map[string]bool, len(taken))
+	for _, username := range taken {
+		takenSet[strings.ToLower(username)] = true
+	}
+
+	credentials := make([]models.GeneratedCredential, 0, req.Count)
+	for number := 1; len(credentials) < req.Count; number++ {
+		username := fmt.Sprintf("%s%03d", prefix, number)
+		if takenSet[username] {
+			continue
+		}
+
+		password, err := generateWorkshopPassword()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate password"})
+			return
+		}
+
+		user, err := h.CreateUserAndPlayerWithTx(models.RegisterRequest{
+			Email:      username + "@" + models.WorkshopEmailDomain,
+			Username:   username,
+			Password:   password,
+			Campus:     req.Campus,
+			Department: req.Department,
+		})
+		if err != nil {
+			// Les comptes déjà créés restent valides : leurs identifiants ne doivent pas être perdus
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":       fmt.Sprintf("Failed to create user %s", username),
+				"credentials": credentials,
+			})
+			return
+		}
+
+		credentials = append(credentials, models.GeneratedCredential{UserID: user.ID, Username: username, Password: password})
+	}
+
+	h.AuditService.Log(adminID.(uint), models.AuditActionUsersGenerated, models.AuditTargetUser, credentials[0].UserID, models.AuditDetails{
+		"count":  len(credentials),
+		"first":  credentials[0].Username,
+		"last":   credentials[len(credentials)-1].Username,
+		"campus": req.Campus,
+	}, c.ClientIP())
+
+	c.JSON(http.StatusCreated, models.BatchGenerateUsersResponse{Count: len(credentials), Credentials: credentials})
+}
+
+// generateWorkshopPassword génère un mot de passe facile à recopier
+func generateWorkshopPassword() (string, error) {
+	max := big.NewInt(int64(len(workshopPasswordAlphabet)))
+	password := make([]byte, workshopPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = workshopPasswordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
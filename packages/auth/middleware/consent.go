@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"auth/models"
+	"auth/services"
+	"auth/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConsentRequiredHeader signale au front un consentement facultatif encore sans réponse pour la version en vigueur
+const ConsentRequiredHeader = "X-Consent-Required"
+
+// consentExemptPrefixes sont les routes utilisables avant d'avoir accepté les conditions :
+// authentification, consentements eux-mêmes et routes techniques
+var consentExemptPrefixes = []string{
+	"/auth/",
+	"/consents",
+	"/health",
+	"/readyz",
+	"/client-config",
+	"/openapi.json",
+	"/swagger",
+}
+
+// RequireConsent répond 428 Precondition Required aux membres connectés qui n'ont pas accepté la version
+// en vigueur des conditions d'utilisation, jusqu'à ce qu'ils l'acceptent via POST /consents. Les requêtes
+// sans jeton valide passent : les routes protégées les refusent ensuite avec 401.
+func RequireConsent(consentService *services.ConsentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if consentService.CurrentVersion(models.ConsentKindTerms) == "" || consentExempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+		claims, err := utils.ValidateToken(parts[1])
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		accepted, err := consentService.TermsAccepted(claims.UserID)
+		if err != nil {
+			// Une base indisponible ne doit pas bloquer tout le monde, la route échouera d'elle-même
+			log.Printf("Consent check failed for user %d: %v", claims.UserID, err)
+			c.Next()
+			return
+		}
+		if !accepted {
+			c.AbortWithStatusJSON(http.StatusPreconditionRequired, models.ConsentRequiredResponse{
+				Error:        "Terms must be accepted",
+				TermsVersion: consentService.CurrentVersion(models.ConsentKindTerms),
+			})
+			return
+		}
+
+		if answered, err := consentService.PhotoAnswered(claims.UserID); err == nil && !answered {
+			c.Header(ConsentRequiredHeader, models.ConsentKindPhoto)
+		}
+		c.Next()
+	}
+}
+
+func consentExempt(path string) bool {
+	for _, prefix := range consentExemptPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Actions enregistrées dans le journal d'audit
+const (
+	AuditActionResendVerificationEmail = "user.resend_verification_email"
+	AuditActionResendResetEmail        = "user.resend_reset_email"
+	AuditActionEmailSuppressed         = "user.email_suppressed"
+	AuditActionEmailStatusChanged      = "user.email_status_changed"
+	AuditActionUserDisabled            = "user.disabled"
+	AuditActionRoleAdded               = "user.role_added"
+	AuditActionUsersGenerated          = "user.batch_generated"
+	AuditActionRatingOverrideCreated   = "player.rating_override_created"
+	AuditActionRatingOverrideRevoked   = "player.rating_override_revoked"
+	AuditActionRatingReset             = "player.rating_reset"
+	AuditActionPlayerArchived          = "player.archived"
+	AuditActionPlayerUnarchived        = "player.unarchived"
+)
+
+// Cibles possibles d'une entrée d'audit
+const (
+	AuditTargetUser   = "user"
+	AuditTargetPlayer = "player"
+)
+
+type AuditDetails map[string]interface{}
+
+// Implémente l'interface driver.Valuer pour GORM
+func (d AuditDetails) Value() (driver.Value, error) {
+	if d == nil {
+		return "{}", nil
+	}
+	bytes, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// Implémente l'interface sql.Scanner pour GORM
+func (d *AuditDetails) Scan(value interface{}) error {
+	if value == nil {
+		*d = AuditDetails{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// AuditLog trace une action sensible effectuée par un utilisateur (généralement un admin)
+type AuditLog struct {
+	ID         uint         `json:"id" gorm:"primaryKey"`
+	ActorID    *uint        `json:"actor_id" gorm:"index"`
+	Action     string       `json:"action" gorm:"not null;index"`
+	TargetType string       `json:"target_type" gorm:"not null"`
+	TargetID   uint         `json:"target_id" gorm:"not null"`
+	Details    AuditDetails `json:"details" gorm:"type:jsonb"`
+	IPAddress  string       `json:"ip_address"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Types de consentement enregistrés
+const (
+	ConsentKindTerms = "terms"             // Conditions d'utilisation, obligatoires
+	ConsentKindPhoto = "photo_publication" // Publication des photos de tournoi où le membre apparaît, facultative
+)
+
+// Consent trace un consentement donné ou refusé par un membre. La table n'est jamais modifiée :
+// chaque réponse ajoute une ligne, la dernière par type fait foi et l'historique sert de preuve.
+type Consent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Kind      string    `json:"kind" gorm:"not null"`
+	Version   string    `json:"version" gorm:"not null"`
+	Granted   bool      `json:"granted"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (Consent) TableName() string {
+	return "consents"
+}
+
+// ConsentState est la situation d'un membre pour un type de consentement
+type ConsentState struct {
+	Kind           string     `json:"kind" example:"terms"`
+	CurrentVersion string     `json:"current_version" example:"2026-09"` // Version en vigueur, vide si non configurée
+	Version        *string    `json:"version"`                           // Dernière version à laquelle le membre a répondu
+	Granted        *bool      `json:"granted"`
+	GivenAt        *time.Time `json:"given_at"`
+	UpToDate       bool       `json:"up_to_date"` // Réponse donnée pour la version en vigueur
+}
+
+type ConsentStatusResponse struct {
+	Terms ConsentState `json:"terms"`
+	Photo ConsentState `json:"photo_publication"`
+}
+
+type RecordConsentRequest struct {
+	Kind    string `json:"kind" binding:"required,oneof=terms photo_publication"`
+	Version string `json:"version" binding:"required,max=50"`
+	Granted *bool  `json:"granted" binding:"required"`
+}
+
+// ConsentRequiredResponse est renvoyée avec 428 tant que les conditions en vigueur ne sont pas acceptées
+type ConsentRequiredResponse struct {
+	Error        string `json:"error" example:"Terms must be accepted"`
+	TermsVersion string `json:"terms_version" example:"2026-09"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Templates d'emails envoyés par l'application
+const (
+	EmailTemplatePasswordReset      = "password_reset"
+	EmailTemplateVerification       = "verification"
+	EmailTemplateNotificationDigest = "notification_digest"
+	EmailTemplateDataQualityReport  = "data_quality_report"
+)
+
+// Statuts d'un envoi dans le journal des emails
+const (
+	EmailLogStatusSent       = "sent"
+	EmailLogStatusFailed     = "failed"
+	EmailLogStatusSuppressed = "suppressed"
+)
+
+// EmailLog trace chaque email sortant pour pouvoir investiguer les problèmes de délivrabilité
+type EmailLog struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	UserID           *uint     `json:"user_id" gorm:"index"`
+	Recipient        string    `json:"recipient" gorm:"not null;index"`
+	Template         string    `json:"template" gorm:"not null"`
+	Subject          string    `json:"subject"`
+	Provider         string    `json:"provider" gorm:"not null"`
+	Status           string    `json:"status" gorm:"not null;index"`
+	ProviderResponse *string   `json:"provider_response"`
+	Error            *string   `json:"error"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TableName spécifie le nom de la table
+func (EmailLog) TableName() string {
+	return "email_logs"
+}
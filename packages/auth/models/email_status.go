@@ -0,0 +1,48 @@
+package models
+
+import "strings"
+
+// Statuts de délivrabilité d'une adresse email
+const (
+	EmailStatusActive     = "active"
+	EmailStatusBounced    = "bounced"
+	EmailStatusComplained = "complained"
+)
+
+// Types d'événements reçus par le webhook de délivrabilité
+const (
+	EmailEventBounce    = "bounce"
+	EmailEventComplaint = "complaint"
+)
+
+// Types de bounce : seuls les bounces définitifs bloquent l'adresse
+const (
+	BounceTypeHard = "hard"
+	BounceTypeSoft = "soft"
+)
+
+// WorkshopEmailDomain est le domaine des adresses fictives des comptes générés en mode atelier.
+// Le TLD .invalid est réservé : aucun email ne peut y être délivré.
+const WorkshopEmailDomain = "workshop.invalid"
+
+// IsWorkshopEmail vérifie si l'adresse est celle d'un compte généré en mode atelier
+func IsWorkshopEmail(email string) bool {
+	return strings.HasSuffix(strings.ToLower(email), "@"+WorkshopEmailDomain)
+}
+
+// IsSuppressedEmailStatus vérifie si un statut bloque les envois
+func IsSuppressedEmailStatus(status string) bool {
+	return status == EmailStatusBounced || status == EmailStatusComplained
+}
+
+type EmailEventRequest struct {
+	Email      string `json:"email" binding:"required,email"`
+	Type       string `json:"type" binding:"required,oneof=bounce complaint"`
+	BounceType string `json:"bounce_type" binding:"omitempty,oneof=hard soft"`
+	Reason     string `json:"reason"`
+}
+
+type EmailEventResponse struct {
+	Success     bool   `json:"success"`
+	EmailStatus string `json:"email_status"`
+}
@@ -24,7 +24,12 @@ func (RefreshToken) TableName() string {
 
 // IsExpired vérifie si le token est expiré
 func (rt *RefreshToken) IsExpired() bool {
-	return time.Now().After(rt.ExpiresAt)
+	return rt.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt vérifie si le token est expiré à l'heure donnée
+func (rt *RefreshToken) IsExpiredAt(now time.Time) bool {
+	return now.After(rt.ExpiresAt)
 }
 
 // RefreshTokenRequest représente une requête de refresh
@@ -5,6 +5,7 @@ const (
 	RoleUser       = "user"
 	RoleAdmin      = "admin"
 	RoleSuperAdmin = "superAdmin"
+	RoleReferee    = "referee"
 )
 
 // GetDefaultRoles retourne les rôles par défaut pour un nouvel utilisateur
@@ -18,6 +19,7 @@ func GetAllRoles() []string {
 		RoleUser,
 		RoleAdmin,
 		RoleSuperAdmin,
+		RoleReferee,
 	}
 }
 
@@ -35,20 +35,25 @@ func (r *Roles) Scan(value interface{}) error {
 }
 
 type User struct {
-	ID                  uint           `json:"id" gorm:"primaryKey"`
-	Email               string         `json:"email" gorm:"uniqueIndex;not null"`
-	Username            string         `json:"username" gorm:"uniqueIndex"`
-	Password            string         `json:"-" gorm:"not null"`
-	Slug                string         `json:"slug" gorm:"uniqueIndex"`
-	Enabled             bool           `json:"enabled" gorm:"default:true"`
-	Roles               Roles          `json:"roles" gorm:"type:jsonb;default:'[\"user\"]'::jsonb"`
-	LastLogin           *time.Time     `json:"last_login"`
-	NbConnexion         int            `json:"nb_connexion" gorm:"default:0"`
-	ConfirmationToken   *string        `json:"-"`
-	PasswordRequestedAt *time.Time     `json:"-"`
-	CreatedAt           time.Time      `json:"created_at"`
-	UpdatedAt           time.Time      `json:"updated_at"`
-	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                     uint           `json:"id" gorm:"primaryKey"`
+	Email                  string         `json:"email" gorm:"uniqueIndex;not null"`
+	Username               string         `json:"username" gorm:"uniqueIndex"`
+	Password               string         `json:"-" gorm:"not null"`
+	Slug                   string         `json:"slug" gorm:"uniqueIndex"`
+	Enabled                bool           `json:"enabled" gorm:"default:true"`
+	Roles                  Roles          `json:"roles" gorm:"type:jsonb;default:'[\"user\"]'::jsonb"`
+	LastLogin              *time.Time     `json:"last_login"`
+	NbConnexion            int            `json:"nb_connexion" gorm:"default:0"`
+	ConfirmationToken      *string        `json:"-"`
+	PasswordRequestedAt    *time.Time     `json:"-"`
+	EmailVerifiedAt        *time.Time     `json:"email_verified_at"`
+	EmailVerificationToken *string        `json:"-"`
+	EmailStatus            string         `json:"email_status" gorm:"default:active"`
+	EmailStatusReason      *string        `json:"email_status_reason"`
+	EmailStatusUpdatedAt   *time.Time     `json:"email_status_updated_at"`
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+	DeletedAt              gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName spécifie le nom de la table au pluriel
@@ -91,8 +96,21 @@ func (u *User) IsPasswordRequestExpired(ttlSeconds int) bool {
 	return time.Since(*u.PasswordRequestedAt).Seconds() > float64(ttlSeconds)
 }
 
+// IsEmailVerified vérifie si l'utilisateur a confirmé son adresse email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsEmailSuppressed vérifie si les envois vers l'adresse de l'utilisateur sont bloqués (bounce, plainte
+// ou adresse fictive d'un compte d'atelier)
+func (u *User) IsEmailSuppressed() bool {
+	return IsSuppressedEmailStatus(u.EmailStatus) || IsWorkshopEmail(u.Email)
+}
+
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
+	Email string `json:"email" binding:"required_without=Username,omitempty,email"`
+	// Username remplace l'email, uniquement en mode atelier (AUTH_MODE=workshop)
+	Username string `json:"username"`
 	Password string `json:"password" binding:"required"`
 }
 
@@ -100,6 +118,15 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required,min=6"`
+	// VerifyCallBackUrl optionnel : si fourni, un email de vérification est envoyé ([token] est remplacé)
+	VerifyCallBackUrl string `json:"verifyCallBackUrl"`
+	// Campus et département optionnels, modifiables ensuite via PUT /players/{id}/campus
+	Campus     *string `json:"campus" binding:"omitempty,max=50"`
+	Department *string `json:"department" binding:"omitempty,max=100"`
+	// Version des conditions d'utilisation acceptée, obligatoire si TERMS_VERSION est configurée
+	TermsVersion string `json:"terms_version" binding:"omitempty,max=50"`
+	// Réponse facultative à la publication des photos de tournoi, pour la version PHOTO_CONSENT_VERSION
+	PhotoConsent *bool `json:"photo_consent"`
 }
 
 type AuthResponse struct {
@@ -125,6 +152,86 @@ type PasswordResetConfirmResponse struct {
 	Success bool `json:"success"`
 }
 
+type EmailVerificationConfirmRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type EmailVerificationConfirmResponse struct {
+	Success bool `json:"success"`
+}
+
+// Types d'emails pouvant être renvoyés par un admin
+const (
+	ResendEmailTypeVerification = "verification"
+	ResendEmailTypeReset        = "reset"
+)
+
+type ResendEmailRequest struct {
+	CallBackUrl string `json:"callBackUrl" binding:"required"`
+}
+
+type ResendEmailResponse struct {
+	Success bool   `json:"success"`
+	Type    string `json:"type"`
+}
+
+// Actions disponibles pour les opérations groupées sur les utilisateurs
+const (
+	BulkUserActionDisable   = "disable"
+	BulkUserActionAddRole   = "add_role"
+	BulkUserActionSendEmail = "send_email"
+)
+
+// Statuts du rapport par utilisateur d'une opération groupée
+const (
+	BulkUserStatusOK      = "ok"
+	BulkUserStatusSkipped = "skipped"
+	BulkUserStatusFailed  = "failed"
+)
+
+type BulkUserRequest struct {
+	UserIDs     []uint `json:"user_ids" binding:"required,min=1,max=500"`
+	Action      string `json:"action" binding:"required,oneof=disable add_role send_email"`
+	Role        string `json:"role,omitempty"`        // requis pour add_role
+	EmailType   string `json:"email_type,omitempty"`  // requis pour send_email : verification ou reset
+	CallBackUrl string `json:"callBackUrl,omitempty"` // requis pour send_email
+}
+
+type BulkUserResult struct {
+	UserID uint   `json:"user_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkUserResponse struct {
+	Action    string           `json:"action"`
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Skipped   int              `json:"skipped"`
+	Failed    int              `json:"failed"`
+	Results   []BulkUserResult `json:"results"`
+}
+
+type BatchGenerateUsersRequest struct {
+	Count int `json:"count" binding:"required,min=1,max=200"`
+	// Préfixe des identifiants générés (atelier par défaut), suivi d'un numéro : atelier001, atelier002...
+	Prefix     string  `json:"prefix" binding:"omitempty,max=20,alphanum"`
+	Campus     *string `json:"campus" binding:"omitempty,max=50"`
+	Department *string `json:"department" binding:"omitempty,max=100"`
+}
+
+// GeneratedCredential contient un mot de passe en clair, il n'est renvoyé qu'une fois
+type GeneratedCredential struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type BatchGenerateUsersResponse struct {
+	Count       int                   `json:"count"`
+	Credentials []GeneratedCredential `json:"credentials"`
+}
+
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"currentPassword" binding:"required"`
 	NewPassword     string `json:"newPassword" binding:"required,min=6"`
@@ -140,9 +247,10 @@ type UpdateUserRequest struct {
 }
 
 type PatchUserRequest struct {
-	Email   *string `json:"email,omitempty" binding:"omitempty,email"`
-	Roles   *Roles  `json:"roles,omitempty"`
-	Enabled *bool   `json:"enabled,omitempty"`
+	Email       *string `json:"email,omitempty" binding:"omitempty,email"`
+	Roles       *Roles  `json:"roles,omitempty"`
+	Enabled     *bool   `json:"enabled,omitempty"`
+	EmailStatus *string `json:"email_status,omitempty" binding:"omitempty,oneof=active bounced complained"`
 }
 
 type UpdateUserResponse struct {
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"log"
+
+	"auth/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditService enregistre les actions sensibles dans la table audit_logs
+type AuditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService crée une nouvelle instance du service d'audit
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// Log enregistre une action. Une erreur d'écriture est loggée mais ne fait pas échouer l'action auditée.
+func (s *AuditService) Log(actorID uint, action, targetType string, targetID uint, details models.AuditDetails, ipAddress string) {
+	entry := models.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+		IPAddress:  ipAddress,
+	}
+	if actorID != 0 {
+		entry.ActorID = &actorID
+	}
+
+	if err := s.db.Create(&entry).Error; err != nil {
+		log.Printf("Warning: Failed to write audit log %s for %s %d: %v", action, targetType, targetID, err)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"auth/models"
+
+	"gorm.io/gorm"
+)
+
+// ConsentService enregistre les consentements (conditions d'utilisation, publication des photos) et
+// indique si un membre doit répondre à nouveau après un changement de version. Les versions en vigueur
+// sont lues dans TERMS_VERSION et PHOTO_CONSENT_VERSION ; sans TERMS_VERSION, rien n'est exigé.
+type ConsentService struct {
+	db           *gorm.DB
+	termsVersion string
+	photoVersion string
+
+	// Membres ayant accepté les conditions ou répondu pour les photos dans la version en vigueur, pour ne pas
+	// interroger la base à chaque requête. Seules les réponses à jour sont mises en cache : les versions ne
+	// changent qu'au redémarrage.
+	accepted      sync.Map
+	photoAnswered sync.Map
+}
+
+// NewConsentService crée le service avec les versions de la configuration
+func NewConsentService(db *gorm.DB) *ConsentService {
+	return &ConsentService{
+		db:           db,
+		termsVersion: strings.TrimSpace(os.Getenv("TERMS_VERSION")),
+		photoVersion: strings.TrimSpace(os.Getenv("PHOTO_CONSENT_VERSION")),
+	}
+}
+
+// CurrentVersion retourne la version en vigueur d'un type de consentement, vide si non configurée
+func (s *ConsentService) CurrentVersion(kind string) string {
+	if kind == models.ConsentKindPhoto {
+		return s.photoVersion
+	}
+	return s.termsVersion
+}
+
+// Record enregistre une réponse pour la version en vigueur. Les conditions ne peuvent qu'être acceptées.
+func (s *ConsentService) Record(userID uint, kind, version string, granted bool, ipAddress, userAgent string) (*models.Consent, error) {
+	current := s.CurrentVersion(kind)
+	if current == "" {
+		return nil, errors.New("no consent version configured")
+	}
+	if version != current {
+		return nil, errors.New("consent version is not current")
+	}
+	if kind == models.ConsentKindTerms && !granted {
+		return nil, errors.New("terms must be accepted")
+	}
+
+	consent := &models.Consent{
+		UserID:    userID,
+		Kind:      kind,
+		Version:   version,
+		Granted:   granted,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	}
+	if err := s.db.Create(consent).Error; err != nil {
+		return nil, err
+	}
+
+	if kind == models.ConsentKindTerms {
+		s.accepted.Store(userID, true)
+	} else {
+		s.photoAnswered.Store(userID, true)
+	}
+	return consent, nil
+}
+
+// latest retourne la dernière réponse d'un membre pour un type de consentement, nil s'il n'a jamais répondu
+func (s *ConsentService) latest(userID uint, kind string) (*models.Consent, error) {
+	var consent models.Consent
+	if err := s.db.Where("user_id = ? AND kind = ?", userID, kind).Order("created_at DESC, id DESC").First(&consent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// state décrit la situation d'un membre pour un type de consentement
+func (s *ConsentService) state(userID uint, kind string) (models.ConsentState, error) {
+	state := models.ConsentState{Kind: kind, CurrentVersion: s.CurrentVersion(kind)}
+
+	consent, err := s.latest(userID, kind)
+	if err != nil {
+		return state, err
+	}
+	if consent != nil {
+		state.Version = &consent.Version
+		state.Granted = &consent.Granted
+		state.GivenAt = &consent.CreatedAt
+	}
+	state.UpToDate = state.CurrentVersion == "" || (consent != nil && consent.Version == state.CurrentVersion)
+	return state, nil
+}
+
+// Status retourne la situation d'un membre pour chaque type de consentement
+func (s *ConsentService) Status(userID uint) (*models.ConsentStatusResponse, error) {
+	terms, err := s.state(userID, models.ConsentKindTerms)
+	if err != nil {
+		return nil, err
+	}
+	photo, err := s.state(userID, models.ConsentKindPhoto)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ConsentStatusResponse{Terms: terms, Photo: photo}, nil
+}
+
+// TermsAccepted vérifie que le membre a accepté les conditions en vigueur (toujours vrai sans TERMS_VERSION)
+func (s *ConsentService) TermsAccepted(userID uint) (bool, error) {
+	if s.termsVersion == "" {
+		return true, nil
+	}
+	if _, ok := s.accepted.Load(userID); ok {
+		return true, nil
+	}
+
+	consent, err := s.latest(userID, models.ConsentKindTerms)
+	if err != nil {
+		return false, err
+	}
+	if consent == nil || consent.Version != s.termsVersion || !consent.Granted {
+		return false, nil
+	}
+	s.accepted.Store(userID, true)
+	return true, nil
+}
+
+// PhotoAnswered vérifie que le membre a répondu, oui ou non, à la version en vigueur de la publication des photos
+func (s *ConsentService) PhotoAnswered(userID uint) (bool, error) {
+	if s.photoVersion == "" {
+		return true, nil
+	}
+	if _, ok := s.photoAnswered.Load(userID); ok {
+		return true, nil
+	}
+
+	consent, err := s.latest(userID, models.ConsentKindPhoto)
+	if err != nil {
+		return false, err
+	}
+	if consent == nil || consent.Version != s.photoVersion {
+		return false, nil
+	}
+	s.photoAnswered.Store(userID, true)
+	return true, nil
+}
+
+// ConsentExportRow est une ligne de l'export des consentements
+type ConsentExportRow struct {
+	models.Consent
+	Email    string
+	Username string
+}
+
+// Export retourne les consentements d'un type (tous si vide) avec le membre, du plus ancien au plus récent.
+// Avec latestOnly, seule la dernière réponse de chaque membre est gardée.
+func (s *ConsentService) Export(kind string, latestOnly bool, since *time.Time) ([]ConsentExportRow, error) {
+	query := s.db.Table("consents").
+		Select("consents.*, users.email, users.username").
+		Joins("JOIN users ON users.id = consents.user_id")
+	if kind != "" {
+		query = query.Where("consents.kind = ?", kind)
+	}
+	if since != nil {
+		query = query.Where("consents.created_at >= ?", *since)
+	}
+	if latestOnly {
+		query = query.Where(`consents.id = (
+			SELECT latest.id FROM consents AS latest
+			WHERE latest.user_id = consents.user_id AND latest.kind = consents.kind
+			ORDER BY latest.created_at DESC, latest.id DESC LIMIT 1)`)
+	}
+
+	var rows []ConsentExportRow
+	if err := query.Order("consents.created_at ASC, consents.id ASC").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
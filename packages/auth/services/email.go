@@ -1,32 +1,48 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
-	"strconv"
+	"strings"
 
-	"github.com/go-mail/mail/v2"
+	"auth/models"
+
+	"gorm.io/gorm"
 )
 
 // EmailService interface pour l'envoi d'emails
 type EmailService interface {
 	SendPasswordResetEmail(to, resetURL string) error
+	SendVerificationEmail(to, verifyURL string) error
+	SendNotificationDigest(to string, lines []string) error
+	SendDataQualityReport(to string, lines []string) error
 }
 
-// LogEmailService implémentation qui log les emails (pour développement)
-type LogEmailService struct{}
+// ErrEmailSuppressed est retourné lorsqu'un envoi est bloqué car l'adresse a bouncé ou s'est plainte
+var ErrEmailSuppressed = errors.New("email address is suppressed")
+
+// Message est un email prêt à être envoyé, indépendant du provider
+type Message struct {
+	Template string
+	Subject  string
+	Body     string
+}
 
-// NewLogEmailService crée une nouvelle instance du service email de développement
-func NewLogEmailService() *LogEmailService {
-	return &LogEmailService{}
+// Transport envoie un message via un provider (SMTP, API HTTP, log...).
+// La réponse du provider est conservée dans le journal des envois.
+type Transport interface {
+	Name() string
+	Send(to string, message Message) (string, error)
 }
 
-// SendPasswordResetEmail envoie un email de reset de mot de passe (version dev qui log)
-func (s *LogEmailService) SendPasswordResetEmail(to, resetURL string) error {
-	subject := "Réinitialisation de votre mot de passe"
-	body := fmt.Sprintf(`Bonjour,
+// passwordResetMessage construit l'email de reset de mot de passe
+func passwordResetMessage(resetURL string) Message {
+	return Message{
+		Template: models.EmailTemplatePasswordReset,
+		Subject:  "Réinitialisation de votre mot de passe",
+		Body: fmt.Sprintf(`Bonjour,
 
 Vous avez demandé la réinitialisation de votre mot de passe.
 Cliquez sur le lien suivant pour créer un nouveau mot de passe :
@@ -38,115 +54,190 @@ Ce lien est valide pendant 2 heures.
 Si vous n'avez pas fait cette demande, ignorez ce message.
 
 Cordialement,
-L'équipe`, resetURL)
-
-	log.Printf("=== EMAIL SENT ===")
-	log.Printf("To: %s", to)
-	log.Printf("Subject: %s", subject)
-	log.Printf("Body: %s", body)
-	log.Printf("=================")
-	return nil
+L'équipe`, resetURL),
+	}
 }
 
-// SMTPEmailService pour l'envoi réel d'emails via SMTP
-type SMTPEmailService struct {
-	host     string
-	port     int
-	username string
-	password string
-	from     string
-}
+// verificationMessage construit l'email de vérification d'adresse
+func verificationMessage(verifyURL string) Message {
+	return Message{
+		Template: models.EmailTemplateVerification,
+		Subject:  "Confirmez votre adresse email",
+		Body: fmt.Sprintf(`Bonjour,
 
-// NewSMTPEmailService crée une nouvelle instance du service email SMTP
-func NewSMTPEmailService() (*SMTPEmailService, error) {
-	mailDSN := os.Getenv("MAIL_DSN")
-	if mailDSN == "" {
-		return nil, fmt.Errorf("MAIL_DSN environment variable is required")
-	}
+Merci de confirmer votre adresse email en cliquant sur le lien suivant :
 
-	u, err := url.Parse(mailDSN)
-	if err != nil {
-		return nil, fmt.Errorf("invalid MAIL_DSN format: %v", err)
+%s
+
+Si vous n'avez pas créé de compte, ignorez ce message.
+
+Cordialement,
+L'équipe`, verifyURL),
 	}
+}
 
-	port := 25 // Port par défaut
-	if u.Port() != "" {
-		port, err = strconv.Atoi(u.Port())
-		if err != nil {
-			return nil, fmt.Errorf("invalid port in MAIL_DSN: %v", err)
-		}
+// notificationDigestMessage construit l'email regroupant les notifications en attente d'un membre
+func notificationDigestMessage(lines []string) Message {
+	subject := lines[0]
+	if len(lines) > 1 {
+		subject = fmt.Sprintf("Vous avez %d nouvelles notifications", len(lines))
 	}
 
-	username := ""
-	password := ""
-	if u.User != nil {
-		username = u.User.Username()
-		password, _ = u.User.Password()
+	return Message{
+		Template: models.EmailTemplateNotificationDigest,
+		Subject:  subject,
+		Body: fmt.Sprintf(`Bonjour,
+
+%s
+
+Cordialement,
+L'équipe`, "- "+strings.Join(lines, "\n- ")),
 	}
+}
 
-	from := "noreply@example.com" // Email par défaut
-	if envSender := os.Getenv("MAILER_ENVELOPE_SENDER"); envSender != "" {
-		from = envSender
-	} else if username != "" {
-		from = username
+// dataQualityReportMessage construit le rapport hebdomadaire de qualité des données envoyé aux admins
+func dataQualityReportMessage(lines []string) Message {
+	return Message{
+		Template: models.EmailTemplateDataQualityReport,
+		Subject:  "Rapport hebdomadaire de qualité des données",
+		Body: fmt.Sprintf(`Bonjour,
+
+Les contrôles d'intégrité de la semaine ont relevé les anomalies suivantes :
+
+%s
+
+Le détail est disponible sur GET /admin/data-quality.
+
+Cordialement,
+L'équipe`, "- "+strings.Join(lines, "\n- ")),
 	}
+}
 
-	return &SMTPEmailService{
-		host:     u.Hostname(),
-		port:     port,
-		username: username,
-		password: password,
-		from:     from,
-	}, nil
+// Mailer implémente EmailService : construit le message, applique la liste de suppression,
+// l'envoie via le transport configuré et trace l'envoi dans email_logs
+type Mailer struct {
+	transport Transport
+	db        *gorm.DB
 }
 
-// SendPasswordResetEmail envoie un email de reset de mot de passe via SMTP
-func (s *SMTPEmailService) SendPasswordResetEmail(to, resetURL string) error {
-	subject := "Réinitialisation de votre mot de passe"
-	body := fmt.Sprintf(`Bonjour,
+// NewMailer crée un mailer pour un transport donné
+func NewMailer(transport Transport, db *gorm.DB) *Mailer {
+	return &Mailer{transport: transport, db: db}
+}
 
-Vous avez demandé la réinitialisation de votre mot de passe.
-Cliquez sur le lien suivant pour créer un nouveau mot de passe :
+// SendPasswordResetEmail envoie un email de reset de mot de passe
+func (m *Mailer) SendPasswordResetEmail(to, resetURL string) error {
+	return m.send(to, passwordResetMessage(resetURL))
+}
 
-%s
+// SendVerificationEmail envoie un email de vérification d'adresse
+func (m *Mailer) SendVerificationEmail(to, verifyURL string) error {
+	return m.send(to, verificationMessage(verifyURL))
+}
 
-Ce lien est valide pendant 2 heures.
+// SendNotificationDigest envoie un email regroupant plusieurs notifications
+func (m *Mailer) SendNotificationDigest(to string, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	return m.send(to, notificationDigestMessage(lines))
+}
 
-Si vous n'avez pas fait cette demande, ignorez ce message.
+// SendDataQualityReport envoie le rapport de qualité des données à un admin
+func (m *Mailer) SendDataQualityReport(to string, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	return m.send(to, dataQualityReportMessage(lines))
+}
 
-Cordialement,
-L'équipe`, resetURL)
+func (m *Mailer) send(to string, message Message) error {
+	entry := models.EmailLog{
+		Recipient: to,
+		Template:  message.Template,
+		Subject:   message.Subject,
+		Provider:  m.transport.Name(),
+		Status:    models.EmailLogStatusSent,
+	}
 
-	m := mail.NewMessage()
-	m.SetHeader("From", s.from)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/plain", body)
+	var user models.User
+	if err := m.db.Select("id", "email_status").Where("email = ?", to).First(&user).Error; err == nil {
+		entry.UserID = &user.ID
+	}
 
-	d := mail.NewDialer(s.host, s.port, s.username, s.password)
+	var err error
+	if user.IsEmailSuppressed() || models.IsWorkshopEmail(to) {
+		log.Printf("Email to %s suppressed (status: %s)", to, user.EmailStatus)
+		entry.Status = models.EmailLogStatusSuppressed
+		err = ErrEmailSuppressed
+	} else {
+		var response string
+		response, err = m.transport.Send(to, message)
+		if response != "" {
+			entry.ProviderResponse = &response
+		}
+		if err != nil {
+			log.Printf("Error sending email to %s via %s: %v", to, m.transport.Name(), err)
+			entry.Status = models.EmailLogStatusFailed
+		}
+	}
 
-	// Pour les serveurs locaux comme Mailpit, désactiver TLS
-	if s.host == "localhost" || s.host == "127.0.0.1" {
-		d.TLSConfig = nil
+	if err != nil {
+		errMessage := err.Error()
+		entry.Error = &errMessage
 	}
 
-	if err := d.DialAndSend(m); err != nil {
-		log.Printf("Error sending email: %v", err)
-		return err
+	// Le journal ne doit jamais bloquer l'envoi d'un email
+	if logErr := m.db.Create(&entry).Error; logErr != nil {
+		log.Printf("Warning: Failed to write email log for %s: %v", to, logErr)
+	}
+
+	return err
+}
+
+// NewTransport crée le transport selon MAIL_PROVIDER (smtp, sendgrid, mailgun, log).
+// Sans MAIL_PROVIDER, SMTP est utilisé si MAIL_DSN est configuré, sinon les emails sont loggés.
+func NewTransport() (Transport, error) {
+	provider := strings.ToLower(os.Getenv("MAIL_PROVIDER"))
+	if provider == "" {
+		if os.Getenv("MAIL_DSN") == "" {
+			log.Println("MAIL_DSN not configured, using log email service")
+			return NewLogTransport(), nil
+		}
+		provider = "smtp"
 	}
 
-	log.Printf("Email sent successfully to %s via SMTP (%s:%d)", to, s.host, s.port)
-	return nil
+	switch provider {
+	case "smtp":
+		return NewSMTPTransport()
+	case "sendgrid":
+		return NewSendGridTransport()
+	case "mailgun":
+		return NewMailgunTransport()
+	case "log":
+		return NewLogTransport(), nil
+	}
+	return nil, fmt.Errorf("unknown MAIL_PROVIDER: %s", provider)
 }
 
 // NewEmailService crée le service email approprié selon la configuration
-func NewEmailService() EmailService {
-	// Essayer d'abord le service SMTP
-	if smtpService, err := NewSMTPEmailService(); err == nil {
-		return smtpService
+func NewEmailService(db *gorm.DB) EmailService {
+	transport, err := NewTransport()
+	if err != nil {
+		// Fallback vers le service de log pour ne pas empêcher le démarrage
+		log.Printf("Invalid mail configuration (%v), using log email service", err)
+		transport = NewLogTransport()
 	}
+	return NewMailer(transport, db)
+}
 
-	// Fallback vers le service de log pour le développement
-	log.Println("MAIL_DSN not configured, using log email service")
-	return NewLogEmailService()
+// mailFrom retourne l'adresse d'expédition configurée
+func mailFrom(fallback string) string {
+	if envSender := os.Getenv("MAILER_ENVELOPE_SENDER"); envSender != "" {
+		return envSender
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return "noreply@example.com" // Email par défaut
 }
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Taille maximale de la réponse du provider conservée dans le journal des envois
+const maxProviderResponseSize = 2048
+
+var mailHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// readProviderResponse lit la réponse HTTP du provider et retourne une erreur si le statut n'est pas 2xx
+func readProviderResponse(resp *http.Response) (string, error) {
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxProviderResponseSize))
+	response := strings.TrimSpace(fmt.Sprintf("%s %s", resp.Status, body))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("provider returned %s", resp.Status)
+	}
+	return response, nil
+}
+
+// SendGridTransport envoie les emails via l'API HTTP v3 de SendGrid
+type SendGridTransport struct {
+	apiKey string
+	from   string
+}
+
+// NewSendGridTransport crée un transport SendGrid à partir de SENDGRID_API_KEY
+func NewSendGridTransport() (*SendGridTransport, error) {
+	apiKey := os.Getenv("SENDGRID_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("SENDGRID_API_KEY environment variable is required")
+	}
+	return &SendGridTransport{apiKey: apiKey, from: mailFrom("")}, nil
+}
+
+func (t *SendGridTransport) Name() string {
+	return "sendgrid"
+}
+
+func (t *SendGridTransport) Send(to string, message Message) (string, error) {
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": to}}},
+		},
+		"from":    map[string]string{"email": t.from},
+		"subject": message.Subject,
+		"content": []map[string]string{
+			{"type": "text/plain", "value": message.Body},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.sendgrid.com/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := mailHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := readProviderResponse(resp)
+	// SendGrid ne renvoie pas de corps en cas de succès, seulement l'identifiant du message
+	if messageID := resp.Header.Get("X-Message-Id"); messageID != "" {
+		response = fmt.Sprintf("%s (message id: %s)", response, messageID)
+	}
+	return response, err
+}
+
+// MailgunTransport envoie les emails via l'API HTTP de Mailgun
+type MailgunTransport struct {
+	apiKey  string
+	domain  string
+	baseURL string
+	from    string
+}
+
+// NewMailgunTransport crée un transport Mailgun à partir de MAILGUN_API_KEY et MAILGUN_DOMAIN
+func NewMailgunTransport() (*MailgunTransport, error) {
+	apiKey := os.Getenv("MAILGUN_API_KEY")
+	domain := os.Getenv("MAILGUN_DOMAIN")
+	if apiKey == "" || domain == "" {
+		return nil, fmt.Errorf("MAILGUN_API_KEY and MAILGUN_DOMAIN environment variables are required")
+	}
+
+	// Région EU : https://api.eu.mailgun.net/v3
+	baseURL := os.Getenv("MAILGUN_API_BASE")
+	if baseURL == "" {
+		baseURL = "https://api.mailgun.net/v3"
+	}
+
+	return &MailgunTransport{
+		apiKey:  apiKey,
+		domain:  domain,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		from:    mailFrom("noreply@" + domain),
+	}, nil
+}
+
+func (t *MailgunTransport) Name() string {
+	return "mailgun"
+}
+
+func (t *MailgunTransport) Send(to string, message Message) (string, error) {
+	form := url.Values{}
+	form.Set("from", t.from)
+	form.Set("to", to)
+	form.Set("subject", message.Subject)
+	form.Set("text", message.Body)
+
+	endpoint := fmt.Sprintf("%s/%s/messages", t.baseURL, t.domain)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth("api", t.apiKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := mailHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	return readProviderResponse(resp)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-mail/mail/v2"
+)
+
+// LogTransport log les emails au lieu de les envoyer (pour développement)
+type LogTransport struct{}
+
+// NewLogTransport crée un transport de développement
+func NewLogTransport() *LogTransport {
+	return &LogTransport{}
+}
+
+func (t *LogTransport) Name() string {
+	return "log"
+}
+
+func (t *LogTransport) Send(to string, message Message) (string, error) {
+	log.Printf("=== EMAIL SENT ===")
+	log.Printf("To: %s", to)
+	log.Printf("Subject: %s", message.Subject)
+	log.Printf("Body: %s", message.Body)
+	log.Printf("=================")
+	return "", nil
+}
+
+// SMTPTransport envoie les emails via SMTP (MAIL_DSN)
+type SMTPTransport struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPTransport crée un transport SMTP à partir de MAIL_DSN
+func NewSMTPTransport() (*SMTPTransport, error) {
+	mailDSN := os.Getenv("MAIL_DSN")
+	if mailDSN == "" {
+		return nil, fmt.Errorf("MAIL_DSN environment variable is required")
+	}
+
+	u, err := url.Parse(mailDSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAIL_DSN format: %v", err)
+	}
+
+	port := 25 // Port par défaut
+	if u.Port() != "" {
+		port, err = strconv.Atoi(u.Port())
+		if err != nil {
+			return nil, fmt.Errorf("invalid port in MAIL_DSN: %v", err)
+		}
+	}
+
+	username := ""
+	password := ""
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+
+	return &SMTPTransport{
+		host:     u.Hostname(),
+		port:     port,
+		username: username,
+		password: password,
+		from:     mailFrom(username),
+	}, nil
+}
+
+func (t *SMTPTransport) Name() string {
+	return "smtp"
+}
+
+// Ping vérifie que le serveur SMTP accepte les connexions, sans envoyer d'email
+func (t *SMTPTransport) Ping(timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(t.host, strconv.Itoa(t.port)), timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (t *SMTPTransport) Send(to string, message Message) (string, error) {
+	m := mail.NewMessage()
+	m.SetHeader("From", t.from)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", message.Subject)
+	m.SetBody("text/plain", message.Body)
+
+	d := mail.NewDialer(t.host, t.port, t.username, t.password)
+
+	// Pour les serveurs locaux comme Mailpit, désactiver TLS
+	if t.host == "localhost" || t.host == "127.0.0.1" {
+		d.TLSConfig = nil
+	}
+
+	if err := d.DialAndSend(m); err != nil {
+		return "", err
+	}
+
+	log.Printf("Email sent successfully to %s via SMTP (%s:%d)", to, t.host, t.port)
+	return fmt.Sprintf("accepted by %s:%d", t.host, t.port), nil
+}
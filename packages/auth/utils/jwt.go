@@ -3,44 +3,85 @@ package utils
 import (
 	"errors"
 	"os"
+	"sync"
 	"time"
 
 	"auth/models"
+	"core/clock"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret []byte
+// defaultJWTSecret est utilisé quand JWT_SECRET n'est pas défini, le contrôle au démarrage le refuse
+const defaultJWTSecret = "your-secret-key"
 
-func init() {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "your-secret-key"
+// minJWTSecretLength est la longueur minimale d'un secret HS256 sûr (256 bits)
+const minJWTSecretLength = 32
+
+var (
+	jwtSecret     []byte
+	jwtSecretOnce sync.Once
+
+	// authClock donne l'heure aux expirations des tokens et au comptage des connexions
+	authClock clock.Clock = clock.Real
+)
+
+// SetClock remplace l'horloge réelle, pour simuler l'expiration des tokens
+func SetClock(c clock.Clock) {
+	authClock = c
+}
+
+// Now retourne l'heure de l'horloge du module auth
+func Now() time.Time {
+	return authClock.Now()
+}
+
+// secret lit JWT_SECRET au premier usage, une fois le .env chargé par main
+func secret() []byte {
+	jwtSecretOnce.Do(func() {
+		value := os.Getenv("JWT_SECRET")
+		if value == "" {
+			value = defaultJWTSecret
+		}
+		jwtSecret = []byte(value)
+	})
+	return jwtSecret
+}
+
+// CheckJWTSecret vérifie que le secret de signature est défini et assez long
+func CheckJWTSecret() error {
+	value := string(secret())
+	if value == defaultJWTSecret {
+		return errors.New("JWT_SECRET is not set, tokens are signed with the default secret")
+	}
+	if len(value) < minJWTSecretLength {
+		return errors.New("JWT_SECRET is shorter than 32 characters")
 	}
-	jwtSecret = []byte(secret)
+	return nil
 }
 
 func GenerateToken(user models.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+	now := Now()
+	expirationTime := now.Add(24 * time.Hour)
 	claims := &models.Claims{
 		UserID: user.ID,
 		Email:  user.Email,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString(secret())
 }
 
 func ValidateToken(tokenString string) (*models.Claims, error) {
 	claims := &models.Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		return secret(), nil
+	}, jwt.WithTimeFunc(Now))
 
 	if err != nil {
 		return nil, err
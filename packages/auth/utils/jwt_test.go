@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"auth/models"
+	"core/clock"
+)
+
+func TestTokenExpiresAfter24Hours(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC))
+	SetClock(fake)
+	t.Cleanup(func() { SetClock(clock.Real) })
+
+	token, err := GenerateToken(models.User{ID: 42, Email: "alexandre@bab-insa.fr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Advance(23 * time.Hour)
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("token refused before expiry: %v", err)
+	}
+	if claims.UserID != 42 {
+		t.Errorf("got user %d, want 42", claims.UserID)
+	}
+
+	fake.Advance(2 * time.Hour)
+	if _, err := ValidateToken(token); err == nil {
+		t.Error("token accepted after expiry")
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limite le nombre d'actions par clé sur une fenêtre glissante (en mémoire)
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+
+	// Dernier nettoyage des clés inactives
+	sweptAt time.Time
+}
+
+// NewRateLimiter crée un limiteur autorisant `limit` actions par `window` et par clé
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// Allow enregistre une action pour la clé si la limite n'est pas atteinte.
+// Sinon retourne false et le délai avant que la prochaine action soit autorisée.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-rl.window)
+
+	// Une fois par fenêtre, oublier les clés sans action récente (IP de passage)
+	if now.Sub(rl.sweptAt) >= rl.window {
+		rl.sweep(cutoff)
+		rl.sweptAt = now
+	}
+
+	// Purger les actions sorties de la fenêtre
+	recent := rl.hits[key][:0]
+	for _, hit := range rl.hits[key] {
+		if hit.After(cutoff) {
+			recent = append(recent, hit)
+		}
+	}
+
+	if len(recent) >= rl.limit {
+		rl.hits[key] = recent
+		return false, recent[0].Add(rl.window).Sub(now)
+	}
+
+	rl.hits[key] = append(recent, now)
+	return true, 0
+}
+
+// sweep supprime les clés dont la dernière action est sortie de la fenêtre, pour que la mémoire
+// ne grandisse pas avec chaque clé vue une fois
+func (rl *RateLimiter) sweep(cutoff time.Time) {
+	for key, hits := range rl.hits {
+		if len(hits) == 0 || !hits[len(hits)-1].After(cutoff) {
+			delete(rl.hits, key)
+		}
+	}
+}
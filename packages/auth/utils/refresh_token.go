@@ -36,7 +36,7 @@ func GenerateTokenPair(db *gorm.DB, user models.User) (*models.TokenResponse, er
 	refreshToken := models.RefreshToken{
 		UserID:    user.ID,
 		Token:     refreshTokenString,
-		ExpiresAt: time.Now().Add(RefreshTokenExpiry),
+		ExpiresAt: Now().Add(RefreshTokenExpiry),
 	}
 
 	if err := db.Create(&refreshToken).Error; err != nil {
@@ -61,7 +61,7 @@ func RefreshAccessToken(db *gorm.DB, refreshTokenString string) (*models.TokenRe
 	}
 
 	// Vérifier si le token est expiré
-	if refreshToken.IsExpired() {
+	if refreshToken.IsExpiredAt(Now()) {
 		// Supprimer le token expiré
 		db.Delete(&refreshToken)
 		return nil, gorm.ErrRecordNotFound
@@ -81,7 +81,7 @@ func RefreshAccessToken(db *gorm.DB, refreshTokenString string) (*models.TokenRe
 
 	// Mettre à jour le refresh token
 	refreshToken.Token = newRefreshTokenString
-	refreshToken.ExpiresAt = time.Now().Add(RefreshTokenExpiry)
+	refreshToken.ExpiresAt = Now().Add(RefreshTokenExpiry)
 	db.Save(&refreshToken)
 
 	return &models.TokenResponse{
@@ -104,7 +104,7 @@ func RevokeAllUserTokens(db *gorm.DB, userID uint) error {
 
 // CleanExpiredTokens supprime les tokens expirés (à appeler périodiquement)
 func CleanExpiredTokens(db *gorm.DB) error {
-	return db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{}).Error
+	return db.Where("expires_at < ?", Now()).Delete(&models.RefreshToken{}).Error
 }
 
 // generateSecureToken génère un token sécurisé pour le refresh token
@@ -1,54 +1,168 @@
 package core
 
 import (
+	"core/clock"
 	"core/cron"
+	"core/events"
 	"core/handlers"
+	coreMiddleware "core/middleware"
+	"core/models"
 	"core/services"
 	"log"
 
 	authMiddleware "auth/middleware"
 	authModels "auth/models"
+	authServices "auth/services"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type Module struct {
-	PlayerHandler         *handlers.PlayerHandler
-	PlayerService         *services.PlayerService
-	MatchHandler          *handlers.MatchHandler
-	MatchService          *services.MatchService
-	TeamHandler           *handlers.TeamHandler
-	TeamService           *services.TeamService
-	TeamMatchHandler      *handlers.TeamMatchHandler
-	TeamMatchService      *services.TeamMatchService
-	TournamentHandler     *handlers.TournamentHandler
-	TournamentService     *services.TournamentService
-	EloHistoryHandler     *handlers.EloHistoryHandler
-	TeamEloHistoryHandler *handlers.TeamEloHistoryHandler
-	EloHistoryService     *services.EloHistoryService
-	StatsHandler          *handlers.StatsHandler
-	StatsService          *services.StatsService
-	AutoValidationService *services.AutoValidationService
-	Scheduler             *cron.Scheduler
-	db                    *gorm.DB
+	PlayerHandler           *handlers.PlayerHandler
+	PlayerService           *services.PlayerService
+	MatchHandler            *handlers.MatchHandler
+	MatchService            *services.MatchService
+	LiveMatchHandler        *handlers.LiveMatchHandler
+	LiveMatchService        *services.LiveMatchService
+	TeamHandler             *handlers.TeamHandler
+	TeamService             *services.TeamService
+	TeamMatchHandler        *handlers.TeamMatchHandler
+	TeamMatchService        *services.TeamMatchService
+	RefereeHandler          *handlers.RefereeHandler
+	RefereeService          *services.RefereeService
+	TournamentHandler       *handlers.TournamentHandler
+	TournamentService       *services.TournamentService
+	TemplateHandler         *handlers.TournamentTemplateHandler
+	RecurrenceHandler       *handlers.TournamentRecurrenceHandler
+	RecurrenceService       *services.TournamentRecurrenceService
+	TrophyHandler           *handlers.TrophyHandler
+	TrophyService           *services.TrophyService
+	TitleHandler            *handlers.TitleHandler
+	TitleService            *services.TitleService
+	RatingHandler           *handlers.RatingHandler
+	RatingService           *services.RatingService
+	MatchFeedHandler        *handlers.MatchFeedHandler
+	MatchFeedService        *services.MatchFeedService
+	PredictionHandler       *handlers.PredictionHandler
+	PredictionService       *services.PredictionService
+	MonthlyAwardHandler     *handlers.MonthlyAwardHandler
+	MonthlyAwardService     *services.MonthlyAwardService
+	EloHistoryHandler       *handlers.EloHistoryHandler
+	TeamEloHistoryHandler   *handlers.TeamEloHistoryHandler
+	EloHistoryService       *services.EloHistoryService
+	StatsHandler            *handlers.StatsHandler
+	StatsService            *services.StatsService
+	HallOfFameHandler       *handlers.HallOfFameHandler
+	HallOfFameService       *services.HallOfFameService
+	PublicAPIHandler        *handlers.PublicAPIHandler
+	WebhookHandler          *handlers.WebhookHandler
+	PublicAPI               *coreMiddleware.PublicAPI
+	ImportHandler           *handlers.ImportHandler
+	ImportService           *services.ImportService
+	KioskHandler            *handlers.KioskHandler
+	KioskService            *services.KioskService
+	AnomalyHandler          *handlers.AnomalyHandler
+	AnomalyService          *services.AnomalyService
+	EventLeaderboardHandler *handlers.EventLeaderboardHandler
+	EventLeaderboardService *services.EventLeaderboardService
+	SeasonHandler           *handlers.SeasonHandler
+	SeasonService           *services.SeasonService
+	RatingOverrideHandler   *handlers.RatingOverrideHandler
+	PlayerArchiveHandler    *handlers.PlayerArchiveHandler
+	RatingOverrideService   *services.RatingOverrideService
+	RatingResetHandler      *handlers.RatingResetHandler
+	RatingResetService      *services.RatingResetService
+	NotificationHandler     *handlers.NotificationHandler
+	NotificationService     *services.NotificationService
+	AutoValidationService   *services.AutoValidationService
+	AdminConsoleHandler     *handlers.AdminConsoleHandler
+	AdminUIHandler          *handlers.AdminUIHandler
+	SchedulerHandler        *handlers.SchedulerHandler
+	ExportHandler           *handlers.ExportHandler
+	DebugLogHandler         *handlers.DebugLogHandler
+	DebugLogService         *services.DebugLogService
+	LeaderboardHandler      *handlers.LeaderboardHandler
+	LeaderboardService      *services.LeaderboardService
+	SlowQueryHandler        *handlers.SlowQueryHandler
+	SlowQueryService        *services.SlowQueryService
+	IntegrityHandler        *handlers.IntegrityHandler
+	UsageHandler            *handlers.UsageHandler
+	UsageService            *services.UsageService
+	IntegrityService        *services.IntegrityService
+	ClientConfigHandler     *handlers.ClientConfigHandler
+	ClientConfigService     *services.ClientConfigService
+	ClientVersionHandler    *handlers.ClientVersionHandler
+	ClientVersionService    *services.ClientVersionService
+	SyncHandler             *handlers.SyncHandler
+	SyncService             *services.SyncService
+	Events                  *events.Bus
+	Scheduler               *cron.Scheduler
+	db                      *gorm.DB
 }
 
 func NewModule(db *gorm.DB) *Module {
+	// Operational events streamed to the admin console
+	bus := events.NewBus()
+	adminConsoleHandler := handlers.NewAdminConsoleHandler(bus)
+	adminUIHandler := handlers.NewAdminUIHandler()
+
+	debugLogService := services.NewDebugLogService(db)
+	debugLogHandler := handlers.NewDebugLogHandler(debugLogService)
+
+	slowQueryService := services.NewSlowQueryService(db, bus)
+	slowQueryHandler := handlers.NewSlowQueryHandler(slowQueryService)
+
+	usageService := services.NewUsageService(db)
+	usageHandler := handlers.NewUsageHandler(usageService)
+
+	emailService := authServices.NewEmailService(db)
+	notificationService := services.NewNotificationService(db, emailService, bus)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
 	playerService := services.NewPlayerService(db)
+	playerService.SetEvents(bus)
 	teamService := services.NewTeamService(db)
-	playerHandler := handlers.NewPlayerHandler(playerService, teamService)
+	playerHandler := handlers.NewPlayerHandler(playerService, teamService, db)
 
 	matchService := services.NewMatchService(db)
-	matchHandler := handlers.NewMatchHandler(matchService, db)
+	matchHandler := handlers.NewMatchHandler(matchService, notificationService, db)
+
+	liveMatchService := services.NewLiveMatchService(db, matchService)
+	liveMatchHandler := handlers.NewLiveMatchHandler(liveMatchService, notificationService, db)
 
 	teamHandler := handlers.NewTeamHandler(db)
 
 	teamMatchService := services.NewTeamMatchService(db)
-	teamMatchHandler := handlers.NewTeamMatchHandler(db)
+	teamMatchHandler := handlers.NewTeamMatchHandler(db, notificationService)
+
+	refereeService := services.NewRefereeService(db, matchService, teamMatchService)
+	refereeHandler := handlers.NewRefereeHandler(refereeService)
 
 	tournamentService := services.NewTournamentService(db)
-	tournamentHandler := handlers.NewTournamentHandler(db)
+	tournamentHandler := handlers.NewTournamentHandler(db, notificationService)
+	templateHandler := handlers.NewTournamentTemplateHandler(db)
+
+	recurrenceService := services.NewTournamentRecurrenceService(db, notificationService)
+	recurrenceHandler := handlers.NewTournamentRecurrenceHandler(recurrenceService)
+
+	trophyService := services.NewTrophyService(db)
+	trophyHandler := handlers.NewTrophyHandler(trophyService)
+
+	monthlyAwardService := services.NewMonthlyAwardService(db, notificationService, services.NewAnnouncer())
+	monthlyAwardHandler := handlers.NewMonthlyAwardHandler(monthlyAwardService)
+
+	titleService := services.NewTitleService(db)
+	titleHandler := handlers.NewTitleHandler(titleService, db)
+
+	ratingService := services.NewRatingService(db)
+	ratingHandler := handlers.NewRatingHandler(ratingService)
+
+	matchFeedService := services.NewMatchFeedService(db)
+	matchFeedHandler := handlers.NewMatchFeedHandler(matchFeedService)
+
+	predictionService := services.NewPredictionService(db)
+	predictionHandler := handlers.NewPredictionHandler(predictionService)
 
 	eloHistoryService := services.NewEloHistoryService(db)
 	eloHistoryHandler := handlers.NewEloHistoryHandler(eloHistoryService)
@@ -57,29 +171,141 @@ func NewModule(db *gorm.DB) *Module {
 	statsService := services.NewStatsService(db)
 	statsHandler := handlers.NewStatsHandler(statsService)
 
+	hallOfFameService := services.NewHallOfFameService(db)
+	hallOfFameHandler := handlers.NewHallOfFameHandler(hallOfFameService)
+
+	publicAPIService := services.NewPublicAPIService(db)
+	publicAPIHandler := handlers.NewPublicAPIHandler(publicAPIService)
+	publicAPI := coreMiddleware.NewPublicAPI(publicAPIService)
+
+	importService := services.NewImportService(db)
+	importHandler := handlers.NewImportHandler(importService, db)
+
+	kioskService := services.NewKioskService(db, matchService)
+	kioskHandler := handlers.NewKioskHandler(kioskService, notificationService, db)
+
 	// Initialize auto-validation service and scheduler
-	autoValidationService := services.NewAutoValidationService(db, matchService, teamMatchService)
-	scheduler := cron.NewScheduler(autoValidationService)
+	seasonService := services.NewSeasonService(db)
+	seasonHandler := handlers.NewSeasonHandler(seasonService)
+	eventLeaderboardService := services.NewEventLeaderboardService(db)
+	eventLeaderboardHandler := handlers.NewEventLeaderboardHandler(eventLeaderboardService)
+
+	auditService := authServices.NewAuditService(db)
+	ratingOverrideService := services.NewRatingOverrideService(db)
+	ratingOverrideHandler := handlers.NewRatingOverrideHandler(ratingOverrideService, auditService)
+	playerArchiveHandler := handlers.NewPlayerArchiveHandler(playerService, auditService)
+
+	ratingResetService := services.NewRatingResetService(db)
+	ratingResetHandler := handlers.NewRatingResetHandler(ratingResetService, auditService)
+
+	leaderboardService := services.NewLeaderboardService(db)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+
+	clientVersionService := services.NewClientVersionService(db)
+	clientVersionHandler := handlers.NewClientVersionHandler(clientVersionService)
+	clientConfigService := services.NewClientConfigService(matchService, clientVersionService)
+	clientConfigHandler := handlers.NewClientConfigHandler(clientConfigService)
+
+	syncService := services.NewSyncService(db, kioskService)
+	syncHandler := handlers.NewSyncHandler(syncService, notificationService)
+
+	anomalyService := services.NewAnomalyService(db)
+	anomalyHandler := handlers.NewAnomalyHandler(anomalyService)
+
+	webhookService := services.NewWebhookService(db, publicAPIService, bus)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	exportService := services.NewExportService(db, matchService, playerService, ratingService)
+	exportHandler := handlers.NewExportHandler(exportService, db)
+
+	integrityService := services.NewIntegrityService(db, emailService, bus)
+	integrityHandler := handlers.NewIntegrityHandler(integrityService)
+
+	autoValidationService := services.NewAutoValidationService(db, matchService, teamMatchService, notificationService, bus)
+	scheduler := cron.NewScheduler(autoValidationService, notificationService, recurrenceService, importService, anomalyService, leaderboardService, eloHistoryService, monthlyAwardService, exportService, webhookService, integrityService, bus)
+	schedulerHandler := handlers.NewSchedulerHandler(scheduler)
 
 	return &Module{
-		PlayerHandler:         playerHandler,
-		PlayerService:         playerService,
-		MatchHandler:          matchHandler,
-		MatchService:          matchService,
-		TeamHandler:           teamHandler,
-		TeamService:           teamService,
-		TeamMatchHandler:      teamMatchHandler,
-		TeamMatchService:      teamMatchService,
-		TournamentHandler:     tournamentHandler,
-		TournamentService:     tournamentService,
-		EloHistoryHandler:     eloHistoryHandler,
-		TeamEloHistoryHandler: teamEloHistoryHandler,
-		EloHistoryService:     eloHistoryService,
-		StatsHandler:          statsHandler,
-		StatsService:          statsService,
-		AutoValidationService: autoValidationService,
-		Scheduler:             scheduler,
-		db:                    db,
+		PlayerHandler:           playerHandler,
+		PlayerService:           playerService,
+		MatchHandler:            matchHandler,
+		MatchService:            matchService,
+		LiveMatchHandler:        liveMatchHandler,
+		LiveMatchService:        liveMatchService,
+		TeamHandler:             teamHandler,
+		TeamService:             teamService,
+		TeamMatchHandler:        teamMatchHandler,
+		TeamMatchService:        teamMatchService,
+		RefereeHandler:          refereeHandler,
+		RefereeService:          refereeService,
+		TournamentHandler:       tournamentHandler,
+		TournamentService:       tournamentService,
+		TemplateHandler:         templateHandler,
+		RecurrenceHandler:       recurrenceHandler,
+		RecurrenceService:       recurrenceService,
+		TrophyHandler:           trophyHandler,
+		TrophyService:           trophyService,
+		TitleHandler:            titleHandler,
+		TitleService:            titleService,
+		RatingHandler:           ratingHandler,
+		RatingService:           ratingService,
+		MatchFeedHandler:        matchFeedHandler,
+		MatchFeedService:        matchFeedService,
+		PredictionHandler:       predictionHandler,
+		PredictionService:       predictionService,
+		MonthlyAwardHandler:     monthlyAwardHandler,
+		MonthlyAwardService:     monthlyAwardService,
+		EloHistoryHandler:       eloHistoryHandler,
+		TeamEloHistoryHandler:   teamEloHistoryHandler,
+		EloHistoryService:       eloHistoryService,
+		StatsHandler:            statsHandler,
+		StatsService:            statsService,
+		HallOfFameHandler:       hallOfFameHandler,
+		HallOfFameService:       hallOfFameService,
+		PublicAPIHandler:        publicAPIHandler,
+		WebhookHandler:          webhookHandler,
+		PublicAPI:               publicAPI,
+		ImportHandler:           importHandler,
+		ImportService:           importService,
+		KioskHandler:            kioskHandler,
+		KioskService:            kioskService,
+		AnomalyHandler:          anomalyHandler,
+		AnomalyService:          anomalyService,
+		EventLeaderboardHandler: eventLeaderboardHandler,
+		EventLeaderboardService: eventLeaderboardService,
+		SeasonHandler:           seasonHandler,
+		SeasonService:           seasonService,
+		RatingOverrideHandler:   ratingOverrideHandler,
+		PlayerArchiveHandler:    playerArchiveHandler,
+		RatingOverrideService:   ratingOverrideService,
+		RatingResetHandler:      ratingResetHandler,
+		RatingResetService:      ratingResetService,
+		NotificationHandler:     notificationHandler,
+		NotificationService:     notificationService,
+		AutoValidationService:   autoValidationService,
+		AdminConsoleHandler:     adminConsoleHandler,
+		AdminUIHandler:          adminUIHandler,
+		SchedulerHandler:        schedulerHandler,
+		ExportHandler:           exportHandler,
+		DebugLogHandler:         debugLogHandler,
+		DebugLogService:         debugLogService,
+		LeaderboardHandler:      leaderboardHandler,
+		LeaderboardService:      leaderboardService,
+		SlowQueryHandler:        slowQueryHandler,
+		SlowQueryService:        slowQueryService,
+		IntegrityHandler:        integrityHandler,
+		UsageHandler:            usageHandler,
+		UsageService:            usageService,
+		IntegrityService:        integrityService,
+		ClientConfigHandler:     clientConfigHandler,
+		ClientConfigService:     clientConfigService,
+		ClientVersionHandler:    clientVersionHandler,
+		ClientVersionService:    clientVersionService,
+		SyncHandler:             syncHandler,
+		SyncService:             syncService,
+		Events:                  bus,
+		Scheduler:               scheduler,
+		db:                      db,
 	}
 }
 
@@ -87,30 +313,74 @@ func (m *Module) SetupRoutes(r *gin.Engine) {
 	players := r.Group("/players")
 	{
 		players.GET("", m.PlayerHandler.GetAllPlayers)
-		players.GET("/top", authMiddleware.OptionalJWTMiddleware(), m.PlayerHandler.GetTopPlayers)
+		players.GET("/top", authMiddleware.OptionalJWTMiddleware(), m.LeaderboardHandler.GetTopPlayers)
 		players.GET("/top-teams", authMiddleware.OptionalJWTMiddleware(), m.PlayerHandler.GetTopPlayersByTeamElo)
 		players.GET("/:id", m.PlayerHandler.GetPlayer)
 		players.GET("/:id/elo-history", m.PlayerHandler.GetEloHistory)
 		players.GET("/:id/team-elo-history", m.PlayerHandler.GetTeamEloHistory)
+		players.GET("/:id/ratings", m.RatingHandler.GetPlayerRatings)
 		players.GET("/:id/matches", m.PlayerHandler.GetPlayerMatches)
+		players.GET("/:id/history", m.MatchFeedHandler.GetPlayerHistory)
 		players.GET("/:id/teams", m.PlayerHandler.GetPlayerTeams)
+		players.GET("/:id/trophies", m.TrophyHandler.GetPlayerTrophies)
+		players.GET("/:id/titles", m.TitleHandler.GetPlayerTitles)
+		players.POST("/:id/titles", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TitleHandler.AssignTitle)
+		players.DELETE("/:id/titles/:titleId", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TitleHandler.RevokeTitle)
+		players.PUT("/:id/flair", authMiddleware.JWTMiddleware(), m.TitleHandler.UpdateFlair)
+		players.PUT("/:id/public-consent", authMiddleware.JWTMiddleware(), m.PublicAPIHandler.UpdateConsent)
+		players.GET("/:id/external-ids", m.ImportHandler.GetExternalIDs)
+		players.POST("/:id/external-ids", authMiddleware.JWTMiddleware(), m.ImportHandler.AddExternalID)
+		players.DELETE("/:id/external-ids/:source", authMiddleware.JWTMiddleware(), m.ImportHandler.RemoveExternalID)
+		players.GET("/:id/external-matches", m.ImportHandler.GetExternalMatches)
+		players.PUT("/:id/away", authMiddleware.JWTMiddleware(), m.PlayerHandler.SetAway)
+		players.PUT("/:id/campus", authMiddleware.JWTMiddleware(), m.PlayerHandler.SetCampus)
+		players.PUT("/:id/display-name", authMiddleware.JWTMiddleware(), m.PlayerHandler.SetDisplayName)
+		players.DELETE("/:id/away", authMiddleware.JWTMiddleware(), m.PlayerHandler.ClearAway)
+		players.POST("/:id/archive", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.PlayerArchiveHandler.ArchivePlayer)
+		players.DELETE("/:id/archive", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.PlayerArchiveHandler.UnarchivePlayer)
+		players.GET("/:id/rating-overrides", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RatingOverrideHandler.GetRatingOverrides)
+		players.POST("/:id/rating-overrides", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RatingOverrideHandler.CreateRatingOverride)
+		players.DELETE("/:id/rating-overrides/:overrideId", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RatingOverrideHandler.RevokeRatingOverride)
+		players.POST("/:id/rating-reset-requests", authMiddleware.JWTMiddleware(), m.RatingResetHandler.RequestRatingReset)
+		players.PUT("/:id/card", authMiddleware.JWTMiddleware(), m.KioskHandler.SetPlayerCard)
+		players.DELETE("/:id/card", authMiddleware.JWTMiddleware(), m.KioskHandler.RemovePlayerCard)
 	}
 
 	matches := r.Group("/matches")
 	{
 		matches.GET("", m.MatchHandler.GetMatches)
 		matches.GET("/recent", m.MatchHandler.GetRecentMatches)
+		matches.GET("/all", m.MatchFeedHandler.GetFeed)
+		matches.GET("/:id/timeline", m.LiveMatchHandler.GetMatchTimeline)
 		matches.POST("", authMiddleware.JWTMiddleware(), m.MatchHandler.CreateMatch)
 		matches.PATCH("/:id", authMiddleware.JWTMiddleware(), m.MatchHandler.UpdateMatchStatus)
 		matches.PATCH("/:id/reject", authMiddleware.JWTMiddleware(), m.MatchHandler.RejectMatch)
 		matches.PATCH("/:id/cancel", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.MatchHandler.CancelMatch)
+		matches.PATCH("/:id/floor-exemption", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.MatchHandler.UpdateFloorExemption)
+		matches.PATCH("/:id/referee", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RefereeHandler.AssignMatchReferee)
 		matches.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.MatchHandler.DeleteMatch)
 	}
 
+	liveMatches := r.Group("/live-matches")
+	{
+		liveMatches.GET("", m.LiveMatchHandler.GetLiveMatches)
+		liveMatches.GET("/:id", m.LiveMatchHandler.GetLiveMatch)
+		liveMatches.GET("/:id/timer", m.LiveMatchHandler.GetTimer)
+		liveMatches.POST("", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.StartLiveMatch)
+		liveMatches.POST("/:id/goals", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.RecordGoal)
+		liveMatches.DELETE("/:id/goals/last", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.UndoLastGoal)
+		liveMatches.POST("/:id/finalize", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.FinalizeLiveMatch)
+		liveMatches.POST("/:id/abandon", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.AbandonLiveMatch)
+		liveMatches.POST("/:id/timer/pause", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.PauseTimer)
+		liveMatches.POST("/:id/timer/resume", authMiddleware.JWTMiddleware(), m.LiveMatchHandler.ResumeTimer)
+	}
+
 	teams := r.Group("/teams")
 	{
 		teams.GET("", m.TeamHandler.GetAllTeams)
 		teams.GET("/:id", m.TeamHandler.GetTeam)
+		teams.GET("/:id/trophies", m.TrophyHandler.GetTeamTrophies)
+		teams.GET("/:id/elo-history", m.TeamEloHistoryHandler.GetTeamRatingHistory)
 		teams.POST("", authMiddleware.JWTMiddleware(), m.TeamHandler.CreateTeam)
 		teams.PUT("/:id", authMiddleware.JWTMiddleware(), m.TeamHandler.UpdateTeam)
 		teams.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TeamHandler.DeleteTeam)
@@ -125,21 +395,106 @@ func (m *Module) SetupRoutes(r *gin.Engine) {
 		teamMatches.PATCH("/:id", authMiddleware.JWTMiddleware(), m.TeamMatchHandler.UpdateTeamMatchStatus)
 		teamMatches.PATCH("/:id/reject", authMiddleware.JWTMiddleware(), m.TeamMatchHandler.RejectTeamMatch)
 		teamMatches.PATCH("/:id/cancel", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TeamMatchHandler.CancelTeamMatch)
+		teamMatches.PATCH("/:id/referee", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RefereeHandler.AssignTeamMatchReferee)
 	}
 
+	referee := r.Group("/referee")
+	referee.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleReferee))
+	{
+		referee.POST("/matches", m.RefereeHandler.RecordMatch)
+		referee.POST("/team-matches", m.RefereeHandler.RecordTeamMatch)
+	}
+
+	r.GET("/referees/:id/stats", m.RefereeHandler.GetRefereeStats)
+
 	tournaments := r.Group("/tournaments")
 	{
 		tournaments.GET("", m.TournamentHandler.GetAllTournaments)
 		tournaments.GET("/:id", m.TournamentHandler.GetTournament)
 		tournaments.GET("/:id/teams", m.TournamentHandler.GetTournamentTeams)
 		tournaments.GET("/:id/matches", m.TournamentHandler.GetTournamentMatches)
+		tournaments.GET("/:id/standings", m.TournamentHandler.GetTournamentStandings)
+		tournaments.GET("/:id/waitlist", m.TournamentHandler.GetTournamentWaitlist)
 		tournaments.POST("", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.CreateTournament)
 		tournaments.PUT("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.UpdateTournament)
+		tournaments.POST("/:id/pools", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.AssignPools)
+		tournaments.POST("/:id/knockout", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.StartKnockout)
+		tournaments.POST("/:id/template", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TemplateHandler.SaveTournamentAsTemplate)
+		tournaments.POST("/from-template/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TemplateHandler.CreateTournamentFromTemplate)
 		tournaments.POST("/:id/join", authMiddleware.JWTMiddleware(), m.TournamentHandler.JoinTournament)
 		tournaments.DELETE("/:id/teams/:teamId", authMiddleware.JWTMiddleware(), m.TournamentHandler.LeaveTournament)
+		tournaments.PATCH("/:id/teams/:teamId/payment", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.UpdateEntryPayment)
+		tournaments.GET("/:id/fees", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.GetFeesSummary)
 		tournaments.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TournamentHandler.DeleteTournament)
 	}
 
+	tournamentTemplates := r.Group("/tournament-templates")
+	{
+		tournamentTemplates.GET("", m.TemplateHandler.GetTemplates)
+		tournamentTemplates.GET("/:id", m.TemplateHandler.GetTemplate)
+		tournamentTemplates.POST("", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TemplateHandler.CreateTemplate)
+		tournamentTemplates.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TemplateHandler.DeleteTemplate)
+	}
+
+	tournamentRecurrences := r.Group("/tournament-recurrences")
+	{
+		tournamentRecurrences.GET("", m.RecurrenceHandler.GetRecurrences)
+		tournamentRecurrences.GET("/:id", m.RecurrenceHandler.GetRecurrence)
+		tournamentRecurrences.POST("", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RecurrenceHandler.CreateRecurrence)
+		tournamentRecurrences.PUT("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RecurrenceHandler.UpdateRecurrence)
+		tournamentRecurrences.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RecurrenceHandler.DeleteRecurrence)
+		tournamentRecurrences.POST("/:id/editions", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.RecurrenceHandler.CreateNextEdition)
+		tournamentRecurrences.POST("/:id/subscription", authMiddleware.JWTMiddleware(), m.RecurrenceHandler.Subscribe)
+		tournamentRecurrences.DELETE("/:id/subscription", authMiddleware.JWTMiddleware(), m.RecurrenceHandler.Unsubscribe)
+	}
+
+	trophies := r.Group("/trophies")
+	{
+		trophies.GET("", m.TrophyHandler.GetTrophies)
+		trophies.GET("/:id", m.TrophyHandler.GetTrophy)
+		trophies.POST("", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TrophyHandler.CreateTrophy)
+		trophies.PUT("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TrophyHandler.UpdateTrophy)
+		trophies.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TrophyHandler.DeleteTrophy)
+	}
+
+	monthlyAwards := r.Group("/monthly-awards")
+	{
+		monthlyAwards.GET("", m.MonthlyAwardHandler.GetMonthlyAwards)
+		monthlyAwards.POST("/run", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.MonthlyAwardHandler.RunMonthlyAwards)
+	}
+
+	titles := r.Group("/titles")
+	{
+		titles.GET("", m.TitleHandler.GetTitles)
+		titles.POST("", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TitleHandler.CreateTitle)
+		titles.PUT("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TitleHandler.UpdateTitle)
+		titles.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.TitleHandler.DeleteTitle)
+	}
+
+	eventLeaderboards := r.Group("/event-leaderboards")
+	{
+		eventLeaderboards.GET("", m.EventLeaderboardHandler.GetEvents)
+		eventLeaderboards.GET("/:id/standings", m.EventLeaderboardHandler.GetStandings)
+		eventLeaderboards.POST("", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.EventLeaderboardHandler.CreateEvent)
+		eventLeaderboards.PUT("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.EventLeaderboardHandler.UpdateEvent)
+		eventLeaderboards.DELETE("/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.EventLeaderboardHandler.DeleteEvent)
+		eventLeaderboards.POST("/:id/participants", authMiddleware.JWTMiddleware(), m.EventLeaderboardHandler.Join)
+		eventLeaderboards.DELETE("/:id/participants", authMiddleware.JWTMiddleware(), m.EventLeaderboardHandler.Leave)
+	}
+
+	ratingResetRequests := r.Group("/rating-reset-requests")
+	ratingResetRequests.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		ratingResetRequests.GET("", m.RatingResetHandler.GetRatingResetRequests)
+		ratingResetRequests.PATCH("/:id", m.RatingResetHandler.ReviewRatingResetRequest)
+	}
+
+	seasons := r.Group("/seasons")
+	{
+		seasons.GET("/:season/settings", m.SeasonHandler.GetSeasonSettings)
+		seasons.PUT("/:season/settings", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.SeasonHandler.UpdateSeasonSettings)
+	}
+
 	eloHistory := r.Group("/elo-history")
 	{
 		eloHistory.GET("/recent", m.EloHistoryHandler.GetRecentEloChanges)
@@ -150,13 +505,215 @@ func (m *Module) SetupRoutes(r *gin.Engine) {
 		teamEloHistory.GET("/recent", m.TeamEloHistoryHandler.GetRecentTeamEloChanges)
 	}
 
+	notifications := r.Group("/notifications")
+	notifications.Use(authMiddleware.JWTMiddleware())
+	{
+		notifications.GET("/preferences", m.NotificationHandler.GetPreferences)
+		notifications.PUT("/preferences", m.NotificationHandler.UpdatePreferences)
+	}
+
 	r.GET("/stats", m.StatsHandler.GetStats)
+	r.GET("/hall-of-fame", m.HallOfFameHandler.GetHallOfFame)
+	r.GET("/leaderboard", m.LeaderboardHandler.GetLeaderboard)
+	r.GET("/ladders/:ladder", m.RatingHandler.GetLadder)
+	r.GET("/campuses", m.PlayerHandler.GetCampuses)
+	r.GET("/predict", m.PredictionHandler.PredictPlayers)
+	r.GET("/predict/teams", m.PredictionHandler.PredictTeams)
+	r.GET("/client-config", m.ClientConfigHandler.GetClientConfig)
+
+	apiTokens := r.Group("/api-tokens")
+	apiTokens.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		apiTokens.GET("", m.PublicAPIHandler.GetTokens)
+		apiTokens.POST("", m.PublicAPIHandler.CreateToken)
+		apiTokens.DELETE("/:id", m.PublicAPIHandler.RevokeToken)
+	}
+
+	webhookEndpoints := r.Group("/webhook-endpoints")
+	webhookEndpoints.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		webhookEndpoints.GET("", m.WebhookHandler.GetEndpoints)
+		webhookEndpoints.POST("", m.WebhookHandler.CreateEndpoint)
+		webhookEndpoints.DELETE("/:id", m.WebhookHandler.DeleteEndpoint)
+		webhookEndpoints.POST("/:id/rotate-secret", m.WebhookHandler.RotateSecret)
+		webhookEndpoints.POST("/:id/ping", m.WebhookHandler.Ping)
+		webhookEndpoints.GET("/:id/deliveries", m.WebhookHandler.GetDeliveries)
+	}
+
+	// Verification helper for integrators, it checks a forwarded delivery without any secret
+	r.POST("/webhooks/verify", m.WebhookHandler.Verify)
+
+	importSources := r.Group("/import-sources")
+	importSources.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		importSources.GET("", m.ImportHandler.GetSources)
+		importSources.POST("", m.ImportHandler.CreateSource)
+		importSources.DELETE("/:id", m.ImportHandler.DeleteSource)
+		importSources.POST("/:id/run", m.ImportHandler.RunSource)
+		importSources.POST("/:id/upload", m.ImportHandler.UploadFile)
+	}
+
+	// Review queue of the anomaly detection job
+	anomalies := r.Group("/anomalies")
+	anomalies.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		anomalies.GET("", m.AnomalyHandler.GetAnomalies)
+		anomalies.PATCH("/:id", m.AnomalyHandler.ReviewAnomaly)
+	}
+
+	// Realtime channel of the admin dashboard, the JWT may come from the query string
+	r.GET("/admin/console", coreMiddleware.TokenFromQuery(), authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.AdminConsoleHandler.Stream)
+
+	// Embedded admin page, it authenticates its own calls to the admin endpoints
+	r.GET("/admin/ui", m.AdminUIHandler.Page)
+
+	jobs := r.Group("/admin/jobs")
+	jobs.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		jobs.GET("", m.SchedulerHandler.GetJobs)
+		jobs.POST("/:name/run", m.SchedulerHandler.RunJob)
+	}
+
+	// Background exports, the download is authenticated by the signature of its URL
+	exports := r.Group("/exports")
+	{
+		exports.POST("", authMiddleware.JWTMiddleware(), m.ExportHandler.CreateExport)
+		exports.GET("", authMiddleware.JWTMiddleware(), m.ExportHandler.GetExports)
+		exports.GET("/:id", authMiddleware.JWTMiddleware(), m.ExportHandler.GetExport)
+		exports.GET("/:id/download", m.ExportHandler.DownloadExport)
+	}
+
+	debugLogs := r.Group("/admin/debug-logs")
+	debugLogs.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		debugLogs.GET("/rules", m.DebugLogHandler.GetDebugLogRules)
+		debugLogs.POST("/rules", m.DebugLogHandler.CreateDebugLogRule)
+		debugLogs.DELETE("/rules/:id", m.DebugLogHandler.DeleteDebugLogRule)
+		debugLogs.GET("/rules/:id/entries", m.DebugLogHandler.GetDebugLogEntries)
+	}
+
+	r.GET("/admin/data-quality", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.IntegrityHandler.GetDataQualityReport)
+
+	r.POST("/admin/players/archive-inactive", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.PlayerArchiveHandler.ArchiveInactivePlayers)
+
+	r.GET("/admin/usage", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.UsageHandler.GetUsage)
+
+	r.GET("/admin/slow-queries", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.SlowQueryHandler.GetWorstOffenders)
+
+	clientVersions := r.Group("/admin/client-versions")
+	clientVersions.Use(authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin))
+	{
+		clientVersions.GET("", m.ClientVersionHandler.GetSetting)
+		clientVersions.PUT("", m.ClientVersionHandler.UpdateSetting)
+	}
+
+	// Offline-capable clients catch up with the changes and submit what they recorded offline
+	offlineSync := r.Group("/sync")
+	{
+		offlineSync.GET("", coreMiddleware.RequireKioskOrJWT(m.KioskService), m.SyncHandler.GetChanges)
+		offlineSync.POST("/matches", coreMiddleware.RequireKiosk(m.KioskService), m.SyncHandler.SubmitMatches)
+	}
+
+	// Shared clubroom devices, authenticated by their kiosk token
+	kiosk := r.Group("/kiosk")
+	{
+		kiosk.POST("/pair", m.KioskHandler.Pair)
+		kiosk.GET("/players", coreMiddleware.RequireKiosk(m.KioskService), m.KioskHandler.GetPlayers)
+		kiosk.POST("/cards/resolve", coreMiddleware.RequireKiosk(m.KioskService), m.KioskHandler.ResolveCard)
+		kiosk.POST("/matches", coreMiddleware.RequireKiosk(m.KioskService), m.KioskHandler.CreateMatch)
+		kiosk.POST("/matches/:id/confirm", coreMiddleware.RequireKiosk(m.KioskService), m.KioskHandler.ConfirmMatch)
+		kiosk.GET("/devices", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.KioskHandler.GetDevices)
+		kiosk.POST("/devices", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.KioskHandler.RegisterDevice)
+		kiosk.POST("/devices/:id/pairing", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.KioskHandler.StartPairing)
+		kiosk.DELETE("/devices/:id", authMiddleware.JWTMiddleware(), authMiddleware.RequireRole(m.db, authModels.RoleAdmin), m.KioskHandler.RevokeDevice)
+	}
+
+	// Public API for external rating sites, authenticated by scoped tokens
+	publicAPI := r.Group("/public/v1")
+	{
+		publicAPI.GET("/players", m.PublicAPI.RequireScope(models.PublicAPIScopePlayersRead), m.PublicAPIHandler.GetPublicPlayers)
+		publicAPI.GET("/players/:id", m.PublicAPI.RequireScope(models.PublicAPIScopePlayersRead), m.PublicAPIHandler.GetPublicPlayer)
+		publicAPI.GET("/results", m.PublicAPI.RequireScope(models.PublicAPIScopeResultsRead), m.PublicAPIHandler.GetPublicResults)
+	}
+}
+
+// ErrorRateMonitor reports server error spikes to the admin console, it must be registered before the routes
+func (m *Module) ErrorRateMonitor() gin.HandlerFunc {
+	return coreMiddleware.ErrorRateMonitor(m.Events)
+}
+
+// Compression gzips the large textual responses, it must be registered before the routes and the debug logger
+func (m *Module) Compression() gin.HandlerFunc {
+	return coreMiddleware.Compression(coreMiddleware.CompressionConfigFromEnv())
+}
+
+// RequestID tags each request with an X-Request-ID, it must be registered before the other middlewares
+func (m *Module) RequestID() gin.HandlerFunc {
+	return coreMiddleware.RequestID()
+}
+
+// Recovery answers panics with a JSON 500 carrying the request ID and reports them to the admin console
+func (m *Module) Recovery() gin.HandlerFunc {
+	return coreMiddleware.Recovery(m.Events)
+}
+
+// ClientVersionGate answers 426 to the app versions below the minimum or blocked, it must be registered before the routes
+func (m *Module) ClientVersionGate() gin.HandlerFunc {
+	return coreMiddleware.RequireClientVersion(m.ClientVersionService)
+}
+
+// CacheControl sets the Cache-Control header of each response from its route class, it must be registered before the routes
+func (m *Module) CacheControl() gin.HandlerFunc {
+	return coreMiddleware.CacheControl(coreMiddleware.CacheControlConfigFromEnv())
+}
+
+// DebugLogger captures the bodies of the routes with an active debug log rule, it must be registered before the routes
+func (m *Module) DebugLogger() gin.HandlerFunc {
+	return coreMiddleware.DebugLogger(m.DebugLogService)
+}
+
+// UsageTracker counts the requests per user and endpoint group, it must be registered before the routes
+func (m *Module) UsageTracker() gin.HandlerFunc {
+	return coreMiddleware.UsageTracker(m.UsageService)
+}
+
+// StartUsageTracking writes the usage counters every minute
+func (m *Module) StartUsageTracking() {
+	m.UsageService.Start()
+}
+
+// FlushUsage writes the usage counters still in memory, on shutdown
+func (m *Module) FlushUsage() {
+	if err := m.UsageService.Flush(); err != nil {
+		log.Printf("Failed to write API usage: %v", err)
+	}
+}
+
+// EnableSlowQueryLogging starts recording the database queries slower than SLOW_QUERY_THRESHOLD_MS
+func (m *Module) EnableSlowQueryLogging() error {
+	return m.SlowQueryService.Register()
 }
 
-// StartScheduler starts the cron scheduler for auto-validation
+// SetClock drives the solo and team match dates, the 24h auto-validation window, the rating overrides
+// and the away badge with another clock than the wall clock
+func (m *Module) SetClock(c clock.Clock) {
+	m.MatchService.SetClock(c)
+	m.TeamMatchService.SetClock(c)
+	m.AutoValidationService.SetClock(c)
+	m.RatingOverrideService.SetClock(c)
+	models.SetClock(c)
+}
+
+// StartScheduler starts the cron scheduler. When it fails the scheduler keeps retrying in the
+// background with a backoff, the error only tells the caller that it serves in degraded mode.
 func (m *Module) StartScheduler() error {
 	log.Println("Starting core module scheduler...")
-	return m.Scheduler.Start()
+	return m.Scheduler.StartWithRetry()
+}
+
+// SchedulerHealth reports whether the background jobs run, for the readiness probe
+func (m *Module) SchedulerHealth() cron.Health {
+	return m.Scheduler.Health()
 }
 
 // StopScheduler stops the cron scheduler
@@ -1,24 +1,80 @@
 package cron
 
 import (
+	"context"
+	"core/events"
 	"core/services"
+	"errors"
 	"log"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
+// Retry delays of a scheduler that failed to start, doubled on each failure
+const (
+	retryMinDelay = 5 * time.Second
+	retryMaxDelay = 5 * time.Minute
+)
+
+// Health states of the scheduler
+const (
+	HealthStopped  = "stopped"
+	HealthRunning  = "running"
+	HealthDegraded = "degraded" // Failed to start, retrying in the background
+)
+
+// Health is the state of the scheduler reported by /readyz
+type Health struct {
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"` // Start attempts, reset once started
+	LastError   *string    `json:"last_error,omitempty"`
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	Jobs        int        `json:"jobs"`
+}
+
 type Scheduler struct {
 	cron                  *cron.Cron
 	autoValidationService *services.AutoValidationService
+	notificationService   *services.NotificationService
+	recurrenceService     *services.TournamentRecurrenceService
+	importService         *services.ImportService
+	anomalyService        *services.AnomalyService
+	leaderboardService    *services.LeaderboardService
+	eloHistoryService     *services.EloHistoryService
+	monthlyAwardService   *services.MonthlyAwardService
+	exportService         *services.ExportService
+	webhookService        *services.WebhookService
+	integrityService      *services.IntegrityService
+	events                *events.Bus
+
+	mu        sync.Mutex
+	health    Health
+	stopRetry chan struct{}
 }
 
-func NewScheduler(autoValidationService *services.AutoValidationService) *Scheduler {
+func NewScheduler(autoValidationService *services.AutoValidationService, notificationService *services.NotificationService, recurrenceService *services.TournamentRecurrenceService, importService *services.ImportService, anomalyService *services.AnomalyService, leaderboardService *services.LeaderboardService, eloHistoryService *services.EloHistoryService, monthlyAwardService *services.MonthlyAwardService, exportService *services.ExportService, webhookService *services.WebhookService, integrityService *services.IntegrityService, bus *events.Bus) *Scheduler {
 	// Create cron with seconds precision and logging
 	c := cron.New(cron.WithSeconds(), cron.WithLogger(cron.VerbosePrintfLogger(log.Default())))
 
 	return &Scheduler{
 		cron:                  c,
 		autoValidationService: autoValidationService,
+		notificationService:   notificationService,
+		recurrenceService:     recurrenceService,
+		importService:         importService,
+		anomalyService:        anomalyService,
+		leaderboardService:    leaderboardService,
+		eloHistoryService:     eloHistoryService,
+		monthlyAwardService:   monthlyAwardService,
+		exportService:         exportService,
+		webhookService:        webhookService,
+		integrityService:      integrityService,
+		events:                bus,
+		health:                Health{Status: HealthStopped},
 	}
 }
 
@@ -26,44 +82,226 @@ func NewScheduler(autoValidationService *services.AutoValidationService) *Schedu
 func (s *Scheduler) Start() error {
 	log.Println("Starting cron scheduler...")
 
+	if err := s.schedule(); err != nil {
+		// Drop the jobs registered before the failure so that a retry does not register them twice
+		for _, entry := range s.cron.Entries() {
+			s.cron.Remove(entry.ID)
+		}
+		return err
+	}
+
+	s.cron.Start()
+	log.Println("Cron scheduler started successfully")
+
+	return nil
+}
+
+// StartWithRetry starts the scheduler and, when it fails, keeps retrying in the background with an
+// exponential backoff until it starts or Stop is called. The first error is returned so that the
+// caller can serve in degraded mode; each failure is reported to the admin console.
+func (s *Scheduler) StartWithRetry() error {
+	err := s.attemptStart()
+	if err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.stopRetry = make(chan struct{})
+	stop := s.stopRetry
+	s.mu.Unlock()
+
+	go s.retry(stop)
+	return err
+}
+
+func (s *Scheduler) retry(stop chan struct{}) {
+	for {
+		s.mu.Lock()
+		nextRetryAt := s.health.NextRetryAt
+		s.mu.Unlock()
+		if nextRetryAt == nil {
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Until(*nextRetryAt)):
+		}
+
+		if s.attemptStart() == nil {
+			return
+		}
+	}
+}
+
+// attemptStart starts the scheduler once and records the outcome in its health
+func (s *Scheduler) attemptStart() error {
+	err := s.Start()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.health.Attempts++
+	if err == nil {
+		if s.health.Attempts > 1 {
+			log.Printf("Scheduler started after %d attempts", s.health.Attempts)
+			s.events.Publish(events.TypeSchedulerRecovered, map[string]interface{}{
+				"attempts": s.health.Attempts,
+			})
+		}
+		s.health = Health{Status: HealthRunning, StartedAt: &now}
+		return nil
+	}
+
+	delay := retryMinDelay << min(s.health.Attempts-1, 16)
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	nextRetryAt := now.Add(delay)
+	message := err.Error()
+	s.health.Status = HealthDegraded
+	s.health.LastError = &message
+	s.health.NextRetryAt = &nextRetryAt
+
+	log.Printf("Scheduler failed to start (attempt %d), retrying in %s: %v", s.health.Attempts, delay, err)
+	s.events.Publish(events.TypeSchedulerDegraded, map[string]interface{}{
+		"attempts":      s.health.Attempts,
+		"error":         message,
+		"next_retry_at": nextRetryAt,
+	})
+	return err
+}
+
+// Health returns the state of the scheduler and the number of registered jobs
+func (s *Scheduler) Health() Health {
+	s.mu.Lock()
+	health := s.health
+	s.mu.Unlock()
+
+	health.Jobs = s.JobCount()
+	return health
+}
+
+// schedule registers every job on the cron
+func (s *Scheduler) schedule() error {
+
 	// Schedule auto-validation job to run every hour
 	// Cron expression: "0 0 * * * *" = at minute 0 of every hour
-	_, err := s.cron.AddFunc("0 0 * * * *", s.runAutoValidation)
+	_, err := s.cron.AddFunc("0 0 * * * *", s.track("auto_validation", s.runAutoValidation))
 	if err != nil {
 		log.Printf("Error scheduling auto-validation job: %v", err)
 		return err
 	}
 
+	// Schedule notification dispatch every 5 minutes
+	// Quiet hours and batching are applied per player by the dispatcher
+	_, err = s.cron.AddFunc("0 */5 * * * *", s.track("notification_dispatch", s.runNotificationDispatch))
+	if err != nil {
+		log.Printf("Error scheduling notification dispatch job: %v", err)
+		return err
+	}
+
+	// Schedule recurring tournament editions every 15 minutes
+	_, err = s.cron.AddFunc("0 */15 * * * *", s.track("tournament_recurrences", s.runTournamentRecurrences))
+	if err != nil {
+		log.Printf("Error scheduling tournament recurrence job: %v", err)
+		return err
+	}
+
+	// Schedule external match imports every day at 4am
+	_, err = s.cron.AddFunc("0 0 4 * * *", s.track("match_imports", s.runMatchImports))
+	if err != nil {
+		log.Printf("Error scheduling match import job: %v", err)
+		return err
+	}
+
+	// Schedule anomaly detection on recent results every 15 minutes
+	_, err = s.cron.AddFunc("0 */15 * * * *", s.track("anomaly_detection", s.runAnomalyDetection))
+	if err != nil {
+		log.Printf("Error scheduling anomaly detection job: %v", err)
+		return err
+	}
+
+	// Refresh the leaderboard view every minute when results changed
+	_, err = s.cron.AddFunc("0 * * * * *", s.track("leaderboard_refresh", s.runLeaderboardRefresh))
+	if err != nil {
+		log.Printf("Error scheduling leaderboard refresh job: %v", err)
+		return err
+	}
+
+	// Move old ELO history entries to the archive every day at 3:30am
+	_, err = s.cron.AddFunc("0 30 3 * * *", s.track("elo_history_archive", s.runEloHistoryArchive))
+	if err != nil {
+		log.Printf("Error scheduling ELO history archive job: %v", err)
+		return err
+	}
+
+	// Give the awards of the previous month every day at 9am, only the first run of the month writes them
+	_, err = s.cron.AddFunc("0 0 9 * * *", s.track("monthly_awards", s.runMonthlyAwards))
+	if err != nil {
+		log.Printf("Error scheduling monthly awards job: %v", err)
+		return err
+	}
+
+	// Produce the exports left pending (restart, another instance busy) and delete the expired files every minute
+	_, err = s.cron.AddFunc("30 * * * * *", s.track("exports", s.runExports))
+	if err != nil {
+		log.Printf("Error scheduling exports job: %v", err)
+		return err
+	}
+
+	// Queue the newly confirmed results for the webhook endpoints and send the due deliveries every minute
+	_, err = s.cron.AddFunc("15 * * * * *", s.track("webhook_dispatch", s.runWebhookDispatch))
+	if err != nil {
+		log.Printf("Error scheduling webhook dispatch job: %v", err)
+		return err
+	}
+
+	// Email the data quality report to the admins every Monday at 8am
+	_, err = s.cron.AddFunc("0 0 8 * * 1", s.track("data_quality_report", s.runDataQualityReport))
+	if err != nil {
+		log.Printf("Error scheduling data quality report job: %v", err)
+		return err
+	}
+
 	// You can add more scheduled jobs here in the future
 	// Example: cleanup job, statistics calculation, etc.
 
-	s.cron.Start()
-	log.Println("Cron scheduler started successfully")
-
 	return nil
 }
 
 // Stop gracefully shuts down the scheduler
 func (s *Scheduler) Stop() {
 	log.Println("Stopping cron scheduler...")
+
+	s.mu.Lock()
+	if s.stopRetry != nil {
+		close(s.stopRetry)
+		s.stopRetry = nil
+	}
+	s.health = Health{Status: HealthStopped}
+	s.mu.Unlock()
+
 	s.cron.Stop()
 	log.Println("Cron scheduler stopped")
 }
 
 // runAutoValidation is the job function that validates expired matches
-func (s *Scheduler) runAutoValidation() {
+func (s *Scheduler) runAutoValidation() error {
 	log.Println("Running auto-validation job...")
 
 	// Check how many expired matches we have before processing
 	expiredCount, err := s.autoValidationService.GetExpiredMatchesCount()
 	if err != nil {
 		log.Printf("Error checking expired matches count: %v", err)
-		return
+		return err
 	}
 
 	if expiredCount == 0 {
 		log.Println("No expired matches to validate")
-		return
+		return nil
 	}
 
 	log.Printf("Found %d expired matches to validate", expiredCount)
@@ -72,14 +310,224 @@ func (s *Scheduler) runAutoValidation() {
 	err = s.autoValidationService.ValidateExpiredMatches()
 	if err != nil {
 		log.Printf("Error during auto-validation: %v", err)
-		return
+		return err
 	}
 
 	log.Println("Auto-validation job completed successfully")
+	return nil
+}
+
+// runNotificationDispatch delivers pending notifications that are ready to be sent
+func (s *Scheduler) runNotificationDispatch() error {
+	delivered, err := s.notificationService.DispatchPending(time.Now())
+	if err != nil {
+		log.Printf("Error during notification dispatch: %v", err)
+		return err
+	}
+
+	if delivered > 0 {
+		log.Printf("Delivered %d notifications", delivered)
+	}
+	return nil
+}
+
+// runTournamentRecurrences creates the next edition of recurring tournaments that are due
+func (s *Scheduler) runTournamentRecurrences() error {
+	created, err := s.recurrenceService.CreateDueEditions(time.Now())
+	if err != nil {
+		log.Printf("Error during tournament recurrence job: %v", err)
+		return err
+	}
+
+	if created > 0 {
+		log.Printf("Created %d recurring tournament editions", created)
+	}
+	return nil
+}
+
+// runMatchImports pulls the matches of every enabled import source
+func (s *Scheduler) runMatchImports() error {
+	imported, err := s.importService.RunEnabledSources(context.Background())
+	if err != nil {
+		log.Printf("Error during match import job: %v", err)
+		return err
+	}
+
+	log.Printf("Imported %d external matches", imported)
+	return nil
+}
+
+// runAnomalyDetection flags suspicious results into the admin review queue
+func (s *Scheduler) runAnomalyDetection() error {
+	flagged, err := s.anomalyService.DetectAnomalies(time.Now())
+	if err != nil {
+		log.Printf("Error during anomaly detection: %v", err)
+		return err
+	}
+
+	if flagged > 0 {
+		log.Printf("Flagged %d suspicious matches for review", flagged)
+	}
+	return nil
+}
+
+// runLeaderboardRefresh recomputes the leaderboard view after confirmations
+func (s *Scheduler) runLeaderboardRefresh() error {
+	if _, err := s.leaderboardService.RefreshIfStale(); err != nil {
+		log.Printf("Error refreshing leaderboard: %v", err)
+		return err
+	}
+	return nil
+}
+
+// runEloHistoryArchive moves the ELO history entries past the archive age to elo_history_archive
+func (s *Scheduler) runEloHistoryArchive() error {
+	archived, err := s.eloHistoryService.ArchiveOldEntries(time.Now())
+	if err != nil {
+		log.Printf("Error archiving ELO history: %v", err)
+		return err
+	}
+
+	if archived > 0 {
+		log.Printf("Archived %d ELO history entries", archived)
+	}
+	return nil
+}
+
+// runMonthlyAwards gives the awards of the previous month, once
+func (s *Scheduler) runMonthlyAwards() error {
+	awards, err := s.monthlyAwardService.AwardPreviousMonth(time.Now())
+	if err != nil {
+		log.Printf("Error giving monthly awards: %v", err)
+		return err
+	}
+
+	if len(awards) > 0 {
+		log.Printf("Gave %d monthly awards", len(awards))
+	}
+	return nil
+}
+
+// runExports produces the pending exports and deletes the files past their expiry
+func (s *Scheduler) runExports() error {
+	deleted, err := s.exportService.CleanupExpired(time.Now())
+	if err != nil {
+		log.Printf("Error cleaning up exports: %v", err)
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d expired exports", deleted)
+	}
+
+	done, err := s.exportService.RunPending()
+	if err != nil {
+		log.Printf("Error running exports: %v", err)
+		return err
+	}
+	if done > 0 {
+		log.Printf("Produced %d exports", done)
+	}
+	return nil
+}
+
+// runWebhookDispatch sends the webhook deliveries that are due, retries included
+func (s *Scheduler) runWebhookDispatch() error {
+	delivered, err := s.webhookService.Dispatch(time.Now())
+	if err != nil {
+		log.Printf("Error during webhook dispatch: %v", err)
+		return err
+	}
+
+	if delivered > 0 {
+		log.Printf("Delivered %d webhooks", delivered)
+	}
+	return nil
+}
+
+// runDataQualityReport runs the integrity checks and emails the failing ones to the admins
+func (s *Scheduler) runDataQualityReport() error {
+	sent, err := s.integrityService.SendWeeklyReport(time.Now())
+	if err != nil {
+		log.Printf("Error during data quality report: %v", err)
+		return err
+	}
+
+	if sent > 0 {
+		log.Printf("Sent the data quality report to %d admins", sent)
+	}
+	return nil
+}
+
+// JobCount returns the number of registered jobs, zero until Start succeeded
+func (s *Scheduler) JobCount() int {
+	return len(s.cron.Entries())
+}
+
+// jobs lists the scheduled jobs by name, so that an admin can also run them by hand
+func (s *Scheduler) jobs() map[string]func() error {
+	return map[string]func() error{
+		"auto_validation":        s.runAutoValidation,
+		"notification_dispatch":  s.runNotificationDispatch,
+		"tournament_recurrences": s.runTournamentRecurrences,
+		"match_imports":          s.runMatchImports,
+		"anomaly_detection":      s.runAnomalyDetection,
+		"leaderboard_refresh":    s.runLeaderboardRefresh,
+		"elo_history_archive":    s.runEloHistoryArchive,
+		"monthly_awards":         s.runMonthlyAwards,
+		"exports":                s.runExports,
+		"webhook_dispatch":       s.runWebhookDispatch,
+		"data_quality_report":    s.runDataQualityReport,
+	}
+}
+
+// JobNames returns the names of the jobs that can be run by hand, sorted
+func (s *Scheduler) JobNames() []string {
+	names := make([]string, 0, len(s.jobs()))
+	for name := range s.jobs() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RunJob runs a job by hand and returns its error. The run is reported to the admin console like a
+// scheduled one.
+func (s *Scheduler) RunJob(name string) error {
+	job, ok := s.jobs()[name]
+	if !ok {
+		return errors.New("job not found")
+	}
+
+	log.Printf("Manually triggering %s job...", name)
+	var jobErr error
+	s.track(name, func() error {
+		jobErr = job()
+		return jobErr
+	})()
+	return jobErr
 }
 
 // RunNow manually triggers the auto-validation job (useful for testing)
 func (s *Scheduler) RunNow() {
 	log.Println("Manually triggering auto-validation job...")
-	s.runAutoValidation()
+	s.track("auto_validation", s.runAutoValidation)()
+}
+
+// track wraps a job so that each run is reported to the admin console with its duration and outcome
+func (s *Scheduler) track(name string, job func() error) func() {
+	return func() {
+		startedAt := time.Now()
+		err := job()
+
+		data := map[string]interface{}{
+			"job":         name,
+			"started_at":  startedAt,
+			"duration_ms": time.Since(startedAt).Milliseconds(),
+			"success":     err == nil,
+		}
+		if err != nil {
+			data["error"] = err.Error()
+		}
+		s.events.Publish(events.TypeSchedulerRun, data)
+	}
 }
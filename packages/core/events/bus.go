@@ -0,0 +1,94 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types streamed to the admin console
+const (
+	TypeSchedulerRun         = "scheduler.run"
+	TypeSchedulerDegraded    = "scheduler.degraded"
+	TypeSchedulerRecovered   = "scheduler.recovered"
+	TypeAutoValidationResult = "auto_validation.result"
+	TypeDeliveryFailed       = "delivery.failed"
+	TypeErrorRateSpike       = "error_rate.spike"
+	TypeSlowQuery            = "slow_query.alert"
+	TypePanic                = "panic"
+	TypePasswordResetCapped  = "password_reset.capped"
+	TypeDisplayNameChanged   = "display_name.changed"
+	TypeHeartbeat            = "heartbeat"
+)
+
+const (
+	// subscriberBuffer is how many events a slow subscriber can lag behind before events are dropped for it
+	subscriberBuffer = 64
+	// recentSize is how many past events a new subscriber receives on connection
+	recentSize = 50
+)
+
+// Event is one entry of the admin console feed
+type Event struct {
+	Type string                 `json:"type"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// Bus fans out operational events to the connected admin consoles.
+// A nil *Bus is valid and drops everything, so services can be built without one.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	recent      []Event
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish sends an event to every subscriber without blocking, subscribers that are full miss it
+func (b *Bus) Publish(eventType string, data map[string]interface{}) {
+	if b == nil {
+		return
+	}
+
+	event := Event{Type: eventType, Time: time.Now(), Data: data}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.recent = append(b.recent, event)
+	if len(b.recent) > recentSize {
+		b.recent = b.recent[len(b.recent)-recentSize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new listener and returns its channel, primed with the recent events,
+// along with the function to call when the listener goes away
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer+recentSize)
+
+	b.mu.Lock()
+	for _, event := range b.recent {
+		ch <- event
+	}
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
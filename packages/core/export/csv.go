@@ -0,0 +1,113 @@
+// Package export builds the rows of the CSV exports, streamed by the list endpoints and written to
+// files by the export jobs
+package export
+
+import (
+	"core/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// text neutralizes user-provided values that a spreadsheet would run as a formula
+func text(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func float(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func timestamp(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func optionalID(value *uint) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*value), 10)
+}
+
+// PlayerHeader is the header of the players export
+var PlayerHeader = []string{
+	"id", "username", "elo_rating", "rank", "total_matches", "wins", "losses", "win_rate",
+	"team_elo_rating", "team_rank", "team_total_matches", "team_wins", "team_losses", "team_win_rate", "created_at",
+}
+
+// PlayerRecord is a player as a row of the players export
+func PlayerRecord(player models.Player) []string {
+	return []string{
+		strconv.FormatUint(uint64(player.ID), 10),
+		text(player.Username),
+		float(player.EloRating),
+		strconv.Itoa(player.Rank),
+		strconv.Itoa(player.TotalMatches),
+		strconv.Itoa(player.Wins),
+		strconv.Itoa(player.Losses),
+		float(player.WinRate),
+		float(player.TeamEloRating),
+		strconv.Itoa(player.TeamRank),
+		strconv.Itoa(player.TeamTotalMatches),
+		strconv.Itoa(player.TeamWins),
+		strconv.Itoa(player.TeamLosses),
+		float(player.TeamWinRate),
+		timestamp(&player.CreatedAt),
+	}
+}
+
+// MatchHeader is the header of the matches export
+var MatchHeader = []string{
+	"id", "created_at", "confirmed_at", "status", "player1_id", "player1", "player2_id", "player2",
+	"winner_id", "winner", "tournament_id",
+}
+
+// MatchRecord is a match as a row of the matches export, the players must be loaded
+func MatchRecord(match models.Match) []string {
+	return []string{
+		strconv.FormatUint(uint64(match.ID), 10),
+		timestamp(&match.CreatedAt),
+		timestamp(match.ConfirmedAt),
+		match.Status,
+		strconv.FormatUint(uint64(match.Player1ID), 10),
+		text(match.Player1.Username),
+		strconv.FormatUint(uint64(match.Player2ID), 10),
+		text(match.Player2.Username),
+		strconv.FormatUint(uint64(match.WinnerID), 10),
+		text(match.Winner.Username),
+		optionalID(match.TournamentID),
+	}
+}
+
+// EloHistoryHeader is the header of the ELO history export
+var EloHistoryHeader = []string{
+	"id", "created_at", "player_id", "player", "kind", "match_id", "opponent_id", "opponent",
+	"elo_before", "elo_after", "elo_change",
+}
+
+// EloHistoryRecord is an ELO history entry as a row of the ELO history export, the player and opponent must be loaded
+func EloHistoryRecord(entry models.EloHistory) []string {
+	opponent := ""
+	if entry.Opponent != nil {
+		opponent = entry.Opponent.Username
+	}
+	return []string{
+		strconv.FormatUint(uint64(entry.ID), 10),
+		timestamp(&entry.CreatedAt),
+		strconv.FormatUint(uint64(entry.PlayerID), 10),
+		text(entry.Player.Username),
+		entry.Kind,
+		optionalID(entry.MatchID),
+		optionalID(entry.OpponentID),
+		text(opponent),
+		float(entry.EloBefore),
+		float(entry.EloAfter),
+		float(entry.EloChange),
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"core/events"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// consoleHeartbeat keeps idle console connections alive through proxies
+const consoleHeartbeat = 30 * time.Second
+
+type AdminConsoleHandler struct {
+	events *events.Bus
+}
+
+func NewAdminConsoleHandler(bus *events.Bus) *AdminConsoleHandler {
+	return &AdminConsoleHandler{
+		events: bus,
+	}
+}
+
+// Stream opens the realtime channel of the admin dashboard
+// @Summary Admin console realtime channel
+// @Description Upgrade to a WebSocket streaming operational events as JSON messages {type, time, data}: scheduler runs (scheduler.run), auto-validation results (auto_validation.result), email delivery failures reported by the mail provider webhook or the notification dispatcher (delivery.failed) and 5xx error-rate spikes (error_rate.spike), and the password reset emails reaching their global hourly cap (password_reset.capped). The last events are replayed on connection, a heartbeat message is sent every 30 seconds. The JWT can be passed in the token query parameter since browsers cannot set headers on WebSocket connections (admin only).
+// @Tags admin
+// @Security BearerAuth
+// @Param token query string false "JWT access token, when the Authorization header cannot be set"
+// @Success 101 {object} events.Event
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/console [get]
+func (h *AdminConsoleHandler) Stream(c *gin.Context) {
+	server := websocket.Server{Handler: h.serve}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *AdminConsoleHandler) serve(ws *websocket.Conn) {
+	defer ws.Close()
+
+	feed, unsubscribe := h.events.Subscribe()
+	defer unsubscribe()
+
+	// The console does not send anything, reading only detects when it disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard string
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	heartbeat := time.NewTicker(consoleHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-feed:
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				return
+			}
+		case now := <-heartbeat.C:
+			if err := websocket.JSON.Send(ws, events.Event{Type: events.TypeHeartbeat, Time: now}); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
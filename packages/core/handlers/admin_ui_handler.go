@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminUIPage is the admin page, a single HTML file with its script inlined so that a deploy is
+// never served a stale asset from the static cache
+//
+//go:embed adminui/index.html
+var adminUIPage []byte
+
+type AdminUIHandler struct{}
+
+func NewAdminUIHandler() *AdminUIHandler {
+	return &AdminUIHandler{}
+}
+
+// Page serves the embedded admin page
+// @Summary Admin UI
+// @Description Minimal admin page embedded in the API: review of suspicious matches, manual job runs and common settings. The page itself is public, it logs in through /auth/login and every operation goes through the admin endpoints.
+// @Tags admin
+// @Produce html
+// @Success 200 {string} string
+// @Router /admin/ui [get]
+func (h *AdminUIHandler) Page(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", adminUIPage)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnomalyHandler struct {
+	anomalyService *services.AnomalyService
+}
+
+func NewAnomalyHandler(anomalyService *services.AnomalyService) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomalyService: anomalyService,
+	}
+}
+
+// GetAnomalies lists the anomaly review queue
+// @Summary Get match anomalies
+// @Description Get the suspicious matches flagged by the detection job (rapid rematches, upset streaks), most recent first (admin only)
+// @Tags anomalies
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Review status (default: open)" Enums(open, dismissed, confirmed)
+// @Success 200 {array} models.MatchAnomaly
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /anomalies [get]
+func (h *AnomalyHandler) GetAnomalies(c *gin.Context) {
+	status := c.DefaultQuery("status", models.AnomalyStatusOpen)
+	if status != models.AnomalyStatusOpen && status != models.AnomalyStatusDismissed && status != models.AnomalyStatusConfirmed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status parameter"})
+		return
+	}
+
+	anomalies, err := h.anomalyService.GetAnomalies(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, anomalies)
+}
+
+// ReviewAnomaly closes an anomaly of the review queue
+// @Summary Review a match anomaly
+// @Description Dismiss an anomaly (the match is released if it was held) or confirm it (the match is rejected if still pending) (admin only)
+// @Tags anomalies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Anomaly ID"
+// @Param review body models.ReviewAnomalyRequest true "Review decision"
+// @Success 200 {object} models.MatchAnomaly
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /anomalies/{id} [patch]
+func (h *AnomalyHandler) ReviewAnomaly(c *gin.Context) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anomaly ID"})
+		return
+	}
+
+	var req models.ReviewAnomalyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	anomaly, err := h.anomalyService.ReviewAnomaly(uint(id), userID, req.Status)
+	if err != nil {
+		switch err.Error() {
+		case "anomaly not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "anomaly already reviewed":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, anomaly)
+}
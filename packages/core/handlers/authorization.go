@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+
+	authModels "auth/models"
+
+	"gorm.io/gorm"
+)
+
+// isAdmin reports whether the user has the admin role
+func isAdmin(db *gorm.DB, userID uint) bool {
+	var user authModels.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return false
+	}
+	return user.HasRole(authModels.RoleAdmin)
+}
+
+// checkPlayersOrAdmin returns an "unauthorized" error unless the user is one of the players or an admin
+func checkPlayersOrAdmin(db *gorm.DB, userID uint, playerIDs ...uint) error {
+	// Check if user is one of the players (user_id = player_id)
+	for _, playerID := range playerIDs {
+		if userID == playerID {
+			return nil
+		}
+	}
+
+	// Check if user is admin
+	var user authModels.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	if user.HasRole(authModels.RoleAdmin) {
+		return nil
+	}
+
+	return errors.New("unauthorized")
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientConfigHandler struct {
+	clientConfigService *services.ClientConfigService
+}
+
+func NewClientConfigHandler(clientConfigService *services.ClientConfigService) *ClientConfigHandler {
+	return &ClientConfigHandler{
+		clientConfigService: clientConfigService,
+	}
+}
+
+// GetClientConfig returns the bootstrap configuration of the mobile app
+// @Summary Get the client configuration
+// @Description Get the feature flags, auto-validation window, ELO floor and floor mode, daily match limit, supported auth providers and minimum client version, for the mobile app to read on startup instead of hard-coding them
+// @Tags client
+// @Produce json
+// @Success 200 {object} models.ClientConfig
+// @Router /client-config [get]
+func (h *ClientConfigHandler) GetClientConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.clientConfigService.GetClientConfig())
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientVersionHandler struct {
+	clientVersionService *services.ClientVersionService
+}
+
+func NewClientVersionHandler(clientVersionService *services.ClientVersionService) *ClientVersionHandler {
+	return &ClientVersionHandler{
+		clientVersionService: clientVersionService,
+	}
+}
+
+// GetSetting gets the client version policy
+// @Summary Get the client version policy
+// @Description Get the minimum app version and the blocked versions; apps sending an older or blocked X-Client-Version get 426 Upgrade Required (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.ClientVersionSetting
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/client-versions [get]
+func (h *ClientVersionHandler) GetSetting(c *gin.Context) {
+	setting, err := h.clientVersionService.GetSetting()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// UpdateSetting replaces the client version policy
+// @Summary Update the client version policy
+// @Description Set the minimum app version (empty falls back to MIN_CLIENT_VERSION), the known-broken versions to block and the message shown on the upgrade screen. Applied within 30 seconds (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param policy body models.UpdateClientVersionSettingRequest true "Client version policy"
+// @Success 200 {object} models.ClientVersionSetting
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/client-versions [put]
+func (h *ClientVersionHandler) UpdateSetting(c *gin.Context) {
+	var req models.UpdateClientVersionSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	setting, err := h.clientVersionService.UpdateSetting(req)
+	if err != nil {
+		if err.Error() == "invalid version" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const csvContentType = "text/csv"
+
+// wantsCSV reports whether the Accept header asks for CSV (q=0 refuses it). List endpoints answer
+// JSON or CSV on the same URL, so the response varies on Accept for the caches.
+func wantsCSV(c *gin.Context) bool {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(mediaType) != csvContentType {
+			continue
+		}
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// csvStream writes the rows of a CSV download
+type csvStream struct {
+	c      *gin.Context
+	writer *csv.Writer
+}
+
+func (s *csvStream) Write(record []string) {
+	s.writer.Write(record)
+}
+
+// Flush sends the rows written so far, call it after each batch so that large lists are streamed
+func (s *csvStream) Flush() error {
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// streamCSV answers with a CSV attachment filled by write. An error before anything was sent becomes
+// a JSON 500 with errorMessage, a later one can only truncate the download and is logged.
+func streamCSV(c *gin.Context, filename string, header []string, errorMessage string, write func(*csvStream) error) {
+	c.Header("Content-Type", csvContentType+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	stream := &csvStream{c: c, writer: csv.NewWriter(c.Writer)}
+	stream.Write(header)
+
+	err := write(stream)
+	if err == nil {
+		err = stream.Flush()
+	}
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": errorMessage,
+		})
+		return
+	}
+	log.Printf("CSV export %s interrupted: %v", filename, err)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebugLogHandler struct {
+	debugLogService *services.DebugLogService
+}
+
+func NewDebugLogHandler(debugLogService *services.DebugLogService) *DebugLogHandler {
+	return &DebugLogHandler{
+		debugLogService: debugLogService,
+	}
+}
+
+// GetDebugLogRules lists the debug log rules
+// @Summary Get debug log rules
+// @Description Get the routes whose request/response bodies are or were captured, most recent first (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.DebugLogRule
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/debug-logs/rules [get]
+func (h *DebugLogHandler) GetDebugLogRules(c *gin.Context) {
+	rules, err := h.debugLogService.GetRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateDebugLogRule starts capturing a route
+// @Summary Create a debug log rule
+// @Description Capture the request and response bodies of a route (method and route pattern, e.g. POST /matches or GET /players/:id) for 1 to 240 minutes. Bodies are stored with passwords, tokens, secrets, names and email addresses redacted; non-JSON bodies are not kept (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param rule body models.CreateDebugLogRuleRequest true "Route and duration"
+// @Success 201 {object} models.DebugLogRule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/debug-logs/rules [post]
+func (h *DebugLogHandler) CreateDebugLogRule(c *gin.Context) {
+	var req models.CreateDebugLogRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	rule, err := h.debugLogService.CreateRule(adminID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// DeleteDebugLogRule stops a capture
+// @Summary Delete a debug log rule
+// @Description Stop capturing a route and delete the entries collected by the rule (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/debug-logs/rules/{id} [delete]
+func (h *DebugLogHandler) DeleteDebugLogRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	if err := h.debugLogService.DeleteRule(uint(id)); err != nil {
+		if err.Error() == "debug log rule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Debug log rule deleted successfully"})
+}
+
+// GetDebugLogEntries lists the exchanges captured by a rule
+// @Summary Get debug log entries
+// @Description Get the sanitized request/response bodies captured by a rule, most recent first (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param limit query int false "Number of entries (default: 50, max: 500)"
+// @Success 200 {array} models.DebugLogEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/debug-logs/rules/{id}/entries [get]
+func (h *DebugLogHandler) GetDebugLogEntries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	entries, err := h.debugLogService.GetEntries(uint(id), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
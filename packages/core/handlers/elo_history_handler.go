@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"core/export"
 	"core/services"
 	"net/http"
 	"strconv"
@@ -20,9 +21,9 @@ func NewEloHistoryHandler(eloHistoryService *services.EloHistoryService) *EloHis
 
 // GetRecentEloChanges retrieves recent ELO changes for all players
 // @Summary Get recent ELO changes
-// @Description Get recent ELO changes for all players ordered by date (newest first)
+// @Description Get recent ELO changes for all players ordered by date (newest first). Send Accept: text/csv for a CSV download.
 // @Tags elo-history
-// @Produce json
+// @Produce json,text/csv
 // @Param limit query int false "Number of ELO changes to retrieve (default: 10, max: 100)"
 // @Success 200 {array} models.EloHistory
 // @Failure 400 {object} map[string]string
@@ -51,5 +52,15 @@ func (h *EloHistoryHandler) GetRecentEloChanges(c *gin.Context) {
 		return
 	}
 
+	if wantsCSV(c) {
+		streamCSV(c, "elo-history.csv", export.EloHistoryHeader, "Failed to retrieve recent ELO changes", func(stream *csvStream) error {
+			for _, entry := range eloChanges {
+				stream.Write(export.EloHistoryRecord(entry))
+			}
+			return nil
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, eloChanges)
 }
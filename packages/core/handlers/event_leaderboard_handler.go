@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventLeaderboardHandler struct {
+	eventService *services.EventLeaderboardService
+}
+
+func NewEventLeaderboardHandler(eventService *services.EventLeaderboardService) *EventLeaderboardHandler {
+	return &EventLeaderboardHandler{
+		eventService: eventService,
+	}
+}
+
+// GetEvents lists the event leaderboards
+// @Summary Get event leaderboards
+// @Description Get the temporary event ladders (e.g. Integration Week) with their window, status (upcoming, live, ended) and, for opt-in events, the number of participants, most recent first
+// @Tags event-leaderboards
+// @Produce json
+// @Success 200 {array} models.EventLeaderboard
+// @Failure 500 {object} map[string]string
+// @Router /event-leaderboards [get]
+func (h *EventLeaderboardHandler) GetEvents(c *gin.Context) {
+	events, err := h.eventService.GetEvents(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetStandings gets the standings of an event leaderboard
+// @Summary Get event standings
+// @Description Get the standings of an event: the confirmed solo matches of its window are replayed with an event rating starting at 1200, without the ELO floor, and players are ranked by rating then wins. For opt-in events only the matches between two participants count. The main ELO is not affected
+// @Tags event-leaderboards
+// @Produce json
+// @Param id path int true "Event leaderboard ID"
+// @Success 200 {object} models.EventStandingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /event-leaderboards/{id}/standings [get]
+func (h *EventLeaderboardHandler) GetStandings(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event leaderboard ID"})
+		return
+	}
+
+	standings, err := h.eventService.GetStandings(uint(id), time.Now())
+	if err != nil {
+		if err.Error() == "event leaderboard not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// CreateEvent creates an event leaderboard
+// @Summary Create an event leaderboard
+// @Description Create a temporary ladder over a time window; with opt_in, players join it and only their matches against other participants count (admin only)
+// @Tags event-leaderboards
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param event body models.CreateEventLeaderboardRequest true "Event name, window and opt-in"
+// @Success 201 {object} models.EventLeaderboard
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /event-leaderboards [post]
+func (h *EventLeaderboardHandler) CreateEvent(c *gin.Context) {
+	var req models.CreateEventLeaderboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	event, err := h.eventService.CreateEvent(req, adminID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// UpdateEvent updates an event leaderboard
+// @Summary Update an event leaderboard
+// @Description Rename an event, move its window or change its opt-in; the standings are computed on read and follow at once (admin only)
+// @Tags event-leaderboards
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Event leaderboard ID"
+// @Param event body models.UpdateEventLeaderboardRequest true "Event update data"
+// @Success 200 {object} models.EventLeaderboard
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /event-leaderboards/{id} [put]
+func (h *EventLeaderboardHandler) UpdateEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event leaderboard ID"})
+		return
+	}
+
+	var req models.UpdateEventLeaderboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	event, err := h.eventService.UpdateEvent(uint(id), req, time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "event leaderboard not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "ends_at must be after starts_at":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// DeleteEvent deletes an event leaderboard
+// @Summary Delete an event leaderboard
+// @Description Delete an event and its participants; matches and ratings are untouched (admin only)
+// @Tags event-leaderboards
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Event leaderboard ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /event-leaderboards/{id} [delete]
+func (h *EventLeaderboardHandler) DeleteEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event leaderboard ID"})
+		return
+	}
+
+	if err := h.eventService.DeleteEvent(uint(id)); err != nil {
+		if err.Error() == "event leaderboard not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event leaderboard deleted successfully"})
+}
+
+// Join registers the current player in an opt-in event
+// @Summary Join an event leaderboard
+// @Description Join an opt-in event until it ends; matches between participants confirmed within the window count, including those played before joining
+// @Tags event-leaderboards
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Event leaderboard ID"
+// @Success 201 {object} models.EventLeaderboardParticipant
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /event-leaderboards/{id}/participants [post]
+func (h *EventLeaderboardHandler) Join(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event leaderboard ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	participant, err := h.eventService.Join(uint(id), userID, time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "event leaderboard not found", "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "event is not opt-in", "event has ended":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "already joined":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, participant)
+}
+
+// Leave removes the current player from an opt-in event
+// @Summary Leave an event leaderboard
+// @Description Leave an opt-in event, your matches stop counting in its standings
+// @Tags event-leaderboards
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Event leaderboard ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /event-leaderboards/{id}/participants [delete]
+func (h *EventLeaderboardHandler) Leave(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event leaderboard ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := h.eventService.Leave(uint(id), userID); err != nil {
+		if err.Error() == "not joined" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left the event leaderboard"})
+}
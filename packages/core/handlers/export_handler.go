@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportService
+	db            *gorm.DB
+}
+
+func NewExportHandler(exportService *services.ExportService, db *gorm.DB) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		db:            db,
+	}
+}
+
+// CreateExport queues an export
+// @Summary Request an export
+// @Description Queue a large export produced in the background instead of a synchronous download: full match history (matches, CSV, optional player_id, status, date_from, date_to), every player (players, CSV) or everything stored about a player and their account (personal_data, JSON, the requester's own player, any player for an admin). Poll GET /exports/{id} until the status is done, then download the file from download_url. At most 3 exports in progress per user
+// @Tags exports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param export body models.CreateExportRequest true "Export"
+// @Success 202 {object} models.ExportJob
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exports [post]
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	job, err := h.exportService.CreateJob(userID, isAdmin(h.db, userID), req)
+	if err != nil {
+		switch err.Error() {
+		case "unauthorized":
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only export your own personal data or you must be an admin"})
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "too many exports in progress":
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case "invalid date, use YYYY-MM-DD":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create export"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetExports lists the exports of the current user
+// @Summary List my exports
+// @Description Get the last 50 exports requested by the current user, most recent first, with their download URL once done
+// @Tags exports
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.ExportJob
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exports [get]
+func (h *ExportHandler) GetExports(c *gin.Context) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	jobs, err := h.exportService.GetJobs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetExport returns the status of an export
+// @Summary Get export status
+// @Description Get the status of an export (pending, running, done, failed, expired) and, once done, its signed download URL, valid until expires_at (requester or admin)
+// @Tags exports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Export ID"
+// @Success 200 {object} models.ExportJob
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	job, err := h.exportService.GetJob(uint(id), userID, isAdmin(h.db, userID))
+	if err != nil {
+		if err.Error() == "export not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve export"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadExport sends the file of an export
+// @Summary Download an export
+// @Description Download the file of a finished export. The URL is the download_url of the export: its signature authenticates the request, so it can be opened directly by the browser until it expires
+// @Tags exports
+// @Produce octet-stream
+// @Param id path int true "Export ID"
+// @Param expires query int true "Expiry of the link (Unix time)"
+// @Param signature query string true "Signature of the link"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expires parameter"})
+		return
+	}
+
+	job, err := h.exportService.OpenDownload(uint(id), expires, c.Query("signature"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid signature":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "export not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "download link expired":
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve export"})
+		}
+		return
+	}
+
+	// Personal data must not be kept by the reverse proxy, even behind an anonymous URL
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(job.FilePath, job.FileName)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HallOfFameHandler struct {
+	hallOfFameService *services.HallOfFameService
+}
+
+func NewHallOfFameHandler(hallOfFameService *services.HallOfFameService) *HallOfFameHandler {
+	return &HallOfFameHandler{
+		hallOfFameService: hallOfFameService,
+	}
+}
+
+// GetHallOfFame retrieves the all-time records
+// @Summary Get hall of fame
+// @Description Get the all-time record holders: highest solo and team ELO ever reached, longest win streak, most tournament wins and most matches played. Refreshed every 10 minutes.
+// @Tags stats
+// @Produce json
+// @Success 200 {object} models.HallOfFame
+// @Failure 500 {object} map[string]string
+// @Router /hall-of-fame [get]
+func (h *HallOfFameHandler) GetHallOfFame(c *gin.Context) {
+	hallOfFame, err := h.hallOfFameService.GetHallOfFame()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve hall of fame",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hallOfFame)
+}
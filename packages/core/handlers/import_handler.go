@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ImportHandler struct {
+	importService *services.ImportService
+	db            *gorm.DB
+}
+
+func NewImportHandler(importService *services.ImportService, db *gorm.DB) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		db:            db,
+	}
+}
+
+// GetSources lists the import sources
+// @Summary Get import sources
+// @Description List the external match sources with the outcome of their last import (admin only)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.ImportSource
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /import-sources [get]
+func (h *ImportHandler) GetSources(c *gin.Context) {
+	sources, err := h.importService.GetSources()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sources)
+}
+
+// CreateSource creates an import source
+// @Summary Create an import source
+// @Description Declare an external match source read by the generic JSON or CSV connector; sources with a URL are pulled daily (admin only)
+// @Tags imports
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param source body models.CreateImportSourceRequest true "Source data"
+// @Success 201 {object} models.ImportSource
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /import-sources [post]
+func (h *ImportHandler) CreateSource(c *gin.Context) {
+	var req models.CreateImportSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	source, err := h.importService.CreateSource(req)
+	if err != nil {
+		if err.Error() == "import source already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, source)
+}
+
+// DeleteSource deletes an import source
+// @Summary Delete an import source
+// @Description Delete an import source and the matches imported from it (admin only)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Source ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /import-sources/{id} [delete]
+func (h *ImportHandler) DeleteSource(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+		return
+	}
+
+	if err := h.importService.DeleteSource(uint(id)); err != nil {
+		if err.Error() == "import source not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Import source deleted successfully"})
+}
+
+// RunSource pulls the matches of a source now
+// @Summary Run an import
+// @Description Pull the matches of a source from its URL now (admin only)
+// @Tags imports
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Source ID"
+// @Success 200 {object} models.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /import-sources/{id}/run [post]
+func (h *ImportHandler) RunSource(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+		return
+	}
+
+	result, err := h.importService.RunSource(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err.Error() {
+		case "import source not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "import source has no URL":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UploadFile imports a file of matches
+// @Summary Upload matches
+// @Description Import a JSON or CSV file (multipart field `file`, or the raw request body) in the format of the source's connector (admin only)
+// @Tags imports
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Source ID"
+// @Param file formData file false "Matches file"
+// @Success 200 {object} models.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /import-sources/{id}/upload [post]
+func (h *ImportHandler) UploadFile(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+		return
+	}
+
+	body := c.Request.Body
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.importService.ImportFile(uint(id), body)
+	if err != nil {
+		if err.Error() == "import source not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetExternalIDs gets the external identifiers of a player
+// @Summary Get player external identifiers
+// @Description Get the identifiers of a player in external sources
+// @Tags players
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {array} models.PlayerExternalID
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/external-ids [get]
+func (h *ImportHandler) GetExternalIDs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	mappings, err := h.importService.GetExternalIDs(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, mappings)
+}
+
+// AddExternalID maps a player to an external identifier
+// @Summary Add player external identifier
+// @Description Map a player to their identifier in an external source (e.g. federation licence number), so that their external matches are imported (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param mapping body models.AddPlayerExternalIDRequest true "Source and identifier"
+// @Success 201 {object} models.PlayerExternalID
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/external-ids [post]
+func (h *ImportHandler) AddExternalID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only add your own identifiers"})
+		return
+	}
+
+	var req models.AddPlayerExternalIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	mapping, err := h.importService.AddExternalID(uint(id), req)
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player already has an identifier for this source", "external identifier already belongs to another player":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, mapping)
+}
+
+// RemoveExternalID removes the external identifier of a player for a source
+// @Summary Remove player external identifier
+// @Description Remove the identifier of a player in an external source, already imported matches are kept (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param source path string true "Source name"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /players/{id}/external-ids/{source} [delete]
+func (h *ImportHandler) RemoveExternalID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only remove your own identifiers"})
+		return
+	}
+
+	if err := h.importService.RemoveExternalID(uint(id), c.Param("source")); err != nil {
+		if err.Error() == "external identifier not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "External identifier removed successfully"})
+}
+
+// GetExternalMatches gets the unranked external matches of a player
+// @Summary Get player external matches
+// @Description Get the matches a player played outside the association, imported from external sources; they do not affect ratings
+// @Tags players
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Number of matches per page (default: 10, max: 100)"
+// @Success 200 {object} models.PaginatedExternalMatchesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/external-matches [get]
+func (h *ImportHandler) GetExternalMatches(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pageSize parameter"})
+		return
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	matches, err := h.importService.GetExternalMatches(uint(id), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve external matches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, matches)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IntegrityHandler struct {
+	integrityService *services.IntegrityService
+}
+
+func NewIntegrityHandler(integrityService *services.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{
+		integrityService: integrityService,
+	}
+}
+
+// GetDataQualityReport runs the integrity checks now
+// @Summary Get data quality report
+// @Description Run the integrity checks and return each one with its count and the first 20 offending IDs: matches and team matches pending for more than 48 hours, users without a player, players whose username differs from their user's, teams referencing a deleted player, players and teams with a negative counter. The same report is emailed to the admins every Monday when a check fails (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.DataQualityReport
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/data-quality [get]
+func (h *IntegrityHandler) GetDataQualityReport(c *gin.Context) {
+	report, err := h.integrityService.Report(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
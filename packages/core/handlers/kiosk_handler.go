@@ -0,0 +1,451 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	authMiddleware "auth/middleware"
+	"auth/utils"
+	coreMiddleware "core/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	kioskPairLimit  = 5
+	kioskPairWindow = 15 * time.Minute
+)
+
+type KioskHandler struct {
+	kioskService        *services.KioskService
+	notificationService *services.NotificationService
+	pairLimiter         *utils.RateLimiter
+	db                  *gorm.DB
+}
+
+func NewKioskHandler(kioskService *services.KioskService, notificationService *services.NotificationService, db *gorm.DB) *KioskHandler {
+	return &KioskHandler{
+		kioskService:        kioskService,
+		notificationService: notificationService,
+		pairLimiter:         utils.NewRateLimiter(kioskPairLimit, kioskPairWindow),
+		db:                  db,
+	}
+}
+
+// GetDevices lists the kiosk devices
+// @Summary Get kiosk devices
+// @Description List the registered kiosk devices with their pairing status and last activity (admin only)
+// @Tags kiosk
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.KioskDevice
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /kiosk/devices [get]
+func (h *KioskHandler) GetDevices(c *gin.Context) {
+	devices, err := h.kioskService.GetDevices()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// RegisterDevice registers a kiosk device
+// @Summary Register a kiosk device
+// @Description Register a device and get the PIN to enter on it, valid 10 minutes (admin only)
+// @Tags kiosk
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param device body models.CreateKioskDeviceRequest true "Device name"
+// @Success 201 {object} models.KioskPairingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /kiosk/devices [post]
+func (h *KioskHandler) RegisterDevice(c *gin.Context) {
+	var req models.CreateKioskDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	pairing, err := h.kioskService.RegisterDevice(req, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pairing)
+}
+
+// StartPairing issues a new pairing PIN for a device
+// @Summary Pair a kiosk device again
+// @Description Issue a new PIN for a device, e.g. after a reset; its current token stops working (admin only)
+// @Tags kiosk
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Device ID"
+// @Success 200 {object} models.KioskPairingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /kiosk/devices/{id}/pairing [post]
+func (h *KioskHandler) StartPairing(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	pairing, err := h.kioskService.StartPairing(uint(id))
+	if err != nil {
+		if err.Error() == "device not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pairing)
+}
+
+// RevokeDevice revokes a kiosk device
+// @Summary Revoke a kiosk device
+// @Description Unpair a device, its token is rejected immediately (admin only)
+// @Tags kiosk
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Device ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /kiosk/devices/{id} [delete]
+func (h *KioskHandler) RevokeDevice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	if err := h.kioskService.RevokeDevice(uint(id)); err != nil {
+		if err.Error() == "device not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device revoked successfully"})
+}
+
+// Pair pairs a kiosk device
+// @Summary Pair a kiosk device
+// @Description Exchange the PIN shown to the admin for the kiosk token of the device (5 attempts per 15 minutes)
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param pairing body models.PairKioskDeviceRequest true "PIN"
+// @Success 200 {object} models.KioskTokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /kiosk/pair [post]
+func (h *KioskHandler) Pair(c *gin.Context) {
+	if allowed, retryAfter := h.pairLimiter.Allow(c.ClientIP()); !allowed {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Too many pairing attempts, try again later",
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	var req models.PairKioskDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	pairing, err := h.kioskService.Pair(req.Pin)
+	if err != nil {
+		if err.Error() == "invalid or expired PIN" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pairing)
+}
+
+// GetPlayers lists the players to pick from on the kiosk
+// @Summary Kiosk: get players
+// @Description List the active players by name, to select the two players of a match; archived players are left out (kiosk token required)
+// @Tags kiosk
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Success 200 {array} models.KioskPlayer
+// @Failure 401 {object} map[string]string
+// @Router /kiosk/players [get]
+func (h *KioskHandler) GetPlayers(c *gin.Context) {
+	players, err := h.kioskService.GetPlayers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve players"})
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
+
+// CreateMatch records a match on the kiosk
+// @Summary Kiosk: create a match
+// @Description Record a pending match between two selected players; both players are notified until it is confirmed (kiosk token required). With a client_uuid, sending the request again returns the match recorded the first time (200).
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Param match body models.CreateKioskMatchRequest true "Match data"
+// @Success 200 {object} models.Match
+// @Success 201 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /kiosk/matches [post]
+func (h *KioskHandler) CreateMatch(c *gin.Context) {
+	var req models.CreateKioskMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	// A request sent again with the same client UUID returns the match recorded the first time
+	replayed, err := h.kioskService.FindReplayedMatch(req)
+	if err != nil {
+		if err.Error() == "client_uuid already used" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create match"})
+		}
+		return
+	}
+	if replayed != nil {
+		c.JSON(http.StatusOK, replayed)
+		return
+	}
+
+	deviceID, _ := coreMiddleware.GetKioskDeviceID(c)
+	match, err := h.kioskService.CreateMatch(deviceID, req)
+	if err != nil {
+		switch err.Error() {
+		case "player1 not found", "player2 not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player1 and player2 must be different", "winner must be either player1 or player2":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "daily match limit reached":
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case "client_uuid already used", "player is archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create match"})
+		}
+		return
+	}
+
+	// No creator: both players are asked to confirm
+	h.notificationService.NotifyMatchCreated(match, 0)
+
+	c.JSON(http.StatusCreated, match)
+}
+
+// ConfirmMatch confirms a match at the table
+// @Summary Kiosk: confirm a match
+// @Description Confirm, with both players at the table, a pending match recorded on this device. The kiosk counts as the confirmation of both players, also in two-player confirmation mode where each confirmation is recorded (kiosk token required)
+// @Tags kiosk
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Param id path int true "Match ID"
+// @Success 200 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /kiosk/matches/{id}/confirm [post]
+func (h *KioskHandler) ConfirmMatch(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	deviceID, _ := coreMiddleware.GetKioskDeviceID(c)
+	match, err := h.kioskService.ConfirmMatch(deviceID, uint(matchID))
+	if err != nil {
+		switch err.Error() {
+		case "match not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "match was not recorded on this device":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case "match is not pending":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "match is on hold for review", "scoreboard photo required":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm match"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// ResolveCard identifies the player of a scanned card
+// @Summary Kiosk: resolve a card
+// @Description Get the player a scanned NFC/student card belongs to (kiosk token required)
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Param card body models.CardUIDRequest true "Scanned card UID"
+// @Success 200 {object} models.KioskPlayer
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /kiosk/cards/resolve [post]
+func (h *KioskHandler) ResolveCard(c *gin.Context) {
+	var req models.CardUIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	player, err := h.kioskService.ResolveCard(req.UID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid card UID":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "unknown card":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "card login is not configured":
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// SetPlayerCard associates a card with a player
+// @Summary Associate a card with a player
+// @Description Associate an NFC/student card with a player, replacing their previous card; only a keyed hash of the UID is stored (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param card body models.CardUIDRequest true "Card UID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /players/{id}/card [put]
+func (h *KioskHandler) SetPlayerCard(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only associate your own card"})
+		return
+	}
+
+	var req models.CardUIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	if err := h.kioskService.SetPlayerCard(uint(id), req.UID); err != nil {
+		switch err.Error() {
+		case "invalid card UID":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "card is already associated with another player":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "card login is not configured":
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Card associated successfully"})
+}
+
+// RemovePlayerCard removes the card of a player
+// @Summary Remove a player's card
+// @Description Remove the NFC/student card associated with a player (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /players/{id}/card [delete]
+func (h *KioskHandler) RemovePlayerCard(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only remove your own card"})
+		return
+	}
+
+	if err := h.kioskService.RemovePlayerCard(uint(id)); err != nil {
+		if err.Error() == "player has no card" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Card removed successfully"})
+}
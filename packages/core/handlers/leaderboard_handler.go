@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	authMiddleware "auth/middleware"
+	"core/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *services.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService *services.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// GetLeaderboard retrieves the public leaderboard
+// @Summary Get the leaderboard
+// @Description Get the players ordered by ELO with their rank, win rate, current and best win streaks and last match date, optionally for a single campus. Served from a precomputed view refreshed every minute when results changed, refreshed_at tells when
+// @Tags players
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Number of players per page (default: 50, max: 200)"
+// @Param campus query string false "Only the players of a campus (e.g. lyon), each with their campus_rank; all campuses when omitted"
+// @Success 200 {object} models.LeaderboardResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /leaderboard [get]
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "50"))
+	if err != nil || pageSize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pageSize parameter"})
+		return
+	}
+	if pageSize > 200 {
+		pageSize = 200
+	}
+
+	leaderboard, err := h.leaderboardService.GetLeaderboard(page, pageSize, campusQuery(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
+
+// GetTopPlayers retrieves top N players by ELO rating
+// @Summary Get top players by ELO rating
+// @Description Get top N players ordered by ELO rating (highest first), with option to include current user. Served from the leaderboard view like GET /leaderboard, with the same fields
+// @Tags players
+// @Produce json
+// @Param limit query int false "Number of players to retrieve (default: 10, max: 100)"
+// @Param includeCurrentUser query bool false "Include current user in results even if not in top (default: false)"
+// @Param campus query string false "Only the players of a campus (e.g. lyon), all campuses when omitted"
+// @Success 200 {array} models.LeaderboardEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/top [get]
+func (h *LeaderboardHandler) GetTopPlayers(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit parameter",
+		})
+		return
+	}
+
+	// Cap the limit to prevent excessive queries
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Vérifier si on doit inclure l'utilisateur connecté
+	includeCurrentUserStr := c.DefaultQuery("includeCurrentUser", "false")
+	includeCurrentUser, err := strconv.ParseBool(includeCurrentUserStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid includeCurrentUser parameter",
+		})
+		return
+	}
+
+	var currentUserID *uint
+	if includeCurrentUser {
+		// Récupérer l'ID de l'utilisateur connecté depuis le contexte JWT
+		if userID, exists := authMiddleware.GetUserID(c); exists {
+			currentUserID = &userID
+		}
+	}
+
+	players, err := h.leaderboardService.GetTopPlayers(limit, currentUserID, campusQuery(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve top players",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type LiveMatchHandler struct {
+	liveMatchService    *services.LiveMatchService
+	notificationService *services.NotificationService
+	db                  *gorm.DB
+}
+
+func NewLiveMatchHandler(liveMatchService *services.LiveMatchService, notificationService *services.NotificationService, db *gorm.DB) *LiveMatchHandler {
+	return &LiveMatchHandler{
+		liveMatchService:    liveMatchService,
+		notificationService: notificationService,
+		db:                  db,
+	}
+}
+
+// liveMatchErrorStatus maps live match service errors to HTTP status codes
+func liveMatchErrorStatus(err error) int {
+	switch err.Error() {
+	case "live match not found", "match not found", "player1 not found", "player2 not found", "tournament not found":
+		return http.StatusNotFound
+	case "player already in a live match", "player is archived", "live match is not in progress", "live match is paused",
+		"live match time is over", "live match timer is not running", "live match timer is not paused":
+		return http.StatusConflict
+	case "player1 and player2 must be different", "scorer must be either player1 or player2",
+		"tournament is not ongoing", "live match cannot end in a draw", "no goal to undo":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// authorizeLiveMatch loads the live match and checks that the user is one of its players or an admin
+func (h *LiveMatchHandler) authorizeLiveMatch(c *gin.Context) (uint, bool) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid live match ID"})
+		return 0, false
+	}
+
+	liveMatch, err := h.liveMatchService.GetLiveMatch(uint(id))
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	if err := checkPlayersOrAdmin(h.db, userID, liveMatch.Player1ID, liveMatch.Player2ID); err != nil {
+		if err.Error() == "unauthorized" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the players or an admin can update this live match"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization check failed"})
+		}
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// StartLiveMatch starts a live match
+// @Summary Start a live match
+// @Description Start a live match between two players; goals are then recorded one by one until the match is finalized
+// @Tags live-matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param match body models.StartLiveMatchRequest true "Live match players"
+// @Success 201 {object} models.LiveMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches [post]
+func (h *LiveMatchHandler) StartLiveMatch(c *gin.Context) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.StartLiveMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	if err := checkPlayersOrAdmin(h.db, userID, req.Player1ID, req.Player2ID); err != nil {
+		if err.Error() == "unauthorized" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only start live matches for yourself or you must be an admin"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization check failed"})
+		}
+		return
+	}
+
+	liveMatch, err := h.liveMatchService.StartLiveMatch(req, userID)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, liveMatch)
+}
+
+// GetLiveMatches lists the live matches in progress
+// @Summary Get live matches in progress
+// @Description Get the live matches currently being played, for the live scoreboard
+// @Tags live-matches
+// @Produce json
+// @Success 200 {array} models.LiveMatch
+// @Failure 500 {object} map[string]string
+// @Router /live-matches [get]
+func (h *LiveMatchHandler) GetLiveMatches(c *gin.Context) {
+	liveMatches, err := h.liveMatchService.GetLiveMatches()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve live matches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveMatches)
+}
+
+// GetLiveMatch returns a live match with its goal timeline
+// @Summary Get a live match
+// @Description Get a live match with its current score and goal-by-goal timeline
+// @Tags live-matches
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatch
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /live-matches/{id} [get]
+func (h *LiveMatchHandler) GetLiveMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid live match ID"})
+		return
+	}
+
+	liveMatch, err := h.liveMatchService.GetLiveMatch(uint(id))
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveMatch)
+}
+
+// RecordGoal records a goal in a live match
+// @Summary Record a goal
+// @Description Record a goal for one of the players of a live match
+// @Tags live-matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Param goal body models.RecordGoalRequest true "Scorer"
+// @Success 200 {object} models.LiveMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches/{id}/goals [post]
+func (h *LiveMatchHandler) RecordGoal(c *gin.Context) {
+	id, ok := h.authorizeLiveMatch(c)
+	if !ok {
+		return
+	}
+
+	var req models.RecordGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	liveMatch, err := h.liveMatchService.RecordGoal(id, req.ScorerID)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveMatch)
+}
+
+// UndoLastGoal removes the last goal of a live match
+// @Summary Undo the last goal
+// @Description Remove the last recorded goal of a live match and restore the previous score
+// @Tags live-matches
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches/{id}/goals/last [delete]
+func (h *LiveMatchHandler) UndoLastGoal(c *gin.Context) {
+	id, ok := h.authorizeLiveMatch(c)
+	if !ok {
+		return
+	}
+
+	liveMatch, err := h.liveMatchService.UndoLastGoal(id)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveMatch)
+}
+
+// FinalizeLiveMatch ends a live match and creates the corresponding match
+// @Summary Finalize a live match
+// @Description End a live match: a regular pending match is created with the winner from the final score, and the goal timeline is attached to it
+// @Tags live-matches
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches/{id}/finalize [post]
+func (h *LiveMatchHandler) FinalizeLiveMatch(c *gin.Context) {
+	id, ok := h.authorizeLiveMatch(c)
+	if !ok {
+		return
+	}
+
+	liveMatch, err := h.liveMatchService.FinalizeLiveMatch(id)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	h.notificationService.NotifyMatchCreated(&models.Match{
+		ID:        *liveMatch.MatchID,
+		Player1ID: liveMatch.Player1ID,
+		Player2ID: liveMatch.Player2ID,
+	}, userID)
+
+	c.JSON(http.StatusOK, liveMatch)
+}
+
+// AbandonLiveMatch stops a live match without creating a match
+// @Summary Abandon a live match
+// @Description Stop a live match without recording a match
+// @Tags live-matches
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches/{id}/abandon [post]
+func (h *LiveMatchHandler) AbandonLiveMatch(c *gin.Context) {
+	id, ok := h.authorizeLiveMatch(c)
+	if !ok {
+		return
+	}
+
+	liveMatch, err := h.liveMatchService.AbandonLiveMatch(id)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, liveMatch)
+}
+
+// GetTimer returns the synchronized timer state of a live match
+// @Summary Get live match timer
+// @Description Get the timer state of a live match for referee devices: elapsed and remaining time (pauses excluded), phase (running, paused, golden_goal, time_over...) and server time to synchronize clocks
+// @Tags live-matches
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatchTimer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /live-matches/{id}/timer [get]
+func (h *LiveMatchHandler) GetTimer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid live match ID"})
+		return
+	}
+
+	timer, err := h.liveMatchService.GetTimer(uint(id))
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, timer)
+}
+
+// PauseTimer pauses the clock of a live match
+// @Summary Pause live match timer
+// @Description Pause the clock of a live match; goals cannot be recorded while paused
+// @Tags live-matches
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatchTimer
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches/{id}/timer/pause [post]
+func (h *LiveMatchHandler) PauseTimer(c *gin.Context) {
+	id, ok := h.authorizeLiveMatch(c)
+	if !ok {
+		return
+	}
+
+	timer, err := h.liveMatchService.PauseTimer(id)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, timer)
+}
+
+// ResumeTimer resumes the clock of a paused live match
+// @Summary Resume live match timer
+// @Description Resume the clock of a paused live match
+// @Tags live-matches
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Live match ID"
+// @Success 200 {object} models.LiveMatchTimer
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /live-matches/{id}/timer/resume [post]
+func (h *LiveMatchHandler) ResumeTimer(c *gin.Context) {
+	id, ok := h.authorizeLiveMatch(c)
+	if !ok {
+		return
+	}
+
+	timer, err := h.liveMatchService.ResumeTimer(id)
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, timer)
+}
+
+// GetMatchTimeline returns the goal timeline of a match played in live mode
+// @Summary Get match score timeline
+// @Description Get the goal-by-goal timeline of a match recorded with the live match mode (empty for matches entered afterwards)
+// @Tags matches
+// @Produce json
+// @Param id path int true "Match ID"
+// @Success 200 {array} models.MatchGoal
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /matches/{id}/timeline [get]
+func (h *LiveMatchHandler) GetMatchTimeline(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	goals, err := h.liveMatchService.GetMatchTimeline(uint(id))
+	if err != nil {
+		c.JSON(liveMatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, goals)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MatchFeedHandler struct {
+	matchFeedService *services.MatchFeedService
+}
+
+func NewMatchFeedHandler(matchFeedService *services.MatchFeedService) *MatchFeedHandler {
+	return &MatchFeedHandler{
+		matchFeedService: matchFeedService,
+	}
+}
+
+// GetFeed retrieves solo and team matches in a single stream
+// @Summary Get solo and team matches
+// @Description Get solo and team matches in one chronological stream (latest first), each item tagged with its match_kind and holding either match or team_match. With player_id, the team matches are those of the player's teams: a player profile can show its whole history from this single list.
+// @Tags matches
+// @Produce json
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param per_page query int false "Items per page (default: 10, max: 100)" default(10)
+// @Param kind query string false "Only one kind of matches" Enums(solo,team)
+// @Param player_id query int false "Filter by player ID (solo matches of the player, team matches of their teams)"
+// @Param tournament_id query int false "Filter by tournament ID"
+// @Param status query string false "Filter by match status" Enums(pending,confirmed,rejected,cancelled)
+// @Param date_from query string false "Filter from date (YYYY-MM-DD format)"
+// @Param date_to query string false "Filter to date (YYYY-MM-DD format)"
+// @Success 200 {object} models.PaginatedMatchFeedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /matches/all [get]
+func (h *MatchFeedHandler) GetFeed(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	if err != nil || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid per_page parameter"})
+		return
+	}
+
+	// Limit per_page to maximum 100
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	filters := services.MatchFeedFilters{
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	if kind := c.Query("kind"); kind != "" {
+		if kind != models.MatchKindSolo && kind != models.MatchKindTeam {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kind. Must be one of: solo, team"})
+			return
+		}
+		filters.Kind = &kind
+	}
+
+	if playerIDStr := c.Query("player_id"); playerIDStr != "" {
+		playerID, err := strconv.ParseUint(playerIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player_id parameter"})
+			return
+		}
+		playerIDUint := uint(playerID)
+		filters.PlayerID = &playerIDUint
+	}
+
+	if tournamentIDStr := c.Query("tournament_id"); tournamentIDStr != "" {
+		tournamentID, err := strconv.ParseUint(tournamentIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament_id parameter"})
+			return
+		}
+		tournamentIDUint := uint(tournamentID)
+		filters.TournamentID = &tournamentIDUint
+	}
+
+	if status := c.Query("status"); status != "" {
+		if status != "pending" && status != "confirmed" && status != "rejected" && status != "cancelled" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Must be one of: pending, confirmed, rejected, cancelled"})
+			return
+		}
+		filters.Status = &status
+	}
+
+	if dateFromStr := c.Query("date_from"); dateFromStr != "" {
+		dateFrom, err := time.Parse("2006-01-02", dateFromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_from format. Use YYYY-MM-DD"})
+			return
+		}
+		filters.DateFrom = &dateFrom
+	}
+
+	if dateToStr := c.Query("date_to"); dateToStr != "" {
+		dateTo, err := time.Parse("2006-01-02", dateToStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_to format. Use YYYY-MM-DD"})
+			return
+		}
+		filters.DateTo = &dateTo
+	}
+
+	feed, err := h.matchFeedService.GetFeed(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve matches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// GetPlayerHistory retrieves the solo and team matches of a player
+// @Summary Get the full match history of a player
+// @Description Get the solo matches of a player and the team matches of their teams in one chronological stream (newest first), with the same filters and pagination as /players/{id}/matches. Each item is tagged with its match_kind and holds either match or team_match.
+// @Tags players
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param wins query string false "Filter for wins only (set to '1')"
+// @Param losses query string false "Filter for losses only (set to '1')"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Number of matches per page (default: 10, max: 100)"
+// @Success 200 {object} models.PaginatedMatchFeedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/history [get]
+func (h *MatchFeedHandler) GetPlayerHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	var outcome string
+	wins := c.Query("wins")
+	losses := c.Query("losses")
+	if wins == "1" && losses == "1" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot filter for both wins and losses at the same time"})
+		return
+	} else if wins == "1" {
+		outcome = "wins"
+	} else if losses == "1" {
+		outcome = "losses"
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pageSize parameter"})
+		return
+	}
+
+	// Cap the pageSize to prevent excessive queries
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	history, err := h.matchFeedService.GetPlayerHistory(uint(id), outcome, page, pageSize)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve player matches"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
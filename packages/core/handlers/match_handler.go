@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"core/export"
 	"core/models"
 	"core/services"
+	"core/validation"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,14 +18,16 @@ import (
 )
 
 type MatchHandler struct {
-	matchService *services.MatchService
-	db           *gorm.DB
+	matchService        *services.MatchService
+	notificationService *services.NotificationService
+	db                  *gorm.DB
 }
 
-func NewMatchHandler(matchService *services.MatchService, db *gorm.DB) *MatchHandler {
+func NewMatchHandler(matchService *services.MatchService, notificationService *services.NotificationService, db *gorm.DB) *MatchHandler {
 	return &MatchHandler{
-		matchService: matchService,
-		db:           db,
+		matchService:        matchService,
+		notificationService: notificationService,
+		db:                  db,
 	}
 }
 
@@ -65,9 +69,9 @@ func (h *MatchHandler) GetRecentMatches(c *gin.Context) {
 
 // GetMatches retrieves matches with pagination and filters
 // @Summary Get matches with pagination and filters
-// @Description Get matches with optional filters for player, status, and date range
+// @Description Get matches with optional filters for player, status, and date range. Send Accept: text/csv to download every matching match as CSV, without pagination.
 // @Tags matches
-// @Produce json
+// @Produce json,text/csv
 // @Param page query int false "Page number (default: 1)" default(1)
 // @Param per_page query int false "Items per page (default: 10, max: 100)" default(10)
 // @Param player_id query int false "Filter by player ID (matches where player is player1 or player2)"
@@ -147,6 +151,18 @@ func (h *MatchHandler) GetMatches(c *gin.Context) {
 		filters.DateTo = &dateTo
 	}
 
+	if wantsCSV(c) {
+		streamCSV(c, "matches.csv", export.MatchHeader, "Failed to retrieve matches", func(stream *csvStream) error {
+			return h.matchService.EachMatch(filters, func(matches []models.Match) error {
+				for _, match := range matches {
+					stream.Write(export.MatchRecord(match))
+				}
+				return stream.Flush()
+			})
+		})
+		return
+	}
+
 	// Get matches from service
 	result, err := h.matchService.GetMatches(filters)
 	if err != nil {
@@ -159,17 +175,20 @@ func (h *MatchHandler) GetMatches(c *gin.Context) {
 
 // CreateMatch creates a new match
 // @Summary Create a new match
-// @Description Create a new match between two players with automatic ELO calculation and stats update
+// @Description Create a new match between two players with automatic ELO calculation and stats update. Outside tournaments, each player can be in a limited number of matches per day (MATCH_DAILY_LIMIT); admins are exempt. With a client_uuid, sending the request again returns the match created the first time (200); the UUID cannot be reused for another match (409).
 // @Tags matches
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param match body models.CreateMatchRequest true "Match data"
+// @Success 200 {object} models.Match
 // @Success 201 {object} models.Match
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
 // @Failure 500 {object} map[string]string
 // @Router /matches [post]
 func (h *MatchHandler) CreateMatch(c *gin.Context) {
@@ -185,9 +204,7 @@ func (h *MatchHandler) CreateMatch(c *gin.Context) {
 	var req models.CreateMatchRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request body",
-		})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -205,6 +222,43 @@ func (h *MatchHandler) CreateMatch(c *gin.Context) {
 		return
 	}
 
+	// A request sent again with the same client UUID returns the match created the first time,
+	// before the daily cap counts it twice
+	replayed, err := h.matchService.FindReplayedMatch(req)
+	if err != nil {
+		if err.Error() == "client_uuid already used" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to create match",
+			})
+		}
+		return
+	}
+	if replayed != nil {
+		c.JSON(http.StatusOK, replayed)
+		return
+	}
+
+	// Daily cap on ranked submissions, admins are exempt (e.g. to enter a backlog of paper results)
+	if req.TournamentID == nil && !isAdmin(h.db, userID) {
+		if err := h.matchService.CheckDailyQuota(req.Player1ID, req.Player2ID); err != nil {
+			if err.Error() == "daily match limit reached" {
+				c.JSON(http.StatusTooManyRequests, gin.H{
+					"error": err.Error(),
+					"limit": h.matchService.DailyMatchLimit(),
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to check daily match limit",
+				})
+			}
+			return
+		}
+	}
+
 	match, err := h.matchService.CreateMatch(req)
 	if err != nil {
 		if err.Error() == "player1 not found" || err.Error() == "player2 not found" {
@@ -222,18 +276,34 @@ func (h *MatchHandler) CreateMatch(c *gin.Context) {
 			return
 		}
 
+		if err.Error() == "client_uuid already used" || err.Error() == "player is archived" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to create match",
 		})
 		return
 	}
 
+	// In two-player confirmation mode, the creator's own confirmation is recorded explicitly
+	if match.RequiresBothConfirmations && (userID == match.Player1ID || userID == match.Player2ID) {
+		if confirmed, err := h.matchService.ConfirmByPlayer(match.ID, userID); err == nil {
+			match = confirmed
+		}
+	}
+
+	h.notificationService.NotifyMatchCreated(match, userID)
+
 	c.JSON(http.StatusCreated, match)
 }
 
 // UpdateMatchStatus updates match status and/or winner
 // @Summary Update match status and/or winner (PATCH)
-// @Description Update the status and/or winner of a pending match. All fields are optional. Only player2 or admin can update.
+// @Description Update the status and/or winner of a pending match, or attach its scoreboard photo. All fields are optional. Only player2, the assigned referee or admin can update. In two-player confirmation mode both players confirm: the match is confirmed, and ELO applied, once both did (a winner change resets the confirmations). Under a strict photo policy (MATCH_PHOTO_POLICY, or the tournament photo_policy), disputed matches, or all of them, need a photo_url to be confirmed (409 otherwise); a winner change marks the match as disputed.
 // @Tags matches
 // @Security BearerAuth
 // @Accept json
@@ -245,6 +315,7 @@ func (h *MatchHandler) CreateMatch(c *gin.Context) {
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /matches/{id} [patch]
 func (h *MatchHandler) UpdateMatchStatus(c *gin.Context) {
@@ -269,25 +340,23 @@ func (h *MatchHandler) UpdateMatchStatus(c *gin.Context) {
 
 	var req models.UpdateMatchStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request body",
-		})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
 	// Validate that at least one field is provided
-	if req.Status == nil && req.WinnerID == nil {
+	if req.Status == nil && req.WinnerID == nil && req.PhotoURL == nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "At least one field (status or winner_id) must be provided",
+			"error": "At least one field (status, winner_id or photo_url) must be provided",
 		})
 		return
 	}
 
-	// Authorization check: user must be player2 or admin
+	// Authorization check: user must be player2, the assigned referee or admin
 	if err := h.checkMatchStatusUpdateAuthorization(c, userID, uint(matchID)); err != nil {
 		if err.Error() == "unauthorized" {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Only player2 or admin can confirm/reject matches",
+				"error": "Only player2, the referee or admin can confirm/reject matches",
 			})
 		} else if err.Error() == "match not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -302,7 +371,7 @@ func (h *MatchHandler) UpdateMatchStatus(c *gin.Context) {
 	}
 
 	// Update match status
-	match, err := h.matchService.UpdateMatchStatus(uint(matchID), req)
+	match, err := h.matchService.UpdateMatchStatusBy(uint(matchID), userID, req)
 	if err != nil {
 		if err.Error() == "match not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -316,6 +385,19 @@ func (h *MatchHandler) UpdateMatchStatus(c *gin.Context) {
 			})
 			return
 		}
+		if err.Error() == "match is on hold for review" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Match is on hold for review",
+			})
+			return
+		}
+		if err.Error() == "scoreboard photo required" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "A scoreboard photo (photo_url) is required to confirm this match",
+			})
+			return
+		}
+
 		if err.Error() == "winner must be either player1 or player2" {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "Winner must be either player1 or player2",
@@ -334,22 +416,7 @@ func (h *MatchHandler) UpdateMatchStatus(c *gin.Context) {
 
 // checkMatchAuthorization vérifie si l'utilisateur a le droit de créer ce match
 func (h *MatchHandler) checkMatchAuthorization(c *gin.Context, userID, player1ID, player2ID uint) error {
-	// Check if user is one of the players (user_id = player_id)
-	if userID == player1ID || userID == player2ID {
-		return nil
-	}
-
-	// Check if user is admin
-	var user authModels.User
-	if err := h.db.First(&user, userID).Error; err != nil {
-		return err
-	}
-
-	if user.HasRole(authModels.RoleAdmin) {
-		return nil
-	}
-
-	return errors.New("unauthorized")
+	return checkPlayersOrAdmin(h.db, userID, player1ID, player2ID)
 }
 
 // checkMatchStatusUpdateAuthorization vérifie si l'utilisateur peut mettre à jour le statut du match
@@ -368,6 +435,16 @@ func (h *MatchHandler) checkMatchStatusUpdateAuthorization(c *gin.Context, userI
 		return nil
 	}
 
+	// In two-player confirmation mode player1 confirms too
+	if match.RequiresBothConfirmations && userID == match.Player1ID {
+		return nil
+	}
+
+	// The referee assigned to a tournament match can confirm it directly
+	if match.RefereeID != nil && *match.RefereeID == userID {
+		return nil
+	}
+
 	// Check if user is admin
 	var user authModels.User
 	if err := h.db.First(&user, userID).Error; err != nil {
@@ -381,9 +458,9 @@ func (h *MatchHandler) checkMatchStatusUpdateAuthorization(c *gin.Context, userI
 	return errors.New("unauthorized")
 }
 
-// RejectMatch rejects a match (only accessible to player2 or admin)
+// RejectMatch rejects a match (only accessible to player2, the referee or admin)
 // @Summary Reject a match
-// @Description Reject a pending match. Only player2 or admin can reject.
+// @Description Reject a pending match. Only player2, the assigned referee or admin can reject.
 // @Tags matches
 // @Security BearerAuth
 // @Produce json
@@ -415,11 +492,11 @@ func (h *MatchHandler) RejectMatch(c *gin.Context) {
 		return
 	}
 
-	// Authorization check: user must be player2 or admin
+	// Authorization check: user must be player2, the assigned referee or admin
 	if err := h.checkMatchStatusUpdateAuthorization(c, userID, uint(matchID)); err != nil {
 		if err.Error() == "unauthorized" {
 			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Only player2 or admin can reject matches",
+				"error": "Only player2, the referee or admin can reject matches",
 			})
 		} else if err.Error() == "match not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -525,6 +602,52 @@ func (h *MatchHandler) CancelMatch(c *gin.Context) {
 	c.JSON(http.StatusOK, match)
 }
 
+// UpdateFloorExemption flags a solo match so that the ELO floor does not hold its players
+// @Summary Set the ELO floor exemption of a match
+// @Description Flag a pending solo match (calibration, admin event...) so that its ELO changes are not clamped at the 1200 floor, or clear the flag. The exemption is recorded on the ELO history of the match. Team matches have no exemption, they always follow ELO_FLOOR_MODE (admin only)
+// @Tags matches
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Param request body models.UpdateFloorExemptionRequest true "Floor exemption"
+// @Success 200 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /matches/{id}/floor-exemption [patch]
+func (h *MatchHandler) UpdateFloorExemption(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.UpdateFloorExemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	match, err := h.matchService.SetFloorExemption(uint(matchID), *req.FloorExempt)
+	if err != nil {
+		switch err.Error() {
+		case "match not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "match is not pending":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
 // checkAdminAuthorization vérifie si l'utilisateur est admin
 func (h *MatchHandler) checkAdminAuthorization(userID uint) error {
 	var user authModels.User
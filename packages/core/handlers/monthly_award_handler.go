@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MonthlyAwardHandler struct {
+	monthlyAwardService *services.MonthlyAwardService
+}
+
+func NewMonthlyAwardHandler(monthlyAwardService *services.MonthlyAwardService) *MonthlyAwardHandler {
+	return &MonthlyAwardHandler{
+		monthlyAwardService: monthlyAwardService,
+	}
+}
+
+// GetMonthlyAwards lists the monthly awards
+// @Summary Get monthly awards
+// @Description Get the monthly awards (most improved, most active, giant-killer), most recent month first
+// @Tags trophies
+// @Produce json
+// @Param month query string false "Month (e.g. 2026-09)"
+// @Success 200 {array} models.MonthlyAward
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /monthly-awards [get]
+func (h *MonthlyAwardHandler) GetMonthlyAwards(c *gin.Context) {
+	var month *string
+	if m := c.Query("month"); m != "" {
+		if _, err := time.Parse(models.MonthlyAwardMonthLayout, m); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month parameter (expected YYYY-MM)"})
+			return
+		}
+		month = &m
+	}
+
+	awards, err := h.monthlyAwardService.GetAwards(month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, awards)
+}
+
+// RunMonthlyAwards gives the awards of a month
+// @Summary Give monthly awards
+// @Description Compute the awards of a finished month that were not given yet, with their trophies, notifications and announcement. The scheduler does it every day for the previous month (admin only)
+// @Tags trophies
+// @Security BearerAuth
+// @Produce json
+// @Param month query string false "Month (e.g. 2026-09, default: previous month)"
+// @Success 200 {array} models.MonthlyAward
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /monthly-awards/run [post]
+func (h *MonthlyAwardHandler) RunMonthlyAwards(c *gin.Context) {
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.ParseInLocation(models.MonthlyAwardMonthLayout, m, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month parameter (expected YYYY-MM)"})
+			return
+		}
+		month = parsed
+	}
+
+	awards, err := h.monthlyAwardService.AwardMonth(month)
+	if err != nil {
+		if err.Error() == "month not over" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if awards == nil {
+		awards = []models.MonthlyAward{}
+	}
+
+	c.JSON(http.StatusOK, awards)
+}
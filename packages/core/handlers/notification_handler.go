@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetPreferences returns the authenticated player's notification preferences
+// @Summary Get notification preferences
+// @Description Get quiet hours and batching preferences of the authenticated player (defaults: quiet hours 22h-8h Europe/Paris, 15 minutes batch window)
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.NotificationPreference
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	preference, err := h.notificationService.GetPreferences(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+// UpdatePreferences updates the authenticated player's notification preferences
+// @Summary Update notification preferences
+// @Description Update quiet hours (hours 0-23 in the given timezone, may wrap around midnight) and the batch window used to group notifications
+// @Tags notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param preferences body models.UpdateNotificationPreferenceRequest true "Notification preferences"
+// @Success 200 {object} models.NotificationPreference
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	preference, err := h.notificationService.UpdatePreferences(userID, req)
+	if err != nil {
+		if err.Error() == "invalid timezone" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	authMiddleware "auth/middleware"
+	authModels "auth/models"
+	authServices "auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlayerArchiveHandler struct {
+	playerService *services.PlayerService
+	auditService  *authServices.AuditService
+}
+
+func NewPlayerArchiveHandler(playerService *services.PlayerService, auditService *authServices.AuditService) *PlayerArchiveHandler {
+	return &PlayerArchiveHandler{
+		playerService: playerService,
+		auditService:  auditService,
+	}
+}
+
+// ArchivePlayer archives a player who left the school
+// @Summary Archive a player
+// @Description Archive a player who left the school: they leave the leaderboards, ladders and kiosk player list, and cannot play nor change their profile anymore. The profile, history and trophies stay visible read-only. Reversible (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/archive [post]
+func (h *PlayerArchiveHandler) ArchivePlayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	player, err := h.playerService.ArchivePlayer(uint(id), time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player already archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	h.auditService.Log(adminID, authModels.AuditActionPlayerArchived, authModels.AuditTargetPlayer, player.ID, authModels.AuditDetails{}, c.ClientIP())
+
+	c.JSON(http.StatusOK, player)
+}
+
+// UnarchivePlayer brings an archived player back
+// @Summary Unarchive a player
+// @Description Bring an archived player back to the leaderboards with the rating they left with (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/archive [delete]
+func (h *PlayerArchiveHandler) UnarchivePlayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	player, err := h.playerService.UnarchivePlayer(uint(id))
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player is not archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	h.auditService.Log(adminID, authModels.AuditActionPlayerUnarchived, authModels.AuditTargetPlayer, player.ID, authModels.AuditDetails{}, c.ClientIP())
+
+	c.JSON(http.StatusOK, player)
+}
+
+// ArchiveInactivePlayers archives in bulk the players inactive since a date
+// @Summary Archive inactive players
+// @Description Archive every player without any solo or team match nor login since inactive_since (creation date for players who never played nor logged in), e.g. the start of the academic year for those who left. Use dry_run to list them first. Each archived player is audited (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.ArchiveInactivePlayersRequest true "Inactivity date and dry run"
+// @Success 200 {object} models.ArchiveInactivePlayersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/players/archive-inactive [post]
+func (h *PlayerArchiveHandler) ArchiveInactivePlayers(c *gin.Context) {
+	var req models.ArchiveInactivePlayersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	now := time.Now()
+	if !req.InactiveSince.Before(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "inactive_since must be in the past"})
+		return
+	}
+
+	response, err := h.playerService.ArchiveInactivePlayers(req.InactiveSince, req.DryRun, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !response.DryRun {
+		adminID, _ := authMiddleware.GetUserID(c)
+		for _, player := range response.Players {
+			h.auditService.Log(adminID, authModels.AuditActionPlayerArchived, authModels.AuditTargetPlayer, player.PlayerID, authModels.AuditDetails{
+				"bulk":             true,
+				"inactive_since":   req.InactiveSince,
+				"last_activity_at": player.LastActivityAt,
+			}, c.ClientIP())
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -1,23 +1,30 @@
 package handlers
 
 import (
+	"core/export"
+	"core/models"
 	"core/services"
+	"core/validation"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	authMiddleware "auth/middleware"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type PlayerHandler struct {
 	playerService *services.PlayerService
 	teamService   *services.TeamService
+	db            *gorm.DB
 }
 
-func NewPlayerHandler(playerService *services.PlayerService, teamService *services.TeamService) *PlayerHandler {
+func NewPlayerHandler(playerService *services.PlayerService, teamService *services.TeamService, db *gorm.DB) *PlayerHandler {
 	return &PlayerHandler{
 		playerService: playerService,
 		teamService:   teamService,
+		db:            db,
 	}
 }
 
@@ -61,9 +68,9 @@ func (h *PlayerHandler) GetPlayer(c *gin.Context) {
 
 // GetEloHistory retrieves ELO history for a player
 // @Summary Get player ELO history
-// @Description Get ELO rating history for a specific player (solo matches)
+// @Description Get ELO rating history for a specific player (solo matches). Send Accept: text/csv for a CSV download.
 // @Tags players
-// @Produce json
+// @Produce json,text/csv
 // @Param id path int true "Player ID"
 // @Success 200 {array} models.EloHistory
 // @Failure 400 {object} map[string]string
@@ -80,7 +87,7 @@ func (h *PlayerHandler) GetEloHistory(c *gin.Context) {
 		return
 	}
 
-	_, err = h.playerService.GetPlayerByID(uint(id))
+	player, err := h.playerService.GetPlayerByID(uint(id))
 	if err != nil {
 		if err.Error() == "player not found" {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -94,6 +101,7 @@ func (h *PlayerHandler) GetEloHistory(c *gin.Context) {
 		return
 	}
 
+	asCSV := wantsCSV(c)
 	eloHistory, err := h.playerService.GetEloHistoryByPlayerID(uint(id))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -102,6 +110,18 @@ func (h *PlayerHandler) GetEloHistory(c *gin.Context) {
 		return
 	}
 
+	if asCSV {
+		filename := fmt.Sprintf("player-%d-elo-history.csv", player.ID)
+		streamCSV(c, filename, export.EloHistoryHeader, "Failed to retrieve ELO history", func(stream *csvStream) error {
+			for _, entry := range eloHistory {
+				entry.Player = *player
+				stream.Write(export.EloHistoryRecord(entry))
+			}
+			return nil
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, eloHistory)
 }
 
@@ -151,61 +171,6 @@ func (h *PlayerHandler) GetTeamEloHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, eloHistory)
 }
 
-// GetTopPlayers retrieves top N players by ELO rating
-// @Summary Get top players by ELO rating
-// @Description Get top N players ordered by ELO rating (highest first), with option to include current user
-// @Tags players
-// @Produce json
-// @Param limit query int false "Number of players to retrieve (default: 10, max: 100)"
-// @Param includeCurrentUser query bool false "Include current user in results even if not in top (default: false)"
-// @Success 200 {array} models.Player
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /players/top [get]
-func (h *PlayerHandler) GetTopPlayers(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid limit parameter",
-		})
-		return
-	}
-
-	// Cap the limit to prevent excessive queries
-	if limit > 100 {
-		limit = 100
-	}
-
-	// Vérifier si on doit inclure l'utilisateur connecté
-	includeCurrentUserStr := c.DefaultQuery("includeCurrentUser", "false")
-	includeCurrentUser, err := strconv.ParseBool(includeCurrentUserStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid includeCurrentUser parameter",
-		})
-		return
-	}
-
-	var currentUserID *uint
-	if includeCurrentUser {
-		// Récupérer l'ID de l'utilisateur connecté depuis le contexte JWT
-		if userID, exists := authMiddleware.GetUserID(c); exists {
-			currentUserID = &userID
-		}
-	}
-
-	players, err := h.playerService.GetTopPlayersByElo(limit, currentUserID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve top players",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, players)
-}
-
 // GetTopPlayersByTeamElo gets top players by team ELO rating
 // @Summary Get top players by team ELO rating
 // @Description Get top N players ordered by team ELO rating (highest first), with option to include current user
@@ -213,6 +178,7 @@ func (h *PlayerHandler) GetTopPlayers(c *gin.Context) {
 // @Produce json
 // @Param limit query int false "Number of players to retrieve (default: 10, max: 100)"
 // @Param includeCurrentUser query bool false "Include current user in results even if not in top (default: false)"
+// @Param campus query string false "Only the players of a campus (e.g. lyon), all campuses when omitted"
 // @Success 200 {array} models.Player
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -250,7 +216,7 @@ func (h *PlayerHandler) GetTopPlayersByTeamElo(c *gin.Context) {
 		}
 	}
 
-	players, err := h.playerService.GetTopPlayersByTeamElo(limit, currentUserID)
+	players, err := h.playerService.GetTopPlayersByTeamElo(limit, currentUserID, campusQuery(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve top players",
@@ -263,7 +229,7 @@ func (h *PlayerHandler) GetTopPlayersByTeamElo(c *gin.Context) {
 
 // GetPlayerMatches retrieves matches for a specific player with pagination
 // @Summary Get matches for a player
-// @Description Get matches for a specific player, ordered from newest to oldest, with optional filtering and pagination
+// @Description Get solo matches for a specific player, ordered from newest to oldest, with optional filtering and pagination. Use /players/{id}/history to include the matches of the player's teams.
 // @Tags players
 // @Produce json
 // @Param id path int true "Player ID"
@@ -356,10 +322,10 @@ func (h *PlayerHandler) GetPlayerMatches(c *gin.Context) {
 
 // GetAllPlayers retrieves all players with pagination and sorting
 // @Summary Get all players
-// @Description Get all players with pagination and sorting options
+// @Description Get all players with pagination and sorting options. Send Accept: text/csv to download every player as CSV, with the same sorting and no pagination.
 // @Tags players
-// @Produce json
-// @Param orderBy query string false "Sort field: 'created_at', 'elo_rating', 'username' (default: 'created_at')"
+// @Produce json,text/csv
+// @Param orderBy query string false "Sort field: 'created_at', 'elo_rating', 'username', 'win_rate', 'team_win_rate' (default: 'created_at')"
 // @Param direction query string false "Sort direction: 'ASC' or 'DESC' (default: 'DESC')"
 // @Param page query int false "Page number (default: 1)"
 // @Param pageSize query int false "Number of players per page (default: 10, max: 100)"
@@ -374,6 +340,18 @@ func (h *PlayerHandler) GetAllPlayers(c *gin.Context) {
 	// Get direction parameter
 	direction := c.DefaultQuery("direction", "DESC")
 
+	if wantsCSV(c) {
+		streamCSV(c, "players.csv", export.PlayerHeader, "Failed to retrieve players", func(stream *csvStream) error {
+			return h.playerService.EachPlayer(orderBy, direction, func(players []models.Player) error {
+				for _, player := range players {
+					stream.Write(export.PlayerRecord(player))
+				}
+				return stream.Flush()
+			})
+		})
+		return
+	}
+
 	// Get page parameter
 	pageStr := c.DefaultQuery("page", "1")
 	page, err := strconv.Atoi(pageStr)
@@ -458,3 +436,260 @@ func (h *PlayerHandler) GetPlayerTeams(c *gin.Context) {
 
 	c.JSON(http.StatusOK, teams)
 }
+
+// SetDisplayName sets the name shown for a player on public payloads
+// @Summary Set player display name
+// @Description Set the name shown on leaderboards, profiles and match feeds (e.g. "El Maestro 🏆"), up to 32 characters with emoji; the username, used to log in and for the slug, does not change. The name goes through the moderation checks (invisible characters, DISPLAY_NAME_BLOCKLIST, another player's name) and each change is published on the admin console; null or empty clears it (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param displayName body models.UpdateDisplayNameRequest true "Display name"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/display-name [put]
+func (h *PlayerHandler) SetDisplayName(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		if err.Error() == "unauthorized" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own display name or you must be an admin"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization check failed"})
+		}
+		return
+	}
+
+	var req models.UpdateDisplayNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	player, err := h.playerService.SetDisplayName(uint(id), req.DisplayName, userID)
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player is archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// SetCampus sets the campus and department of a player
+// @Summary Set player campus
+// @Description Set the campus (e.g. lyon, stored lowercase) and department of a player, used by the per-campus leaderboards and stats; null or empty clears a value (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param campus body models.UpdateCampusRequest true "Campus and department"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/campus [put]
+func (h *PlayerHandler) SetCampus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		if err.Error() == "unauthorized" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own campus or you must be an admin"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization check failed"})
+		}
+		return
+	}
+
+	var req models.UpdateCampusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	player, err := h.playerService.SetCampus(uint(id), req.Campus, req.Department)
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player is archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// GetCampuses lists the campuses
+// @Summary Get campuses
+// @Description Get the campuses set on the players with their number of players, to build the campus filter of the leaderboards
+// @Tags players
+// @Produce json
+// @Success 200 {array} models.CampusCount
+// @Failure 500 {object} map[string]string
+// @Router /campuses [get]
+func (h *PlayerHandler) GetCampuses(c *gin.Context) {
+	campuses, err := h.playerService.GetCampuses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campuses)
+}
+
+// campusQuery reads the campus filter of the leaderboards and stats, normalized like the stored campuses
+func campusQuery(c *gin.Context) string {
+	campus := c.Query("campus")
+	if normalized := models.NormalizeCampus(&campus); normalized != nil {
+		return *normalized
+	}
+	return ""
+}
+
+// SetAway marks a player away for a date range
+// @Summary Set player away window
+// @Description Mark a player away (vacation, internship...) between two dates: an away badge is shown on the profile and leaderboards meanwhile, the rating is kept (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param away body models.UpdateAwayRequest true "Away window"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/away [put]
+func (h *PlayerHandler) SetAway(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	if !h.canManageAway(c, uint(id)) {
+		return
+	}
+
+	var req models.UpdateAwayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	player, err := h.playerService.SetAway(uint(id), req.AwayFrom, req.AwayUntil)
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player is archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// ClearAway ends the away window of a player
+// @Summary Clear player away window
+// @Description Mark a player as back (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/away [delete]
+func (h *PlayerHandler) ClearAway(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	if !h.canManageAway(c, uint(id)) {
+		return
+	}
+
+	player, err := h.playerService.ClearAway(uint(id))
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "player is archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// canManageAway checks that the user is the player or an admin, and writes the error response otherwise
+func (h *PlayerHandler) canManageAway(c *gin.Context, playerID uint) bool {
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return false
+	}
+
+	if err := checkPlayersOrAdmin(h.db, userID, playerID); err != nil {
+		if err.Error() == "unauthorized" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own away status or you must be an admin"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization check failed"})
+		}
+		return false
+	}
+	return true
+}
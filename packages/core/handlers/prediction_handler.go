@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PredictionHandler struct {
+	predictionService *services.PredictionService
+}
+
+func NewPredictionHandler(predictionService *services.PredictionService) *PredictionHandler {
+	return &PredictionHandler{
+		predictionService: predictionService,
+	}
+}
+
+// PredictPlayers predicts a solo match between two players
+// @Summary Predict a solo match
+// @Description Get the probability of each player winning a match against the other, from their ELO and adjusted with their head-to-head record of confirmed matches
+// @Tags predictions
+// @Produce json
+// @Param player1_id query int true "First player ID"
+// @Param player2_id query int true "Second player ID"
+// @Success 200 {object} models.MatchupPrediction
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /predict [get]
+func (h *PredictionHandler) PredictPlayers(c *gin.Context) {
+	player1ID, err := strconv.ParseUint(c.Query("player1_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player1_id parameter"})
+		return
+	}
+	player2ID, err := strconv.ParseUint(c.Query("player2_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player2_id parameter"})
+		return
+	}
+
+	prediction, err := h.predictionService.PredictPlayers(uint(player1ID), uint(player2ID))
+	if err != nil {
+		switch err.Error() {
+		case "players must be different":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, prediction)
+}
+
+// PredictTeams predicts a match between two teams
+// @Summary Predict a team match
+// @Description Get the probability of each team winning a match against the other, from the average team ELO of their players and adjusted with their head-to-head record of confirmed matches
+// @Tags predictions
+// @Produce json
+// @Param team1_id query int true "First team ID"
+// @Param team2_id query int true "Second team ID"
+// @Success 200 {object} models.MatchupPrediction
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /predict/teams [get]
+func (h *PredictionHandler) PredictTeams(c *gin.Context) {
+	team1ID, err := strconv.ParseUint(c.Query("team1_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team1_id parameter"})
+		return
+	}
+	team2ID, err := strconv.ParseUint(c.Query("team2_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team2_id parameter"})
+		return
+	}
+
+	prediction, err := h.predictionService.PredictTeams(uint(team1ID), uint(team2ID))
+	if err != nil {
+		switch err.Error() {
+		case "teams must be different":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "team not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, prediction)
+}
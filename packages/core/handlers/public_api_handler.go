@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PublicAPIHandler struct {
+	publicAPIService *services.PublicAPIService
+}
+
+func NewPublicAPIHandler(publicAPIService *services.PublicAPIService) *PublicAPIHandler {
+	return &PublicAPIHandler{
+		publicAPIService: publicAPIService,
+	}
+}
+
+// CreateToken creates a public API token
+// @Summary Create a public API token
+// @Description Create a scoped token for an external rating site; the token is only returned in this response (admin only)
+// @Tags public-api
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param token body models.CreatePublicAPITokenRequest true "Token name and scopes (players:read, results:read)"
+// @Success 201 {object} models.PublicAPITokenCreatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api-tokens [post]
+func (h *PublicAPIHandler) CreateToken(c *gin.Context) {
+	var req models.CreatePublicAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	token, err := h.publicAPIService.CreateToken(req, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// GetTokens lists the public API tokens
+// @Summary Get public API tokens
+// @Description List the public API tokens with their scopes and last use (admin only)
+// @Tags public-api
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.PublicAPIToken
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api-tokens [get]
+func (h *PublicAPIHandler) GetTokens(c *gin.Context) {
+	tokens, err := h.publicAPIService.GetTokens()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RevokeToken revokes a public API token
+// @Summary Revoke a public API token
+// @Description Revoke a public API token, requests using it are rejected immediately (admin only)
+// @Tags public-api
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Token ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api-tokens/{id} [delete]
+func (h *PublicAPIHandler) RevokeToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	if err := h.publicAPIService.RevokeToken(uint(id)); err != nil {
+		if err.Error() == "token not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked successfully"})
+}
+
+// UpdateConsent sets whether a player is exposed through the public API
+// @Summary Update public API consent
+// @Description Opt in or out of sharing your rating and confirmed results with external rating sites
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param consent body models.UpdatePublicAPIConsentRequest true "Consent"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /players/{id}/public-consent [put]
+func (h *PublicAPIHandler) UpdateConsent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	// Consent can only be given by the player themselves
+	userID, _ := authMiddleware.GetUserID(c)
+	if userID != uint(id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own consent"})
+		return
+	}
+
+	var req models.UpdatePublicAPIConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	player, err := h.publicAPIService.SetConsent(uint(id), *req.Consent)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// GetPublicPlayers lists the consenting players
+// @Summary Public: get players
+// @Description Get the ratings of the players who opted in, best ELO first. Requires the players:read scope, limited to 60 requests per minute per token.
+// @Tags public-api
+// @Security PublicAPIKey
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Number of players per page (default: 50, max: 200)"
+// @Success 200 {object} models.PaginatedPublicPlayersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /public/v1/players [get]
+func (h *PublicAPIHandler) GetPublicPlayers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "50"))
+	if err != nil || pageSize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pageSize parameter"})
+		return
+	}
+	if pageSize > 200 {
+		pageSize = 200
+	}
+
+	players, err := h.publicAPIService.GetPlayers(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve players"})
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
+
+// GetPublicPlayer gets a consenting player
+// @Summary Public: get player by ID
+// @Description Get the rating of a player who opted in. Requires the players:read scope.
+// @Tags public-api
+// @Security PublicAPIKey
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {object} models.PublicPlayer
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /public/v1/players/{id} [get]
+func (h *PublicAPIHandler) GetPublicPlayer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	player, err := h.publicAPIService.GetPlayer(uint(id))
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// GetPublicResults lists the confirmed results between consenting players
+// @Summary Public: get results
+// @Description Get the confirmed solo matches between players who both opted in, oldest confirmation first. To sync, pass the `next` value of the previous response as `since`. Requires the results:read scope.
+// @Tags public-api
+// @Security PublicAPIKey
+// @Produce json
+// @Param since query string false "Only results confirmed after this date (RFC 3339)"
+// @Param limit query int false "Number of results (default and max: 500)"
+// @Success 200 {object} models.PublicResultsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Router /public/v1/results [get]
+func (h *PublicAPIHandler) GetPublicResults(c *gin.Context) {
+	var since *time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since parameter, expected RFC 3339 date"})
+			return
+		}
+		since = &parsed
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := h.publicAPIService.GetResults(since, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve results"})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatingHandler struct {
+	ratingService *services.RatingService
+}
+
+func NewRatingHandler(ratingService *services.RatingService) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+	}
+}
+
+// GetPlayerRatings gets the ratings of a player on every ladder
+// @Summary Get player ratings
+// @Description Get the rating, rank and record of a player on every ladder they played (solo, team...)
+// @Tags players
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {array} models.Rating
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/ratings [get]
+func (h *RatingHandler) GetPlayerRatings(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	ratings, err := h.ratingService.GetPlayerRatings(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, ratings)
+}
+
+// GetLadder gets the best players of a ladder
+// @Summary Get ladder standings
+// @Description Get the top N players of a rating ladder (highest ELO first)
+// @Tags ratings
+// @Produce json
+// @Param ladder path string true "Ladder (solo, team)"
+// @Param limit query int false "Number of players to retrieve (default: 10, max: 100)"
+// @Param campus query string false "Only the players of a campus (e.g. lyon), ranked among themselves; all campuses when omitted"
+// @Success 200 {array} models.Rating
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /ladders/{ladder} [get]
+func (h *RatingHandler) GetLadder(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid limit parameter",
+		})
+		return
+	}
+
+	// Cap the limit to prevent excessive queries
+	if limit > 100 {
+		limit = 100
+	}
+
+	ratings, err := h.ratingService.GetLadder(c.Param("ladder"), limit, campusQuery(c))
+	if err != nil {
+		if err.Error() == "ladder not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ratings)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+	authModels "auth/models"
+	authServices "auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatingOverrideHandler struct {
+	overrideService *services.RatingOverrideService
+	auditService    *authServices.AuditService
+}
+
+func NewRatingOverrideHandler(overrideService *services.RatingOverrideService, auditService *authServices.AuditService) *RatingOverrideHandler {
+	return &RatingOverrideHandler{
+		overrideService: overrideService,
+		auditService:    auditService,
+	}
+}
+
+// GetRatingOverrides lists the rating overrides of a player
+// @Summary Get player rating overrides
+// @Description Get the K-factor multipliers and ELO adjustments set on a player, most recent first (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {array} models.RatingOverride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/rating-overrides [get]
+func (h *RatingOverrideHandler) GetRatingOverrides(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	overrides, err := h.overrideService.GetPlayerOverrides(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// CreateRatingOverride sets a rating override on a player
+// @Summary Create a rating override
+// @Description Correct a player's solo rating: a K-factor multiplier applied to their ELO changes until expires_at, and/or a one-off ELO adjustment applied immediately (never below the 1200 floor). A reason is required and the change is audited (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param override body models.CreateRatingOverrideRequest true "Override data"
+// @Success 201 {object} models.RatingOverride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /players/{id}/rating-overrides [post]
+func (h *RatingOverrideHandler) CreateRatingOverride(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	var req models.CreateRatingOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	override, err := h.overrideService.CreateOverride(uint(id), adminID, req)
+	if err != nil {
+		if err.Error() == "player not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	h.auditService.Log(adminID, authModels.AuditActionRatingOverrideCreated, authModels.AuditTargetPlayer, override.PlayerID, authModels.AuditDetails{
+		"override_id":  override.ID,
+		"k_multiplier": override.KMultiplier,
+		"adjustment":   override.Adjustment,
+		"reason":       override.Reason,
+		"expires_at":   override.ExpiresAt,
+	}, c.ClientIP())
+
+	c.JSON(http.StatusCreated, override)
+}
+
+// RevokeRatingOverride ends a rating override early
+// @Summary Revoke a rating override
+// @Description Stop applying a K-factor multiplier before its expiry; an ELO adjustment already applied is kept. Audited (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param overrideId path int true "Override ID"
+// @Success 200 {object} models.RatingOverride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/rating-overrides/{overrideId} [delete]
+func (h *RatingOverrideHandler) RevokeRatingOverride(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	overrideID, err := strconv.ParseUint(c.Param("overrideId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid override ID"})
+		return
+	}
+
+	override, err := h.overrideService.RevokeOverride(uint(id), uint(overrideID))
+	if err != nil {
+		switch err.Error() {
+		case "rating override not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "rating override already revoked":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	h.auditService.Log(adminID, authModels.AuditActionRatingOverrideRevoked, authModels.AuditTargetPlayer, override.PlayerID, authModels.AuditDetails{
+		"override_id": override.ID,
+	}, c.ClientIP())
+
+	c.JSON(http.StatusOK, override)
+}
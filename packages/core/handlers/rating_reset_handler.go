@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+	authModels "auth/models"
+	authServices "auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatingResetHandler struct {
+	resetService *services.RatingResetService
+	auditService *authServices.AuditService
+}
+
+func NewRatingResetHandler(resetService *services.RatingResetService, auditService *authServices.AuditService) *RatingResetHandler {
+	return &RatingResetHandler{
+		resetService: resetService,
+		auditService: auditService,
+	}
+}
+
+// RequestRatingReset asks for a rating reset
+// @Summary Request a rating reset
+// @Description Ask an admin to reset your solo rating to the base rating; the ELO history is kept (player themselves only)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param request body models.CreateRatingResetRequest true "Reason of the request"
+// @Success 201 {object} models.RatingResetRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/rating-reset-requests [post]
+func (h *RatingResetHandler) RequestRatingReset(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	// Only the player themselves can ask for a reset
+	userID, _ := authMiddleware.GetUserID(c)
+	if userID != uint(id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only request a reset of your own rating"})
+		return
+	}
+
+	var req models.CreateRatingResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	request, err := h.resetService.CreateRequest(uint(id), req.Reason)
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "a rating reset request is already pending":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+// GetRatingResetRequests lists the rating reset requests
+// @Summary Get rating reset requests
+// @Description Get the rating reset requests with a status, oldest first (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Status (default: pending)" Enums(pending, approved, rejected)
+// @Success 200 {array} models.RatingResetRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /rating-reset-requests [get]
+func (h *RatingResetHandler) GetRatingResetRequests(c *gin.Context) {
+	status := c.DefaultQuery("status", models.RatingResetStatusPending)
+	if status != models.RatingResetStatusPending && status != models.RatingResetStatusApproved && status != models.RatingResetStatusRejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status parameter"})
+		return
+	}
+
+	requests, err := h.resetService.GetRequests(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// ReviewRatingResetRequest approves or rejects a rating reset request
+// @Summary Review a rating reset request
+// @Description Approve (the player's solo rating goes back to 1200 and a reset entry is added to their ELO history) or reject a request. Audited (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Request ID"
+// @Param review body models.ReviewRatingResetRequest true "Review decision"
+// @Success 200 {object} models.RatingResetRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /rating-reset-requests/{id} [patch]
+func (h *RatingResetHandler) ReviewRatingResetRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	var req models.ReviewRatingResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	adminID, _ := authMiddleware.GetUserID(c)
+	request, err := h.resetService.ReviewRequest(uint(id), adminID, req.Status)
+	if err != nil {
+		switch err.Error() {
+		case "rating reset request not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "rating reset request already reviewed":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if request.Status == models.RatingResetStatusApproved {
+		h.auditService.Log(adminID, authModels.AuditActionRatingReset, authModels.AuditTargetPlayer, request.PlayerID, authModels.AuditDetails{
+			"request_id": request.ID,
+			"reason":     request.Reason,
+		}, c.ClientIP())
+	}
+
+	c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefereeHandler struct {
+	refereeService *services.RefereeService
+}
+
+func NewRefereeHandler(refereeService *services.RefereeService) *RefereeHandler {
+	return &RefereeHandler{
+		refereeService: refereeService,
+	}
+}
+
+// refereeErrorStatus maps referee service errors to HTTP status codes
+func refereeErrorStatus(err error) int {
+	switch err.Error() {
+	case "match not found", "team match not found", "referee not found", "tournament not found":
+		return http.StatusNotFound
+	case "user is not a referee", "referee cannot play in the match":
+		return http.StatusForbidden
+	case "match is not a tournament match", "tournament is not ongoing", "match is not pending":
+		return http.StatusBadRequest
+	case "client_uuid already used":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// AssignMatchReferee assigns a referee to a tournament match
+// @Summary Assign a referee to a match
+// @Description Assign a user with the referee role to a tournament match (admin only). The referee can then confirm or reject the result without waiting for the 24h auto-validation.
+// @Tags referees
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Match ID"
+// @Param referee body models.AssignRefereeRequest true "Referee"
+// @Success 200 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /matches/{id}/referee [patch]
+func (h *RefereeHandler) AssignMatchReferee(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.AssignRefereeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	match, err := h.refereeService.AssignMatchReferee(uint(matchID), req.RefereeID)
+	if err != nil {
+		c.JSON(refereeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// AssignTeamMatchReferee assigns a referee to a tournament team match
+// @Summary Assign a referee to a team match
+// @Description Assign a user with the referee role to a tournament team match (admin only)
+// @Tags referees
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Team Match ID"
+// @Param referee body models.AssignRefereeRequest true "Referee"
+// @Success 200 {object} models.TeamMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /team-matches/{id}/referee [patch]
+func (h *RefereeHandler) AssignTeamMatchReferee(c *gin.Context) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team match ID"})
+		return
+	}
+
+	var req models.AssignRefereeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	match, err := h.refereeService.AssignTeamMatchReferee(uint(matchID), req.RefereeID)
+	if err != nil {
+		c.JSON(refereeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// RecordMatch records a tournament match result entered by a referee
+// @Summary Record a refereed match
+// @Description Create a tournament match as referee. The result is confirmed immediately, bypassing the 24h validation flow.
+// @Tags referees
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param match body models.CreateMatchRequest true "Match data (tournament_id required)"
+// @Success 201 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /referee/matches [post]
+func (h *RefereeHandler) RecordMatch(c *gin.Context) {
+	refereeID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.CreateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	match, err := h.refereeService.RecordRefereedMatch(req, refereeID)
+	if err != nil {
+		status := refereeErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			// Validation errors from match creation
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, match)
+}
+
+// RecordTeamMatch records a tournament team match result entered by a referee
+// @Summary Record a refereed team match
+// @Description Create a tournament team match as referee. The result is confirmed immediately, bypassing the 24h validation flow.
+// @Tags referees
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param match body models.CreateTeamMatchRequest true "Team match data (tournament_id required)"
+// @Success 201 {object} models.TeamMatch
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /referee/team-matches [post]
+func (h *RefereeHandler) RecordTeamMatch(c *gin.Context) {
+	refereeID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	var req models.CreateTeamMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	match, err := h.refereeService.RecordRefereedTeamMatch(req, refereeID)
+	if err != nil {
+		status := refereeErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			// Validation errors from team match creation
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, match)
+}
+
+// GetRefereeStats returns the refereeing stats of a user
+// @Summary Get referee stats
+// @Description Get the number of matches, team matches and tournaments officiated by a referee
+// @Tags referees
+// @Produce json
+// @Param id path int true "Referee user ID"
+// @Success 200 {object} models.RefereeStats
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /referees/{id}/stats [get]
+func (h *RefereeHandler) GetRefereeStats(c *gin.Context) {
+	refereeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid referee ID"})
+		return
+	}
+
+	stats, err := h.refereeService.GetRefereeStats(uint(refereeID))
+	if err != nil {
+		c.JSON(refereeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"core/cron"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulerHandler struct {
+	scheduler *cron.Scheduler
+}
+
+func NewSchedulerHandler(scheduler *cron.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		scheduler: scheduler,
+	}
+}
+
+// GetJobs lists the scheduled jobs
+// @Summary List scheduled jobs
+// @Description Get the names of the scheduled jobs that can be run by hand (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/jobs [get]
+func (h *SchedulerHandler) GetJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scheduler.JobNames())
+}
+
+// RunJob runs a scheduled job now
+// @Summary Run a scheduled job
+// @Description Run a scheduled job immediately and wait for it to finish; the run is reported on the admin console like a scheduled one (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Job name (see GET /admin/jobs)"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jobs/{name}/run [post]
+func (h *SchedulerHandler) RunJob(c *gin.Context) {
+	if err := h.scheduler.RunJob(c.Param("name")); err != nil {
+		if err.Error() == "job not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job completed successfully"})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeasonHandler struct {
+	seasonService *services.SeasonService
+}
+
+func NewSeasonHandler(seasonService *services.SeasonService) *SeasonHandler {
+	return &SeasonHandler{
+		seasonService: seasonService,
+	}
+}
+
+// GetSeasonSettings gets the rules of a season
+// @Summary Get season settings
+// @Description Get the rules selected for an academic season (defaults when never configured)
+// @Tags seasons
+// @Produce json
+// @Param season path string true "Season (e.g. 2025-2026)"
+// @Success 200 {object} models.SeasonSetting
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /seasons/{season}/settings [get]
+func (h *SeasonHandler) GetSeasonSettings(c *gin.Context) {
+	setting, err := h.seasonService.GetSetting(c.Param("season"))
+	if err != nil {
+		if err.Error() == "invalid season" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// UpdateSeasonSettings selects the rules of a season
+// @Summary Update season settings
+// @Description Select the match confirmation mode of a season: single (the opponent confirms) or both (both participants must confirm before ELO applies). Only matches created afterwards are affected (admin only)
+// @Tags seasons
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param season path string true "Season (e.g. 2025-2026)"
+// @Param settings body models.UpdateSeasonSettingRequest true "Season settings"
+// @Success 200 {object} models.SeasonSetting
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /seasons/{season}/settings [put]
+func (h *SeasonHandler) UpdateSeasonSettings(c *gin.Context) {
+	var req models.UpdateSeasonSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	setting, err := h.seasonService.UpdateSetting(c.Param("season"), req)
+	if err != nil {
+		if err.Error() == "invalid season" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SlowQueryHandler struct {
+	slowQueryService *services.SlowQueryService
+}
+
+func NewSlowQueryHandler(slowQueryService *services.SlowQueryService) *SlowQueryHandler {
+	return &SlowQueryHandler{
+		slowQueryService: slowQueryService,
+	}
+}
+
+// GetWorstOffenders lists the queries that cost the most time
+// @Summary Get slow query offenders
+// @Description Get the slow queries recorded over the last hours, grouped by SQL fingerprint and the route handler or scheduled job that issued them, ordered by total time spent. Queries are recorded above SLOW_QUERY_THRESHOLD_MS and reported to the admin console above SLOW_QUERY_ALERT_MS (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param hours query int false "Period in hours (default: 24, max: 720)"
+// @Param limit query int false "Number of offenders (default: 20, max: 100)"
+// @Success 200 {array} models.SlowQueryOffender
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/slow-queries [get]
+func (h *SlowQueryHandler) GetWorstOffenders(c *gin.Context) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours < 1 || hours > 720 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hours parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offenders, err := h.slowQueryService.GetWorstOffenders(time.Now().Add(-time.Duration(hours)*time.Hour), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, offenders)
+}
@@ -22,11 +22,12 @@ func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
 // @Description Get general statistics including players, solo matches, teams, team matches, and recent activity counts
 // @Tags stats
 // @Produce json
+// @Param campus query string false "Only the players of a campus (e.g. lyon), with the matches and teams they play in; all campuses when omitted"
 // @Success 200 {object} models.Stats
 // @Failure 500 {object} map[string]string
 // @Router /stats [get]
 func (h *StatsHandler) GetStats(c *gin.Context) {
-	stats, err := h.statsService.GetStats()
+	stats, err := h.statsService.GetStats(campusQuery(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve statistics",
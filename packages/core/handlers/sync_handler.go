@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"time"
+
+	coreMiddleware "core/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SyncHandler struct {
+	syncService         *services.SyncService
+	notificationService *services.NotificationService
+}
+
+func NewSyncHandler(syncService *services.SyncService, notificationService *services.NotificationService) *SyncHandler {
+	return &SyncHandler{
+		syncService:         syncService,
+		notificationService: notificationService,
+	}
+}
+
+// GetChanges returns the entities changed since a checkpoint
+// @Summary Sync players, matches and teams
+// @Description Get the players, matches and teams created, updated or deleted since a checkpoint, for offline-capable clients (kiosk token or JWT required) to reconcile their local copy. Without since, every entity is returned. Each response holds at most 500 entities of each kind: while has_more is true, sync again from the returned cursor. Send the returned cursor as since on the next call; entities changed around the checkpoint may be returned twice.
+// @Tags sync
+// @Produce json
+// @Security BearerAuth
+// @Param X-Kiosk-Token header string false "Kiosk token, instead of a JWT"
+// @Param since query string false "Cursor returned by the previous sync, or an RFC 3339 timestamp (2026-10-16T08:00:00Z)"
+// @Success 200 {object} models.SyncResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /sync [get]
+func (h *SyncHandler) GetChanges(c *gin.Context) {
+	var since *time.Time
+	if sinceParam := c.Query("since"); sinceParam != "" {
+		at, err := services.ParseSince(sinceParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid since parameter, use the cursor of the previous sync or an RFC 3339 timestamp",
+			})
+			return
+		}
+		since = &at
+	}
+
+	changes, err := h.syncService.GetChanges(since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve changes",
+		})
+		return
+	}
+
+	// A cached answer would hand out a stale cursor
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, changes)
+}
+
+// SubmitMatches creates the matches a kiosk recorded while offline
+// @Summary Kiosk: submit offline matches
+// @Description Submit a batch of up to 100 matches queued while offline, each with a client-generated UUID (kiosk token required). The batch can be sent again safely: a UUID already submitted returns its match as a duplicate. Each item gets a result, in order: accepted, duplicate, conflict (a match with the same players and winner was recorded within 15 minutes; resubmit with force to create it anyway), rejected (invalid, do not retry) or error (retry later).
+// @Tags sync
+// @Accept json
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Param request body models.SyncMatchesRequest true "Queued matches"
+// @Success 200 {object} models.SyncMatchesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /sync/matches [post]
+func (h *SyncHandler) SubmitMatches(c *gin.Context) {
+	var req models.SyncMatchesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	deviceID, _ := coreMiddleware.GetKioskDeviceID(c)
+	results, created := h.syncService.SubmitMatches(deviceID, req.Matches)
+
+	// No creator: both players are asked to confirm, as for the matches recorded online on the kiosk
+	for _, match := range created {
+		h.notificationService.NotifyMatchCreated(match, 0)
+	}
+
+	c.JSON(http.StatusOK, models.SyncMatchesResponse{Results: results})
+}
@@ -52,3 +52,40 @@ func (h *TeamEloHistoryHandler) GetRecentTeamEloChanges(c *gin.Context) {
 
 	c.JSON(http.StatusOK, eloChanges)
 }
+
+// GetTeamRatingHistory retrieves the rating history of a team
+// @Summary Get team ELO history
+// @Description Get the ELO rating changes of a team itself (not of its players), oldest first, for per-team charts
+// @Tags teams
+// @Produce json
+// @Param id path int true "Team ID"
+// @Success 200 {array} models.TeamRatingHistory
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /teams/{id}/elo-history [get]
+func (h *TeamEloHistoryHandler) GetTeamRatingHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid team ID",
+		})
+		return
+	}
+
+	history, err := h.eloHistoryService.GetTeamRatingHistory(uint(id))
+	if err != nil {
+		if err.Error() == "team not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Team not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve team ELO history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"core/models"
 	"core/services"
+	"core/validation"
 	"net/http"
 	"strconv"
 
@@ -36,7 +37,7 @@ func NewTeamHandler(db *gorm.DB) *TeamHandler {
 func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	var req models.CreateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -110,7 +111,7 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 
 	var req models.UpdateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -164,9 +165,11 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 
 // GetAllTeams gets all teams with pagination
 // @Summary Get all teams
-// @Description Get all teams with pagination
+// @Description Get all teams with pagination and sorting options
 // @Tags teams
 // @Produce json
+// @Param orderBy query string false "Sort field: 'created_at', 'elo_rating', 'name', 'win_rate' (default: 'created_at')"
+// @Param direction query string false "Sort direction: 'ASC' or 'DESC' (default: 'DESC')"
 // @Param page query int false "Page number (default: 1)"
 // @Param pageSize query int false "Items per page (default: 10, max: 100)"
 // @Success 200 {object} models.PaginatedTeamsResponse
@@ -189,7 +192,7 @@ func (h *TeamHandler) GetAllTeams(c *gin.Context) {
 		}
 	}
 
-	result, err := h.teamService.GetAllTeams(page, pageSize)
+	result, err := h.teamService.GetAllTeams(c.DefaultQuery("orderBy", "created_at"), c.DefaultQuery("direction", "DESC"), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
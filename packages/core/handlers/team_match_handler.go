@@ -3,50 +3,79 @@ package handlers
 import (
 	"core/models"
 	"core/services"
+	"core/validation"
 	"net/http"
 	"strconv"
 	"time"
 
+	authMiddleware "auth/middleware"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type TeamMatchHandler struct {
-	teamMatchService *services.TeamMatchService
+	teamMatchService    *services.TeamMatchService
+	notificationService *services.NotificationService
 }
 
-func NewTeamMatchHandler(db *gorm.DB) *TeamMatchHandler {
+func NewTeamMatchHandler(db *gorm.DB, notificationService *services.NotificationService) *TeamMatchHandler {
 	return &TeamMatchHandler{
-		teamMatchService: services.NewTeamMatchService(db),
+		teamMatchService:    services.NewTeamMatchService(db),
+		notificationService: notificationService,
 	}
 }
 
 // CreateTeamMatch creates a new team match
 // @Summary Create a new team match
-// @Description Create a new match between two teams
+// @Description Create a new match between two teams. With a client_uuid, sending the request again returns the match created the first time (200); the UUID cannot be reused for another match (409).
 // @Tags team-matches
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param match body models.CreateTeamMatchRequest true "Team match data"
+// @Success 200 {object} models.TeamMatch
 // @Success 201 {object} models.TeamMatch
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /team-matches [post]
 func (h *TeamMatchHandler) CreateTeamMatch(c *gin.Context) {
 	var req models.CreateTeamMatchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	// A request sent again with the same client UUID returns the match created the first time
+	replayed, err := h.teamMatchService.FindReplayedTeamMatch(req)
+	if err != nil {
+		if err.Error() == "client_uuid already used" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team match"})
+		}
+		return
+	}
+	if replayed != nil {
+		c.JSON(http.StatusOK, replayed)
 		return
 	}
 
 	match, err := h.teamMatchService.CreateTeamMatch(req)
 	if err != nil {
+		if err.Error() == "client_uuid already used" || err.Error() == "player is archived" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	creatorID, _ := authMiddleware.GetUserID(c)
+	h.notificationService.NotifyTeamMatchCreated(match, creatorID)
+
 	c.JSON(http.StatusCreated, match)
 }
 
@@ -169,7 +198,7 @@ func (h *TeamMatchHandler) GetRecentTeamMatches(c *gin.Context) {
 
 // UpdateTeamMatchStatus updates team match status
 // @Summary Update team match status
-// @Description Update the status and/or winner of a pending team match
+// @Description Update the status and/or winner of a pending team match, or attach its scoreboard photo. Under a strict photo policy (MATCH_PHOTO_POLICY, or the tournament photo_policy), disputed matches, or all of them, need a photo_url to be confirmed (409 otherwise); a winner change marks the match as disputed.
 // @Tags team-matches
 // @Security BearerAuth
 // @Accept json
@@ -180,6 +209,7 @@ func (h *TeamMatchHandler) GetRecentTeamMatches(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /team-matches/{id} [patch]
 func (h *TeamMatchHandler) UpdateTeamMatchStatus(c *gin.Context) {
@@ -192,7 +222,7 @@ func (h *TeamMatchHandler) UpdateTeamMatchStatus(c *gin.Context) {
 
 	var req models.UpdateTeamMatchStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -202,6 +232,8 @@ func (h *TeamMatchHandler) UpdateTeamMatchStatus(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else if err.Error() == "team match is not pending" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "scoreboard photo required" {
+			c.JSON(http.StatusConflict, gin.H{"error": "A scoreboard photo (photo_url) is required to confirm this match"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TitleHandler struct {
+	titleService *services.TitleService
+	db           *gorm.DB
+}
+
+func NewTitleHandler(titleService *services.TitleService, db *gorm.DB) *TitleHandler {
+	return &TitleHandler{
+		titleService: titleService,
+		db:           db,
+	}
+}
+
+// GetTitles lists the titles
+// @Summary Get titles
+// @Description Get the titles players can hold and show as flair, by name
+// @Tags titles
+// @Produce json
+// @Success 200 {array} models.Title
+// @Failure 500 {object} map[string]string
+// @Router /titles [get]
+func (h *TitleHandler) GetTitles(c *gin.Context) {
+	titles, err := h.titleService.GetTitles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, titles)
+}
+
+// CreateTitle creates a title
+// @Summary Create a title
+// @Description Create a title that can then be assigned to players; tournament champion titles are written automatically (admin only)
+// @Tags titles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param title body models.CreateTitleRequest true "Title data"
+// @Success 201 {object} models.Title
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /titles [post]
+func (h *TitleHandler) CreateTitle(c *gin.Context) {
+	var req models.CreateTitleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	title, err := h.titleService.CreateTitle(req)
+	if err != nil {
+		if err.Error() == "title name already used" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, title)
+}
+
+// UpdateTitle updates a title
+// @Summary Update title
+// @Description Rename a title or change its description, for every player holding it (admin only)
+// @Tags titles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Title ID"
+// @Param title body models.UpdateTitleRequest true "Title update data"
+// @Success 200 {object} models.Title
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /titles/{id} [put]
+func (h *TitleHandler) UpdateTitle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid title ID"})
+		return
+	}
+
+	var req models.UpdateTitleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	title, err := h.titleService.UpdateTitle(uint(id), req)
+	if err != nil {
+		switch err.Error() {
+		case "title not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "title name already used":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, title)
+}
+
+// DeleteTitle deletes a title
+// @Summary Delete title
+// @Description Delete a title, taking it back from every player holding it (admin only)
+// @Tags titles
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Title ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /titles/{id} [delete]
+func (h *TitleHandler) DeleteTitle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid title ID"})
+		return
+	}
+
+	if err := h.titleService.DeleteTitle(uint(id)); err != nil {
+		if err.Error() == "title not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Title deleted successfully"})
+}
+
+// GetPlayerTitles gets the titles of a player
+// @Summary Get player titles
+// @Description Get the titles held by a player, most recent first
+// @Tags players
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {array} models.PlayerTitle
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/titles [get]
+func (h *TitleHandler) GetPlayerTitles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	titles, err := h.titleService.GetPlayerTitles(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, titles)
+}
+
+// AssignTitle gives a title to a player
+// @Summary Assign a title
+// @Description Give a title to a player (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param request body models.AssignTitleRequest true "Title to assign"
+// @Success 201 {object} models.PlayerTitle
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/titles [post]
+func (h *TitleHandler) AssignTitle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	var req models.AssignTitleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	playerTitle, err := h.titleService.AssignTitle(uint(id), req.TitleID)
+	if err != nil {
+		switch err.Error() {
+		case "player not found", "title not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "title already held":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, playerTitle)
+}
+
+// RevokeTitle takes a title back from a player
+// @Summary Revoke a title
+// @Description Take a title back from a player, clearing their flair if they were showing it (admin only)
+// @Tags players
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param titleId path int true "Title ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /players/{id}/titles/{titleId} [delete]
+func (h *TitleHandler) RevokeTitle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+	titleID, err := strconv.ParseUint(c.Param("titleId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid title ID"})
+		return
+	}
+
+	if err := h.titleService.RevokeTitle(uint(id), uint(titleID)); err != nil {
+		if err.Error() == "player title not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Title revoked successfully"})
+}
+
+// UpdateFlair selects the title shown next to a player's name
+// @Summary Set player flair
+// @Description Choose which of the player's titles is shown next to their name on the leaderboard, or null to show none (player themselves or admin)
+// @Tags players
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Player ID"
+// @Param request body models.UpdateFlairRequest true "Title to show"
+// @Success 200 {object} models.Player
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /players/{id}/flair [put]
+func (h *TitleHandler) UpdateFlair(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := checkPlayersOrAdmin(h.db, userID, uint(id)); err != nil {
+		if err.Error() == "unauthorized" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only choose your own flair or you must be an admin"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization check failed"})
+		}
+		return
+	}
+
+	var req models.UpdateFlairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	player, err := h.titleService.SetFlair(uint(id), req.TitleID)
+	if err != nil {
+		switch err.Error() {
+		case "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "title not held":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "player is archived":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"core/models"
 	"core/services"
+	"core/validation"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,14 +15,16 @@ import (
 )
 
 type TournamentHandler struct {
-	tournamentService *services.TournamentService
-	db                *gorm.DB
+	tournamentService   *services.TournamentService
+	notificationService *services.NotificationService
+	db                  *gorm.DB
 }
 
-func NewTournamentHandler(db *gorm.DB) *TournamentHandler {
+func NewTournamentHandler(db *gorm.DB, notificationService *services.NotificationService) *TournamentHandler {
 	return &TournamentHandler{
-		tournamentService: services.NewTournamentService(db),
-		db:                db,
+		tournamentService:   services.NewTournamentService(db),
+		notificationService: notificationService,
+		db:                  db,
 	}
 }
 
@@ -41,7 +44,7 @@ func NewTournamentHandler(db *gorm.DB) *TournamentHandler {
 func (h *TournamentHandler) CreateTournament(c *gin.Context) {
 	var req models.CreateTournamentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -157,7 +160,7 @@ func (h *TournamentHandler) UpdateTournament(c *gin.Context) {
 
 	var req models.UpdateTournamentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -165,6 +168,8 @@ func (h *TournamentHandler) UpdateTournament(c *gin.Context) {
 	if err != nil {
 		if err.Error() == "tournament not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "tournament has no group stage" || err.Error() == "pools are already assigned" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -176,7 +181,7 @@ func (h *TournamentHandler) UpdateTournament(c *gin.Context) {
 
 // JoinTournament registers a team for a tournament
 // @Summary Join tournament
-// @Description Register a team for a tournament (must be a team member)
+// @Description Register a team for a tournament (must be a team member). When the tournament is full, the team is put on the waitlist (202).
 // @Tags tournaments
 // @Security BearerAuth
 // @Accept json
@@ -184,9 +189,11 @@ func (h *TournamentHandler) UpdateTournament(c *gin.Context) {
 // @Param id path int true "Tournament ID"
 // @Param request body models.JoinTournamentRequest true "Join request"
 // @Success 201 {object} models.TournamentTeam
+// @Success 202 {object} models.TournamentWaitlistItem
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /tournaments/{id}/join [post]
 func (h *TournamentHandler) JoinTournament(c *gin.Context) {
 	idParam := c.Param("id")
@@ -198,7 +205,7 @@ func (h *TournamentHandler) JoinTournament(c *gin.Context) {
 
 	var req models.JoinTournamentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
 		return
 	}
 
@@ -209,6 +216,15 @@ func (h *TournamentHandler) JoinTournament(c *gin.Context) {
 	}
 
 	tournamentTeam, err := h.tournamentService.JoinTournament(uint(tournamentID), req.TeamID, userID)
+	if err != nil && err.Error() == "tournament is full" {
+		// Full tournament: the team is put on the waitlist instead
+		waitlistItem, waitlistErr := h.tournamentService.JoinWaitlist(uint(tournamentID), req.TeamID, userID)
+		if waitlistErr == nil {
+			c.JSON(http.StatusAccepted, waitlistItem)
+			return
+		}
+		err = waitlistErr
+	}
 	if err != nil {
 		switch {
 		case err.Error() == "tournament not found" || err.Error() == "team not found":
@@ -226,7 +242,7 @@ func (h *TournamentHandler) JoinTournament(c *gin.Context) {
 
 // LeaveTournament removes a team from a tournament
 // @Summary Leave tournament
-// @Description Remove a team from a tournament (must be a team member)
+// @Description Remove a team from a tournament or its waitlist (must be a team member). The first team of the waitlist takes the freed spot and is notified.
 // @Tags tournaments
 // @Security BearerAuth
 // @Produce json
@@ -258,7 +274,7 @@ func (h *TournamentHandler) LeaveTournament(c *gin.Context) {
 		return
 	}
 
-	err = h.tournamentService.LeaveTournament(uint(tournamentID), uint(teamID), userID)
+	promoted, err := h.tournamentService.LeaveTournament(uint(tournamentID), uint(teamID), userID)
 	if err != nil {
 		switch err.Error() {
 		case "tournament not found", "team not found", "team is not registered in this tournament":
@@ -269,9 +285,42 @@ func (h *TournamentHandler) LeaveTournament(c *gin.Context) {
 		return
 	}
 
+	if promoted != nil {
+		var tournament models.Tournament
+		if err := h.db.Select("name").First(&tournament, tournamentID).Error; err == nil {
+			h.notificationService.NotifyWaitlistPromoted(promoted, tournament.Name)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Team removed from tournament successfully"})
 }
 
+// GetTournamentWaitlist gets the waitlist of a tournament
+// @Summary Get tournament waitlist
+// @Description Get the teams waiting for a spot in a full tournament, in promotion order
+// @Tags tournaments
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {array} models.TournamentWaitlistItem
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tournaments/{id}/waitlist [get]
+func (h *TournamentHandler) GetTournamentWaitlist(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	waitlist, err := h.tournamentService.GetWaitlist(uint(tournamentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, waitlist)
+}
+
 // GetTournamentTeams gets teams registered in a tournament
 // @Summary Get tournament teams
 // @Description Get paginated list of teams registered in a tournament
@@ -393,3 +442,199 @@ func (h *TournamentHandler) DeleteTournament(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Tournament deleted successfully"})
 }
+
+// tournamentStageErrorStatus maps group/knockout stage errors to HTTP status codes
+func tournamentStageErrorStatus(err error) int {
+	switch err.Error() {
+	case "tournament not found":
+		return http.StatusNotFound
+	case "tournament has no group stage", "knockout stage has already started", "group stage has already started",
+		"not enough teams to fill the pools", "all registered teams must be assigned to a pool",
+		"tournament is not in group stage", "group stage still has pending matches",
+		"not enough qualified teams for a knockout stage":
+		return http.StatusBadRequest
+	}
+	if strings.HasPrefix(err.Error(), "team ") || strings.HasPrefix(err.Error(), "unknown pool") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// AssignPools assigns the registered teams to the group stage pools
+// @Summary Assign pools
+// @Description Assign registered teams to the pools of a groups_knockout tournament (admin only). Without assignments, teams are spread across pools by team ELO (snake seeding). Opens the group stage.
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Param request body models.AssignPoolsRequest false "Manual pool assignments"
+// @Success 200 {object} models.TournamentStandingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tournaments/{id}/pools [post]
+func (h *TournamentHandler) AssignPools(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	var req models.AssignPoolsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, validation.BindError(err))
+			return
+		}
+	}
+
+	standings, err := h.tournamentService.AssignPools(uint(tournamentID), req)
+	if err != nil {
+		c.JSON(tournamentStageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// StartKnockout closes the group stage and builds the knockout bracket
+// @Summary Start knockout stage
+// @Description Close the group stage and build the knockout bracket from the qualified teams of each pool (admin only). Pool winners are seeded first; best seeds get a bye when needed.
+// @Tags tournaments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.TournamentStandingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tournaments/{id}/knockout [post]
+func (h *TournamentHandler) StartKnockout(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	standings, err := h.tournamentService.StartKnockout(uint(tournamentID))
+	if err != nil {
+		c.JSON(tournamentStageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// GetTournamentStandings gets pool standings and the knockout bracket
+// @Summary Get tournament standings
+// @Description Get the combined pool standings (with qualified teams) and knockout bracket of a tournament
+// @Tags tournaments
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.TournamentStandingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tournaments/{id}/standings [get]
+func (h *TournamentHandler) GetTournamentStandings(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	standings, err := h.tournamentService.GetStandings(uint(tournamentID))
+	if err != nil {
+		c.JSON(tournamentStageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// UpdateEntryPayment sets the entry fee payment status of a registered team
+// @Summary Update entry payment
+// @Description Mark the entry fee of a registered team as paid, unpaid or waived (admin only, tracking only)
+// @Tags tournaments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Param teamId path int true "Team ID"
+// @Param payment body models.UpdateEntryPaymentRequest true "Payment status"
+// @Success 200 {object} models.TournamentTeam
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournaments/{id}/teams/{teamId}/payment [patch]
+func (h *TournamentHandler) UpdateEntryPayment(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	teamID, err := strconv.ParseUint(c.Param("teamId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req models.UpdateEntryPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	actorID, _ := authMiddleware.GetUserID(c)
+	tournamentTeam, err := h.tournamentService.UpdateEntryPayment(uint(tournamentID), uint(teamID), req, actorID)
+	if err != nil {
+		if err.Error() == "team is not registered in this tournament" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tournamentTeam)
+}
+
+// GetFeesSummary gets the entry fees summary of a tournament
+// @Summary Get entry fees summary
+// @Description Get collected, outstanding and waived entry fees with the payment status of each team, for the treasurer (admin only)
+// @Tags tournaments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Success 200 {object} models.TournamentFeesSummary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournaments/{id}/fees [get]
+func (h *TournamentHandler) GetFeesSummary(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	summary, err := h.tournamentService.GetFeesSummary(uint(tournamentID))
+	if err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
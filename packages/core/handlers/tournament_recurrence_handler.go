@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TournamentRecurrenceHandler struct {
+	recurrenceService *services.TournamentRecurrenceService
+}
+
+func NewTournamentRecurrenceHandler(recurrenceService *services.TournamentRecurrenceService) *TournamentRecurrenceHandler {
+	return &TournamentRecurrenceHandler{
+		recurrenceService: recurrenceService,
+	}
+}
+
+// recurrenceErrorStatus maps recurrence service errors to HTTP status codes
+func recurrenceErrorStatus(err error) int {
+	switch err.Error() {
+	case "recurrence not found", "tournament not found":
+		return http.StatusNotFound
+	case "tournament is already recurring":
+		return http.StatusConflict
+	case "invalid timezone", "latest edition was deleted":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetRecurrences lists the recurring tournaments
+// @Summary Get recurring tournaments
+// @Description Get all recurring tournaments with their schedule and latest edition
+// @Tags tournament-recurrences
+// @Produce json
+// @Success 200 {array} models.TournamentRecurrence
+// @Failure 500 {object} map[string]string
+// @Router /tournament-recurrences [get]
+func (h *TournamentRecurrenceHandler) GetRecurrences(c *gin.Context) {
+	recurrences, err := h.recurrenceService.GetRecurrences()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurrences)
+}
+
+// GetRecurrence gets a recurring tournament by ID
+// @Summary Get recurring tournament by ID
+// @Description Get the schedule and latest edition of a recurring tournament
+// @Tags tournament-recurrences
+// @Produce json
+// @Param id path int true "Recurrence ID"
+// @Success 200 {object} models.TournamentRecurrence
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-recurrences/{id} [get]
+func (h *TournamentRecurrenceHandler) GetRecurrence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	recurrence, err := h.recurrenceService.GetRecurrenceByID(uint(id))
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurrence)
+}
+
+// CreateRecurrence makes a tournament recurring
+// @Summary Create a recurring tournament
+// @Description Make a tournament the first edition of a recurring tournament, e.g. a weekly league every Thursday (admin only). The next editions copy the settings of the previous one.
+// @Tags tournament-recurrences
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param recurrence body models.CreateTournamentRecurrenceRequest true "Recurrence rule"
+// @Success 201 {object} models.TournamentRecurrence
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /tournament-recurrences [post]
+func (h *TournamentRecurrenceHandler) CreateRecurrence(c *gin.Context) {
+	var req models.CreateTournamentRecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	recurrence, err := h.recurrenceService.CreateRecurrence(req)
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurrence)
+}
+
+// UpdateRecurrence updates the rule of a recurring tournament
+// @Summary Update a recurring tournament
+// @Description Update the name, schedule or active flag of a recurring tournament (admin only)
+// @Tags tournament-recurrences
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Recurrence ID"
+// @Param recurrence body models.UpdateTournamentRecurrenceRequest true "Recurrence update"
+// @Success 200 {object} models.TournamentRecurrence
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-recurrences/{id} [put]
+func (h *TournamentRecurrenceHandler) UpdateRecurrence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	var req models.UpdateTournamentRecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	recurrence, err := h.recurrenceService.UpdateRecurrence(uint(id), req)
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurrence)
+}
+
+// DeleteRecurrence stops a recurring tournament
+// @Summary Delete a recurring tournament
+// @Description Stop creating new editions, existing editions are kept (admin only)
+// @Tags tournament-recurrences
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Recurrence ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-recurrences/{id} [delete]
+func (h *TournamentRecurrenceHandler) DeleteRecurrence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	if err := h.recurrenceService.DeleteRecurrence(uint(id)); err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recurrence deleted successfully"})
+}
+
+// CreateNextEdition creates the next edition immediately
+// @Summary Create the next edition now
+// @Description Create the next edition of a recurring tournament without waiting for the schedule, subscribers are notified (admin only)
+// @Tags tournament-recurrences
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Recurrence ID"
+// @Success 201 {object} models.Tournament
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-recurrences/{id}/editions [post]
+func (h *TournamentRecurrenceHandler) CreateNextEdition(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	tournament, err := h.recurrenceService.CreateNextEdition(uint(id))
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tournament)
+}
+
+// Subscribe subscribes the authenticated player to new editions
+// @Summary Subscribe to a recurring tournament
+// @Description Be notified when a new edition of the recurring tournament opens
+// @Tags tournament-recurrences
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Recurrence ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-recurrences/{id}/subscription [post]
+func (h *TournamentRecurrenceHandler) Subscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := h.recurrenceService.Subscribe(uint(id), userID); err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Subscribed successfully"})
+}
+
+// Unsubscribe stops notifications of new editions for the authenticated player
+// @Summary Unsubscribe from a recurring tournament
+// @Description Stop being notified of new editions of the recurring tournament
+// @Tags tournament-recurrences
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Recurrence ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tournament-recurrences/{id}/subscription [delete]
+func (h *TournamentRecurrenceHandler) Unsubscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence ID"})
+		return
+	}
+
+	userID, exists := authMiddleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	if err := h.recurrenceService.Unsubscribe(uint(id), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully"})
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TournamentTemplateHandler struct {
+	templateService *services.TournamentTemplateService
+}
+
+func NewTournamentTemplateHandler(db *gorm.DB) *TournamentTemplateHandler {
+	return &TournamentTemplateHandler{
+		templateService: services.NewTournamentTemplateService(db),
+	}
+}
+
+// GetTemplates lists the tournament templates
+// @Summary Get tournament templates
+// @Description Get all saved tournament configurations
+// @Tags tournament-templates
+// @Produce json
+// @Success 200 {array} models.TournamentTemplate
+// @Failure 500 {object} map[string]string
+// @Router /tournament-templates [get]
+func (h *TournamentTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.templateService.GetTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate gets a tournament template by ID
+// @Summary Get tournament template by ID
+// @Description Get a saved tournament configuration
+// @Tags tournament-templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} models.TournamentTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-templates/{id} [get]
+func (h *TournamentTemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplateByID(uint(id))
+	if err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateTemplate creates a tournament template
+// @Summary Create a tournament template
+// @Description Save a reusable tournament configuration: format, rules, size and ELO weighting (admin only)
+// @Tags tournament-templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param template body models.CreateTournamentTemplateRequest true "Template data"
+// @Success 201 {object} models.TournamentTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /tournament-templates [post]
+func (h *TournamentTemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.CreateTournamentTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// SaveTournamentAsTemplate saves the configuration of a tournament as a template
+// @Summary Save tournament as template
+// @Description Save the configuration of an existing tournament as a reusable template (admin only)
+// @Tags tournament-templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tournament ID"
+// @Param template body models.SaveTournamentAsTemplateRequest true "Template name"
+// @Success 201 {object} models.TournamentTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournaments/{id}/template [post]
+func (h *TournamentTemplateHandler) SaveTournamentAsTemplate(c *gin.Context) {
+	tournamentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	var req models.SaveTournamentAsTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	template, err := h.templateService.SaveTournamentAsTemplate(uint(tournamentID), req)
+	if err != nil {
+		if err.Error() == "tournament not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// CreateTournamentFromTemplate creates a tournament from a template
+// @Summary Create tournament from template
+// @Description Create a new opened tournament with the configuration of a template (admin only)
+// @Tags tournament-templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param tournament body models.CreateTournamentFromTemplateRequest true "Tournament name"
+// @Success 201 {object} models.Tournament
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournaments/from-template/{id} [post]
+func (h *TournamentTemplateHandler) CreateTournamentFromTemplate(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req models.CreateTournamentFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	tournament, err := h.templateService.CreateTournamentFromTemplate(uint(templateID), req)
+	if err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, tournament)
+}
+
+// DeleteTemplate deletes a tournament template
+// @Summary Delete tournament template
+// @Description Delete a tournament template, tournaments created from it are kept (admin only)
+// @Tags tournament-templates
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /tournament-templates/{id} [delete]
+func (h *TournamentTemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(uint(id)); err != nil {
+		if err.Error() == "template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrophyHandler struct {
+	trophyService *services.TrophyService
+}
+
+func NewTrophyHandler(trophyService *services.TrophyService) *TrophyHandler {
+	return &TrophyHandler{
+		trophyService: trophyService,
+	}
+}
+
+// GetTrophies lists the trophies
+// @Summary Get trophies
+// @Description Get the trophy registry, most recent first
+// @Tags trophies
+// @Produce json
+// @Param season query string false "Season (e.g. 2025-2026)"
+// @Success 200 {array} models.Trophy
+// @Failure 500 {object} map[string]string
+// @Router /trophies [get]
+func (h *TrophyHandler) GetTrophies(c *gin.Context) {
+	var season *string
+	if s := c.Query("season"); s != "" {
+		season = &s
+	}
+
+	trophies, err := h.trophyService.GetTrophies(season)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trophies)
+}
+
+// GetTrophy gets a trophy by ID
+// @Summary Get trophy by ID
+// @Description Get a trophy with its tournament and winner
+// @Tags trophies
+// @Produce json
+// @Param id path int true "Trophy ID"
+// @Success 200 {object} models.Trophy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /trophies/{id} [get]
+func (h *TrophyHandler) GetTrophy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trophy ID"})
+		return
+	}
+
+	trophy, err := h.trophyService.GetTrophyByID(uint(id))
+	if err != nil {
+		if err.Error() == "trophy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, trophy)
+}
+
+// CreateTrophy awards a trophy by hand
+// @Summary Create a trophy
+// @Description Award a trophy to a team or a player, typically a season award; tournament trophies are written automatically (admin only)
+// @Tags trophies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param trophy body models.CreateTrophyRequest true "Trophy data"
+// @Success 201 {object} models.Trophy
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /trophies [post]
+func (h *TrophyHandler) CreateTrophy(c *gin.Context) {
+	var req models.CreateTrophyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	trophy, err := h.trophyService.CreateTrophy(req)
+	if err != nil {
+		switch err.Error() {
+		case "team not found", "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, trophy)
+}
+
+// UpdateTrophy updates a trophy
+// @Summary Update trophy
+// @Description Rename a trophy, change its image or correct its winner (admin only)
+// @Tags trophies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Trophy ID"
+// @Param trophy body models.UpdateTrophyRequest true "Trophy update data"
+// @Success 200 {object} models.Trophy
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /trophies/{id} [put]
+func (h *TrophyHandler) UpdateTrophy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trophy ID"})
+		return
+	}
+
+	var req models.UpdateTrophyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	trophy, err := h.trophyService.UpdateTrophy(uint(id), req)
+	if err != nil {
+		switch err.Error() {
+		case "trophy not found", "team not found", "player not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, trophy)
+}
+
+// DeleteTrophy deletes a trophy
+// @Summary Delete trophy
+// @Description Remove a trophy from the registry (admin only)
+// @Tags trophies
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Trophy ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /trophies/{id} [delete]
+func (h *TrophyHandler) DeleteTrophy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trophy ID"})
+		return
+	}
+
+	if err := h.trophyService.DeleteTrophy(uint(id)); err != nil {
+		if err.Error() == "trophy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Trophy deleted successfully"})
+}
+
+// GetPlayerTrophies gets the trophies of a player
+// @Summary Get player trophies
+// @Description Get the trophies won by a player, alone or with one of their teams
+// @Tags players
+// @Produce json
+// @Param id path int true "Player ID"
+// @Success 200 {array} models.Trophy
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/trophies [get]
+func (h *TrophyHandler) GetPlayerTrophies(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return
+	}
+
+	trophies, err := h.trophyService.GetPlayerTrophies(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trophies)
+}
+
+// GetTeamTrophies gets the trophies of a team
+// @Summary Get team trophies
+// @Description Get the trophies won by a team
+// @Tags teams
+// @Produce json
+// @Param id path int true "Team ID"
+// @Success 200 {array} models.Trophy
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /teams/{id}/trophies [get]
+func (h *TrophyHandler) GetTeamTrophies(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	trophies, err := h.trophyService.GetTeamTrophies(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trophies)
+}
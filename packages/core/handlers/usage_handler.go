@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"core/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler struct {
+	usageService *services.UsageService
+}
+
+func NewUsageHandler(usageService *services.UsageService) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+	}
+}
+
+// GetUsage reports which features are used
+// @Summary Get API usage
+// @Description Get the requests per endpoint group (first segment of the route, e.g. players, or two under /admin and /public, e.g. admin/jobs) over the last days, with the distinct users, the anonymous requests and the last activity, most requested first. Counters are written every minute and kept USAGE_RETENTION_DAYS days (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Period in days (default: 30, max: 365)"
+// @Param user_id query int false "Only the requests of this user"
+// @Success 200 {object} models.APIUsageReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter"})
+		return
+	}
+
+	var userID *uint
+	if value := c.Query("user_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id parameter"})
+			return
+		}
+		uid := uint(id)
+		userID = &uid
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -(days - 1))
+	report, err := h.usageService.GetUsage(from, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
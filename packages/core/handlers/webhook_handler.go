@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"core/models"
+	"core/services"
+	"core/validation"
+	"core/webhooks"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	authMiddleware "auth/middleware"
+	"auth/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	webhookVerifyLimit   = 30
+	webhookVerifyWindow  = time.Minute
+	webhookVerifyMaxBody = 1 << 20
+	webhookDeliveryLimit = 50
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	verifyLimiter  *utils.RateLimiter
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		verifyLimiter:  utils.NewRateLimiter(webhookVerifyLimit, webhookVerifyWindow),
+	}
+}
+
+// CreateEndpoint registers a webhook endpoint
+// @Summary Create a webhook endpoint
+// @Description Register an integrator HTTPS endpoint receiving signed events (result.confirmed: solo match confirmed between two consenting players, from now on). The signing secret is only returned in this response (admin only)
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param endpoint body models.CreateWebhookEndpointRequest true "Endpoint name, URL and events"
+// @Success 201 {object} models.WebhookSecretResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhook-endpoints [post]
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	var req models.CreateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, validation.BindError(err))
+		return
+	}
+
+	userID, _ := authMiddleware.GetUserID(c)
+	endpoint, err := h.webhookService.CreateEndpoint(req, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// GetEndpoints lists the webhook endpoints
+// @Summary Get webhook endpoints
+// @Description List the webhook endpoints with their events and the expiry of the previous secret during a rotation (admin only)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.WebhookEndpoint
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhook-endpoints [get]
+func (h *WebhookHandler) GetEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookService.GetEndpoints()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// DeleteEndpoint removes a webhook endpoint
+// @Summary Delete a webhook endpoint
+// @Description Remove a webhook endpoint and its deliveries, nothing is sent to it anymore (admin only)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhook-endpoints/{id} [delete]
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(uint(id)); err != nil {
+		if err.Error() == "webhook endpoint not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint deleted successfully"})
+}
+
+// RotateSecret gives a webhook endpoint a new secret
+// @Summary Rotate a webhook secret
+// @Description Generate a new signing secret, only returned in this response. During the grace period (24 hours by default, 0 to revoke the previous secret at once) deliveries carry a signature with each secret, so the integrator can switch without losing any (admin only)
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Param rotation body models.RotateWebhookSecretRequest false "Grace period"
+// @Success 200 {object} models.WebhookSecretResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhook-endpoints/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	var req models.RotateWebhookSecretRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, validation.BindError(err))
+			return
+		}
+	}
+
+	endpoint, err := h.webhookService.RotateSecret(uint(id), req.GracePeriodHours)
+	if err != nil {
+		if err.Error() == "webhook endpoint not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// Ping sends a test delivery to a webhook endpoint
+// @Summary Ping a webhook endpoint
+// @Description Send a signed ping event to an endpoint now and return the delivery with the response status; failed pings are retried like any delivery (admin only)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhook-endpoints/{id}/ping [post]
+func (h *WebhookHandler) Ping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	delivery, err := h.webhookService.Ping(uint(id))
+	if err != nil {
+		if err.Error() == "webhook endpoint not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GetDeliveries lists the deliveries of a webhook endpoint
+// @Summary Get webhook deliveries
+// @Description List the last 50 deliveries of an endpoint with their status, attempts, last response and next retry (admin only)
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /webhook-endpoints/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(uint(id), webhookDeliveryLimit)
+	if err != nil {
+		if err.Error() == "webhook endpoint not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Verify checks a delivery the way the integrator should
+// @Summary Verify a webhook delivery
+// @Description Verification helper for integrators: forward a delivery exactly as received (same body, X-Webhook-Id, X-Webhook-Timestamp and X-Webhook-Signature headers) and get whether it passes the checks an endpoint should run: known delivery ID, HMAC-SHA256 signature of "id.timestamp.body" by a current secret of the endpoint, timestamp within the replay window (5 minutes). No secret is sent or returned. Limited to 30 requests per minute per IP
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Id header string true "Delivery ID"
+// @Param X-Webhook-Timestamp header string true "Unix time of the attempt"
+// @Param X-Webhook-Signature header string true "Signatures, v1=<hex> comma separated"
+// @Success 200 {object} models.VerifyWebhookResponse
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/verify [post]
+func (h *WebhookHandler) Verify(c *gin.Context) {
+	if allowed, retryAfter := h.verifyLimiter.Allow(c.ClientIP()); !allowed {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Too many verification requests, try again later",
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, webhookVerifyMaxBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	result, err := h.webhookService.VerifyDelivery(
+		c.GetHeader(webhooks.HeaderID),
+		c.GetHeader(webhooks.HeaderTimestamp),
+		c.GetHeader(webhooks.HeaderSignature),
+		body,
+		time.Now(),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify delivery"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,70 @@
+// Package importers reads matches played outside the association from external sources.
+// Each source is read by a connector; new kinds of sources (e.g. a federation API with its
+// own format) are added by implementing Connector and registering it in New.
+package importers
+
+import (
+	"context"
+	"core/models"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Connector pulls the matches published by an external source
+type Connector interface {
+	Fetch(ctx context.Context) ([]models.ExternalMatchRecord, error)
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// New returns the connector reading a source
+func New(source *models.ImportSource) (Connector, error) {
+	if source.URL == nil {
+		return nil, errors.New("import source has no URL")
+	}
+
+	switch source.Connector {
+	case models.ImportConnectorJSON, models.ImportConnectorCSV:
+		return &feedConnector{url: *source.URL, format: source.Connector}, nil
+	}
+	return nil, fmt.Errorf("unknown connector %s", source.Connector)
+}
+
+// Decode reads records in one of the generic formats, used for pulled feeds and uploaded files
+func Decode(format string, r io.Reader) ([]models.ExternalMatchRecord, error) {
+	switch format {
+	case models.ImportConnectorJSON:
+		return decodeJSON(r)
+	case models.ImportConnectorCSV:
+		return decodeCSV(r)
+	}
+	return nil, fmt.Errorf("unknown format %s", format)
+}
+
+// feedConnector is the generic adapter: a JSON or CSV document served over HTTP
+type feedConnector struct {
+	url    string
+	format string
+}
+
+func (c *feedConnector) Fetch(ctx context.Context) ([]models.ExternalMatchRecord, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("source responded with status %d", resp.StatusCode)
+	}
+
+	return Decode(c.format, resp.Body)
+}
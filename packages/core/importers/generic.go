@@ -0,0 +1,99 @@
+package importers
+
+import (
+	"core/models"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decodeJSON reads an array of records
+func decodeJSON(r io.Reader) ([]models.ExternalMatchRecord, error) {
+	var records []models.ExternalMatchRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return records, nil
+}
+
+// decodeCSV reads a CSV document whose header names the record fields
+// (external_id, played_at, player1_id, player1_name, player2_id, player2_name, score1, score2, winner_id),
+// in any order; only external_id, played_at, player1_id and player2_id are required
+func decodeCSV(r io.Reader) ([]models.ExternalMatchRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"external_id", "played_at", "player1_id", "player2_id"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing CSV column %s", required)
+		}
+	}
+
+	var records []models.ExternalMatchRecord
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		playedAt, err := time.Parse(time.RFC3339, value("played_at"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid played_at, expected RFC 3339 date", line)
+		}
+
+		record := models.ExternalMatchRecord{
+			ExternalID:  value("external_id"),
+			PlayedAt:    playedAt,
+			Player1ID:   value("player1_id"),
+			Player1Name: value("player1_name"),
+			Player2ID:   value("player2_id"),
+			Player2Name: value("player2_name"),
+			WinnerID:    value("winner_id"),
+		}
+		if record.Score1, err = optionalInt(value("score1")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid score1", line)
+		}
+		if record.Score2, err = optionalInt(value("score2")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid score2", line)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func optionalInt(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultPublicMaxAge is how long anonymous GET responses may be reused, in seconds
+	defaultPublicMaxAge = 30
+	// defaultStaticMaxAge is how long static assets may be reused, in seconds (one year)
+	defaultStaticMaxAge = 365 * 24 * 60 * 60
+
+	cacheControlNoStore = "no-store"
+)
+
+// staticExtensions are the file types served as immutable assets (Swagger UI, admin UI)
+var staticExtensions = map[string]bool{
+	".js": true, ".css": true, ".png": true, ".svg": true, ".ico": true,
+	".woff": true, ".woff2": true, ".map": true,
+}
+
+// CacheControlConfig holds the max-age of each route class
+type CacheControlConfig struct {
+	Enabled      bool
+	PublicMaxAge int
+	StaticMaxAge int
+	// Overrides sets the public max-age of the paths starting with a prefix, the longest prefix wins
+	Overrides map[string]int
+}
+
+// CacheControlConfigFromEnv reads CACHE_CONTROL_ENABLED, CACHE_PUBLIC_MAX_AGE, CACHE_STATIC_MAX_AGE
+// and CACHE_PUBLIC_MAX_AGE_OVERRIDES ("/leaderboard=60,/public/v1=120")
+func CacheControlConfigFromEnv() CacheControlConfig {
+	config := CacheControlConfig{
+		Enabled:      os.Getenv("CACHE_CONTROL_ENABLED") != "false",
+		PublicMaxAge: defaultPublicMaxAge,
+		StaticMaxAge: defaultStaticMaxAge,
+		Overrides:    make(map[string]int),
+	}
+	if value, err := strconv.Atoi(os.Getenv("CACHE_PUBLIC_MAX_AGE")); err == nil && value >= 0 {
+		config.PublicMaxAge = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("CACHE_STATIC_MAX_AGE")); err == nil && value >= 0 {
+		config.StaticMaxAge = value
+	}
+	for _, override := range strings.Split(os.Getenv("CACHE_PUBLIC_MAX_AGE_OVERRIDES"), ",") {
+		prefix, maxAge, found := strings.Cut(strings.TrimSpace(override), "=")
+		if !found {
+			continue
+		}
+		if value, err := strconv.Atoi(strings.TrimSpace(maxAge)); err == nil && value >= 0 {
+			config.Overrides[strings.TrimSpace(prefix)] = value
+		}
+	}
+	return config
+}
+
+// cacheControlWriter replaces the default policy with no-store when the response turns out to be an error
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	policy string
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	// Keep a policy set by the handler itself
+	if code >= http.StatusBadRequest && w.Header().Get("Cache-Control") == w.policy {
+		w.Header().Set("Cache-Control", cacheControlNoStore)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// CacheControl sets a Cache-Control header per route class so that the reverse proxy and browsers
+// can absorb part of the load: static assets are immutable, anonymous reads get a short max-age,
+// and authenticated requests, writes and errors are never stored. Handlers may set their own header.
+func CacheControl(config CacheControlConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.Enabled {
+			c.Next()
+			return
+		}
+
+		policy := config.policyFor(c.Request)
+		c.Header("Cache-Control", policy)
+		if policy != cacheControlNoStore {
+			// Routes with an optional JWT answer differently once logged in
+			c.Writer.Header().Add("Vary", "Authorization")
+		}
+
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, policy: policy}
+		c.Next()
+	}
+}
+
+// policyFor classifies a request into a static asset, an anonymous read or a private request
+func (config CacheControlConfig) policyFor(r *http.Request) string {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return cacheControlNoStore
+	}
+
+	if staticExtensions[path.Ext(r.URL.Path)] {
+		return fmt.Sprintf("public, max-age=%d, immutable", config.StaticMaxAge)
+	}
+
+	if r.Header.Get("Authorization") != "" || r.Header.Get(KioskTokenHeader) != "" ||
+		r.Header.Get(PublicAPIKeyHeader) != "" || r.URL.Query().Get("token") != "" {
+		return cacheControlNoStore
+	}
+
+	maxAge := config.PublicMaxAge
+	longest := -1
+	for prefix, value := range config.Overrides {
+		if strings.HasPrefix(r.URL.Path, prefix) && len(prefix) > longest {
+			maxAge, longest = value, len(prefix)
+		}
+	}
+	if maxAge == 0 {
+		return cacheControlNoStore
+	}
+	return fmt.Sprintf("public, max-age=%d", maxAge)
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"core/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientVersionHeader carries the version of the mobile app, other clients do not send it
+const ClientVersionHeader = "X-Client-Version"
+
+// clientVersionExempt are the routes an outdated app still needs to show its upgrade screen
+var clientVersionExempt = map[string]bool{
+	"/client-config": true,
+	"/health":        true,
+}
+
+// RequireClientVersion rejects the app versions that are blocked or below the minimum with
+// 426 Upgrade Required. Requests without X-Client-Version (web, kiosk, public API) go through.
+func RequireClientVersion(clientVersionService *services.ClientVersionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := c.GetHeader(ClientVersionHeader)
+		if version == "" || clientVersionExempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		if upgrade := clientVersionService.Check(version); upgrade != nil {
+			c.AbortWithStatusJSON(http.StatusUpgradeRequired, upgrade)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCompressionMinSize is the response size below which compressing costs more than it saves, in bytes
+const defaultCompressionMinSize = 1024
+
+// compressibleTypes are the content types worth compressing, images and archives are already compressed
+var compressibleTypes = []string{
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+	"text/",
+}
+
+// CompressionConfig holds the size threshold and level of the response compression
+type CompressionConfig struct {
+	Enabled bool
+	MinSize int
+	Level   int
+}
+
+// CompressionConfigFromEnv reads COMPRESSION_ENABLED, COMPRESSION_MIN_SIZE and COMPRESSION_LEVEL (1 to 9)
+func CompressionConfigFromEnv() CompressionConfig {
+	config := CompressionConfig{
+		Enabled: os.Getenv("COMPRESSION_ENABLED") != "false",
+		MinSize: defaultCompressionMinSize,
+		Level:   gzip.DefaultCompression,
+	}
+	if value, err := strconv.Atoi(os.Getenv("COMPRESSION_MIN_SIZE")); err == nil && value >= 0 {
+		config.MinSize = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("COMPRESSION_LEVEL")); err == nil && value >= gzip.BestSpeed && value <= gzip.BestCompression {
+		config.Level = value
+	}
+	return config
+}
+
+// gzipWriter buffers the start of the response until it knows whether it is large and compressible enough,
+// then either streams it through gzip or writes it untouched
+type gzipWriter struct {
+	gin.ResponseWriter
+	config  CompressionConfig
+	pool    *sync.Pool
+	buffer  bytes.Buffer
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.config.MinSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(data string) (int, error) {
+	return w.Write([]byte(data))
+}
+
+// Flush sends what was buffered so far, streaming responses are compressed whatever their size
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) write(data []byte) (int, error) {
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// decide picks compressed or plain output from the headers set by the handler and writes the buffered bytes
+func (w *gzipWriter) decide(largeEnough bool) error {
+	w.decided = true
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && w.buffer.Len() > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buffer.Bytes()))
+	}
+
+	if largeEnough && w.compressible() {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := w.write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+func (w *gzipWriter) compressible() bool {
+	status := w.Status()
+	if status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		return false
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	for _, compressible := range compressibleTypes {
+		if strings.HasPrefix(contentType, compressible) {
+			return true
+		}
+	}
+	return false
+}
+
+// finish writes a response that stayed below the threshold and terminates the gzip stream
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// Compression gzips the responses larger than COMPRESSION_MIN_SIZE whose content type is textual,
+// for the clients that accept it. It must be registered before the middlewares reading the response body.
+func Compression(config CompressionConfig) gin.HandlerFunc {
+	pool := &sync.Pool{
+		New: func() interface{} {
+			gz, err := gzip.NewWriterLevel(nil, config.Level)
+			if err != nil {
+				return gzip.NewWriter(nil)
+			}
+			return gz
+		},
+	}
+
+	return func(c *gin.Context) {
+		if !config.Enabled || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" ||
+			!acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		writer := &gzipWriter{ResponseWriter: c.Writer, config: config, pool: pool}
+		c.Writer = writer
+		defer writer.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip (q=0 refuses it)
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"core/models"
+	"core/services"
+	"core/utils"
+	"io"
+	"time"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDebugBody caps each captured body once redacted
+const maxDebugBody = 8 * 1024
+
+// bodyCaptureWriter copies the response body while it is written to the client
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	if w.body.Len() < maxDebugBody*4 {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) WriteString(data string) (int, error) {
+	if w.body.Len() < maxDebugBody*4 {
+		w.body.WriteString(data)
+	}
+	return w.ResponseWriter.WriteString(data)
+}
+
+// DebugLogger captures the sanitized request and response bodies of the routes an admin enabled
+// a debug log rule for. Other routes go through untouched.
+func DebugLogger(debugLogService *services.DebugLogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startedAt := time.Now()
+		rule := debugLogService.ActiveRule(c.Request.Method, c.FullPath(), startedAt)
+		if rule == nil {
+			c.Next()
+			return
+		}
+
+		var requestBody []byte
+		if c.Request.Body != nil {
+			requestBody, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		entry := models.DebugLogEntry{
+			RuleID:       rule.ID,
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.Path,
+			Status:       writer.Status(),
+			DurationMs:   time.Since(startedAt).Milliseconds(),
+			RequestBody:  utils.RedactBody(requestBody, maxDebugBody),
+			ResponseBody: utils.RedactBody(writer.body.Bytes(), maxDebugBody),
+		}
+		if userID, ok := authMiddleware.GetUserID(c); ok {
+			entry.UserID = &userID
+		}
+		debugLogService.Record(entry)
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"core/events"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultErrorRateThreshold is the number of server errors within a minute that counts as a spike
+const defaultErrorRateThreshold = 20
+
+// ErrorRateMonitor counts 5xx responses per minute and reports a spike to the admin console
+// once per minute when they reach ERROR_RATE_SPIKE_THRESHOLD
+func ErrorRateMonitor(bus *events.Bus) gin.HandlerFunc {
+	threshold := defaultErrorRateThreshold
+	if value, err := strconv.Atoi(os.Getenv("ERROR_RATE_SPIKE_THRESHOLD")); err == nil && value > 0 {
+		threshold = value
+	}
+
+	var (
+		mu         sync.Mutex
+		minute     time.Time
+		serverErrs int
+		requests   int
+		reported   bool
+	)
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		now := time.Now().Truncate(time.Minute)
+
+		mu.Lock()
+		if !now.Equal(minute) {
+			minute, serverErrs, requests, reported = now, 0, 0, false
+		}
+		requests++
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			serverErrs++
+		}
+		spike := !reported && serverErrs >= threshold
+		if spike {
+			reported = true
+		}
+		errorCount, requestCount := serverErrs, requests
+		mu.Unlock()
+
+		if spike {
+			bus.Publish(events.TypeErrorRateSpike, map[string]interface{}{
+				"minute":    now,
+				"errors":    errorCount,
+				"requests":  requestCount,
+				"threshold": threshold,
+				"last_path": c.FullPath(),
+			})
+		}
+	}
+}
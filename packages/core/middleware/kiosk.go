@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	authMiddleware "auth/middleware"
+	"core/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KioskTokenHeader carries the token of a paired kiosk device
+const KioskTokenHeader = "X-Kiosk-Token"
+
+// RequireKiosk only lets through requests from a paired kiosk device
+func RequireKiosk(kioskService *services.KioskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(KioskTokenHeader)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "X-Kiosk-Token header required"})
+			c.Abort()
+			return
+		}
+
+		device, err := kioskService.Authenticate(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid kiosk token"})
+			c.Abort()
+			return
+		}
+
+		c.Set("kiosk_device_id", device.ID)
+		c.Next()
+	}
+}
+
+// RequireKioskOrJWT lets through paired kiosk devices and signed-in users: a request carrying a kiosk token
+// is authenticated as a kiosk, any other one needs a JWT
+func RequireKioskOrJWT(kioskService *services.KioskService) gin.HandlerFunc {
+	requireKiosk := RequireKiosk(kioskService)
+	requireJWT := authMiddleware.JWTMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader(KioskTokenHeader) != "" {
+			requireKiosk(c)
+			return
+		}
+		requireJWT(c)
+	}
+}
+
+// GetKioskDeviceID returns the device authenticated by RequireKiosk
+func GetKioskDeviceID(c *gin.Context) (uint, bool) {
+	deviceID, exists := c.Get("kiosk_device_id")
+	if !exists {
+		return 0, false
+	}
+	return deviceID.(uint), true
+}
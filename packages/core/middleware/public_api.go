@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"core/services"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// PublicAPIKeyHeader carries the public API token
+	PublicAPIKeyHeader = "X-API-Key"
+
+	publicAPIRateLimit  = 60
+	publicAPIRateWindow = time.Minute
+)
+
+// PublicAPI authenticates public API tokens and rate limits each token
+type PublicAPI struct {
+	service *services.PublicAPIService
+	limiter *utils.RateLimiter
+}
+
+func NewPublicAPI(service *services.PublicAPIService) *PublicAPI {
+	return &PublicAPI{
+		service: service,
+		limiter: utils.NewRateLimiter(publicAPIRateLimit, publicAPIRateWindow),
+	}
+}
+
+// RequireScope only lets through requests with an active token granting the scope
+func (p *PublicAPI) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(PublicAPIKeyHeader)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "X-API-Key header required"})
+			c.Abort()
+			return
+		}
+
+		apiToken, err := p.service.Authenticate(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+
+		if !apiToken.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":          "Insufficient scope",
+				"required_scope": scope,
+			})
+			c.Abort()
+			return
+		}
+
+		if allowed, retryAfter := p.limiter.Allow(strconv.FormatUint(uint64(apiToken.ID), 10)); !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Rate limit exceeded, try again later",
+				"retry_after": seconds,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("public_api_token_id", apiToken.ID)
+		c.Next()
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"core/events"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic into a 500 with the usual {"error"} body and the request ID, instead of
+// Gin's empty response, and reports it to the admin console with the stack trace logged.
+// It must be registered after RequestID and ErrorRateMonitor so that both see the request.
+func Recovery(bus *events.Bus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// The client went away, there is nobody to answer
+			if brokenPipe(recovered) {
+				c.Abort()
+				return
+			}
+
+			requestID := c.GetString(RequestIDKey)
+			log.Printf("[PANIC] %s %s (request %s): %v\n%s", c.Request.Method, c.Request.URL.Path, requestID, recovered, debug.Stack())
+
+			bus.Publish(events.TypePanic, map[string]interface{}{
+				"method":     c.Request.Method,
+				"path":       c.FullPath(),
+				"request_id": requestID,
+				"error":      fmt.Sprint(recovered),
+			})
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":      "Internal server error",
+				"request_id": requestID,
+			})
+		}()
+
+		c.Next()
+	}
+}
+
+// brokenPipe reports whether a panic comes from writing to a closed connection
+func brokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, http.ErrAbortHandler) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		var syscallErr *os.SyscallError
+		if errors.As(opErr, &syscallErr) {
+			message := strings.ToLower(syscallErr.Error())
+			return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"core/services"
+	"strings"
+	"time"
+
+	authMiddleware "auth/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route prefixes whose group is made of two segments, the next one names the feature
+var usageNestedPrefixes = map[string]bool{"admin": true, "public": true}
+
+// Probes and documentation, not features
+var usageIgnoredGroups = map[string]bool{"health": true, "readyz": true, "swagger": true, "openapi.json": true}
+
+// UsageTracker counts each request per user and endpoint group. It reads the user after the handler,
+// once the JWT middleware of the route authenticated them; unmatched routes are not counted.
+func UsageTracker(usageService *services.UsageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		group := endpointGroup(c.FullPath())
+		if group == "" {
+			return
+		}
+		userID, _ := authMiddleware.GetUserID(c)
+		usageService.Record(userID, group, time.Now())
+	}
+}
+
+// endpointGroup names the feature of a route: its first segment (/players/:id -> players), or the
+// first two under /admin and /public (/admin/jobs/:name/run -> admin/jobs, /public/v1/results -> public/results)
+func endpointGroup(route string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(route, "/"), "/") {
+		if segment == "" || segment[0] == ':' || segment[0] == '*' || (len(segment) > 1 && segment[0] == 'v' && segment[1] >= '0' && segment[1] <= '9') {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	if len(segments) == 0 || usageIgnoredGroups[segments[0]] {
+		return ""
+	}
+	if usageNestedPrefixes[segments[0]] && len(segments) > 1 {
+		return segments[0] + "/" + segments[1]
+	}
+	return segments[0]
+}
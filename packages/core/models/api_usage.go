@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// APIUsage counts the requests of a user to an endpoint group (players, matches, admin/jobs...) on a day.
+// Anonymous requests are counted under user 0.
+type APIUsage struct {
+	Day            time.Time `gorm:"type:date;primaryKey" json:"day"`
+	UserID         uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	EndpointGroup  string    `gorm:"size:50;primaryKey" json:"endpoint_group"`
+	RequestCount   int64     `gorm:"not null;default:0" json:"request_count"`
+	LastActivityAt time.Time `gorm:"not null" json:"last_activity_at"`
+}
+
+func (APIUsage) TableName() string {
+	return "api_usage"
+}
+
+// APIUsageGroup is the use of an endpoint group over a period
+type APIUsageGroup struct {
+	EndpointGroup     string    `json:"endpoint_group"`
+	Requests          int64     `json:"requests"`
+	AnonymousRequests int64     `json:"anonymous_requests"`
+	Users             int64     `json:"users"` // Distinct authenticated users
+	LastActivityAt    time.Time `json:"last_activity_at"`
+}
+
+// APIUsageReport is the answer of GET /admin/usage, for every user or for one
+type APIUsageReport struct {
+	From   time.Time       `json:"from"`
+	UserID *uint           `json:"user_id,omitempty"`
+	Groups []APIUsageGroup `json:"groups"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// ClientConfig is the server behavior the mobile app reads on startup instead of hard-coding it
+type ClientConfig struct {
+	Features            map[string]bool `json:"features"`
+	AutoValidationHours int             `json:"auto_validation_hours"` // Pending matches are confirmed automatically after this delay
+	EloFloor            float64         `json:"elo_floor"`
+	EloFloorMode        string          `json:"elo_floor_mode"` // clamp, zero_sum or none
+	StartingElo         float64         `json:"starting_elo"`
+	DailyMatchLimit     int             `json:"daily_match_limit"` // 0 = unlimited
+	AuthProviders       []string        `json:"auth_providers"`
+	MinClientVersion    string          `json:"min_client_version"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ClientVersionSetting is the single row of the client version policy managed by the admins.
+// Apps sending an X-Client-Version below MinVersion or listed in BlockedVersions are asked to upgrade.
+type ClientVersionSetting struct {
+	ID              uint      `gorm:"primaryKey" json:"-"`
+	MinVersion      string    `gorm:"size:20" json:"min_version"` // Empty falls back to MIN_CLIENT_VERSION
+	BlockedVersions []string  `gorm:"type:jsonb;serializer:json;not null" json:"blocked_versions"`
+	Message         string    `gorm:"size:255" json:"message"` // Shown by the app on the upgrade screen
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (ClientVersionSetting) TableName() string {
+	return "client_version_settings"
+}
+
+// DTOs
+
+type UpdateClientVersionSettingRequest struct {
+	MinVersion      string   `json:"min_version" binding:"omitempty,max=20"`
+	BlockedVersions []string `json:"blocked_versions" binding:"omitempty,dive,min=1,max=20"`
+	Message         string   `json:"message" binding:"omitempty,max=255"`
+}
+
+// UpgradeRequiredResponse is returned with 426 to the apps that must be upgraded
+type UpgradeRequiredResponse struct {
+	Error      string `json:"error" example:"Client version no longer supported"`
+	Code       string `json:"code" example:"upgrade_required"`
+	Version    string `json:"version" example:"1.2.0"`
+	MinVersion string `json:"min_version" example:"1.3.0"`
+	Message    string `json:"message,omitempty"`
+}
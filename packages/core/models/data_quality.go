@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Checks run by the integrity service
+const (
+	DataQualityStalePendingMatches     = "stale_pending_matches"
+	DataQualityStalePendingTeamMatches = "stale_pending_team_matches"
+	DataQualityUsersWithoutPlayer      = "users_without_player"
+	DataQualityUsernameMismatch        = "username_mismatch"
+	DataQualityTeamsWithDeletedPlayers = "teams_with_deleted_players"
+	DataQualityNegativePlayerCounters  = "negative_player_counters"
+	DataQualityNegativeTeamCounters    = "negative_team_counters"
+)
+
+// DataQualityCheck is the result of one integrity check, with a sample of the offending rows
+type DataQualityCheck struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int64  `json:"count"`
+	SampleIDs   []uint `json:"sample_ids"` // IDs of the first offending rows, lowest first
+}
+
+// DataQualityReport gathers every integrity check, including the ones that found nothing
+type DataQualityReport struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	IssueCount  int64              `json:"issue_count"`
+	Checks      []DataQualityCheck `json:"checks"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// DebugLogRule turns on the capture of sanitized request/response bodies for one route until it expires
+type DebugLogRule struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Method    string    `gorm:"size:10;not null" json:"method"`
+	Path      string    `gorm:"size:255;not null" json:"path"` // Route pattern, e.g. /matches/:id
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedBy *uint     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (DebugLogRule) TableName() string {
+	return "debug_log_rules"
+}
+
+// IsActiveAt checks whether the rule still captures requests at the given time
+func (r DebugLogRule) IsActiveAt(t time.Time) bool {
+	return t.Before(r.ExpiresAt)
+}
+
+// DebugLogEntry is one captured exchange, bodies are redacted before being stored
+type DebugLogEntry struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	RuleID       uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"rule_id"`
+	Method       string    `gorm:"size:10;not null" json:"method"`
+	Path         string    `gorm:"size:255;not null" json:"path"` // Actual request path, the query string is not kept
+	Status       int       `gorm:"not null" json:"status"`
+	DurationMs   int64     `gorm:"not null" json:"duration_ms"`
+	UserID       *uint     `json:"user_id"`
+	RequestBody  string    `gorm:"type:text" json:"request_body"`
+	ResponseBody string    `gorm:"type:text" json:"response_body"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (DebugLogEntry) TableName() string {
+	return "debug_log_entries"
+}
+
+// DTOs
+
+type CreateDebugLogRuleRequest struct {
+	Method          string `json:"method" binding:"required,oneof=GET POST PUT PATCH DELETE"`
+	Path            string `json:"path" binding:"required,startswith=/"`
+	DurationMinutes int    `json:"duration_minutes" binding:"required,min=1,max=240"`
+}
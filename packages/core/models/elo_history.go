@@ -6,21 +6,33 @@ import (
 	"gorm.io/gorm"
 )
 
+// EloHistoryAllView unions elo_history with elo_history_archive, where entries older than
+// ELO_HISTORY_ARCHIVE_MONTHS are moved; full-history reads go through it
+const EloHistoryAllView = "elo_history_all"
+
+// Kinds of ELO history entries
+const (
+	EloHistoryKindMatch = "match"
+	EloHistoryKindReset = "reset" // Rating reset approved by an admin, no match
+)
+
 type EloHistory struct {
-	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	PlayerID   uint           `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
-	MatchID    uint           `gorm:"not null;constraint:OnDelete:CASCADE" json:"match_id"`
-	EloBefore  float64        `gorm:"not null" json:"elo_before"`
-	EloAfter   float64        `gorm:"not null" json:"elo_after"`
-	EloChange  float64        `gorm:"not null" json:"elo_change"`
-	OpponentID *uint          `json:"opponent_id"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlayerID    uint           `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
+	MatchID     *uint          `gorm:"constraint:OnDelete:CASCADE" json:"match_id"`
+	Kind        string         `gorm:"size:20;not null;default:match" json:"kind"`
+	EloBefore   float64        `gorm:"not null" json:"elo_before"`
+	EloAfter    float64        `gorm:"not null" json:"elo_after"`
+	EloChange   float64        `gorm:"not null" json:"elo_change"`
+	OpponentID  *uint          `json:"opponent_id"`
+	FloorExempt bool           `gorm:"not null;default:false" json:"floor_exempt"` // The ELO floor did not apply: flagged match or calibration
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
 	Player   Player  `gorm:"foreignKey:PlayerID;references:ID" json:"player,omitempty"`
-	Match    Match   `gorm:"foreignKey:MatchID;references:ID" json:"match,omitempty"`
+	Match    *Match  `gorm:"foreignKey:MatchID;references:ID" json:"match,omitempty"`
 	Opponent *Player `gorm:"foreignKey:OpponentID;references:ID" json:"opponent,omitempty"`
 }
 
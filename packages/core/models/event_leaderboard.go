@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// Event leaderboard statuses, derived from the time window
+const (
+	EventLeaderboardUpcoming = "upcoming"
+	EventLeaderboardLive     = "live"
+	EventLeaderboardEnded    = "ended"
+)
+
+// EventStartingRating is the rating every player starts an event leaderboard with
+const EventStartingRating = 1200.0
+
+// EventLeaderboard is a temporary ladder (e.g. Integration Week) computed from the confirmed solo
+// matches of its time window with a rating of its own, the main ELO is never touched. With opt-in,
+// only the matches between two players who joined the event count.
+type EventLeaderboard struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Description *string   `gorm:"type:text" json:"description"`
+	StartsAt    time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt      time.Time `gorm:"not null" json:"ends_at"`
+	OptIn       bool      `gorm:"not null;default:false" json:"opt_in"`
+	CreatedBy   *uint     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Status       string `gorm:"-" json:"status"`       // upcoming, live or ended
+	Participants int64  `gorm:"-" json:"participants"` // Players who joined, opt-in events only
+}
+
+func (EventLeaderboard) TableName() string {
+	return "event_leaderboards"
+}
+
+// StatusAt returns whether the event is upcoming, live or ended at the given time
+func (e *EventLeaderboard) StatusAt(now time.Time) string {
+	switch {
+	case now.Before(e.StartsAt):
+		return EventLeaderboardUpcoming
+	case now.Before(e.EndsAt):
+		return EventLeaderboardLive
+	default:
+		return EventLeaderboardEnded
+	}
+}
+
+// EventLeaderboardParticipant records that a player joined an opt-in event
+type EventLeaderboardParticipant struct {
+	EventLeaderboardID uint      `gorm:"primaryKey" json:"event_leaderboard_id"`
+	PlayerID           uint      `gorm:"primaryKey" json:"player_id"`
+	JoinedAt           time.Time `json:"joined_at"`
+}
+
+func (EventLeaderboardParticipant) TableName() string {
+	return "event_leaderboard_participants"
+}
+
+// EventStanding is the line of a player in an event leaderboard
+type EventStanding struct {
+	Rank        int     `json:"rank"`
+	PlayerID    uint    `json:"player_id"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name"`
+	Rating      float64 `json:"rating"` // Event rating, starting at 1200
+	Matches     int     `json:"matches"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	WinRate     float64 `json:"win_rate"`
+}
+
+type EventStandingsResponse struct {
+	Event      EventLeaderboard `json:"event"`
+	Matches    int              `json:"matches"` // Matches counted in the window
+	Standings  []EventStanding  `json:"standings"`
+	ComputedAt time.Time        `json:"computed_at"`
+}
+
+// DTOs
+
+type CreateEventLeaderboardRequest struct {
+	Name        string    `json:"name" binding:"required,max=255"`
+	Description *string   `json:"description,omitempty"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required,gtfield=StartsAt"`
+	OptIn       bool      `json:"opt_in"` // Only players who joined the event are ranked
+}
+
+type UpdateEventLeaderboardRequest struct {
+	Name        *string    `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string    `json:"description,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	OptIn       *bool      `json:"opt_in,omitempty"`
+}
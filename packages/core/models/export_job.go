@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Kinds of export job
+const (
+	ExportKindMatches      = "matches"       // Full match history, CSV
+	ExportKindPlayers      = "players"       // Every player, CSV
+	ExportKindPersonalData = "personal_data" // Everything stored about a player and their account, JSON (GDPR)
+)
+
+// Statuses of an export job
+const (
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+	ExportStatusExpired = "expired" // The file was deleted, the export must be requested again
+)
+
+// ExportJob is an export produced in the background into a file, downloaded through a signed URL
+// once done, so that large exports do not run into request timeouts
+type ExportJob struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Kind        string `gorm:"size:30;not null" json:"kind"`
+	Status      string `gorm:"size:20;not null;default:pending;index" json:"status"`
+	RequestedBy uint   `gorm:"not null;index" json:"requested_by"` // User ID
+
+	// Filters: the player of a personal data export, or the matches of a player; status and dates for matches
+	PlayerID    *uint      `json:"player_id,omitempty"`
+	MatchStatus *string    `gorm:"size:20" json:"match_status,omitempty"`
+	DateFrom    *time.Time `json:"date_from,omitempty"`
+	DateTo      *time.Time `json:"date_to,omitempty"`
+
+	FileName   string     `gorm:"size:100" json:"file_name,omitempty"` // Name suggested to the browser
+	FilePath   string     `gorm:"size:500" json:"-"`
+	Size       int64      `json:"size"` // Bytes
+	Rows       int        `json:"rows"`
+	Error      *string    `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // The file is deleted after this time
+
+	// Signed download URL, valid until the file expires, set when the job is done
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
+
+// DTOs
+
+type CreateExportRequest struct {
+	Kind string `json:"kind" binding:"required,oneof=matches players personal_data"`
+	// personal_data: the player to export, the requester by default (admin for another player);
+	// matches: only the matches of this player
+	PlayerID *uint   `json:"player_id"`
+	Status   *string `json:"status" binding:"omitempty,oneof=pending confirmed rejected cancelled"` // matches only
+	DateFrom *string `json:"date_from" binding:"omitempty,datetime=2006-01-02"`                     // matches only, YYYY-MM-DD
+	DateTo   *string `json:"date_to" binding:"omitempty,datetime=2006-01-02"`                       // matches only, YYYY-MM-DD
+}
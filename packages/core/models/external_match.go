@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// Import connectors
+const (
+	ImportConnectorJSON = "json"
+	ImportConnectorCSV  = "csv"
+)
+
+// PlayerExternalID maps a player to their identifier in an external source (e.g. federation licence number)
+type PlayerExternalID struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlayerID   uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
+	Source     string    `gorm:"size:50;not null" json:"source"` // Name of the import source
+	ExternalID string    `gorm:"size:255;not null" json:"external_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (PlayerExternalID) TableName() string {
+	return "player_external_ids"
+}
+
+// ImportSource is an external source of matches, read by a connector
+type ImportSource struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name            string     `gorm:"size:50;uniqueIndex;not null" json:"name"`
+	Connector       string     `gorm:"size:20;not null" json:"connector"` // json, csv
+	URL             *string    `json:"url"`                               // Pulled daily when set, otherwise files are uploaded
+	Enabled         bool       `gorm:"default:true" json:"enabled"`
+	LastImportAt    *time.Time `json:"last_import_at"`
+	LastImportCount int        `gorm:"default:0" json:"last_import_count"`
+	LastError       *string    `json:"last_error"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (ImportSource) TableName() string {
+	return "import_sources"
+}
+
+// ExternalMatch is a match played outside the association, from the point of view of one
+// of our players. It is shown in the player's history but never affects ratings.
+type ExternalMatch struct {
+	ID                 uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	SourceID           uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"source_id"`
+	ExternalID         string    `gorm:"size:255;not null" json:"external_id"`
+	PlayerID           uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
+	OpponentPlayerID   *uint     `gorm:"constraint:OnDelete:SET NULL" json:"opponent_player_id"` // Set when the opponent is one of our players
+	OpponentExternalID string    `gorm:"size:255" json:"opponent_external_id"`
+	OpponentName       string    `gorm:"size:255" json:"opponent_name"`
+	Won                bool      `json:"won"`
+	ScoreFor           *int      `json:"score_for"`
+	ScoreAgainst       *int      `json:"score_against"`
+	PlayedAt           time.Time `json:"played_at"`
+	CreatedAt          time.Time `json:"created_at"`
+
+	// Relationships
+	Source ImportSource `gorm:"foreignKey:SourceID" json:"source,omitempty"`
+}
+
+func (ExternalMatch) TableName() string {
+	return "external_matches"
+}
+
+// ExternalMatchRecord is a match as read by a connector, identified by external player IDs.
+// The winner is WinnerID, or deduced from the scores when empty.
+type ExternalMatchRecord struct {
+	ExternalID  string    `json:"external_id"`
+	PlayedAt    time.Time `json:"played_at"`
+	Player1ID   string    `json:"player1_id"`
+	Player1Name string    `json:"player1_name"`
+	Player2ID   string    `json:"player2_id"`
+	Player2Name string    `json:"player2_name"`
+	Score1      *int      `json:"score1"`
+	Score2      *int      `json:"score2"`
+	WinnerID    string    `json:"winner_id"`
+}
+
+// DTOs
+
+type CreateImportSourceRequest struct {
+	Name      string  `json:"name" binding:"required,max=50"`
+	Connector string  `json:"connector" binding:"required,oneof=json csv"`
+	URL       *string `json:"url,omitempty" binding:"omitempty,url"`
+}
+
+type AddPlayerExternalIDRequest struct {
+	Source     string `json:"source" binding:"required"`
+	ExternalID string `json:"external_id" binding:"required"`
+}
+
+// ImportResult summarizes an import run
+type ImportResult struct {
+	Fetched   int      `json:"fetched"`
+	Imported  int      `json:"imported"`  // Matches added to at least one player's history
+	Duplicate int      `json:"duplicate"` // Already imported
+	Unmapped  int      `json:"unmapped"`  // Neither player is mapped to one of ours
+	Invalid   int      `json:"invalid"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+type PaginatedExternalMatchesResponse struct {
+	Data       []ExternalMatch `json:"data"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
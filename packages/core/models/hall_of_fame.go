@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// HallOfFameEntry is a player holding an all-time record
+type HallOfFameEntry struct {
+	PlayerID    uint       `json:"player_id"`
+	Username    string     `json:"username"`
+	DisplayName *string    `json:"display_name"`
+	Value       float64    `json:"value"`
+	AchievedAt  *time.Time `json:"achieved_at,omitempty"` // When the record was set, if known
+}
+
+// HallOfFame lists the record holders of each category, best first
+type HallOfFame struct {
+	HighestElo         []HallOfFameEntry `json:"highest_elo"`
+	HighestTeamElo     []HallOfFameEntry `json:"highest_team_elo"`
+	LongestWinStreak   []HallOfFameEntry `json:"longest_win_streak"`
+	MostTournamentWins []HallOfFameEntry `json:"most_tournament_wins"`
+	MostMatches        []HallOfFameEntry `json:"most_matches"`
+	ComputedAt         time.Time         `json:"computed_at"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// KioskDevice is a shared device (e.g. the clubroom tablet) allowed to record matches.
+// An admin registers it and gets a short-lived PIN; the device exchanges the PIN for its
+// kiosk token. Only hashes of the PIN and token are stored.
+type KioskDevice struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string     `gorm:"size:255;not null" json:"name"`
+	PinHash      *string    `gorm:"size:64" json:"-"`
+	PinExpiresAt *time.Time `json:"pin_expires_at"`
+	TokenHash    *string    `gorm:"size:64;uniqueIndex" json:"-"`
+	PairedAt     *time.Time `json:"paired_at"`
+	LastSeenAt   *time.Time `json:"last_seen_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedBy    *uint      `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (KioskDevice) TableName() string {
+	return "kiosk_devices"
+}
+
+// DTOs
+
+type CreateKioskDeviceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type PairKioskDeviceRequest struct {
+	Pin string `json:"pin" binding:"required,len=6,numeric"`
+}
+
+// KioskPairingResponse contains the PIN to enter on the device, it is only shown once
+type KioskPairingResponse struct {
+	Device KioskDevice `json:"device"`
+	Pin    string      `json:"pin"`
+}
+
+// KioskTokenResponse contains the kiosk token, to send in the X-Kiosk-Token header
+type KioskTokenResponse struct {
+	Device KioskDevice `json:"device"`
+	Token  string      `json:"token"`
+}
+
+type CreateKioskMatchRequest struct {
+	Player1ID uint `json:"player1_id" binding:"required"`
+	Player2ID uint `json:"player2_id" binding:"required"`
+	WinnerID  uint `json:"winner_id" binding:"required"`
+	// Sending the request again with the same UUID returns the match created the first time
+	ClientUUID *string `json:"client_uuid,omitempty" binding:"omitempty,uuid"`
+}
+
+// KioskPlayer is the player shown in the kiosk selection list
+type KioskPlayer struct {
+	ID          uint    `json:"id"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name"`
+	EloRating   float64 `json:"elo_rating"`
+}
+
+// CardUIDRequest carries a card UID as read by the NFC reader (hex, separators are ignored)
+type CardUIDRequest struct {
+	UID string `json:"uid" binding:"required,max=64"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeaderboardEntry is a row of the leaderboard materialized view: ratings, streaks and
+// last match of a player, precomputed by the scheduler so the public leaderboard is a single read
+type LeaderboardEntry struct {
+	PlayerID      uint       `gorm:"primaryKey" json:"player_id"`
+	Username      string     `json:"username"`
+	DisplayName   *string    `gorm:"->" json:"display_name"` // Joined at read time, like the flair
+	EloRating     float64    `json:"elo_rating"`
+	Rank          int        `json:"rank"`
+	TotalMatches  int        `json:"total_matches"`
+	Wins          int        `json:"wins"`
+	Losses        int        `json:"losses"`
+	WinRate       float64    `json:"win_rate"`
+	CurrentStreak int        `json:"current_streak"` // Consecutive confirmed wins up to the last match
+	BestStreak    int        `json:"best_streak"`
+	LastMatchAt   *time.Time `json:"last_match_at"`
+	AwayFrom      *time.Time `json:"-"`
+	AwayUntil     *time.Time `json:"-"`
+	Away          bool       `gorm:"-" json:"away"`
+	Flair         *string    `gorm:"->" json:"flair"`                 // Name of the title shown by the player, joined at read time
+	CampusRank    *int       `gorm:"->" json:"campus_rank,omitempty"` // Rank among the players of the campus, when filtered by campus
+}
+
+func (LeaderboardEntry) TableName() string {
+	return "leaderboard"
+}
+
+// AfterFind sets the away badge, computed at read time since the view is only refreshed on changes
+func (e *LeaderboardEntry) AfterFind(tx *gorm.DB) error {
+	now := time.Now()
+	e.Away = e.AwayFrom != nil && e.AwayUntil != nil && !now.Before(*e.AwayFrom) && now.Before(*e.AwayUntil)
+	return nil
+}
+
+type LeaderboardResponse struct {
+	Data        []LeaderboardEntry `json:"data"`
+	Total       int64              `json:"total"`
+	Page        int                `json:"page"`
+	PageSize    int                `json:"pageSize"`
+	TotalPages  int                `json:"totalPages"`
+	RefreshedAt *time.Time         `json:"refreshed_at"`
+}
@@ -0,0 +1,145 @@
+package models
+
+import "time"
+
+// Live match statuses
+const (
+	LiveMatchInProgress = "in_progress"
+	LiveMatchFinished   = "finished"
+	LiveMatchAbandoned  = "abandoned"
+)
+
+type LiveMatch struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Player1ID    uint       `gorm:"not null" json:"player1_id"`
+	Player2ID    uint       `gorm:"not null" json:"player2_id"`
+	Player1Score int        `gorm:"default:0" json:"player1_score"`
+	Player2Score int        `gorm:"default:0" json:"player2_score"`
+	Status       string     `gorm:"size:20;default:in_progress" json:"status"` // in_progress, finished, abandoned
+	StartedBy    *uint      `json:"started_by"`
+	TournamentID *uint      `json:"tournament_id"`
+	MatchID      *uint      `json:"match_id"` // Match created when the live match is finalized
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Timer, rules are copied from the tournament when the live match starts
+	TimeLimitSeconds *int       `json:"time_limit_seconds"`
+	GoldenGoal       bool       `gorm:"default:false" json:"golden_goal"`
+	PausedAt         *time.Time `json:"paused_at"`
+	PausedSeconds    int        `gorm:"default:0" json:"paused_seconds"`
+
+	// Relationships
+	Player1 Player      `gorm:"foreignKey:Player1ID;references:ID" json:"player1,omitempty"`
+	Player2 Player      `gorm:"foreignKey:Player2ID;references:ID" json:"player2,omitempty"`
+	Goals   []MatchGoal `gorm:"foreignKey:LiveMatchID" json:"goals,omitempty"`
+}
+
+func (LiveMatch) TableName() string {
+	return "live_matches"
+}
+
+// Timer phases of a live match
+const (
+	TimerRunning    = "running"
+	TimerPaused     = "paused"
+	TimerGoldenGoal = "golden_goal" // Time is up with a tied score: next goal wins
+	TimerTimeOver   = "time_over"
+)
+
+// LiveMatchTimer is the timer state shared by referee devices.
+// Devices sync on ServerTime and ElapsedSeconds then tick locally.
+type LiveMatchTimer struct {
+	LiveMatchID      uint      `json:"live_match_id"`
+	Phase            string    `json:"phase"` // running, paused, golden_goal, time_over, finished, abandoned
+	ServerTime       time.Time `json:"server_time"`
+	StartedAt        time.Time `json:"started_at"`
+	ElapsedSeconds   int       `json:"elapsed_seconds"`
+	TimeLimitSeconds *int      `json:"time_limit_seconds"`
+	RemainingSeconds *int      `json:"remaining_seconds"`
+	GoldenGoal       bool      `json:"golden_goal"`
+	Player1Score     int       `json:"player1_score"`
+	Player2Score     int       `json:"player2_score"`
+}
+
+// ElapsedSeconds returns the playing time, pauses excluded
+func (m *LiveMatch) ElapsedSeconds(now time.Time) int {
+	end := now
+	if m.FinishedAt != nil {
+		end = *m.FinishedAt
+	}
+	if m.PausedAt != nil {
+		end = *m.PausedAt
+	}
+
+	elapsed := int(end.Sub(m.StartedAt).Seconds()) - m.PausedSeconds
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// Timer computes the timer state of the live match at the given time
+func (m *LiveMatch) Timer(now time.Time) LiveMatchTimer {
+	timer := LiveMatchTimer{
+		LiveMatchID:      m.ID,
+		ServerTime:       now,
+		StartedAt:        m.StartedAt,
+		ElapsedSeconds:   m.ElapsedSeconds(now),
+		TimeLimitSeconds: m.TimeLimitSeconds,
+		GoldenGoal:       m.GoldenGoal,
+		Player1Score:     m.Player1Score,
+		Player2Score:     m.Player2Score,
+	}
+
+	if m.TimeLimitSeconds != nil {
+		remaining := *m.TimeLimitSeconds - timer.ElapsedSeconds
+		if remaining < 0 {
+			remaining = 0
+		}
+		timer.RemainingSeconds = &remaining
+	}
+
+	switch {
+	case m.Status != LiveMatchInProgress:
+		timer.Phase = m.Status
+	case timer.RemainingSeconds != nil && *timer.RemainingSeconds == 0:
+		if m.GoldenGoal && m.Player1Score == m.Player2Score {
+			timer.Phase = TimerGoldenGoal
+		} else {
+			timer.Phase = TimerTimeOver
+		}
+	case m.PausedAt != nil:
+		timer.Phase = TimerPaused
+	default:
+		timer.Phase = TimerRunning
+	}
+
+	return timer
+}
+
+// MatchGoal is one entry of a match score timeline
+type MatchGoal struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	LiveMatchID  uint      `gorm:"not null;index" json:"live_match_id"`
+	MatchID      *uint     `gorm:"index" json:"match_id"`
+	ScorerID     uint      `gorm:"not null" json:"scorer_id"`
+	Player1Score int       `gorm:"not null" json:"player1_score"` // Score after this goal
+	Player2Score int       `gorm:"not null" json:"player2_score"`
+	ScoredAt     time.Time `json:"scored_at"`
+}
+
+func (MatchGoal) TableName() string {
+	return "match_goals"
+}
+
+type StartLiveMatchRequest struct {
+	Player1ID    uint  `json:"player1_id" binding:"required"`
+	Player2ID    uint  `json:"player2_id" binding:"required"`
+	TournamentID *uint `json:"tournament_id,omitempty"`
+}
+
+type RecordGoalRequest struct {
+	ScorerID uint `json:"scorer_id" binding:"required"`
+}
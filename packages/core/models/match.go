@@ -17,7 +17,23 @@ type Match struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
-	TournamentID *uint `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"`
+	TournamentID  *uint `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"`
+	RefereeID     *uint `gorm:"constraint:OnDelete:SET NULL" json:"referee_id"`      // User with the referee role, tournament matches only
+	KioskDeviceID *uint `gorm:"constraint:OnDelete:SET NULL" json:"kiosk_device_id"` // Set when recorded on a kiosk device
+	OnHold        bool  `gorm:"not null;default:false" json:"on_hold"`               // Held by the anomaly review, cannot be confirmed
+	FloorExempt   bool  `gorm:"not null;default:false" json:"floor_exempt"`          // Set by an admin, the ELO floor does not hold the players of this match
+
+	// Generated by the client that submitted the match, makes the submission idempotent
+	ClientUUID *string `gorm:"size:36;uniqueIndex" json:"client_uuid"`
+
+	// Scoreboard photo, required to confirm the match under a strict photo policy
+	PhotoURL *string `json:"photo_url"`
+	Disputed bool    `gorm:"not null;default:false" json:"disputed"` // Result contested: winner corrected, or held by the anomaly review
+
+	// Two-player confirmation mode, fixed from the season setting when the match is created
+	RequiresBothConfirmations bool       `gorm:"not null;default:false" json:"requires_both_confirmations"`
+	Player1ConfirmedAt        *time.Time `json:"player1_confirmed_at"`
+	Player2ConfirmedAt        *time.Time `json:"player2_confirmed_at"`
 
 	// Relationships
 	Player1    Player      `gorm:"foreignKey:Player1ID;references:ID" json:"player1,omitempty"`
@@ -43,9 +59,17 @@ type CreateMatchRequest struct {
 	Player2ID    uint  `json:"player2_id" binding:"required"`
 	WinnerID     uint  `json:"winner_id" binding:"required"`
 	TournamentID *uint `json:"tournament_id,omitempty"`
+	// Sending the request again with the same UUID returns the match created the first time
+	ClientUUID *string `json:"client_uuid,omitempty" binding:"omitempty,uuid"`
 }
 
 type UpdateMatchStatusRequest struct {
 	Status   *string `json:"status,omitempty" binding:"omitempty,oneof=confirmed rejected cancelled"`
 	WinnerID *uint   `json:"winner_id,omitempty"`
+	PhotoURL *string `json:"photo_url,omitempty" binding:"omitempty,url"` // Scoreboard photo, see the photo policy
+}
+
+// UpdateFloorExemptionRequest flags a pending match so that the ELO floor does not hold its players
+type UpdateFloorExemptionRequest struct {
+	FloorExempt *bool `json:"floor_exempt" binding:"required"`
 }
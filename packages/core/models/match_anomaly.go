@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Kinds of suspicious patterns flagged by the anomaly detection job
+const (
+	AnomalyKindRapidRematches = "rapid_rematches" // Many matches between the same pair in a few minutes
+	AnomalyKindUpsetStreak    = "upset_streak"    // Win streak against much higher-rated opponents
+)
+
+// Review statuses of an anomaly
+const (
+	AnomalyStatusOpen      = "open"
+	AnomalyStatusDismissed = "dismissed" // False positive, a held match is released
+	AnomalyStatusConfirmed = "confirmed" // Cheating confirmed, a held pending match is rejected
+)
+
+// MatchAnomaly is an entry of the admin review queue, flagging a match that looks suspicious
+type MatchAnomaly struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	MatchID    uint       `gorm:"not null;constraint:OnDelete:CASCADE" json:"match_id"`
+	PlayerID   uint       `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"` // Player the pattern was detected for
+	Kind       string     `gorm:"size:30;not null" json:"kind"`
+	Details    string     `gorm:"type:text" json:"details"`
+	Status     string     `gorm:"size:20;not null;default:open" json:"status"`
+	ReviewedBy *uint      `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relationships
+	Match  *Match  `gorm:"foreignKey:MatchID" json:"match,omitempty"`
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+func (MatchAnomaly) TableName() string {
+	return "match_anomalies"
+}
+
+// DTOs
+
+type ReviewAnomalyRequest struct {
+	Status string `json:"status" binding:"required,oneof=dismissed confirmed"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Kinds of the matches of the unified feed
+const (
+	MatchKindSolo = "solo"
+	MatchKindTeam = "team"
+)
+
+// MatchFeedItem is a solo or a team match of the unified feed: Match is set for solo matches,
+// TeamMatch for team matches
+type MatchFeedItem struct {
+	MatchKind string     `json:"match_kind"`
+	ID        uint       `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Match     *Match     `json:"match,omitempty"`
+	TeamMatch *TeamMatch `json:"team_match,omitempty"`
+}
+
+type PaginatedMatchFeedResponse struct {
+	Data       []MatchFeedItem `json:"data"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Monthly award kinds
+const (
+	MonthlyAwardMostImproved = "most_improved" // Biggest solo ELO gain over the month
+	MonthlyAwardMostActive   = "most_active"   // Most confirmed matches over the month, solo and team
+	MonthlyAwardGiantKiller  = "giant_killer"  // Solo win against the opponent rated the furthest above the winner
+)
+
+// MonthlyAwardKinds lists the monthly awards, in announcement order
+var MonthlyAwardKinds = []string{MonthlyAwardMostImproved, MonthlyAwardMostActive, MonthlyAwardGiantKiller}
+
+var monthlyAwardNames = map[string]string{
+	MonthlyAwardMostImproved: "Progression du mois",
+	MonthlyAwardMostActive:   "Joueur le plus actif du mois",
+	MonthlyAwardGiantKiller:  "Tueur de géants du mois",
+}
+
+// MonthlyAwardMonthLayout is the format of MonthlyAward.Month, e.g. 2026-09
+const MonthlyAwardMonthLayout = "2006-01"
+
+// MonthlyAwardName is the name of the trophy of an award, e.g. "Progression du mois 2026-09"
+func MonthlyAwardName(kind string, month time.Time) string {
+	return fmt.Sprintf("%s %s", monthlyAwardNames[kind], month.Format(MonthlyAwardMonthLayout))
+}
+
+// MonthlyAward is an award computed at the end of a month, given to a player as a trophy
+type MonthlyAward struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Month     string    `gorm:"size:7;not null;uniqueIndex:idx_monthly_awards_month_kind" json:"month"`
+	Kind      string    `gorm:"size:30;not null;uniqueIndex:idx_monthly_awards_month_kind" json:"kind"`
+	PlayerID  uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
+	TrophyID  *uint     `gorm:"constraint:OnDelete:SET NULL" json:"trophy_id"`
+	Value     float64   `json:"value"` // ELO gained, matches played or rating gap beaten, depending on the kind
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+	Trophy *Trophy `gorm:"foreignKey:TrophyID" json:"trophy,omitempty"`
+}
+
+func (MonthlyAward) TableName() string {
+	return "monthly_awards"
+}
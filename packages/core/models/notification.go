@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// Notification types
+const (
+	NotificationMatchPending       = "match_pending"
+	NotificationMatchAutoValidated = "match_auto_validated"
+	NotificationTournamentEdition  = "tournament_edition"
+	NotificationWaitlistPromoted   = "waitlist_promoted"
+	NotificationMonthlyAward       = "monthly_award"
+)
+
+// Notification statuses
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+)
+
+// Default notification preferences, used until a player saves their own
+const (
+	DefaultQuietHoursStart    = 22
+	DefaultQuietHoursEnd      = 8
+	DefaultNotificationZone   = "Europe/Paris"
+	DefaultBatchWindowMinutes = 15
+)
+
+type Notification struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	Type         string     `gorm:"size:50;not null" json:"type"`
+	Message      string     `gorm:"not null" json:"message"`
+	MatchID      *uint      `json:"match_id"`
+	TeamMatchID  *uint      `json:"team_match_id"`
+	TournamentID *uint      `json:"tournament_id"`
+	Status       string     `gorm:"size:20;default:pending" json:"status"` // pending, sent
+	CreatedAt    time.Time  `json:"created_at"`
+	SentAt       *time.Time `json:"sent_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+type NotificationPreference struct {
+	UserID             uint      `gorm:"primaryKey" json:"user_id"`
+	QuietHoursEnabled  bool      `gorm:"default:true" json:"quiet_hours_enabled"`
+	QuietHoursStart    int       `gorm:"default:22" json:"quiet_hours_start"`
+	QuietHoursEnd      int       `gorm:"default:8" json:"quiet_hours_end"`
+	Timezone           string    `gorm:"size:64;default:Europe/Paris" json:"timezone"`
+	BatchWindowMinutes int       `gorm:"default:15" json:"batch_window_minutes"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference returns the preferences applied to players who never saved any
+func DefaultNotificationPreference(userID uint) NotificationPreference {
+	return NotificationPreference{
+		UserID:             userID,
+		QuietHoursEnabled:  true,
+		QuietHoursStart:    DefaultQuietHoursStart,
+		QuietHoursEnd:      DefaultQuietHoursEnd,
+		Timezone:           DefaultNotificationZone,
+		BatchWindowMinutes: DefaultBatchWindowMinutes,
+	}
+}
+
+// InQuietHours reports whether t falls inside the player's quiet hours (in their timezone).
+// Quiet hours may wrap around midnight, e.g. 22h -> 8h.
+func (p NotificationPreference) InQuietHours(t time.Time) bool {
+	if !p.QuietHoursEnabled || p.QuietHoursStart == p.QuietHoursEnd {
+		return false
+	}
+
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	hour := t.In(location).Hour()
+
+	if p.QuietHoursStart < p.QuietHoursEnd {
+		return hour >= p.QuietHoursStart && hour < p.QuietHoursEnd
+	}
+	return hour >= p.QuietHoursStart || hour < p.QuietHoursEnd
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	QuietHoursEnabled  *bool   `json:"quiet_hours_enabled,omitempty"`
+	QuietHoursStart    *int    `json:"quiet_hours_start,omitempty" binding:"omitempty,min=0,max=23"`
+	QuietHoursEnd      *int    `json:"quiet_hours_end,omitempty" binding:"omitempty,min=0,max=23"`
+	Timezone           *string `json:"timezone,omitempty"`
+	BatchWindowMinutes *int    `json:"batch_window_minutes,omitempty" binding:"omitempty,min=0,max=1440"`
+}
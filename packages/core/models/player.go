@@ -1,42 +1,135 @@
 package models
 
 import (
+	"core/clock"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// modelsClock tells the time to the flags computed on load (away badge)
+var modelsClock clock.Clock = clock.Real
+
+// SetClock replaces the wall clock used by the flags computed on load
+func SetClock(c clock.Clock) {
+	modelsClock = c
+}
+
 type Player struct {
 	ID           uint    `gorm:"primaryKey" json:"id"`
-	Username     string  `gorm:"size:255;not null" json:"username"`
+	Username     string  `gorm:"size:255;not null" json:"username"` // Login identity and slug, unique
+	DisplayName  *string `gorm:"size:50" json:"display_name"`       // Shown on public payloads, changed freely and moderated
 	EloRating    float64 `gorm:"default:1200" json:"elo_rating"`
-	Rank         int     `gorm:"default:1" json:"rank"`
+	Rank         int     `gorm:"default:1" json:"rank"` // 0 while archived
 	TotalMatches int     `gorm:"default:0" json:"total_matches"`
 	Wins         int     `gorm:"default:0" json:"wins"`
 	Losses       int     `gorm:"default:0" json:"losses"`
+	WinRate      float64 `gorm:"->" json:"win_rate"` // wins / total_matches, generated by the database
 
-	// Team-specific ELO fields
+	// Team-specific ELO fields. Deprecated: the team ladder lives in ratings (GET /players/{id}/ratings),
+	// these columns are a copy kept in sync by the database until the clients have moved
 	TeamEloRating    float64 `gorm:"default:1200" json:"team_elo_rating"`
 	TeamRank         int     `gorm:"default:1" json:"team_rank"`
 	TeamTotalMatches int     `gorm:"default:0" json:"team_total_matches"`
 	TeamWins         int     `gorm:"default:0" json:"team_wins"`
 	TeamLosses       int     `gorm:"default:0" json:"team_losses"`
+	TeamWinRate      float64 `gorm:"->" json:"team_win_rate"` // team_wins / team_total_matches, generated by the database
+
+	// Opt-in to share ratings and confirmed results through the public API
+	PublicAPIConsent   bool       `gorm:"default:false" json:"public_api_consent"`
+	PublicAPIConsentAt *time.Time `json:"public_api_consent_at"`
+
+	// Keyed hash of the player's NFC/student card UID, for quick identification on the kiosk
+	CardUIDHash *string `gorm:"size:64;uniqueIndex" json:"-"`
+
+	// Away window set by the player (vacation, internship abroad...); Away is computed when loaded
+	AwayFrom  *time.Time `json:"away_from"`
+	AwayUntil *time.Time `json:"away_until"`
+	Away      bool       `gorm:"-" json:"away"`
+
+	// Title the player chose to show next to their name, among the ones they hold
+	FlairTitleID *uint `gorm:"constraint:OnDelete:SET NULL" json:"flair_title_id"`
+
+	// Campus (lowercase, e.g. lyon) and department of the player, optional, for the per-campus leaderboards
+	Campus     *string `gorm:"size:50;index" json:"campus"`
+	Department *string `gorm:"size:100" json:"department"`
+
+	// Set by an admin when the player left the school: hidden from the leaderboards, read-only profile
+	ArchivedAt *time.Time `json:"archived_at"`
+	Archived   bool       `gorm:"-" json:"archived"`
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
-	Player1Matches []Match      `gorm:"foreignKey:Player1ID" json:"player1_matches,omitempty"`
-	Player2Matches []Match      `gorm:"foreignKey:Player2ID" json:"player2_matches,omitempty"`
-	WonMatches     []Match      `gorm:"foreignKey:WinnerID" json:"won_matches,omitempty"`
-	EloHistory     []EloHistory `gorm:"foreignKey:PlayerID" json:"elo_history,omitempty"`
+	Player1Matches []Match            `gorm:"foreignKey:Player1ID" json:"player1_matches,omitempty"`
+	Player2Matches []Match            `gorm:"foreignKey:Player2ID" json:"player2_matches,omitempty"`
+	WonMatches     []Match            `gorm:"foreignKey:WinnerID" json:"won_matches,omitempty"`
+	EloHistory     []EloHistory       `gorm:"foreignKey:PlayerID" json:"elo_history,omitempty"`
+	ExternalIDs    []PlayerExternalID `gorm:"foreignKey:PlayerID" json:"external_ids,omitempty"`
 }
 
 func (Player) TableName() string {
 	return "players"
 }
 
+// Name returns the display name of the player, their username if they have none
+func (p *Player) Name() string {
+	if p.DisplayName != nil {
+		return *p.DisplayName
+	}
+	return p.Username
+}
+
+// IsAwayAt reports whether the player is in their away window at the given time
+func (p *Player) IsAwayAt(t time.Time) bool {
+	return p.AwayFrom != nil && p.AwayUntil != nil && !t.Before(*p.AwayFrom) && t.Before(*p.AwayUntil)
+}
+
+// AfterFind sets the away badge shown on profiles and leaderboards, and the archived flag
+func (p *Player) AfterFind(tx *gorm.DB) error {
+	p.Away = p.IsAwayAt(modelsClock.Now())
+	p.Archived = p.ArchivedAt != nil
+	return nil
+}
+
+// NotArchived excludes the archived players, from the leaderboards, ladders and kiosk player list
+func NotArchived() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("players.archived_at IS NULL")
+	}
+}
+
+// NormalizeCampus lowercases and trims a campus so that filters match however it was typed, nil when empty
+func NormalizeCampus(campus *string) *string {
+	if campus == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*campus))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+// OnCampus keeps the players of a campus, or every player when the campus is empty
+func OnCampus(campus string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if campus == "" {
+			return db
+		}
+		return db.Where("players.campus = ?", campus)
+	}
+}
+
+// CampusCount is a campus with the number of its players
+type CampusCount struct {
+	Campus  string `json:"campus" example:"lyon"`
+	Players int64  `json:"players"`
+}
+
 type PaginatedPlayersResponse struct {
 	Data       []Player `json:"data"`
 	Total      int64    `json:"total"`
@@ -44,3 +137,38 @@ type PaginatedPlayersResponse struct {
 	PageSize   int      `json:"pageSize"`
 	TotalPages int      `json:"totalPages"`
 }
+
+// DTOs
+
+type UpdateDisplayNameRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=200"` // null or empty clears it
+}
+
+type UpdateCampusRequest struct {
+	Campus     *string `json:"campus" binding:"omitempty,max=50"`      // null or empty clears it
+	Department *string `json:"department" binding:"omitempty,max=100"` // null or empty clears it
+}
+
+// ArchiveInactivePlayersRequest archives the players without activity since a date
+type ArchiveInactivePlayersRequest struct {
+	InactiveSince time.Time `json:"inactive_since" binding:"required"` // No match nor login since this date
+	DryRun        bool      `json:"dry_run"`                           // List the players without archiving them
+}
+
+// ArchivedPlayer is a player archived in bulk, with the date of their last activity
+type ArchivedPlayer struct {
+	PlayerID       uint       `json:"player_id"`
+	Username       string     `json:"username"`
+	LastActivityAt *time.Time `json:"last_activity_at"`
+}
+
+type ArchiveInactivePlayersResponse struct {
+	DryRun  bool             `json:"dry_run"`
+	Count   int              `json:"count"`
+	Players []ArchivedPlayer `json:"players"`
+}
+
+type UpdateAwayRequest struct {
+	AwayFrom  time.Time `json:"away_from" binding:"required"`
+	AwayUntil time.Time `json:"away_until" binding:"required"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// PredictionSide is one side of a predicted matchup, a player or a team
+type PredictionSide struct {
+	ID        uint    `json:"id"`
+	Name      string  `json:"name"`
+	EloRating float64 `json:"elo_rating"` // Team average of the players' team ratings for a team
+	// WinProbability is the expected score of the rating engine
+	WinProbability float64 `json:"win_probability"`
+	// AdjustedWinProbability blends WinProbability with the head-to-head record of the two sides
+	AdjustedWinProbability float64 `json:"adjusted_win_probability"`
+}
+
+// HeadToHead is the record of the confirmed matches between the two sides of a matchup
+type HeadToHead struct {
+	Matches   int `json:"matches"`
+	Side1Wins int `json:"side1_wins"`
+	Side2Wins int `json:"side2_wins"`
+}
+
+// MatchupPrediction is the expected outcome of a match that has not been played yet
+type MatchupPrediction struct {
+	MatchKind  string         `json:"match_kind"` // solo or team
+	Side1      PredictionSide `json:"side1"`
+	Side2      PredictionSide `json:"side2"`
+	HeadToHead HeadToHead     `json:"head_to_head"`
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Public API token scopes
+const (
+	PublicAPIScopePlayersRead = "players:read"
+	PublicAPIScopeResultsRead = "results:read"
+)
+
+// PublicAPIToken grants an external site (e.g. the regional federation) read access to the
+// public API. Only the SHA-256 hash of the token is stored, the token itself is shown once.
+type PublicAPIToken struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string     `gorm:"size:255;not null" json:"name"`
+	TokenHash  string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	Prefix     string     `gorm:"size:12;not null" json:"prefix"`  // First characters of the token, to recognize it
+	Scopes     string     `gorm:"size:255;not null" json:"scopes"` // Comma separated
+	CreatedBy  *uint      `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nil: never expires
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (PublicAPIToken) TableName() string {
+	return "public_api_tokens"
+}
+
+// HasScope reports whether the token grants the given scope
+func (t *PublicAPIToken) HasScope(scope string) bool {
+	for _, s := range strings.Split(t.Scopes, ",") {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the token can still be used
+func (t *PublicAPIToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// DTOs
+
+type CreatePublicAPITokenRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=players:read results:read"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// PublicAPITokenCreatedResponse contains the token in clear, it cannot be retrieved afterwards
+type PublicAPITokenCreatedResponse struct {
+	PublicAPIToken
+	Token string `json:"token"`
+}
+
+type UpdatePublicAPIConsentRequest struct {
+	Consent *bool `json:"consent" binding:"required"`
+}
+
+// Public serializers: only the fields consenting players agreed to share
+
+type PublicPlayer struct {
+	ID            uint      `json:"id"`
+	Username      string    `json:"username"`
+	DisplayName   *string   `json:"display_name"`
+	EloRating     float64   `json:"elo_rating"`
+	Rank          int       `json:"rank"`
+	TotalMatches  int       `json:"total_matches"`
+	Wins          int       `json:"wins"`
+	Losses        int       `json:"losses"`
+	TeamEloRating float64   `json:"team_elo_rating"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewPublicPlayer(player *Player) PublicPlayer {
+	return PublicPlayer{
+		ID:            player.ID,
+		Username:      player.Username,
+		DisplayName:   player.DisplayName,
+		EloRating:     player.EloRating,
+		Rank:          player.Rank,
+		TotalMatches:  player.TotalMatches,
+		Wins:          player.Wins,
+		Losses:        player.Losses,
+		TeamEloRating: player.TeamEloRating,
+		UpdatedAt:     player.UpdatedAt,
+	}
+}
+
+type PublicMatch struct {
+	ID          uint      `json:"id"`
+	Player1ID   uint      `json:"player1_id"`
+	Player2ID   uint      `json:"player2_id"`
+	WinnerID    uint      `json:"winner_id"`
+	PlayedAt    time.Time `json:"played_at"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+}
+
+func NewPublicMatch(match *Match) PublicMatch {
+	publicMatch := PublicMatch{
+		ID:        match.ID,
+		Player1ID: match.Player1ID,
+		Player2ID: match.Player2ID,
+		WinnerID:  match.WinnerID,
+		PlayedAt:  match.CreatedAt,
+	}
+	if match.ConfirmedAt != nil {
+		publicMatch.ConfirmedAt = *match.ConfirmedAt
+	}
+	return publicMatch
+}
+
+type PaginatedPublicPlayersResponse struct {
+	Data       []PublicPlayer `json:"data"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"pageSize"`
+	TotalPages int            `json:"totalPages"`
+}
+
+// PublicResultsResponse is a page of results ordered by confirmation date; pass Next as
+// `since` to fetch the following page
+type PublicResultsResponse struct {
+	Data []PublicMatch `json:"data"`
+	Next *time.Time    `json:"next"`
+}
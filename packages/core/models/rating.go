@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Rating ladders. The solo ladder mirrors the rating columns of Player, kept in sync by the database.
+// The team ladder is written to the ratings table and copied back to the team columns of Player during
+// the transition. New ladders only add a name here and write their results through the ratings table.
+const (
+	LadderSolo = "solo"
+	LadderTeam = "team"
+)
+
+// Ladders lists the rating ladders, in display order
+var Ladders = []string{LadderSolo, LadderTeam}
+
+// IsMirroredLadder reports whether a ladder is written through the Player columns rather than directly
+func IsMirroredLadder(ladder string) bool {
+	return ladder == LadderSolo
+}
+
+// Rating is the standing of a player on one ladder
+type Rating struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlayerID     uint      `gorm:"not null;uniqueIndex:idx_ratings_player_ladder" json:"player_id"`
+	Ladder       string    `gorm:"size:30;not null;uniqueIndex:idx_ratings_player_ladder" json:"ladder"`
+	EloRating    float64   `gorm:"default:1200" json:"elo_rating"`
+	TotalMatches int       `gorm:"default:0" json:"total_matches"`
+	Wins         int       `gorm:"default:0" json:"wins"`
+	Losses       int       `gorm:"default:0" json:"losses"`
+	WinRate      float64   `gorm:"->" json:"win_rate"` // wins / total_matches, generated by the database
+	Rank         int       `gorm:"->" json:"rank"`     // Position on the ladder, computed when read
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+func (Rating) TableName() string {
+	return "ratings"
+}
+
+// RatingDrift is a player whose team columns differ from their team rating, or who has no team rating
+type RatingDrift struct {
+	PlayerID           uint     `json:"player_id"`
+	Username           string   `json:"username"`
+	TeamEloRating      float64  `json:"team_elo_rating"`
+	TeamTotalMatches   int      `json:"team_total_matches"`
+	TeamWins           int      `json:"team_wins"`
+	TeamLosses         int      `json:"team_losses"`
+	RatingEloRating    *float64 `json:"rating_elo_rating"` // nil when the rating is missing
+	RatingTotalMatches *int     `json:"rating_total_matches"`
+	RatingWins         *int     `json:"rating_wins"`
+	RatingLosses       *int     `json:"rating_losses"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// RatingOverride lets an admin correct a player's rating after an import or a bug:
+// a K-factor multiplier applied by the rating engine until the expiry, and/or a
+// one-off adjustment of the ELO applied when the override is created.
+type RatingOverride struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlayerID    uint       `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
+	KMultiplier float64    `gorm:"not null;default:1" json:"k_multiplier"`
+	Adjustment  float64    `gorm:"not null;default:0" json:"adjustment"` // ELO points added once, at creation
+	Reason      string     `gorm:"type:text;not null" json:"reason"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedBy   *uint      `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (RatingOverride) TableName() string {
+	return "rating_overrides"
+}
+
+// DTOs
+
+type CreateRatingOverrideRequest struct {
+	KMultiplier *float64  `json:"k_multiplier,omitempty" binding:"omitempty,gt=0,lte=4"`
+	Adjustment  *float64  `json:"adjustment,omitempty" binding:"omitempty,min=-400,max=400"`
+	Reason      string    `json:"reason" binding:"required"`
+	ExpiresAt   time.Time `json:"expires_at" binding:"required"`
+}
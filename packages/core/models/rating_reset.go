@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// BaseEloRating is the rating of a new player, and the one a reset goes back to
+const BaseEloRating = 1200.0
+
+// Statuses of a rating reset request
+const (
+	RatingResetStatusPending  = "pending"
+	RatingResetStatusApproved = "approved"
+	RatingResetStatusRejected = "rejected"
+)
+
+// RatingResetRequest is a player's request to start over from the base rating, approved by an admin
+type RatingResetRequest struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlayerID   uint       `gorm:"not null;constraint:OnDelete:CASCADE" json:"player_id"`
+	Reason     string     `gorm:"type:text;not null" json:"reason"`
+	Status     string     `gorm:"size:20;not null;default:pending" json:"status"`
+	ReviewedBy *uint      `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relationships
+	Player *Player `gorm:"foreignKey:PlayerID" json:"player,omitempty"`
+}
+
+func (RatingResetRequest) TableName() string {
+	return "rating_reset_requests"
+}
+
+// DTOs
+
+type CreateRatingResetRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type ReviewRatingResetRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+}
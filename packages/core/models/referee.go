@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type AssignRefereeRequest struct {
+	RefereeID uint `json:"referee_id" binding:"required"`
+}
+
+type RefereeStats struct {
+	RefereeID           uint       `json:"referee_id"`
+	Username            string     `json:"username"`
+	MatchesRefereed     int64      `json:"matches_refereed"`
+	TeamMatchesRefereed int64      `json:"team_matches_refereed"`
+	ConfirmedMatches    int64      `json:"confirmed_matches"`
+	TournamentsRefereed int64      `json:"tournaments_refereed"`
+	LastRefereedAt      *time.Time `json:"last_refereed_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Match confirmation modes
+const (
+	ConfirmationModeSingle = "single" // The creator implicitly confirms, the opponent confirms
+	ConfirmationModeBoth   = "both"   // Both participants must explicitly confirm before ELO applies
+)
+
+// SeasonSetting holds the rules selected for an academic season; seasons without a row use the defaults
+type SeasonSetting struct {
+	Season           string    `gorm:"primaryKey;size:9" json:"season"` // e.g. 2025-2026
+	ConfirmationMode string    `gorm:"size:20;not null;default:single" json:"confirmation_mode"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (SeasonSetting) TableName() string {
+	return "season_settings"
+}
+
+// DefaultSeasonSetting returns the rules of a season that was never configured
+func DefaultSeasonSetting(season string) SeasonSetting {
+	return SeasonSetting{
+		Season:           season,
+		ConfirmationMode: ConfirmationModeSingle,
+	}
+}
+
+// IsValidSeason checks the format of a season, two consecutive years like 2025-2026
+func IsValidSeason(season string) bool {
+	var start, end int
+	if _, err := fmt.Sscanf(season, "%4d-%4d", &start, &end); err != nil {
+		return false
+	}
+	return len(season) == 9 && end == start+1
+}
+
+// DTOs
+
+type UpdateSeasonSettingRequest struct {
+	ConfirmationMode string `json:"confirmation_mode" binding:"required,oneof=single both"`
+}
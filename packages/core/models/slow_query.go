@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// SlowQuery is a database query that took longer than the slow query threshold.
+// Bound values are never stored, only the SQL with its placeholders.
+type SlowQuery struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Fingerprint  string    `gorm:"type:text;not null" json:"fingerprint"` // SQL with placeholders and IN lists collapsed
+	Source       string    `gorm:"size:255;not null" json:"source"`       // Caller of the query, e.g. services/team_match_service.go:120
+	Handler      string    `gorm:"size:255;not null" json:"handler"`      // Route handler or scheduled job that issued the query
+	DurationMs   float64   `gorm:"not null" json:"duration_ms"`
+	RowsAffected int64     `gorm:"not null;default:0" json:"rows_affected"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (SlowQuery) TableName() string {
+	return "slow_queries"
+}
+
+// SlowQueryOffender aggregates the slow occurrences of a query issued by the same handler
+type SlowQueryOffender struct {
+	Fingerprint   string    `json:"fingerprint"`
+	Handler       string    `json:"handler"`
+	Source        string    `json:"source"`
+	Count         int64     `json:"count"`
+	AvgDurationMs float64   `json:"avg_duration_ms"`
+	MaxDurationMs float64   `json:"max_duration_ms"`
+	TotalMs       float64   `json:"total_ms"`
+	LastSeen      time.Time `json:"last_seen"`
+}
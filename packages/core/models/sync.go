@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// SyncPlayers are the players changed since the checkpoint, deleted ones only by ID
+type SyncPlayers struct {
+	Created []Player `json:"created"`
+	Updated []Player `json:"updated"`
+	Deleted []uint   `json:"deleted"`
+}
+
+// SyncMatches are the solo matches changed since the checkpoint, deleted ones only by ID
+type SyncMatches struct {
+	Created []Match `json:"created"`
+	Updated []Match `json:"updated"`
+	Deleted []uint  `json:"deleted"`
+}
+
+// SyncTeams are the teams changed since the checkpoint, deleted ones only by ID
+type SyncTeams struct {
+	Created []Team `json:"created"`
+	Updated []Team `json:"updated"`
+	Deleted []uint `json:"deleted"`
+}
+
+// SyncResponse is what an offline-capable client applies to its local copy before resuming from Cursor
+type SyncResponse struct {
+	Players SyncPlayers `json:"players"`
+	Matches SyncMatches `json:"matches"`
+	Teams   SyncTeams   `json:"teams"`
+	// Full is true when no checkpoint was given and every entity is returned
+	Full bool `json:"full"`
+	// Cursor is the checkpoint to send as since on the next sync
+	Cursor string `json:"cursor"`
+	// HasMore is true when a kind of entity had more changes than a page holds, sync again from Cursor
+	HasMore    bool      `json:"has_more"`
+	ServerTime time.Time `json:"server_time"`
+}
+
+// Results of an offline match submission
+const (
+	SyncMatchAccepted  = "accepted"  // Match created
+	SyncMatchDuplicate = "duplicate" // Client UUID already submitted, MatchID is the match created then
+	SyncMatchConflict  = "conflict"  // A similar match was recorded meanwhile (MatchID), resubmit with force to create it anyway
+	SyncMatchRejected  = "rejected"  // Invalid match, resubmitting it will fail again
+	SyncMatchError     = "error"     // Server error, resubmit later
+)
+
+// OfflineMatchSubmission remembers the matches queued offline and submitted through the sync, by the UUID
+// the client generated, so that a batch sent again after a lost response does not create them twice
+type OfflineMatchSubmission struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ClientUUID    string    `gorm:"size:36;not null;uniqueIndex" json:"client_uuid"`
+	KioskDeviceID *uint     `json:"kiosk_device_id"`
+	Player1ID     uint      `gorm:"not null" json:"player1_id"`
+	Player2ID     uint      `gorm:"not null" json:"player2_id"`
+	WinnerID      uint      `gorm:"not null" json:"winner_id"`
+	PlayedAt      time.Time `gorm:"not null" json:"played_at"`
+	Status        string    `gorm:"size:20;not null" json:"status"`
+	MatchID       *uint     `json:"match_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (OfflineMatchSubmission) TableName() string {
+	return "offline_match_submissions"
+}
+
+// Statuses of an OfflineMatchSubmission
+const (
+	OfflineSubmissionProcessing = "processing" // Being created by a request
+	OfflineSubmissionAccepted   = "accepted"
+	OfflineSubmissionConflict   = "conflict"
+)
+
+// SyncMatchItem is a match recorded while offline
+type SyncMatchItem struct {
+	ClientUUID string     `json:"client_uuid" binding:"required,uuid"`
+	Player1ID  uint       `json:"player1_id" binding:"required"`
+	Player2ID  uint       `json:"player2_id" binding:"required"`
+	WinnerID   uint       `json:"winner_id" binding:"required"`
+	PlayedAt   *time.Time `json:"played_at"` // When the match was played, defaults to the submission time
+	Force      bool       `json:"force"`     // Create the match even if a similar one was recorded meanwhile
+}
+
+type SyncMatchesRequest struct {
+	Matches []SyncMatchItem `json:"matches" binding:"required,min=1,max=100,dive"`
+}
+
+// SyncMatchResult is the outcome of one submitted match, in the order of the request
+type SyncMatchResult struct {
+	ClientUUID string `json:"client_uuid"`
+	Status     string `json:"status"`
+	MatchID    *uint  `json:"match_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type SyncMatchesResponse struct {
+	Results []SyncMatchResult `json:"results"`
+}
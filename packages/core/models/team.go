@@ -16,6 +16,7 @@ type Team struct {
 	TotalMatches int            `gorm:"default:0" json:"total_matches"`
 	Wins         int            `gorm:"default:0" json:"wins"`
 	Losses       int            `gorm:"default:0" json:"losses"`
+	WinRate      float64        `gorm:"->" json:"win_rate"` // wins / total_matches, generated by the database
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -27,3 +27,24 @@ type TeamEloHistory struct {
 func (TeamEloHistory) TableName() string {
 	return "team_elo_history"
 }
+
+// TeamRatingHistory is a change of the rating of a team itself, written with the TeamEloHistory
+// entries of its players when a team match is confirmed
+type TeamRatingHistory struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamID         uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"team_id"`
+	TeamMatchID    uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"team_match_id"`
+	EloBefore      float64   `gorm:"not null" json:"elo_before"`
+	EloAfter       float64   `gorm:"not null" json:"elo_after"`
+	EloChange      float64   `gorm:"not null" json:"elo_change"`
+	OpponentTeamID *uint     `json:"opponent_team_id"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relationships
+	TeamMatch    *TeamMatch `gorm:"foreignKey:TeamMatchID;references:ID" json:"team_match,omitempty"`
+	OpponentTeam *Team      `gorm:"foreignKey:OpponentTeamID;references:ID" json:"opponent_team,omitempty"`
+}
+
+func (TeamRatingHistory) TableName() string {
+	return "team_rating_history"
+}
@@ -17,7 +17,16 @@ type TeamMatch struct {
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 
-	TournamentID *uint `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"`
+	TournamentID *uint   `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"`
+	RefereeID    *uint   `gorm:"constraint:OnDelete:SET NULL" json:"referee_id"` // User with the referee role, tournament matches only
+	Stage        *string `gorm:"size:20" json:"stage"`                           // group, knockout (multi-stage tournaments only)
+
+	// Generated by the client that submitted the match, makes the submission idempotent
+	ClientUUID *string `gorm:"size:36;uniqueIndex" json:"client_uuid"`
+
+	// Scoreboard photo, required to confirm the match under a strict photo policy
+	PhotoURL *string `json:"photo_url"`
+	Disputed bool    `gorm:"not null;default:false" json:"disputed"` // Result contested: winner corrected, or held by the anomaly review
 
 	// Relationships
 	Team1      Team        `gorm:"foreignKey:Team1ID;references:ID" json:"team1,omitempty"`
@@ -43,9 +52,12 @@ type CreateTeamMatchRequest struct {
 	Team2ID      uint  `json:"team2_id" binding:"required"`
 	WinnerTeamID uint  `json:"winner_team_id" binding:"required"`
 	TournamentID *uint `json:"tournament_id,omitempty"`
+	// Sending the request again with the same UUID returns the match created the first time
+	ClientUUID *string `json:"client_uuid,omitempty" binding:"omitempty,uuid"`
 }
 
 type UpdateTeamMatchStatusRequest struct {
 	Status       *string `json:"status,omitempty" binding:"omitempty,oneof=confirmed rejected cancelled"`
 	WinnerTeamID *uint   `json:"winner_team_id,omitempty"`
+	PhotoURL     *string `json:"photo_url,omitempty" binding:"omitempty,url"` // Scoreboard photo, see the photo policy
 }
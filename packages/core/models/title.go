@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Title is an honorific (e.g. "Champion d'automne 2024") that players can show as flair next to
+// their name. Titles are created and assigned by admins, or written automatically for the players
+// of the team winning a tournament.
+type Title struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string    `gorm:"size:255;not null;uniqueIndex" json:"name"`
+	Description  *string   `json:"description"`
+	TournamentID *uint     `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"` // Set for tournament champion titles
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (Title) TableName() string {
+	return "titles"
+}
+
+// PlayerTitle records that a player holds a title
+type PlayerTitle struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PlayerID  uint      `gorm:"not null;uniqueIndex:idx_player_titles_player_title" json:"player_id"`
+	TitleID   uint      `gorm:"not null;uniqueIndex:idx_player_titles_player_title" json:"title_id"`
+	AwardedAt time.Time `json:"awarded_at"`
+
+	// Relationships
+	Title *Title `gorm:"foreignKey:TitleID" json:"title,omitempty"`
+}
+
+func (PlayerTitle) TableName() string {
+	return "player_titles"
+}
+
+// DTOs
+
+type CreateTitleRequest struct {
+	Name        string  `json:"name" binding:"required,max=255"`
+	Description *string `json:"description,omitempty"`
+}
+
+type UpdateTitleRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty"`
+}
+
+type AssignTitleRequest struct {
+	TitleID uint `json:"title_id" binding:"required"`
+}
+
+// UpdateFlairRequest selects the title shown next to the player's name, null to show none
+type UpdateFlairRequest struct {
+	TitleID *uint `json:"title_id"`
+}
@@ -6,18 +6,41 @@ import (
 	"gorm.io/gorm"
 )
 
+// Scoreboard photo policies: which matches need a photo of the scoreboard to be confirmed
+const (
+	PhotoPolicyNone     = "none"
+	PhotoPolicyDisputed = "disputed" // Matches whose result was contested: winner corrected, or held by the anomaly review
+	PhotoPolicyAlways   = "always"
+)
+
 type Tournament struct {
-	ID             uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name           string         `gorm:"size:255;not null" json:"name"`
-	Slug           string         `gorm:"size:255;unique;not null" json:"slug"`
-	Type           string         `gorm:"size:20;not null;default:team" json:"type"`     // solo, team
-	Status         string         `gorm:"size:20;not null;default:opened" json:"status"` // opened, ongoing, finished
-	Description    string         `gorm:"type:text" json:"description"`
-	NbParticipants int            `gorm:"default:0" json:"nb_participants"`
-	NbMatches      int            `gorm:"default:0" json:"nb_matches"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                    uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                  string         `gorm:"size:255;not null" json:"name"`
+	Slug                  string         `gorm:"size:255;unique;not null" json:"slug"`
+	Type                  string         `gorm:"size:20;not null;default:team" json:"type"`     // solo, team
+	Status                string         `gorm:"size:20;not null;default:opened" json:"status"` // opened, ongoing, finished
+	Description           string         `gorm:"type:text" json:"description"`
+	NbParticipants        int            `gorm:"default:0" json:"nb_participants"`
+	NbMatches             int            `gorm:"default:0" json:"nb_matches"`
+	MatchTimeLimitSeconds *int           `json:"match_time_limit_seconds"` // Live match rules, nil: no time limit
+	GoldenGoal            bool           `gorm:"default:false" json:"golden_goal"`
+	Format                string         `gorm:"size:20;not null;default:single" json:"format"` // single, groups_knockout
+	Stage                 *string        `gorm:"size:20" json:"stage"`                          // group, knockout (groups_knockout only)
+	PoolCount             int            `gorm:"default:0" json:"pool_count"`
+	QualifiersPerPool     int            `gorm:"default:0" json:"qualifiers_per_pool"`
+	MaxParticipants       *int           `json:"max_participants"`      // nil: unlimited, extra teams go to the waitlist
+	RegistrationDeadline  *time.Time     `json:"registration_deadline"` // nil: registrations open until the tournament starts
+	EntryFeeCents         *int           `json:"entry_fee_cents"`       // Per team, nil: free
+	Currency              string         `gorm:"size:3;default:EUR" json:"currency"`
+	TrophyImageURL        *string        `json:"trophy_image_url"`                                  // Image of the trophy awarded to the winner
+	EloWeight             float64        `gorm:"default:1" json:"elo_weight"`                       // Multiplier applied to ELO changes of the tournament matches
+	PhotoPolicy           string         `gorm:"size:20;not null;default:none" json:"photo_policy"` // none, disputed, always
+	TemplateID            *uint          `gorm:"constraint:OnDelete:SET NULL" json:"template_id"`
+	RecurrenceID          *uint          `gorm:"constraint:OnDelete:SET NULL" json:"recurrence_id"`
+	Edition               *int           `json:"edition"` // Edition number for recurring tournaments
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
 	TournamentTeams []TournamentTeam `gorm:"foreignKey:TournamentID" json:"tournament_teams,omitempty"`
@@ -30,14 +53,22 @@ func (Tournament) TableName() string {
 }
 
 type TournamentTeam struct {
-	ID           uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	TournamentID uint           `gorm:"not null;constraint:OnDelete:CASCADE" json:"tournament_id"`
-	TeamID       uint           `gorm:"not null;constraint:OnDelete:CASCADE" json:"team_id"`
-	Wins         int            `gorm:"default:0" json:"wins"`
-	Losses       int            `gorm:"default:0" json:"losses"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TournamentID uint    `gorm:"not null;constraint:OnDelete:CASCADE" json:"tournament_id"`
+	TeamID       uint    `gorm:"not null;constraint:OnDelete:CASCADE" json:"team_id"`
+	Wins         int     `gorm:"default:0" json:"wins"`
+	Losses       int     `gorm:"default:0" json:"losses"`
+	Pool         *string `gorm:"size:10" json:"pool"` // Group stage pool (A, B, ...)
+
+	// Entry fee tracking, toggled by organizers (no payment processing)
+	PaymentStatus    string     `gorm:"size:20;default:unpaid" json:"payment_status"` // unpaid, paid, waived
+	PaidAt           *time.Time `json:"paid_at"`
+	PaymentNote      *string    `json:"payment_note"`
+	PaymentUpdatedBy *uint      `json:"payment_updated_by"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
 	Tournament Tournament `gorm:"foreignKey:TournamentID;references:ID" json:"tournament,omitempty"`
@@ -51,15 +82,38 @@ func (TournamentTeam) TableName() string {
 // DTOs
 
 type CreateTournamentRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Type        string `json:"type" binding:"required,oneof=solo team"`
-	Description string `json:"description,omitempty"`
+	Name                  string     `json:"name" binding:"required"`
+	Type                  string     `json:"type" binding:"required,oneof=solo team"`
+	Description           string     `json:"description,omitempty"`
+	MatchTimeLimitSeconds *int       `json:"match_time_limit_seconds,omitempty" binding:"omitempty,min=0"` // 0 or empty: no time limit
+	GoldenGoal            bool       `json:"golden_goal,omitempty"`
+	Format                string     `json:"format,omitempty" binding:"omitempty,oneof=single groups_knockout"` // default: single
+	PoolCount             int        `json:"pool_count,omitempty" binding:"omitempty,min=1,max=26"`
+	QualifiersPerPool     int        `json:"qualifiers_per_pool,omitempty" binding:"omitempty,min=1"`
+	MaxParticipants       *int       `json:"max_participants,omitempty" binding:"omitempty,min=2"`
+	EloWeight             float64    `json:"elo_weight,omitempty" binding:"omitempty,gt=0,lte=5"`                   // default: 1
+	PhotoPolicy           string     `json:"photo_policy,omitempty" binding:"omitempty,oneof=none disputed always"` // default: none
+	RegistrationDeadline  *time.Time `json:"registration_deadline,omitempty"`
+	EntryFeeCents         *int       `json:"entry_fee_cents,omitempty" binding:"omitempty,min=0"`
+	Currency              string     `json:"currency,omitempty" binding:"omitempty,len=3"` // default: EUR
+	TrophyImageURL        *string    `json:"trophy_image_url,omitempty" binding:"omitempty,url"`
 }
 
 type UpdateTournamentRequest struct {
-	Name        *string `json:"name,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Status      *string `json:"status,omitempty" binding:"omitempty,oneof=opened ongoing finished"`
+	Name                  *string    `json:"name,omitempty"`
+	Description           *string    `json:"description,omitempty"`
+	Status                *string    `json:"status,omitempty" binding:"omitempty,oneof=opened ongoing finished"`
+	MatchTimeLimitSeconds *int       `json:"match_time_limit_seconds,omitempty" binding:"omitempty,min=0"` // 0: remove the time limit
+	GoldenGoal            *bool      `json:"golden_goal,omitempty"`
+	PoolCount             *int       `json:"pool_count,omitempty" binding:"omitempty,min=1,max=26"`
+	QualifiersPerPool     *int       `json:"qualifiers_per_pool,omitempty" binding:"omitempty,min=1"`
+	MaxParticipants       *int       `json:"max_participants,omitempty" binding:"omitempty,min=0"` // 0: unlimited
+	EloWeight             *float64   `json:"elo_weight,omitempty" binding:"omitempty,gt=0,lte=5"`
+	PhotoPolicy           *string    `json:"photo_policy,omitempty" binding:"omitempty,oneof=none disputed always"`
+	RegistrationDeadline  *time.Time `json:"registration_deadline,omitempty"`                     // zero time: remove the deadline
+	EntryFeeCents         *int       `json:"entry_fee_cents,omitempty" binding:"omitempty,min=0"` // 0: free
+	Currency              *string    `json:"currency,omitempty" binding:"omitempty,len=3"`
+	TrophyImageURL        *string    `json:"trophy_image_url,omitempty" binding:"omitempty,url"`
 }
 
 type JoinTournamentRequest struct {
@@ -69,16 +123,32 @@ type JoinTournamentRequest struct {
 // Responses
 
 type TournamentListItem struct {
-	ID             uint      `json:"id"`
-	Name           string    `json:"name"`
-	Slug           string    `json:"slug"`
-	Type           string    `json:"type"`
-	Status         string    `json:"status"`
-	Description    string    `json:"description"`
-	NbParticipants int       `json:"nb_participants"`
-	NbMatches      int       `json:"nb_matches"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                    uint       `json:"id"`
+	Name                  string     `json:"name"`
+	Slug                  string     `json:"slug"`
+	Type                  string     `json:"type"`
+	Status                string     `json:"status"`
+	Description           string     `json:"description"`
+	NbParticipants        int        `json:"nb_participants"`
+	NbMatches             int        `json:"nb_matches"`
+	MatchTimeLimitSeconds *int       `json:"match_time_limit_seconds"`
+	GoldenGoal            bool       `json:"golden_goal"`
+	Format                string     `json:"format"`
+	Stage                 *string    `json:"stage"`
+	PoolCount             int        `json:"pool_count"`
+	QualifiersPerPool     int        `json:"qualifiers_per_pool"`
+	MaxParticipants       *int       `json:"max_participants"`
+	RegistrationDeadline  *time.Time `json:"registration_deadline"`
+	EntryFeeCents         *int       `json:"entry_fee_cents"`
+	Currency              string     `json:"currency"`
+	TrophyImageURL        *string    `json:"trophy_image_url"`
+	EloWeight             float64    `json:"elo_weight"`
+	PhotoPolicy           string     `json:"photo_policy"`
+	TemplateID            *uint      `json:"template_id"`
+	RecurrenceID          *uint      `json:"recurrence_id"`
+	Edition               *int       `json:"edition"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
 func (TournamentListItem) TableName() string {
@@ -94,11 +164,13 @@ type PaginatedTournamentsResponse struct {
 }
 
 type TournamentTeamItem struct {
-	ID     uint `json:"id"`
-	TeamID uint `json:"team_id"`
-	Wins   int  `json:"wins"`
-	Losses int  `json:"losses"`
-	Team   Team `json:"team"`
+	ID            uint    `json:"id"`
+	TeamID        uint    `json:"team_id"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	Pool          *string `json:"pool"`
+	PaymentStatus string  `json:"payment_status"`
+	Team          Team    `json:"team"`
 }
 
 type PaginatedTournamentTeamsResponse struct {
@@ -108,3 +180,59 @@ type PaginatedTournamentTeamsResponse struct {
 	PageSize   int                  `json:"pageSize"`
 	TotalPages int                  `json:"totalPages"`
 }
+
+type TournamentWaitlistEntry struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TournamentID uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"tournament_id"`
+	TeamID       uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"team_id"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relationships
+	Team Team `gorm:"foreignKey:TeamID;references:ID" json:"team,omitempty"`
+}
+
+func (TournamentWaitlistEntry) TableName() string {
+	return "tournament_waitlist_entries"
+}
+
+type TournamentWaitlistItem struct {
+	Position  int       `json:"position"`
+	TeamID    uint      `json:"team_id"`
+	Team      Team      `json:"team"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Entry payment statuses
+const (
+	PaymentStatusUnpaid = "unpaid"
+	PaymentStatusPaid   = "paid"
+	PaymentStatusWaived = "waived"
+)
+
+type UpdateEntryPaymentRequest struct {
+	Status string  `json:"status" binding:"required,oneof=unpaid paid waived"`
+	Note   *string `json:"note,omitempty"`
+}
+
+type EntryPaymentItem struct {
+	TeamID        uint       `json:"team_id"`
+	TeamName      string     `json:"team_name"`
+	PaymentStatus string     `json:"payment_status"`
+	PaidAt        *time.Time `json:"paid_at"`
+	PaymentNote   *string    `json:"payment_note"`
+}
+
+// TournamentFeesSummary is the treasurer view of the entry fees of a tournament
+type TournamentFeesSummary struct {
+	TournamentID     uint               `json:"tournament_id"`
+	EntryFeeCents    int                `json:"entry_fee_cents"`
+	Currency         string             `json:"currency"`
+	NbEntries        int                `json:"nb_entries"`
+	NbPaid           int                `json:"nb_paid"`
+	NbUnpaid         int                `json:"nb_unpaid"`
+	NbWaived         int                `json:"nb_waived"`
+	ExpectedCents    int                `json:"expected_cents"` // Waived entries excluded
+	CollectedCents   int                `json:"collected_cents"`
+	OutstandingCents int                `json:"outstanding_cents"`
+	Entries          []EntryPaymentItem `json:"entries"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Recurrence frequencies
+const (
+	RecurrenceWeekly   = "weekly"
+	RecurrenceBiweekly = "biweekly"
+)
+
+// TournamentRecurrence creates a new edition of a tournament on a fixed weekday and hour.
+// Each edition copies the settings of the previous one (TournamentID).
+type TournamentRecurrence struct {
+	ID           uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string         `gorm:"size:255;not null" json:"name"`
+	TournamentID *uint          `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"` // Latest edition
+	Frequency    string         `gorm:"size:20;not null;default:weekly" json:"frequency"`  // weekly, biweekly
+	Weekday      int            `gorm:"not null" json:"weekday"`                           // 0 = Sunday ... 6 = Saturday
+	Hour         int            `gorm:"not null;default:18" json:"hour"`
+	Timezone     string         `gorm:"size:64;not null;default:Europe/Paris" json:"timezone"`
+	Edition      int            `gorm:"not null;default:1" json:"edition"` // Number of the latest edition
+	NextRunAt    time.Time      `gorm:"not null" json:"next_run_at"`
+	Active       bool           `gorm:"default:true" json:"active"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Tournament *Tournament `gorm:"foreignKey:TournamentID" json:"tournament,omitempty"`
+}
+
+func (TournamentRecurrence) TableName() string {
+	return "tournament_recurrences"
+}
+
+// NextRunAfter returns the first scheduled run strictly after t
+func (r TournamentRecurrence) NextRunAfter(t time.Time) time.Time {
+	location, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := t.In(location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), r.Hour, 0, 0, 0, location)
+	next = next.AddDate(0, 0, (r.Weekday-int(next.Weekday())+7)%7)
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 7)
+	}
+	if r.Frequency == RecurrenceBiweekly && !r.NextRunAt.IsZero() && next.Sub(r.NextRunAt) < 14*24*time.Hour {
+		next = next.AddDate(0, 0, 7)
+	}
+
+	return next
+}
+
+type TournamentRecurrenceSubscription struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	RecurrenceID uint      `gorm:"not null" json:"recurrence_id"`
+	PlayerID     uint      `gorm:"not null" json:"player_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (TournamentRecurrenceSubscription) TableName() string {
+	return "tournament_recurrence_subscriptions"
+}
+
+// DTOs
+
+type CreateTournamentRecurrenceRequest struct {
+	TournamentID uint   `json:"tournament_id" binding:"required"` // First edition, its settings are carried over
+	Frequency    string `json:"frequency,omitempty" binding:"omitempty,oneof=weekly biweekly"`
+	Weekday      *int   `json:"weekday" binding:"required,min=0,max=6"`
+	Hour         *int   `json:"hour,omitempty" binding:"omitempty,min=0,max=23"` // default: 18
+	Timezone     string `json:"timezone,omitempty"`                              // default: Europe/Paris
+}
+
+type UpdateTournamentRecurrenceRequest struct {
+	Name      *string `json:"name,omitempty"`
+	Frequency *string `json:"frequency,omitempty" binding:"omitempty,oneof=weekly biweekly"`
+	Weekday   *int    `json:"weekday,omitempty" binding:"omitempty,min=0,max=6"`
+	Hour      *int    `json:"hour,omitempty" binding:"omitempty,min=0,max=23"`
+	Timezone  *string `json:"timezone,omitempty"`
+	Active    *bool   `json:"active,omitempty"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+const (
+	TournamentFormatSingle         = "single"
+	TournamentFormatGroupsKnockout = "groups_knockout"
+
+	TournamentStageGroup    = "group"
+	TournamentStageKnockout = "knockout"
+)
+
+// TournamentBracketMatch is a slot of the knockout bracket. Round 1 is the first knockout round,
+// the winner of position p moves to position p/2 of the next round.
+type TournamentBracketMatch struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TournamentID uint      `gorm:"not null;constraint:OnDelete:CASCADE" json:"tournament_id"`
+	Round        int       `gorm:"not null" json:"round"`
+	Position     int       `gorm:"not null" json:"position"`
+	Team1ID      *uint     `json:"team1_id"`
+	Team2ID      *uint     `json:"team2_id"`
+	WinnerTeamID *uint     `json:"winner_team_id"`
+	TeamMatchID  *uint     `json:"team_match_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relationships
+	Team1      *Team `gorm:"foreignKey:Team1ID;references:ID" json:"team1,omitempty"`
+	Team2      *Team `gorm:"foreignKey:Team2ID;references:ID" json:"team2,omitempty"`
+	WinnerTeam *Team `gorm:"foreignKey:WinnerTeamID;references:ID" json:"winner_team,omitempty"`
+}
+
+func (TournamentBracketMatch) TableName() string {
+	return "tournament_bracket_matches"
+}
+
+// DTOs
+
+type PoolAssignment struct {
+	TeamID uint   `json:"team_id" binding:"required"`
+	Pool   string `json:"pool" binding:"required"`
+}
+
+// AssignPoolsRequest assigns teams to pools. Without assignments, teams are spread across
+// pools by team ELO (snake seeding).
+type AssignPoolsRequest struct {
+	Assignments []PoolAssignment `json:"assignments,omitempty" binding:"omitempty,dive"`
+}
+
+// Responses
+
+type PoolStanding struct {
+	Rank      int     `json:"rank"`
+	TeamID    uint    `json:"team_id"`
+	TeamName  string  `json:"team_name"`
+	EloRating float64 `json:"elo_rating"`
+	Played    int     `json:"played"`
+	Wins      int     `json:"wins"`
+	Losses    int     `json:"losses"`
+	Qualified bool    `json:"qualified"`
+}
+
+type TournamentPool struct {
+	Name      string         `json:"name"`
+	Standings []PoolStanding `json:"standings"`
+}
+
+type BracketRound struct {
+	Round   int                      `json:"round"`
+	Name    string                   `json:"name"` // final, semi_final, quarter_final, round_of_16...
+	Matches []TournamentBracketMatch `json:"matches"`
+}
+
+type TournamentStandingsResponse struct {
+	TournamentID      uint             `json:"tournament_id"`
+	Format            string           `json:"format"`
+	Stage             *string          `json:"stage"`
+	QualifiersPerPool int              `json:"qualifiers_per_pool"`
+	Pools             []TournamentPool `json:"pools"`
+	Bracket           []BracketRound   `json:"bracket"`
+	ChampionTeamID    *uint            `json:"champion_team_id"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TournamentTemplate is a reusable tournament configuration (format, rules, size, ELO weighting)
+type TournamentTemplate struct {
+	ID                    uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                  string         `gorm:"size:255;not null" json:"name"`
+	Description           string         `gorm:"type:text" json:"description"`
+	Type                  string         `gorm:"size:20;not null;default:team" json:"type"`
+	Format                string         `gorm:"size:20;not null;default:single" json:"format"`
+	PoolCount             int            `gorm:"default:0" json:"pool_count"`
+	QualifiersPerPool     int            `gorm:"default:0" json:"qualifiers_per_pool"`
+	MaxParticipants       *int           `json:"max_participants"`
+	MatchTimeLimitSeconds *int           `json:"match_time_limit_seconds"`
+	GoldenGoal            bool           `gorm:"default:false" json:"golden_goal"`
+	EloWeight             float64        `gorm:"default:1" json:"elo_weight"`
+	PhotoPolicy           string         `gorm:"size:20;not null;default:none" json:"photo_policy"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (TournamentTemplate) TableName() string {
+	return "tournament_templates"
+}
+
+// DTOs
+
+type CreateTournamentTemplateRequest struct {
+	Name                  string  `json:"name" binding:"required"`
+	Description           string  `json:"description,omitempty"`
+	Type                  string  `json:"type" binding:"required,oneof=solo team"`
+	Format                string  `json:"format,omitempty" binding:"omitempty,oneof=single groups_knockout"`
+	PoolCount             int     `json:"pool_count,omitempty" binding:"omitempty,min=1,max=26"`
+	QualifiersPerPool     int     `json:"qualifiers_per_pool,omitempty" binding:"omitempty,min=1"`
+	MaxParticipants       *int    `json:"max_participants,omitempty" binding:"omitempty,min=2"`
+	MatchTimeLimitSeconds *int    `json:"match_time_limit_seconds,omitempty" binding:"omitempty,min=0"`
+	GoldenGoal            bool    `json:"golden_goal,omitempty"`
+	EloWeight             float64 `json:"elo_weight,omitempty" binding:"omitempty,gt=0,lte=5"`
+	PhotoPolicy           string  `json:"photo_policy,omitempty" binding:"omitempty,oneof=none disputed always"`
+}
+
+// SaveTournamentAsTemplateRequest saves the configuration of an existing tournament
+type SaveTournamentAsTemplateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateTournamentFromTemplateRequest names the tournament created from a template
+type CreateTournamentFromTemplateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description,omitempty"` // default: template description
+}
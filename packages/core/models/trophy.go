@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Trophy is a prize won by a team (tournaments) or a player (season awards).
+// Tournament trophies are written automatically when the tournament finishes.
+type Trophy struct {
+	ID             uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name           string         `gorm:"size:255;not null" json:"name"`
+	ImageURL       *string        `json:"image_url"`
+	Season         string         `gorm:"size:9;not null" json:"season"` // Academic year, e.g. 2025-2026
+	TournamentID   *uint          `gorm:"constraint:OnDelete:SET NULL" json:"tournament_id"`
+	WinnerTeamID   *uint          `gorm:"constraint:OnDelete:SET NULL" json:"winner_team_id"`
+	WinnerPlayerID *uint          `gorm:"constraint:OnDelete:SET NULL" json:"winner_player_id"`
+	AwardedAt      time.Time      `json:"awarded_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Tournament   *Tournament `gorm:"foreignKey:TournamentID" json:"tournament,omitempty"`
+	WinnerTeam   *Team       `gorm:"foreignKey:WinnerTeamID" json:"winner_team,omitempty"`
+	WinnerPlayer *Player     `gorm:"foreignKey:WinnerPlayerID" json:"winner_player,omitempty"`
+}
+
+func (Trophy) TableName() string {
+	return "trophies"
+}
+
+// SeasonOf returns the academic season (starting in September) of a date, e.g. 2025-2026
+func SeasonOf(t time.Time) string {
+	year := t.Year()
+	if t.Month() < time.September {
+		year--
+	}
+	return fmt.Sprintf("%d-%d", year, year+1)
+}
+
+// DTOs
+
+type CreateTrophyRequest struct {
+	Name           string  `json:"name" binding:"required"`
+	ImageURL       *string `json:"image_url,omitempty" binding:"omitempty,url"`
+	Season         string  `json:"season,omitempty" binding:"omitempty,len=9"` // default: current season
+	WinnerTeamID   *uint   `json:"winner_team_id,omitempty"`
+	WinnerPlayerID *uint   `json:"winner_player_id,omitempty"`
+}
+
+type UpdateTrophyRequest struct {
+	Name           *string `json:"name,omitempty"`
+	ImageURL       *string `json:"image_url,omitempty" binding:"omitempty,url"`
+	WinnerTeamID   *uint   `json:"winner_team_id,omitempty"`
+	WinnerPlayerID *uint   `json:"winner_player_id,omitempty"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Events sent to the webhook endpoints
+const (
+	WebhookEventResultConfirmed = "result.confirmed" // Solo match confirmed between two consenting players, like GET /public/v1/results
+	WebhookEventPing            = "ping"             // Sent on demand to test an endpoint
+)
+
+// Statuses of a webhook delivery
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Every attempt failed
+)
+
+// WebhookEndpoint is an integrator URL receiving signed events. Its secret is only shown at creation
+// and rotation; during a rotation the previous secret also signs the deliveries until it expires.
+type WebhookEndpoint struct {
+	ID                      uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                    string     `gorm:"size:255;not null" json:"name"`
+	URL                     string     `gorm:"size:500;not null" json:"url"`
+	Events                  string     `gorm:"size:255;not null" json:"events"` // Comma separated
+	Secret                  string     `gorm:"size:100;not null" json:"-"`
+	PreviousSecret          *string    `gorm:"size:100" json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at"`
+	Cursor                  *time.Time `json:"cursor"` // Confirmation date of the last result queued for the endpoint
+	CreatedBy               *uint      `json:"created_by"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribes reports whether the endpoint receives an event; every endpoint receives pings
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	if event == WebhookEventPing {
+		return true
+	}
+	for _, subscribed := range strings.Split(e.Events, ",") {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Secrets returns the secrets signing the deliveries: the current one, and the previous one until it expires
+func (e *WebhookEndpoint) Secrets(now time.Time) []string {
+	secrets := []string{e.Secret}
+	if e.PreviousSecret != nil && e.PreviousSecretExpiresAt != nil && now.Before(*e.PreviousSecretExpiresAt) {
+		secrets = append(secrets, *e.PreviousSecret)
+	}
+	return secrets
+}
+
+// WebhookDelivery is an event queued for an endpoint. Its body is fixed at creation so that every
+// retry sends the same bytes under the same delivery ID; the timestamp and signature are those of the attempt.
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	DeliveryID     string     `gorm:"size:40;uniqueIndex;not null" json:"delivery_id"`
+	EndpointID     uint       `gorm:"not null;index;constraint:OnDelete:CASCADE" json:"endpoint_id"`
+	Event          string     `gorm:"size:50;not null" json:"event"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"size:20;not null;default:pending" json:"status"`
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	ResponseStatus *int       `json:"response_status"`
+	LastError      *string    `gorm:"type:text" json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookPayload is the JSON body of a delivery
+type WebhookPayload struct {
+	ID        string      `json:"id"` // Delivery ID, also in X-Webhook-Id
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// DTOs
+
+type CreateWebhookEndpointRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	URL    string   `json:"url" binding:"required,url,startswith=https://"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=result.confirmed"`
+}
+
+type RotateWebhookSecretRequest struct {
+	// Hours the previous secret keeps signing the deliveries, 24 by default, 0 to drop it at once
+	GracePeriodHours *int `json:"grace_period_hours" binding:"omitempty,min=0,max=168"`
+}
+
+// WebhookSecretResponse contains the secret in clear, it cannot be retrieved afterwards
+type WebhookSecretResponse struct {
+	WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// VerifyWebhookResponse is the result of the verification helper
+type VerifyWebhookResponse struct {
+	Valid               bool   `json:"valid"`
+	Reason              string `json:"reason,omitempty"` // Why the delivery is refused
+	DeliveryID          string `json:"delivery_id"`
+	ReplayWindowSeconds int    `json:"replay_window_seconds"`
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"core/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MatchQuery filters the solo matches, nil fields are ignored
+type MatchQuery struct {
+	PlayerID *uint
+	// Outcome restricts the matches of PlayerID to its wins or losses ("wins", "losses")
+	Outcome       string
+	Status        *string
+	CreatedFrom   *time.Time
+	CreatedBefore *time.Time
+}
+
+// MatchRepo reads and updates solo matches
+type MatchRepo interface {
+	FindByID(id uint) (*models.Match, error)
+	// FindByIDWithPlayers also loads the two players and the winner
+	FindByIDWithPlayers(id uint) (*models.Match, error)
+	// Recent returns the latest matches with their players
+	Recent(limit int) ([]models.Match, error)
+	// List returns a page of matches with their players, latest first, and the total count
+	List(query MatchQuery, offset, limit int) ([]models.Match, int64, error)
+	// Each passes every match of the query to fn in batches, in the order of List
+	Each(query MatchQuery, batchSize int, fn func([]models.Match) error) error
+	// CountRankedSince counts the pending and confirmed non-tournament matches of a player created since a date
+	CountRankedSince(playerID uint, since time.Time) (int64, error)
+	Update(id uint, fields map[string]interface{}) error
+}
+
+type gormMatchRepo struct {
+	db *gorm.DB
+}
+
+// NewMatchRepo returns the GORM implementation of MatchRepo
+func NewMatchRepo(db *gorm.DB) MatchRepo {
+	return &gormMatchRepo{db: db}
+}
+
+func (r *gormMatchRepo) withPlayers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Player1").Preload("Player2").Preload("Winner")
+}
+
+func (r *gormMatchRepo) FindByID(id uint) (*models.Match, error) {
+	var match models.Match
+	if err := r.db.First(&match, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &match, nil
+}
+
+func (r *gormMatchRepo) FindByIDWithPlayers(id uint) (*models.Match, error) {
+	var match models.Match
+	if err := r.withPlayers(r.db).First(&match, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &match, nil
+}
+
+func (r *gormMatchRepo) Recent(limit int) ([]models.Match, error) {
+	var matches []models.Match
+	if err := r.withPlayers(r.db).Order("created_at DESC").Limit(limit).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// filtered applies the filters of a query to the matches
+func (r *gormMatchRepo) filtered(query MatchQuery) *gorm.DB {
+	db := r.db.Model(&models.Match{})
+
+	if query.PlayerID != nil {
+		playerID := *query.PlayerID
+		db = db.Where("player1_id = ? OR player2_id = ?", playerID, playerID)
+		switch query.Outcome {
+		case "wins":
+			db = db.Where("winner_id = ?", playerID)
+		case "losses":
+			db = db.Where("winner_id != ?", playerID)
+		}
+	}
+	if query.Status != nil {
+		db = db.Where("status = ?", *query.Status)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *query.CreatedBefore)
+	}
+	return db
+}
+
+func (r *gormMatchRepo) List(query MatchQuery, offset, limit int) ([]models.Match, int64, error) {
+	var total int64
+	if err := r.filtered(query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var matches []models.Match
+	if err := r.withPlayers(r.filtered(query)).Order("created_at DESC").Offset(offset).Limit(limit).Find(&matches).Error; err != nil {
+		return nil, 0, err
+	}
+	return matches, total, nil
+}
+
+func (r *gormMatchRepo) Each(query MatchQuery, batchSize int, fn func([]models.Match) error) error {
+	for offset := 0; ; offset += batchSize {
+		var matches []models.Match
+		// The id tie-break keeps the pages stable when matches share a creation date
+		if err := r.withPlayers(r.filtered(query)).Order("created_at DESC, id DESC").
+			Offset(offset).Limit(batchSize).Find(&matches).Error; err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		if err := fn(matches); err != nil {
+			return err
+		}
+		if len(matches) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (r *gormMatchRepo) CountRankedSince(playerID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Match{}).
+		Where("(player1_id = ? OR player2_id = ?) AND tournament_id IS NULL", playerID, playerID).
+		Where("status IN ? AND created_at >= ?", []string{"pending", "confirmed"}, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *gormMatchRepo) Update(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&models.Match{ID: id}).Updates(fields).Error
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"core/models"
+
+	"gorm.io/gorm"
+)
+
+// PlayerRepo reads and updates players
+type PlayerRepo interface {
+	FindByID(id uint) (*models.Player, error)
+	// List returns a page of players ordered by an already validated clause, with the total count
+	List(orderClause string, offset, limit int) ([]models.Player, int64, error)
+	// Each passes every player to fn in batches, ordered by an already validated clause
+	Each(orderClause string, batchSize int, fn func([]models.Player) error) error
+	// Top returns the best active players on a rating column (elo_rating, team_elo_rating), of a campus if not empty
+	Top(column string, limit int, campus string) ([]models.Player, error)
+	// AllByElo returns every active player, best ELO first
+	AllByElo() ([]models.Player, error)
+	Create(player *models.Player) error
+	Update(id uint, fields map[string]interface{}) error
+}
+
+type gormPlayerRepo struct {
+	db *gorm.DB
+}
+
+// NewPlayerRepo returns the GORM implementation of PlayerRepo
+func NewPlayerRepo(db *gorm.DB) PlayerRepo {
+	return &gormPlayerRepo{db: db}
+}
+
+func (r *gormPlayerRepo) FindByID(id uint) (*models.Player, error) {
+	var player models.Player
+	if err := r.db.First(&player, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &player, nil
+}
+
+func (r *gormPlayerRepo) List(orderClause string, offset, limit int) ([]models.Player, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.Player{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var players []models.Player
+	if err := r.db.Order(orderClause).Offset(offset).Limit(limit).Find(&players).Error; err != nil {
+		return nil, 0, err
+	}
+	return players, total, nil
+}
+
+func (r *gormPlayerRepo) Each(orderClause string, batchSize int, fn func([]models.Player) error) error {
+	for offset := 0; ; offset += batchSize {
+		var players []models.Player
+		// The id tie-break keeps the pages stable when players share the ordered value
+		if err := r.db.Order(orderClause + ", id ASC").Offset(offset).Limit(batchSize).Find(&players).Error; err != nil {
+			return err
+		}
+		if len(players) == 0 {
+			return nil
+		}
+		if err := fn(players); err != nil {
+			return err
+		}
+		if len(players) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (r *gormPlayerRepo) Top(column string, limit int, campus string) ([]models.Player, error) {
+	var players []models.Player
+	if err := r.db.Scopes(models.OnCampus(campus), models.NotArchived()).Order(column + " DESC").Limit(limit).Find(&players).Error; err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+func (r *gormPlayerRepo) AllByElo() ([]models.Player, error) {
+	var players []models.Player
+	if err := r.db.Scopes(models.NotArchived()).Order("elo_rating DESC, id ASC").Find(&players).Error; err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+func (r *gormPlayerRepo) Create(player *models.Player) error {
+	return r.db.Create(player).Error
+}
+
+func (r *gormPlayerRepo) Update(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&models.Player{ID: id}).Updates(fields).Error
+}
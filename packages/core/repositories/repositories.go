@@ -0,0 +1,21 @@
+// Package repositories hides the GORM access of the services behind interfaces, so that services
+// can run against fakes in tests and reads can later be routed to a replica or a cache.
+// Writes spanning several tables (match confirmation, ELO replay) stay in transactions in the services.
+package repositories
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned by every repository when the record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// notFound maps the GORM not-found error to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
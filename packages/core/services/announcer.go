@@ -0,0 +1,49 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+var announcerHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Announcer posts community announcements to a Discord channel through its webhook (DISCORD_WEBHOOK_URL).
+// Without a webhook the announcements are only logged.
+type Announcer struct {
+	webhookURL string
+}
+
+func NewAnnouncer() *Announcer {
+	return &Announcer{
+		webhookURL: os.Getenv("DISCORD_WEBHOOK_URL"),
+	}
+}
+
+// Announce posts a message to the channel
+func (a *Announcer) Announce(message string) error {
+	if a.webhookURL == "" {
+		log.Printf("Announcement (no DISCORD_WEBHOOK_URL): %s", message)
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"content": message})
+	if err != nil {
+		return err
+	}
+
+	resp, err := announcerHTTPClient.Post(a.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook answered %s", resp.Status)
+	}
+	return nil
+}
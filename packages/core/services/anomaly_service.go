@@ -0,0 +1,268 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Rapid rematches: this many matches between the same pair within the window
+	rapidRematchCount  = 4
+	rapidRematchWindow = 30 * time.Minute
+
+	// Upset streak: consecutive wins against opponents rated at least this much higher
+	upsetStreakLength = 5
+	upsetEloGap       = 200
+
+	// Matches created within the lookback are scanned again on each run, already flagged ones are skipped
+	anomalyLookback = 24 * time.Hour
+)
+
+type AnomalyService struct {
+	db       *gorm.DB
+	autoHold bool
+}
+
+func NewAnomalyService(db *gorm.DB) *AnomalyService {
+	return &AnomalyService{
+		db:       db,
+		autoHold: os.Getenv("ANOMALY_AUTO_HOLD") == "true",
+	}
+}
+
+// GetAnomalies lists the review queue, most recent first
+func (s *AnomalyService) GetAnomalies(status string) ([]models.MatchAnomaly, error) {
+	var anomalies []models.MatchAnomaly
+	if err := s.db.Preload("Match").
+		Preload("Match.Player1").
+		Preload("Match.Player2").
+		Preload("Player").
+		Where("status = ?", status).
+		Order("created_at DESC").
+		Find(&anomalies).Error; err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
+
+// ReviewAnomaly closes an anomaly. Dismissing it releases its match if nothing else holds it,
+// confirming it rejects the match when it is still pending.
+func (s *AnomalyService) ReviewAnomaly(id, reviewerID uint, status string) (*models.MatchAnomaly, error) {
+	var anomaly models.MatchAnomaly
+	if err := s.db.First(&anomaly, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("anomaly not found")
+		}
+		return nil, err
+	}
+	if anomaly.Status != models.AnomalyStatusOpen {
+		return nil, errors.New("anomaly already reviewed")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&anomaly).Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if status == models.AnomalyStatusConfirmed {
+			return tx.Model(&models.Match{}).
+				Where("id = ? AND status = ?", anomaly.MatchID, "pending").
+				Updates(map[string]interface{}{"status": "rejected", "on_hold": false}).Error
+		}
+
+		return tx.Model(&models.Match{}).
+			Where("id = ? AND on_hold = ?", anomaly.MatchID, true).
+			Where("NOT EXISTS (SELECT 1 FROM match_anomalies WHERE match_id = ? AND status = ?)", anomaly.MatchID, models.AnomalyStatusOpen).
+			Update("on_hold", false).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Match").Preload("Player").First(&anomaly, id).Error; err != nil {
+		return nil, err
+	}
+	return &anomaly, nil
+}
+
+// DetectAnomalies scans the recent ranked matches for suspicious patterns and returns the number of new anomalies
+func (s *AnomalyService) DetectAnomalies(now time.Time) (int, error) {
+	since := now.Add(-anomalyLookback)
+
+	rapid, err := s.detectRapidRematches(since)
+	if err != nil {
+		return 0, err
+	}
+
+	upsets, err := s.detectUpsetStreaks(since)
+	if err != nil {
+		return rapid, err
+	}
+
+	return rapid + upsets, nil
+}
+
+// detectRapidRematches flags every match of a burst of rematches between the same pair
+func (s *AnomalyService) detectRapidRematches(since time.Time) (int, error) {
+	var matches []models.Match
+	if err := s.db.Where("created_at >= ? AND tournament_id IS NULL AND status IN ?", since, []string{"pending", "confirmed"}).
+		Order("created_at ASC").
+		Find(&matches).Error; err != nil {
+		return 0, err
+	}
+
+	pairs := make(map[[2]uint][]models.Match)
+	for _, match := range matches {
+		key := [2]uint{match.Player1ID, match.Player2ID}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		pairs[key] = append(pairs[key], match)
+	}
+
+	flagged := 0
+	for _, pairMatches := range pairs {
+		if len(pairMatches) < rapidRematchCount {
+			continue
+		}
+
+		// Matches are in creation order, slide a window over them
+		inBurst := make([]bool, len(pairMatches))
+		start := 0
+		for end := range pairMatches {
+			for pairMatches[end].CreatedAt.Sub(pairMatches[start].CreatedAt) > rapidRematchWindow {
+				start++
+			}
+			if end-start+1 >= rapidRematchCount {
+				for i := start; i <= end; i++ {
+					inBurst[i] = true
+				}
+			}
+		}
+
+		for i, match := range pairMatches {
+			if !inBurst[i] {
+				continue
+			}
+			details := fmt.Sprintf("At least %d matches between players %d and %d within %d minutes",
+				rapidRematchCount, match.Player1ID, match.Player2ID, int(rapidRematchWindow.Minutes()))
+			created, err := s.flag(match, match.WinnerID, models.AnomalyKindRapidRematches, details)
+			if err != nil {
+				return flagged, err
+			}
+			if created {
+				flagged++
+			}
+		}
+	}
+
+	return flagged, nil
+}
+
+// upsetRow is a recent match of a player with the ratings at the time it was played
+// (from the ELO history once confirmed, current ratings while pending)
+type upsetRow struct {
+	MatchID     uint
+	WinnerID    uint
+	PlayerElo   float64
+	OpponentElo float64
+}
+
+// detectUpsetStreaks flags the latest match of players who won their last matches against much higher-rated opponents
+func (s *AnomalyService) detectUpsetStreaks(since time.Time) (int, error) {
+	var winnerIDs []uint
+	if err := s.db.Model(&models.Match{}).
+		Distinct("winner_id").
+		Where("created_at >= ? AND tournament_id IS NULL AND status IN ?", since, []string{"pending", "confirmed"}).
+		Pluck("winner_id", &winnerIDs).Error; err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	for _, playerID := range winnerIDs {
+		var rows []upsetRow
+		if err := s.db.Raw(`
+			SELECT m.id AS match_id, m.winner_id,
+				COALESCE(h.elo_before, p.elo_rating) AS player_elo,
+				COALESCE(o.elo_before, op.elo_rating) AS opponent_elo
+			FROM matches m
+			JOIN players p ON p.id = ?
+			JOIN players op ON op.id = CASE WHEN m.player1_id = ? THEN m.player2_id ELSE m.player1_id END
+			LEFT JOIN elo_history h ON h.match_id = m.id AND h.player_id = p.id AND h.deleted_at IS NULL
+			LEFT JOIN elo_history o ON o.match_id = m.id AND o.player_id = op.id AND o.deleted_at IS NULL
+			WHERE (m.player1_id = ? OR m.player2_id = ?)
+				AND m.tournament_id IS NULL
+				AND m.status IN ('pending', 'confirmed')
+				AND m.deleted_at IS NULL
+			ORDER BY m.created_at DESC
+			LIMIT ?`, playerID, playerID, playerID, playerID, upsetStreakLength).
+			Scan(&rows).Error; err != nil {
+			return flagged, err
+		}
+
+		if len(rows) < upsetStreakLength {
+			continue
+		}
+		streak := true
+		for _, row := range rows {
+			if row.WinnerID != playerID || row.OpponentElo-row.PlayerElo < upsetEloGap {
+				streak = false
+				break
+			}
+		}
+		if !streak {
+			continue
+		}
+
+		var latest models.Match
+		if err := s.db.First(&latest, rows[0].MatchID).Error; err != nil {
+			return flagged, err
+		}
+		details := fmt.Sprintf("%d consecutive wins against opponents rated %d+ points higher", upsetStreakLength, upsetEloGap)
+		created, err := s.flag(latest, playerID, models.AnomalyKindUpsetStreak, details)
+		if err != nil {
+			return flagged, err
+		}
+		if created {
+			flagged++
+		}
+	}
+
+	return flagged, nil
+}
+
+// flag adds a match to the review queue once per kind and player, and holds it when auto-hold is enabled
+func (s *AnomalyService) flag(match models.Match, playerID uint, kind, details string) (bool, error) {
+	anomaly := models.MatchAnomaly{
+		MatchID:  match.ID,
+		PlayerID: playerID,
+		Kind:     kind,
+		Details:  details,
+		Status:   models.AnomalyStatusOpen,
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&anomaly)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	if s.autoHold && match.Status == "pending" {
+		if err := s.db.Model(&models.Match{}).Where("id = ?", match.ID).Updates(map[string]interface{}{"on_hold": true, "disputed": true}).Error; err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"core/clock"
+	"core/events"
 	"core/models"
 	"log"
 	"time"
@@ -8,28 +10,43 @@ import (
 	"gorm.io/gorm"
 )
 
+// AutoValidationWindow is how long a pending match waits for its opponent before being confirmed automatically
+const AutoValidationWindow = 24 * time.Hour
+
 type AutoValidationService struct {
-	db               *gorm.DB
-	matchService     *MatchService
-	teamMatchService *TeamMatchService
+	db                  *gorm.DB
+	matchService        *MatchService
+	teamMatchService    *TeamMatchService
+	notificationService *NotificationService
+	events              *events.Bus
+	clock               clock.Clock
 }
 
-func NewAutoValidationService(db *gorm.DB, matchService *MatchService, teamMatchService *TeamMatchService) *AutoValidationService {
+func NewAutoValidationService(db *gorm.DB, matchService *MatchService, teamMatchService *TeamMatchService, notificationService *NotificationService, bus *events.Bus) *AutoValidationService {
 	return &AutoValidationService{
-		db:               db,
-		matchService:     matchService,
-		teamMatchService: teamMatchService,
+		db:                  db,
+		matchService:        matchService,
+		teamMatchService:    teamMatchService,
+		notificationService: notificationService,
+		events:              bus,
+		clock:               clock.Real,
 	}
 }
 
+// SetClock replaces the wall clock used to compute the 24h validation window
+func (s *AutoValidationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 // ValidateExpiredMatches finds and confirms all pending matches that are older than 24 hours
 func (s *AutoValidationService) ValidateExpiredMatches() error {
 	// Calculate the cutoff time (24 hours ago)
-	cutoffTime := time.Now().Add(-24 * time.Hour)
+	cutoffTime := s.clock.Now().Add(-AutoValidationWindow)
 
-	// Find all pending solo matches older than 24 hours
+	// Find all pending solo matches older than 24 hours, except those held for review
+	// and those waiting for the explicit confirmation of both players
 	var expiredMatches []models.Match
-	result := s.db.Where("status = ? AND created_at < ?", "pending", cutoffTime).Find(&expiredMatches)
+	result := s.db.Where("status = ? AND created_at < ? AND on_hold = ? AND requires_both_confirmations = ?", "pending", cutoffTime, false, false).Find(&expiredMatches)
 
 	if result.Error != nil {
 		log.Printf("Error finding expired matches: %v", result.Error)
@@ -53,18 +70,30 @@ func (s *AutoValidationService) ValidateExpiredMatches() error {
 
 	log.Printf("Found %d expired matches to validate (%d solo, %d team)", totalExpired, len(expiredMatches), len(expiredTeamMatches))
 
+	confirmed, waitingForPhoto := 0, 0
+	var failedMatchIDs, failedTeamMatchIDs []uint
+
 	// Confirm each expired solo match
 	for _, match := range expiredMatches {
 		log.Printf("Auto-confirming solo match ID %d (created at %v)", match.ID, match.CreatedAt)
 
 		_, err := s.matchService.ConfirmMatch(match.ID)
+		if err != nil && err.Error() == "scoreboard photo required" {
+			// Stays pending until a player attaches the photo
+			log.Printf("Solo match ID %d waits for a scoreboard photo", match.ID)
+			waitingForPhoto++
+			continue
+		}
 		if err != nil {
 			log.Printf("Error auto-confirming solo match ID %d: %v", match.ID, err)
+			failedMatchIDs = append(failedMatchIDs, match.ID)
 			// Continue with other matches even if one fails
 			continue
 		}
 
 		log.Printf("Successfully auto-confirmed solo match ID %d", match.ID)
+		confirmed++
+		s.notificationService.NotifyMatchAutoValidated(&match)
 	}
 
 	// Confirm each expired team match
@@ -72,15 +101,31 @@ func (s *AutoValidationService) ValidateExpiredMatches() error {
 		log.Printf("Auto-confirming team match ID %d (created at %v)", teamMatch.ID, teamMatch.CreatedAt)
 
 		_, err := s.teamMatchService.ConfirmTeamMatch(teamMatch.ID)
+		if err != nil && err.Error() == "scoreboard photo required" {
+			log.Printf("Team match ID %d waits for a scoreboard photo", teamMatch.ID)
+			waitingForPhoto++
+			continue
+		}
 		if err != nil {
 			log.Printf("Error auto-confirming team match ID %d: %v", teamMatch.ID, err)
+			failedTeamMatchIDs = append(failedTeamMatchIDs, teamMatch.ID)
 			// Continue with other matches even if one fails
 			continue
 		}
 
 		log.Printf("Successfully auto-confirmed team match ID %d", teamMatch.ID)
+		confirmed++
+		s.notificationService.NotifyTeamMatchAutoValidated(&teamMatch)
 	}
 
+	s.events.Publish(events.TypeAutoValidationResult, map[string]interface{}{
+		"expired":               totalExpired,
+		"confirmed":             confirmed,
+		"waiting_for_photo":     waitingForPhoto,
+		"failed_match_ids":      failedMatchIDs,
+		"failed_team_match_ids": failedTeamMatchIDs,
+	})
+
 	return nil
 }
 
@@ -105,10 +150,10 @@ func (s *AutoValidationService) GetPendingMatchesCount() (int64, error) {
 
 // GetExpiredMatchesCount returns the number of pending matches older than 24 hours (solo + team)
 func (s *AutoValidationService) GetExpiredMatchesCount() (int64, error) {
-	cutoffTime := time.Now().Add(-24 * time.Hour)
+	cutoffTime := s.clock.Now().Add(-AutoValidationWindow)
 
 	var soloCount int64
-	result := s.db.Model(&models.Match{}).Where("status = ? AND created_at < ?", "pending", cutoffTime).Count(&soloCount)
+	result := s.db.Model(&models.Match{}).Where("status = ? AND created_at < ? AND on_hold = ? AND requires_both_confirmations = ?", "pending", cutoffTime, false, false).Count(&soloCount)
 
 	if result.Error != nil {
 		return 0, result.Error
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"core/models"
+	"core/utils"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultClientFeatures are the features the server offers, CLIENT_FEATURE_FLAGS can switch them or add new ones
+var defaultClientFeatures = map[string]bool{
+	"team_matches":          true,
+	"tournaments":           true,
+	"live_matches":          true,
+	"notifications":         true,
+	"away_mode":             true,
+	"rating_reset_requests": true,
+	"leaderboard":           true,
+}
+
+type ClientConfigService struct {
+	matchService         *MatchService
+	clientVersionService *ClientVersionService
+	features             map[string]bool
+}
+
+// NewClientConfigService reads CLIENT_FEATURE_FLAGS ("live_matches=false,new_profile=true") once at startup
+func NewClientConfigService(matchService *MatchService, clientVersionService *ClientVersionService) *ClientConfigService {
+	features := make(map[string]bool, len(defaultClientFeatures))
+	for name, enabled := range defaultClientFeatures {
+		features[name] = enabled
+	}
+	for _, flag := range strings.Split(os.Getenv("CLIENT_FEATURE_FLAGS"), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(flag), "=")
+		if !found {
+			continue
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("Ignoring client feature flag %q: %v", flag, err)
+			continue
+		}
+		features[strings.TrimSpace(name)] = enabled
+	}
+
+	return &ClientConfigService{
+		matchService:         matchService,
+		clientVersionService: clientVersionService,
+		features:             features,
+	}
+}
+
+// GetClientConfig returns the server behavior the mobile app depends on
+func (s *ClientConfigService) GetClientConfig() models.ClientConfig {
+	features := make(map[string]bool, len(s.features))
+	for name, enabled := range s.features {
+		features[name] = enabled
+	}
+	features["daily_match_limit"] = s.matchService.DailyMatchLimit() > 0
+
+	return models.ClientConfig{
+		Features:            features,
+		AutoValidationHours: int(AutoValidationWindow.Hours()),
+		EloFloor:            utils.EloFloor,
+		EloFloorMode:        s.matchService.FloorMode(),
+		StartingElo:         utils.EloFloor,
+		DailyMatchLimit:     s.matchService.DailyMatchLimit(),
+		AuthProviders:       []string{"password"},
+		MinClientVersion:    s.clientVersionService.MinClientVersion(),
+	}
+}
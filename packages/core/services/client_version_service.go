@@ -0,0 +1,189 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultMinClientVersion accepts every client until MIN_CLIENT_VERSION or the admin setting is set
+	defaultMinClientVersion = "0.0.0"
+
+	// clientVersionRefresh is how long the policy is cached between two reads of the table
+	clientVersionRefresh = 30 * time.Second
+
+	// clientVersionSettingID is the id of the single row of client_version_settings
+	clientVersionSettingID = 1
+)
+
+type ClientVersionService struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	setting  models.ClientVersionSetting
+	loadedAt time.Time
+}
+
+func NewClientVersionService(db *gorm.DB) *ClientVersionService {
+	return &ClientVersionService{
+		db: db,
+	}
+}
+
+// GetSetting returns the client version policy, empty when the admins never set it
+func (s *ClientVersionService) GetSetting() (*models.ClientVersionSetting, error) {
+	setting := models.ClientVersionSetting{ID: clientVersionSettingID, BlockedVersions: []string{}}
+	if err := s.db.First(&setting, clientVersionSettingID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// UpdateSetting replaces the client version policy, applied to requests within a few seconds
+func (s *ClientVersionService) UpdateSetting(req models.UpdateClientVersionSettingRequest) (*models.ClientVersionSetting, error) {
+	minVersion := strings.TrimSpace(req.MinVersion)
+	if minVersion != "" {
+		if _, ok := parseClientVersion(minVersion); !ok {
+			return nil, errors.New("invalid version")
+		}
+	}
+	blocked := make([]string, 0, len(req.BlockedVersions))
+	for _, version := range req.BlockedVersions {
+		version = strings.TrimSpace(version)
+		if _, ok := parseClientVersion(version); !ok {
+			return nil, errors.New("invalid version")
+		}
+		blocked = append(blocked, version)
+	}
+
+	setting := models.ClientVersionSetting{
+		ID:              clientVersionSettingID,
+		MinVersion:      minVersion,
+		BlockedVersions: blocked,
+		Message:         req.Message,
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_version", "blocked_versions", "message", "updated_at"}),
+	}).Create(&setting).Error; err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+
+	return s.GetSetting()
+}
+
+// MinClientVersion returns the oldest supported app version: the admin setting, else MIN_CLIENT_VERSION
+func (s *ClientVersionService) MinClientVersion() string {
+	return s.minVersionOf(s.policy(time.Now()))
+}
+
+func (s *ClientVersionService) minVersionOf(setting models.ClientVersionSetting) string {
+	if setting.MinVersion != "" {
+		return setting.MinVersion
+	}
+	if version := strings.TrimSpace(os.Getenv("MIN_CLIENT_VERSION")); version != "" {
+		return version
+	}
+	return defaultMinClientVersion
+}
+
+// Check returns the upgrade-required error for a client version that is blocked or below the minimum,
+// nil when the version is supported. Unparseable versions are let through.
+func (s *ClientVersionService) Check(version string) *models.UpgradeRequiredResponse {
+	parsed, ok := parseClientVersion(version)
+	if !ok {
+		return nil
+	}
+
+	setting := s.policy(time.Now())
+	minVersion := s.minVersionOf(setting)
+
+	supported := true
+	if minimum, ok := parseClientVersion(minVersion); ok && compareClientVersions(parsed, minimum) < 0 {
+		supported = false
+	}
+	for _, blockedVersion := range setting.BlockedVersions {
+		if blocked, ok := parseClientVersion(blockedVersion); ok && compareClientVersions(parsed, blocked) == 0 {
+			supported = false
+		}
+	}
+	if supported {
+		return nil
+	}
+
+	return &models.UpgradeRequiredResponse{
+		Error:      "Client version no longer supported",
+		Code:       "upgrade_required",
+		Version:    version,
+		MinVersion: minVersion,
+		Message:    setting.Message,
+	}
+}
+
+// policy returns the cached setting, reloaded every clientVersionRefresh since it is read on every request
+func (s *ClientVersionService) policy(now time.Time) models.ClientVersionSetting {
+	s.mu.RLock()
+	setting, stale := s.setting, now.Sub(s.loadedAt) > clientVersionRefresh
+	s.mu.RUnlock()
+	if !stale {
+		return setting
+	}
+
+	loaded, err := s.GetSetting()
+	if err != nil {
+		log.Printf("Error loading client version policy: %v", err)
+		return setting
+	}
+
+	s.mu.Lock()
+	s.setting, s.loadedAt = *loaded, now
+	s.mu.Unlock()
+	return *loaded
+}
+
+// parseClientVersion parses a major.minor.patch version, a pre-release or build suffix is ignored (1.4.0-beta)
+func parseClientVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if cut := strings.IndexAny(version, "-+"); cut >= 0 {
+		version = version[:cut]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return parsed, false
+		}
+		parsed[i] = value
+	}
+	return parsed, true
+}
+
+func compareClientVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
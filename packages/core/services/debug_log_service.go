@@ -0,0 +1,127 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// debugRulesRefresh is how long the active rules are cached between two reads of the table
+const debugRulesRefresh = 30 * time.Second
+
+type DebugLogService struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	rules    []models.DebugLogRule
+	loadedAt time.Time
+}
+
+func NewDebugLogService(db *gorm.DB) *DebugLogService {
+	return &DebugLogService{
+		db: db,
+	}
+}
+
+// ActiveRule returns the rule capturing a route at the given time, nil when the route is not being debugged.
+// Called on every request, so the rules are cached and only reloaded every debugRulesRefresh.
+func (s *DebugLogService) ActiveRule(method, path string, now time.Time) *models.DebugLogRule {
+	s.mu.RLock()
+	stale := now.Sub(s.loadedAt) > debugRulesRefresh
+	s.mu.RUnlock()
+
+	if stale {
+		s.reloadRules(now)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, rule := range s.rules {
+		if rule.Method == method && rule.Path == path && rule.IsActiveAt(now) {
+			return &rule
+		}
+	}
+	return nil
+}
+
+func (s *DebugLogService) reloadRules(now time.Time) {
+	var rules []models.DebugLogRule
+	if err := s.db.Where("expires_at > ?", now).Find(&rules).Error; err != nil {
+		log.Printf("Error loading debug log rules: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.rules = rules
+	s.loadedAt = now
+	s.mu.Unlock()
+}
+
+// invalidate forces the next request to reload the rules
+func (s *DebugLogService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// GetRules lists the rules, most recent first
+func (s *DebugLogService) GetRules() ([]models.DebugLogRule, error) {
+	var rules []models.DebugLogRule
+	if err := s.db.Order("created_at DESC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRule starts capturing a route for the given duration
+func (s *DebugLogService) CreateRule(adminID uint, req models.CreateDebugLogRuleRequest) (*models.DebugLogRule, error) {
+	rule := models.DebugLogRule{
+		Method:    req.Method,
+		Path:      req.Path,
+		ExpiresAt: time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute),
+		CreatedBy: &adminID,
+	}
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return &rule, nil
+}
+
+// DeleteRule stops a capture and deletes the entries it collected
+func (s *DebugLogService) DeleteRule(id uint) error {
+	result := s.db.Delete(&models.DebugLogRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("debug log rule not found")
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// GetEntries lists the exchanges captured by a rule, most recent first
+func (s *DebugLogService) GetEntries(ruleID uint, limit int) ([]models.DebugLogEntry, error) {
+	var entries []models.DebugLogEntry
+	if err := s.db.Where("rule_id = ?", ruleID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Record stores a captured exchange, failures are only logged so that debugging never breaks a request
+func (s *DebugLogService) Record(entry models.DebugLogEntry) {
+	if err := s.db.Create(&entry).Error; err != nil {
+		log.Printf("Error recording debug log entry for %s %s: %v", entry.Method, entry.Path, err)
+	}
+}
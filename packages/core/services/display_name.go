@@ -0,0 +1,121 @@
+package services
+
+import (
+	"core/events"
+	"core/models"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minDisplayNameLength = 2
+	maxDisplayNameLength = 32 // Characters, an emoji counts for one or a few
+)
+
+// DisplayNameCheck is a moderation hook run before a display name is saved: it returns the reason the
+// name is refused, shown to the player, or nil to accept it. The name is already trimmed.
+type DisplayNameCheck func(playerID uint, name string) error
+
+// AddDisplayNameCheck adds a moderation hook after the built-in ones (characters, DISPLAY_NAME_BLOCKLIST,
+// impersonation of another player)
+func (s *PlayerService) AddDisplayNameCheck(check DisplayNameCheck) {
+	s.displayNameChecks = append(s.displayNameChecks, check)
+}
+
+// SetDisplayName sets the name shown on the public payloads of a player, nil or empty clears it so that
+// the username is shown again. The username, used to log in and for the slug, is left untouched.
+func (s *PlayerService) SetDisplayName(id uint, displayName *string, changedBy uint) (*models.Player, error) {
+	player, err := s.editablePlayer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var name *string
+	if displayName != nil {
+		trimmed := strings.Join(strings.Fields(*displayName), " ")
+		if trimmed != "" {
+			name = &trimmed
+		}
+	}
+
+	if name != nil {
+		for _, check := range s.displayNameChecks {
+			if err := check(id, *name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.players.Update(id, map[string]interface{}{"display_name": name}); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(events.TypeDisplayNameChanged, map[string]interface{}{
+		"player_id":  id,
+		"username":   player.Username,
+		"previous":   player.DisplayName,
+		"new":        name,
+		"changed_by": changedBy,
+	})
+
+	return s.GetPlayerByID(id)
+}
+
+// checkDisplayNameCharacters bounds the length and refuses control and invisible characters, keeping
+// the joiners and variation selectors emoji are made of
+func checkDisplayNameCharacters(_ uint, name string) error {
+	length := utf8.RuneCountInString(name)
+	if length < minDisplayNameLength || length > maxDisplayNameLength {
+		return fmt.Errorf("display name must be between %d and %d characters", minDisplayNameLength, maxDisplayNameLength)
+	}
+
+	for _, r := range name {
+		if r == '\u200d' || r == '\ufe0f' { // Zero width joiner, emoji presentation selector
+			continue
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return errors.New("display name contains invisible characters")
+		}
+	}
+	return nil
+}
+
+// newDisplayNameBlocklist refuses the names containing a word of DISPLAY_NAME_BLOCKLIST (comma
+// separated, case insensitive)
+func newDisplayNameBlocklist() DisplayNameCheck {
+	var words []string
+	for _, word := range strings.Split(os.Getenv("DISPLAY_NAME_BLOCKLIST"), ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return func(_ uint, name string) error {
+		lower := strings.ToLower(name)
+		for _, word := range words {
+			if strings.Contains(lower, word) {
+				return errors.New("display name is not allowed")
+			}
+		}
+		return nil
+	}
+}
+
+// checkDisplayNameImpersonation refuses the username or display name of another player, whatever the case
+func (s *PlayerService) checkDisplayNameImpersonation(playerID uint, name string) error {
+	var count int64
+	if err := s.db.Model(&models.Player{}).
+		Where("id <> ?", playerID).
+		Where("LOWER(username) = LOWER(?) OR LOWER(display_name) = LOWER(?)", name, name).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("display name is already used by another player")
+	}
+	return nil
+}
@@ -2,20 +2,70 @@ package services
 
 import (
 	"core/models"
+	"errors"
+	"os"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// defaultEloHistoryArchiveMonths is the age after which ELO history entries are archived
+const defaultEloHistoryArchiveMonths = 12
+
+// eloHistoryColumns are the columns shared by elo_history and elo_history_archive
+const eloHistoryColumns = `id, player_id, match_id, kind, elo_before, elo_after, elo_change,
+	opponent_id, opponent_team_id, match_type, floor_exempt, created_at, updated_at, deleted_at`
+
 type EloHistoryService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	archiveMonths int
 }
 
+// NewEloHistoryService reads ELO_HISTORY_ARCHIVE_MONTHS (default 12, 0 disables the archiving)
 func NewEloHistoryService(db *gorm.DB) *EloHistoryService {
+	archiveMonths := defaultEloHistoryArchiveMonths
+	if value, err := strconv.Atoi(os.Getenv("ELO_HISTORY_ARCHIVE_MONTHS")); err == nil && value >= 0 {
+		archiveMonths = value
+	}
+
 	return &EloHistoryService{
-		db: db,
+		db:            db,
+		archiveMonths: archiveMonths,
 	}
 }
 
+// ArchiveOldEntries moves the ELO history entries older than the archive age to elo_history_archive,
+// keeping elo_history small for the rating engine. Returns the number of entries moved.
+func (s *EloHistoryService) ArchiveOldEntries(now time.Time) (int64, error) {
+	if s.archiveMonths == 0 {
+		return 0, nil
+	}
+
+	cutoff := now.AddDate(0, -s.archiveMonths, 0)
+	result := s.db.Exec(`
+		WITH moved AS (
+			DELETE FROM elo_history WHERE created_at < ?
+			RETURNING `+eloHistoryColumns+`
+		)
+		INSERT INTO elo_history_archive (`+eloHistoryColumns+`, archived_at)
+		SELECT `+eloHistoryColumns+`, ? FROM moved`, cutoff, now)
+	return result.RowsAffected, result.Error
+}
+
+// restoreArchivedEloHistory moves back the archived entries of the matches confirmed since a date,
+// so that reversing and replaying those matches finds their history in elo_history
+func restoreArchivedEloHistory(tx *gorm.DB, since time.Time) error {
+	return tx.Exec(`
+		WITH restored AS (
+			DELETE FROM elo_history_archive
+			WHERE match_id IN (SELECT id FROM matches WHERE confirmed_at >= ?)
+			RETURNING `+eloHistoryColumns+`
+		)
+		INSERT INTO elo_history (`+eloHistoryColumns+`)
+		SELECT `+eloHistoryColumns+` FROM restored`, since).Error
+}
+
 func (s *EloHistoryService) GetRecentEloChanges(limit int) ([]models.EloHistory, error) {
 	var eloHistory []models.EloHistory
 
@@ -33,6 +83,26 @@ func (s *EloHistoryService) GetRecentEloChanges(limit int) ([]models.EloHistory,
 	return eloHistory, nil
 }
 
+// GetTeamRatingHistory returns the rating changes of a team, oldest first
+func (s *EloHistoryService) GetTeamRatingHistory(teamID uint) ([]models.TeamRatingHistory, error) {
+	if err := s.db.First(&models.Team{}, teamID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("team not found")
+		}
+		return nil, err
+	}
+
+	var history []models.TeamRatingHistory
+	if err := s.db.Where("team_id = ?", teamID).
+		Order("created_at ASC, id ASC").
+		Preload("TeamMatch").
+		Preload("OpponentTeam").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
 func (s *EloHistoryService) GetRecentTeamEloChanges(limit int) ([]models.TeamEloHistory, error) {
 	var eloHistory []models.TeamEloHistory
 
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"core/models"
+	"core/utils"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventLeaderboardService struct {
+	db *gorm.DB
+}
+
+func NewEventLeaderboardService(db *gorm.DB) *EventLeaderboardService {
+	return &EventLeaderboardService{
+		db: db,
+	}
+}
+
+// withStatus fills the fields computed at read time: the status and, for opt-in events, the participant count
+func (s *EventLeaderboardService) withStatus(event *models.EventLeaderboard, now time.Time) error {
+	event.Status = event.StatusAt(now)
+	if !event.OptIn {
+		return nil
+	}
+	return s.db.Model(&models.EventLeaderboardParticipant{}).
+		Where("event_leaderboard_id = ?", event.ID).
+		Count(&event.Participants).Error
+}
+
+// GetEvents lists the event leaderboards, most recent window first
+func (s *EventLeaderboardService) GetEvents(now time.Time) ([]models.EventLeaderboard, error) {
+	var events []models.EventLeaderboard
+	if err := s.db.Order("starts_at DESC, id DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	for i := range events {
+		if err := s.withStatus(&events[i], now); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func (s *EventLeaderboardService) GetEventByID(id uint, now time.Time) (*models.EventLeaderboard, error) {
+	var event models.EventLeaderboard
+	if err := s.db.First(&event, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event leaderboard not found")
+		}
+		return nil, err
+	}
+	if err := s.withStatus(&event, now); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (s *EventLeaderboardService) CreateEvent(req models.CreateEventLeaderboardRequest, createdBy uint, now time.Time) (*models.EventLeaderboard, error) {
+	event := &models.EventLeaderboard{
+		Name:        req.Name,
+		Description: req.Description,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		OptIn:       req.OptIn,
+		CreatedBy:   &createdBy,
+	}
+	if err := s.db.Create(event).Error; err != nil {
+		return nil, err
+	}
+	event.Status = event.StatusAt(now)
+	return event, nil
+}
+
+// UpdateEvent changes the name, window or opt-in of an event; its standings follow since they are computed on read
+func (s *EventLeaderboardService) UpdateEvent(id uint, req models.UpdateEventLeaderboardRequest, now time.Time) (*models.EventLeaderboard, error) {
+	event, err := s.GetEventByID(id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.StartsAt != nil {
+		updates["starts_at"] = *req.StartsAt
+		event.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updates["ends_at"] = *req.EndsAt
+		event.EndsAt = *req.EndsAt
+	}
+	if req.OptIn != nil {
+		updates["opt_in"] = *req.OptIn
+	}
+	if !event.EndsAt.After(event.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&models.EventLeaderboard{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetEventByID(id, now)
+}
+
+// DeleteEvent removes an event and its participants, matches and ratings are untouched
+func (s *EventLeaderboardService) DeleteEvent(id uint) error {
+	result := s.db.Delete(&models.EventLeaderboard{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("event leaderboard not found")
+	}
+	return nil
+}
+
+// Join registers a player in an opt-in event, until it ends
+func (s *EventLeaderboardService) Join(id, playerID uint, now time.Time) (*models.EventLeaderboardParticipant, error) {
+	event, err := s.GetEventByID(id, now)
+	if err != nil {
+		return nil, err
+	}
+	if !event.OptIn {
+		return nil, errors.New("event is not opt-in")
+	}
+	if event.Status == models.EventLeaderboardEnded {
+		return nil, errors.New("event has ended")
+	}
+	if err := s.db.First(&models.Player{}, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+
+	participant := &models.EventLeaderboardParticipant{
+		EventLeaderboardID: id,
+		PlayerID:           playerID,
+		JoinedAt:           now,
+	}
+	if err := s.db.Create(participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("already joined")
+		}
+		return nil, err
+	}
+	return participant, nil
+}
+
+// Leave removes a player from an opt-in event, their matches stop counting in its standings
+func (s *EventLeaderboardService) Leave(id, playerID uint) error {
+	result := s.db.Where("event_leaderboard_id = ? AND player_id = ?", id, playerID).Delete(&models.EventLeaderboardParticipant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("not joined")
+	}
+	return nil
+}
+
+// GetStandings replays the confirmed solo matches of the window in confirmation order with an event
+// rating starting at 1200, without the floor, so that the ladder only reflects the event. Players are
+// ranked by rating, then wins. Nothing is stored: an event edited or ended is always consistent.
+func (s *EventLeaderboardService) GetStandings(id uint, now time.Time) (*models.EventStandingsResponse, error) {
+	event, err := s.GetEventByID(id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Model(&models.Match{}).
+		Select("id", "player1_id", "player2_id", "winner_id", "confirmed_at").
+		Where("status = 'confirmed' AND confirmed_at >= ? AND confirmed_at < ?", event.StartsAt, event.EndsAt)
+	if event.OptIn {
+		participants := s.db.Model(&models.EventLeaderboardParticipant{}).
+			Select("player_id").
+			Where("event_leaderboard_id = ?", event.ID)
+		query = query.Where("player1_id IN (?) AND player2_id IN (?)", participants, participants)
+	}
+
+	var matches []models.Match
+	if err := query.Order("confirmed_at ASC, id ASC").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	lines := make(map[uint]*models.EventStanding)
+	line := func(playerID uint) *models.EventStanding {
+		if standing, ok := lines[playerID]; ok {
+			return standing
+		}
+		standing := &models.EventStanding{PlayerID: playerID, Rating: models.EventStartingRating}
+		lines[playerID] = standing
+		return standing
+	}
+
+	for _, match := range matches {
+		player1, player2 := line(match.Player1ID), line(match.Player2ID)
+		change1, change2 := utils.CalculateEloChangeWithExemptions(player1.Rating, player2.Rating, match.WinnerID, match.Player1ID, true, true)
+		player1.Rating += change1
+		player2.Rating += change2
+		for _, standing := range []*models.EventStanding{player1, player2} {
+			standing.Matches++
+			if standing.PlayerID == match.WinnerID {
+				standing.Wins++
+			} else {
+				standing.Losses++
+			}
+		}
+	}
+
+	// Opt-in participants without a match yet are listed at the starting rating
+	if event.OptIn {
+		var joined []uint
+		if err := s.db.Model(&models.EventLeaderboardParticipant{}).
+			Where("event_leaderboard_id = ?", event.ID).
+			Pluck("player_id", &joined).Error; err != nil {
+			return nil, err
+		}
+		for _, playerID := range joined {
+			line(playerID)
+		}
+	}
+
+	standings := make([]models.EventStanding, 0, len(lines))
+	playerIDs := make([]uint, 0, len(lines))
+	for playerID, standing := range lines {
+		standing.Rating = math.Round(standing.Rating*100) / 100
+		if standing.Matches > 0 {
+			standing.WinRate = math.Round(float64(standing.Wins)/float64(standing.Matches)*10000) / 100
+		}
+		standings = append(standings, *standing)
+		playerIDs = append(playerIDs, playerID)
+	}
+
+	var players []models.Player
+	if len(playerIDs) > 0 {
+		if err := s.db.Select("id", "username", "display_name").Where("id IN ?", playerIDs).Find(&players).Error; err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint]models.Player, len(players))
+	for _, player := range players {
+		names[player.ID] = player
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Rating != standings[j].Rating {
+			return standings[i].Rating > standings[j].Rating
+		}
+		if standings[i].Wins != standings[j].Wins {
+			return standings[i].Wins > standings[j].Wins
+		}
+		return standings[i].PlayerID < standings[j].PlayerID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		player := names[standings[i].PlayerID]
+		standings[i].Username = player.Username
+		standings[i].DisplayName = player.DisplayName
+	}
+
+	return &models.EventStandingsResponse{
+		Event:      *event,
+		Matches:    len(matches),
+		Standings:  standings,
+		ComputedAt: now,
+	}, nil
+}
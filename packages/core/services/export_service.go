@@ -0,0 +1,455 @@
+package services
+
+import (
+	"core/export"
+	"core/models"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	authModels "auth/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultExportsDir  = "storage/exports"
+	defaultExportTTL   = 24 * time.Hour
+	maxExportsInFlight = 3                // Pending or running exports per user
+	exportStaleAfter   = 30 * time.Minute // A running export older than this was cut by a restart
+)
+
+// ExportService produces the large exports in the background: a job is created by the request, the
+// worker writes the file in EXPORTS_DIR and the job then carries a signed download URL until the file
+// expires (EXPORT_TTL_HOURS). The worker is kicked on each new job and run every minute by the
+// scheduler, which also picks up the jobs left by a restart and deletes the expired files.
+type ExportService struct {
+	db            *gorm.DB
+	matchService  *MatchService
+	playerService *PlayerService
+	ratingService *RatingService
+
+	dir     string
+	ttl     time.Duration
+	secret  []byte // Key of the download URL signatures
+	running atomic.Bool
+}
+
+func NewExportService(db *gorm.DB, matchService *MatchService, playerService *PlayerService, ratingService *RatingService) *ExportService {
+	dir := os.Getenv("EXPORTS_DIR")
+	if dir == "" {
+		dir = defaultExportsDir
+	}
+
+	ttl := defaultExportTTL
+	if value := os.Getenv("EXPORT_TTL_HOURS"); value != "" {
+		if hours, err := strconv.Atoi(value); err == nil && hours > 0 {
+			ttl = time.Duration(hours) * time.Hour
+		} else {
+			log.Printf("Invalid EXPORT_TTL_HOURS %q, using %s", value, defaultExportTTL)
+		}
+	}
+
+	secret := []byte(os.Getenv("EXPORT_SIGNING_SECRET"))
+	if len(secret) == 0 {
+		// Download URLs then stop working on restart, the jobs stay listed and can be requested again
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(err)
+		}
+	}
+
+	return &ExportService{
+		db:            db,
+		matchService:  matchService,
+		playerService: playerService,
+		ratingService: ratingService,
+		dir:           dir,
+		ttl:           ttl,
+		secret:        secret,
+	}
+}
+
+// CreateJob queues an export for a user. A personal data export is for the user's own player unless
+// an admin asks for another one.
+func (s *ExportService) CreateJob(userID uint, admin bool, req models.CreateExportRequest) (*models.ExportJob, error) {
+	job := models.ExportJob{
+		Kind:        req.Kind,
+		Status:      models.ExportStatusPending,
+		RequestedBy: userID,
+		PlayerID:    req.PlayerID,
+	}
+
+	switch req.Kind {
+	case models.ExportKindPersonalData:
+		if job.PlayerID == nil {
+			job.PlayerID = &userID // A player has the ID of their user
+		}
+		if *job.PlayerID != userID && !admin {
+			return nil, errors.New("unauthorized")
+		}
+	case models.ExportKindMatches:
+		job.MatchStatus = req.Status
+		var err error
+		if job.DateFrom, err = parseExportDate(req.DateFrom); err != nil {
+			return nil, err
+		}
+		if job.DateTo, err = parseExportDate(req.DateTo); err != nil {
+			return nil, err
+		}
+	}
+
+	if job.PlayerID != nil {
+		if _, err := s.playerService.GetPlayerByID(*job.PlayerID); err != nil {
+			return nil, err
+		}
+	}
+
+	var inFlight int64
+	if err := s.db.Model(&models.ExportJob{}).
+		Where("requested_by = ? AND status IN ?", userID, []string{models.ExportStatusPending, models.ExportStatusRunning}).
+		Count(&inFlight).Error; err != nil {
+		return nil, err
+	}
+	if inFlight >= maxExportsInFlight {
+		return nil, errors.New("too many exports in progress")
+	}
+
+	if err := s.db.Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if _, err := s.RunPending(); err != nil {
+			log.Printf("Error running exports: %v", err)
+		}
+	}()
+
+	return &job, nil
+}
+
+func parseExportDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, errors.New("invalid date, use YYYY-MM-DD")
+	}
+	return &date, nil
+}
+
+// GetJob returns an export of the user, with its download URL once done; admins see every export
+func (s *ExportService) GetJob(id, userID uint, admin bool) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := s.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("export not found")
+		}
+		return nil, err
+	}
+	if job.RequestedBy != userID && !admin {
+		return nil, errors.New("export not found")
+	}
+
+	s.sign(&job)
+	return &job, nil
+}
+
+// GetJobs lists the exports requested by a user, most recent first
+func (s *ExportService) GetJobs(userID uint) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	if err := s.db.Where("requested_by = ?", userID).Order("id DESC").Limit(50).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		s.sign(&jobs[i])
+	}
+	return jobs, nil
+}
+
+// sign sets the download URL of a finished export, valid until the file expires
+func (s *ExportService) sign(job *models.ExportJob) {
+	if job.Status != models.ExportStatusDone || job.ExpiresAt == nil {
+		return
+	}
+	expires := job.ExpiresAt.Unix()
+	job.DownloadURL = fmt.Sprintf("/exports/%d/download?expires=%d&signature=%s", job.ID, expires, s.signature(job.ID, expires))
+}
+
+func (s *ExportService) signature(id uint, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// OpenDownload checks the signature of a download URL and returns the export with its file path
+func (s *ExportService) OpenDownload(id uint, expires int64, signature string) (*models.ExportJob, error) {
+	if !hmac.Equal([]byte(signature), []byte(s.signature(id, expires))) {
+		return nil, errors.New("invalid signature")
+	}
+	if time.Now().Unix() > expires {
+		return nil, errors.New("download link expired")
+	}
+
+	var job models.ExportJob
+	if err := s.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("export not found")
+		}
+		return nil, err
+	}
+	if job.Status != models.ExportStatusDone {
+		return nil, errors.New("download link expired")
+	}
+	return &job, nil
+}
+
+// RunPending produces the pending exports one after the other and returns how many were done. Only
+// one worker runs per instance, the jobs are claimed with SKIP LOCKED for the other instances.
+func (s *ExportService) RunPending() (int, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return 0, nil
+	}
+	defer s.running.Store(false)
+
+	done := 0
+	for {
+		var job models.ExportJob
+		result := s.db.Raw(`
+			UPDATE export_jobs SET status = ?, started_at = ?, updated_at = ?
+			WHERE id = (
+				SELECT id FROM export_jobs WHERE status = ? ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *`, models.ExportStatusRunning, time.Now(), time.Now(), models.ExportStatusPending).Scan(&job)
+		if result.Error != nil {
+			return done, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return done, nil
+		}
+
+		if err := s.run(&job); err != nil {
+			log.Printf("Export %d (%s) failed: %v", job.ID, job.Kind, err)
+			message := err.Error()
+			if err := s.db.Model(&job).Updates(map[string]interface{}{
+				"status":      models.ExportStatusFailed,
+				"error":       message,
+				"finished_at": time.Now(),
+			}).Error; err != nil {
+				return done, err
+			}
+			continue
+		}
+		done++
+	}
+}
+
+// run writes the file of an export and marks it done
+func (s *ExportService) run(job *models.ExportJob) error {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+
+	extension := "csv"
+	if job.Kind == models.ExportKindPersonalData {
+		extension = "json"
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("export-%d-%s.%s", job.ID, hex.EncodeToString(suffix), extension))
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	var rows int
+	switch job.Kind {
+	case models.ExportKindMatches:
+		rows, err = s.writeMatches(file, job)
+	case models.ExportKindPlayers:
+		rows, err = s.writePlayers(file)
+	case models.ExportKindPersonalData:
+		rows, err = s.writePersonalData(file, *job.PlayerID)
+	default:
+		err = fmt.Errorf("unknown export kind %s", job.Kind)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+	return s.db.Model(job).Updates(map[string]interface{}{
+		"status":      models.ExportStatusDone,
+		"file_name":   fmt.Sprintf("%s-%s.%s", job.Kind, now.Format("2006-01-02"), extension),
+		"file_path":   path,
+		"size":        info.Size(),
+		"rows":        rows,
+		"finished_at": now,
+		"expires_at":  expiresAt,
+	}).Error
+}
+
+func (s *ExportService) writeMatches(w io.Writer, job *models.ExportJob) (int, error) {
+	writer := csv.NewWriter(w)
+	writer.Write(export.MatchHeader)
+
+	rows := 0
+	filters := MatchFilters{PlayerID: job.PlayerID, Status: job.MatchStatus, DateFrom: job.DateFrom, DateTo: job.DateTo}
+	err := s.matchService.EachMatch(filters, func(matches []models.Match) error {
+		for _, match := range matches {
+			writer.Write(export.MatchRecord(match))
+		}
+		rows += len(matches)
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return 0, err
+	}
+	writer.Flush()
+	return rows, writer.Error()
+}
+
+func (s *ExportService) writePlayers(w io.Writer) (int, error) {
+	writer := csv.NewWriter(w)
+	writer.Write(export.PlayerHeader)
+
+	rows := 0
+	err := s.playerService.EachPlayer("created_at", "ASC", func(players []models.Player) error {
+		for _, player := range players {
+			writer.Write(export.PlayerRecord(player))
+		}
+		rows += len(players)
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return 0, err
+	}
+	writer.Flush()
+	return rows, writer.Error()
+}
+
+// PersonalData is everything stored about a player and their account
+type PersonalData struct {
+	ExportedAt     time.Time               `json:"exported_at"`
+	Account        *authModels.User        `json:"account"`
+	Player         *models.Player          `json:"player"`
+	Ratings        []models.Rating         `json:"ratings"`
+	Teams          []models.Team           `json:"teams"`
+	Matches        []models.Match          `json:"matches"`
+	TeamMatches    []models.TeamMatch      `json:"team_matches"`
+	EloHistory     []models.EloHistory     `json:"elo_history"`
+	TeamEloHistory []models.TeamEloHistory `json:"team_elo_history"`
+}
+
+// writePersonalData writes the personal data of a player as JSON and returns the number of matches
+func (s *ExportService) writePersonalData(w io.Writer, playerID uint) (int, error) {
+	data := PersonalData{ExportedAt: time.Now()}
+
+	var err error
+	if data.Player, err = s.playerService.GetPlayerByID(playerID); err != nil {
+		return 0, err
+	}
+
+	var account authModels.User
+	if err := s.db.Where("id = ?", playerID).Limit(1).Find(&account).Error; err != nil {
+		return 0, err
+	}
+	if account.ID != 0 {
+		data.Account = &account
+	}
+
+	if data.Ratings, err = s.ratingService.GetPlayerRatings(playerID); err != nil {
+		return 0, err
+	}
+	if err := s.db.Where("player1_id = ? OR player2_id = ?", playerID, playerID).Order("id").Find(&data.Teams).Error; err != nil {
+		return 0, err
+	}
+	if err := s.matchService.EachMatch(MatchFilters{PlayerID: &playerID}, func(matches []models.Match) error {
+		data.Matches = append(data.Matches, matches...)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	if len(data.Teams) > 0 {
+		teamIDs := make([]uint, 0, len(data.Teams))
+		for _, team := range data.Teams {
+			teamIDs = append(teamIDs, team.ID)
+		}
+		if err := s.db.Where("team1_id IN ? OR team2_id IN ?", teamIDs, teamIDs).Order("id").Find(&data.TeamMatches).Error; err != nil {
+			return 0, err
+		}
+	}
+	if data.EloHistory, err = s.playerService.GetEloHistoryByPlayerID(playerID); err != nil {
+		return 0, err
+	}
+	if data.TeamEloHistory, err = s.playerService.GetTeamEloHistoryByPlayerID(playerID); err != nil {
+		return 0, err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return 0, err
+	}
+	return len(data.Matches) + len(data.TeamMatches), nil
+}
+
+// CleanupExpired deletes the files past their expiry and fails the exports cut by a restart, and
+// returns how many files were deleted
+func (s *ExportService) CleanupExpired(now time.Time) (int, error) {
+	if err := s.db.Model(&models.ExportJob{}).
+		Where("status = ? AND started_at < ?", models.ExportStatusRunning, now.Add(-exportStaleAfter)).
+		Updates(map[string]interface{}{
+			"status":      models.ExportStatusFailed,
+			"error":       "export interrupted, request it again",
+			"finished_at": now,
+		}).Error; err != nil {
+		return 0, err
+	}
+
+	var expired []models.ExportJob
+	if err := s.db.Where("status = ? AND expires_at < ?", models.ExportStatusDone, now).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	for _, job := range expired {
+		if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Error deleting export file %s: %v", job.FilePath, err)
+			continue
+		}
+		if err := s.db.Model(&job).Updates(map[string]interface{}{
+			"status":    models.ExportStatusExpired,
+			"file_path": "",
+		}).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(expired), nil
+}
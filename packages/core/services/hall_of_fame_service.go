@@ -0,0 +1,137 @@
+package services
+
+import (
+	"core/models"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// hallOfFameSize is the number of record holders listed per category
+	hallOfFameSize = 3
+	// hallOfFameTTL is how long the computed hall of fame is served from memory
+	hallOfFameTTL = 10 * time.Minute
+)
+
+// HallOfFameService computes all-time records. The queries scan the whole match
+// history, so the result is cached in memory for hallOfFameTTL.
+type HallOfFameService struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	cached    *models.HallOfFame
+	expiresAt time.Time
+}
+
+func NewHallOfFameService(db *gorm.DB) *HallOfFameService {
+	return &HallOfFameService{
+		db: db,
+	}
+}
+
+// GetHallOfFame returns the cached hall of fame, computing it again once expired
+func (s *HallOfFameService) GetHallOfFame() (*models.HallOfFame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.cached != nil && now.Before(s.expiresAt) {
+		return s.cached, nil
+	}
+
+	hallOfFame, err := s.compute(now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cached = hallOfFame
+	s.expiresAt = now.Add(hallOfFameTTL)
+	return hallOfFame, nil
+}
+
+func (s *HallOfFameService) compute(now time.Time) (*models.HallOfFame, error) {
+	hallOfFame := &models.HallOfFame{ComputedAt: now}
+
+	records := []struct {
+		query  string
+		target *[]models.HallOfFameEntry
+	}{
+		{highestEloQuery(models.EloHistoryAllView), &hallOfFame.HighestElo},
+		{highestEloQuery("team_elo_history"), &hallOfFame.HighestTeamElo},
+		{longestWinStreakQuery, &hallOfFame.LongestWinStreak},
+		{mostTournamentWinsQuery, &hallOfFame.MostTournamentWins},
+		{mostMatchesQuery, &hallOfFame.MostMatches},
+	}
+
+	for _, record := range records {
+		entries := []models.HallOfFameEntry{}
+		if err := s.db.Raw(record.query, hallOfFameSize).Scan(&entries).Error; err != nil {
+			return nil, err
+		}
+		*record.target = entries
+	}
+
+	return hallOfFame, nil
+}
+
+// highestEloQuery returns the best rating ever reached by each player in an ELO history table
+func highestEloQuery(table string) string {
+	return `
+		SELECT best.player_id, players.username, players.display_name, best.elo_after AS value, best.created_at AS achieved_at
+		FROM (
+			SELECT DISTINCT ON (player_id) player_id, elo_after, created_at
+			FROM ` + table + `
+			WHERE deleted_at IS NULL
+			ORDER BY player_id, elo_after DESC, created_at ASC
+		) best
+		JOIN players ON players.id = best.player_id AND players.deleted_at IS NULL
+		ORDER BY value DESC, achieved_at ASC
+		LIMIT ?`
+}
+
+// longestWinStreakQuery finds the longest run of consecutive confirmed solo wins of each player
+const longestWinStreakQuery = `
+	WITH results AS (
+		SELECT player1_id AS player_id, id, created_at, winner_id = player1_id AS won
+		FROM matches WHERE status = 'confirmed' AND deleted_at IS NULL
+		UNION ALL
+		SELECT player2_id AS player_id, id, created_at, winner_id = player2_id AS won
+		FROM matches WHERE status = 'confirmed' AND deleted_at IS NULL
+	), runs AS (
+		SELECT player_id, created_at, won,
+			ROW_NUMBER() OVER (PARTITION BY player_id ORDER BY created_at, id) -
+			ROW_NUMBER() OVER (PARTITION BY player_id, won ORDER BY created_at, id) AS run
+		FROM results
+	), streaks AS (
+		SELECT DISTINCT ON (player_id) player_id, COUNT(*) AS length, MAX(created_at) AS ended_at
+		FROM runs
+		WHERE won
+		GROUP BY player_id, run
+		ORDER BY player_id, length DESC, ended_at ASC
+	)
+	SELECT streaks.player_id, players.username, players.display_name, streaks.length AS value, streaks.ended_at AS achieved_at
+	FROM streaks
+	JOIN players ON players.id = streaks.player_id AND players.deleted_at IS NULL
+	ORDER BY value DESC, achieved_at ASC
+	LIMIT ?`
+
+// mostTournamentWinsQuery counts the tournament trophies won by each player with any of their teams
+const mostTournamentWinsQuery = `
+	SELECT players.id AS player_id, players.username, players.display_name, COUNT(*) AS value, MAX(trophies.awarded_at) AS achieved_at
+	FROM trophies
+	JOIN teams ON teams.id = trophies.winner_team_id
+	JOIN players ON players.id IN (teams.player1_id, teams.player2_id) AND players.deleted_at IS NULL
+	WHERE trophies.tournament_id IS NOT NULL AND trophies.deleted_at IS NULL
+	GROUP BY players.id
+	ORDER BY value DESC, achieved_at ASC
+	LIMIT ?`
+
+// mostMatchesQuery counts the solo and team matches played by each player
+const mostMatchesQuery = `
+	SELECT id AS player_id, username, display_name, total_matches + team_total_matches AS value
+	FROM players
+	WHERE deleted_at IS NULL
+	ORDER BY value DESC, id ASC
+	LIMIT ?`
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"core/importers"
+	"core/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportService struct {
+	db *gorm.DB
+}
+
+func NewImportService(db *gorm.DB) *ImportService {
+	return &ImportService{
+		db: db,
+	}
+}
+
+func (s *ImportService) GetSources() ([]models.ImportSource, error) {
+	var sources []models.ImportSource
+	if err := s.db.Order("name ASC").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+func (s *ImportService) GetSourceByID(id uint) (*models.ImportSource, error) {
+	var source models.ImportSource
+	if err := s.db.First(&source, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("import source not found")
+		}
+		return nil, err
+	}
+	return &source, nil
+}
+
+func (s *ImportService) CreateSource(req models.CreateImportSourceRequest) (*models.ImportSource, error) {
+	var existing models.ImportSource
+	if err := s.db.Where("name = ?", req.Name).First(&existing).Error; err == nil {
+		return nil, errors.New("import source already exists")
+	}
+
+	source := &models.ImportSource{
+		Name:      req.Name,
+		Connector: req.Connector,
+		URL:       req.URL,
+		Enabled:   true,
+	}
+	if err := s.db.Create(source).Error; err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// DeleteSource removes a source and the matches imported from it
+func (s *ImportService) DeleteSource(id uint) error {
+	result := s.db.Delete(&models.ImportSource{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("import source not found")
+	}
+	return nil
+}
+
+// RunSource pulls the matches of a source through its connector
+func (s *ImportService) RunSource(ctx context.Context, id uint) (*models.ImportResult, error) {
+	source, err := s.GetSourceByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := importers.New(source)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := connector.Fetch(ctx)
+	if err != nil {
+		s.recordRun(source, 0, err)
+		return nil, err
+	}
+
+	result := s.importRecords(source, records)
+	s.recordRun(source, result.Imported, nil)
+	return result, nil
+}
+
+// ImportFile imports a file in the format of the source's connector
+func (s *ImportService) ImportFile(id uint, r io.Reader) (*models.ImportResult, error) {
+	source, err := s.GetSourceByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := importers.Decode(source.Connector, r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.importRecords(source, records)
+	s.recordRun(source, result.Imported, nil)
+	return result, nil
+}
+
+// RunEnabledSources pulls every enabled source with a URL, returns the number of matches imported
+func (s *ImportService) RunEnabledSources(ctx context.Context) (int, error) {
+	var sources []models.ImportSource
+	if err := s.db.Where("enabled = ? AND url IS NOT NULL", true).Find(&sources).Error; err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	for _, source := range sources {
+		result, err := s.RunSource(ctx, source.ID)
+		if err != nil {
+			log.Printf("Error importing matches from %s: %v", source.Name, err)
+			continue
+		}
+		imported += result.Imported
+	}
+	return imported, nil
+}
+
+func (s *ImportService) recordRun(source *models.ImportSource, imported int, runErr error) {
+	updates := map[string]interface{}{
+		"last_import_at":    time.Now(),
+		"last_import_count": imported,
+		"last_error":        nil,
+	}
+	if runErr != nil {
+		updates["last_error"] = runErr.Error()
+	}
+	if err := s.db.Model(source).Updates(updates).Error; err != nil {
+		log.Printf("Error recording import run of %s: %v", source.Name, err)
+	}
+}
+
+// importRecords adds each record to the unranked history of the mapped players
+func (s *ImportService) importRecords(source *models.ImportSource, records []models.ExternalMatchRecord) *models.ImportResult {
+	result := &models.ImportResult{Fetched: len(records)}
+
+	// Resolve every external player ID of the batch at once
+	externalIDs := make([]string, 0, len(records)*2)
+	for _, record := range records {
+		externalIDs = append(externalIDs, record.Player1ID, record.Player2ID)
+	}
+	var mappings []models.PlayerExternalID
+	if err := s.db.Where("source = ? AND external_id IN ?", source.Name, externalIDs).Find(&mappings).Error; err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	players := make(map[string]uint, len(mappings))
+	for _, mapping := range mappings {
+		players[mapping.ExternalID] = mapping.PlayerID
+	}
+
+	for _, record := range records {
+		won1, err := recordWinner(record)
+		if err != nil {
+			result.Invalid++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", record.ExternalID, err))
+			continue
+		}
+
+		player1, mapped1 := players[record.Player1ID]
+		player2, mapped2 := players[record.Player2ID]
+		if !mapped1 && !mapped2 {
+			result.Unmapped++
+			continue
+		}
+
+		var rows []models.ExternalMatch
+		if mapped1 {
+			rows = append(rows, externalMatchFor(source, record, player1, record.Player2ID, record.Player2Name, won1, record.Score1, record.Score2, players))
+		}
+		if mapped2 {
+			rows = append(rows, externalMatchFor(source, record, player2, record.Player1ID, record.Player1Name, !won1, record.Score2, record.Score1, players))
+		}
+
+		insert := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+		if insert.Error != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", record.ExternalID, insert.Error))
+			continue
+		}
+		if insert.RowsAffected == 0 {
+			result.Duplicate++
+		} else {
+			result.Imported++
+		}
+	}
+
+	return result
+}
+
+func externalMatchFor(source *models.ImportSource, record models.ExternalMatchRecord, playerID uint, opponentExternalID, opponentName string, won bool, scoreFor, scoreAgainst *int, players map[string]uint) models.ExternalMatch {
+	externalMatch := models.ExternalMatch{
+		SourceID:           source.ID,
+		ExternalID:         record.ExternalID,
+		PlayerID:           playerID,
+		OpponentExternalID: opponentExternalID,
+		OpponentName:       opponentName,
+		Won:                won,
+		ScoreFor:           scoreFor,
+		ScoreAgainst:       scoreAgainst,
+		PlayedAt:           record.PlayedAt,
+	}
+	if opponentID, ok := players[opponentExternalID]; ok {
+		externalMatch.OpponentPlayerID = &opponentID
+	}
+	return externalMatch
+}
+
+// recordWinner validates a record and reports whether player 1 won
+func recordWinner(record models.ExternalMatchRecord) (bool, error) {
+	if record.ExternalID == "" || record.Player1ID == "" || record.Player2ID == "" {
+		return false, errors.New("external_id, player1_id and player2_id are required")
+	}
+	if record.PlayedAt.IsZero() {
+		return false, errors.New("played_at is required")
+	}
+
+	switch record.WinnerID {
+	case record.Player1ID:
+		return true, nil
+	case record.Player2ID:
+		return false, nil
+	case "":
+		if record.Score1 != nil && record.Score2 != nil && *record.Score1 != *record.Score2 {
+			return *record.Score1 > *record.Score2, nil
+		}
+		return false, errors.New("winner cannot be determined")
+	}
+	return false, errors.New("winner_id is not one of the players")
+}
+
+// AddExternalID maps a player to their identifier in an external source
+func (s *ImportService) AddExternalID(playerID uint, req models.AddPlayerExternalIDRequest) (*models.PlayerExternalID, error) {
+	if err := s.db.First(&models.Player{}, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+
+	source := strings.TrimSpace(req.Source)
+	externalID := strings.TrimSpace(req.ExternalID)
+
+	var existing models.PlayerExternalID
+	err := s.db.Where("source = ? AND (external_id = ? OR player_id = ?)", source, externalID, playerID).First(&existing).Error
+	if err == nil {
+		if existing.PlayerID == playerID {
+			return nil, errors.New("player already has an identifier for this source")
+		}
+		return nil, errors.New("external identifier already belongs to another player")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	mapping := &models.PlayerExternalID{
+		PlayerID:   playerID,
+		Source:     source,
+		ExternalID: externalID,
+	}
+	if err := s.db.Create(mapping).Error; err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+func (s *ImportService) GetExternalIDs(playerID uint) ([]models.PlayerExternalID, error) {
+	var mappings []models.PlayerExternalID
+	if err := s.db.Where("player_id = ?", playerID).Order("source ASC").Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (s *ImportService) RemoveExternalID(playerID uint, source string) error {
+	result := s.db.Where("player_id = ? AND source = ?", playerID, source).Delete(&models.PlayerExternalID{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("external identifier not found")
+	}
+	return nil
+}
+
+// GetExternalMatches returns the unranked history of a player, most recent first
+func (s *ImportService) GetExternalMatches(playerID uint, page, pageSize int) (*models.PaginatedExternalMatchesResponse, error) {
+	query := s.db.Model(&models.ExternalMatch{}).Where("player_id = ?", playerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var matches []models.ExternalMatch
+	if err := query.Preload("Source").
+		Order("played_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedExternalMatchesResponse{
+		Data:       matches,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
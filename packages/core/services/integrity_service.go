@@ -0,0 +1,215 @@
+package services
+
+import (
+	"core/events"
+	"core/models"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	authModels "auth/models"
+	authServices "auth/services"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Matches still pending after this long are stuck: the players ignored them and auto-validation did not apply
+	stalePendingAge = 48 * time.Hour
+
+	// Offending IDs listed per check
+	dataQualitySampleSize = 20
+)
+
+// integrityCheck counts the rows matched by query and samples their id column
+type integrityCheck struct {
+	name        string
+	description string
+	label       string // Line of the email sent to the admins, %d is the count
+	column      string
+	query       func(db *gorm.DB, now time.Time) *gorm.DB
+}
+
+var integrityChecks = []integrityCheck{
+	{
+		name:        models.DataQualityStalePendingMatches,
+		description: "Matches pending for more than 48 hours",
+		label:       "%d match(s) en attente depuis plus de 48 heures",
+		column:      "id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&models.Match{}).Where("status = ? AND created_at < ?", "pending", now.Add(-stalePendingAge))
+		},
+	},
+	{
+		name:        models.DataQualityStalePendingTeamMatches,
+		description: "Team matches pending for more than 48 hours",
+		label:       "%d match(s) en équipe en attente depuis plus de 48 heures",
+		column:      "id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&models.TeamMatch{}).Where("status = ? AND created_at < ?", "pending", now.Add(-stalePendingAge))
+		},
+	},
+	{
+		name:        models.DataQualityUsersWithoutPlayer,
+		description: "Users without a player (the player shares the ID of its user)",
+		label:       "%d utilisateur(s) sans joueur",
+		column:      "users.id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&authModels.User{}).
+				Joins("LEFT JOIN players ON players.id = users.id AND players.deleted_at IS NULL").
+				Where("players.id IS NULL")
+		},
+	},
+	{
+		name:        models.DataQualityUsernameMismatch,
+		description: "Players whose username differs from the username of their user",
+		label:       "%d joueur(s) dont le nom d'utilisateur diffère de celui du compte",
+		column:      "players.id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&models.Player{}).
+				Joins("JOIN users ON users.id = players.id AND users.deleted_at IS NULL").
+				Where("players.username <> users.username")
+		},
+	},
+	{
+		name:        models.DataQualityTeamsWithDeletedPlayers,
+		description: "Teams referencing a missing or deleted player",
+		label:       "%d équipe(s) référençant un joueur supprimé",
+		column:      "teams.id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&models.Team{}).
+				Joins("LEFT JOIN players p1 ON p1.id = teams.player1_id").
+				Joins("LEFT JOIN players p2 ON p2.id = teams.player2_id").
+				Where("p1.id IS NULL OR p1.deleted_at IS NOT NULL OR p2.id IS NULL OR p2.deleted_at IS NOT NULL")
+		},
+	},
+	{
+		name:        models.DataQualityNegativePlayerCounters,
+		description: "Players with a negative match, win or loss counter (solo or team)",
+		label:       "%d joueur(s) avec un compteur négatif",
+		column:      "id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&models.Player{}).
+				Where("total_matches < 0 OR wins < 0 OR losses < 0 OR team_total_matches < 0 OR team_wins < 0 OR team_losses < 0")
+		},
+	},
+	{
+		name:        models.DataQualityNegativeTeamCounters,
+		description: "Teams with a negative match, win or loss counter",
+		label:       "%d équipe(s) avec un compteur négatif",
+		column:      "id",
+		query: func(db *gorm.DB, now time.Time) *gorm.DB {
+			return db.Model(&models.Team{}).Where("total_matches < 0 OR wins < 0 OR losses < 0")
+		},
+	},
+}
+
+type IntegrityService struct {
+	db           *gorm.DB
+	emailService authServices.EmailService
+	events       *events.Bus
+}
+
+func NewIntegrityService(db *gorm.DB, emailService authServices.EmailService, bus *events.Bus) *IntegrityService {
+	return &IntegrityService{
+		db:           db,
+		emailService: emailService,
+		events:       bus,
+	}
+}
+
+// Report runs every integrity check. The checks only read, they never fix anything.
+func (s *IntegrityService) Report(now time.Time) (*models.DataQualityReport, error) {
+	report := &models.DataQualityReport{
+		GeneratedAt: now,
+		Checks:      make([]models.DataQualityCheck, 0, len(integrityChecks)),
+	}
+
+	for _, check := range integrityChecks {
+		result := models.DataQualityCheck{
+			Name:        check.name,
+			Description: check.description,
+			SampleIDs:   []uint{},
+		}
+
+		if err := check.query(s.db, now).Count(&result.Count).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", check.name, err)
+		}
+		if result.Count > 0 {
+			if err := check.query(s.db, now).
+				Order(check.column).
+				Limit(dataQualitySampleSize).
+				Pluck(check.column, &result.SampleIDs).Error; err != nil {
+				return nil, fmt.Errorf("%s: %w", check.name, err)
+			}
+		}
+
+		report.IssueCount += result.Count
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report, nil
+}
+
+// SendWeeklyReport emails the report to every enabled admin and returns the number of emails sent.
+// Nothing is sent when every check passes.
+func (s *IntegrityService) SendWeeklyReport(now time.Time) (int, error) {
+	report, err := s.Report(now)
+	if err != nil {
+		return 0, err
+	}
+	if report.IssueCount == 0 {
+		return 0, nil
+	}
+
+	lines := reportLines(report)
+
+	var admins []authModels.User
+	if err := s.db.Select("id", "email").
+		Where("enabled = ? AND roles @> ?", true, fmt.Sprintf(`["%s"]`, authModels.RoleAdmin)).
+		Find(&admins).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, admin := range admins {
+		if err := s.emailService.SendDataQualityReport(admin.Email, lines); err != nil {
+			if errors.Is(err, authServices.ErrEmailSuppressed) {
+				continue
+			}
+			log.Printf("Error sending data quality report to user %d: %v", admin.ID, err)
+			s.events.Publish(events.TypeDeliveryFailed, map[string]interface{}{
+				"channel": "data_quality_report",
+				"user_id": admin.ID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// reportLines writes one line per failing check, with its first IDs
+func reportLines(report *models.DataQualityReport) []string {
+	var lines []string
+	for i, result := range report.Checks {
+		if result.Count == 0 {
+			continue
+		}
+
+		ids := make([]string, 0, len(result.SampleIDs))
+		for _, id := range result.SampleIDs {
+			ids = append(ids, fmt.Sprintf("#%d", id))
+		}
+		if int64(len(ids)) < result.Count {
+			ids = append(ids, "...")
+		}
+
+		lines = append(lines, fmt.Sprintf(integrityChecks[i].label, result.Count)+" : "+strings.Join(ids, ", "))
+	}
+	return lines
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"core/models"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// kioskPinTTL is how long a pairing PIN can be entered on the device
+const kioskPinTTL = 10 * time.Minute
+
+type KioskService struct {
+	db           *gorm.DB
+	matchService *MatchService
+}
+
+func NewKioskService(db *gorm.DB, matchService *MatchService) *KioskService {
+	return &KioskService{
+		db:           db,
+		matchService: matchService,
+	}
+}
+
+func (s *KioskService) GetDevices() ([]models.KioskDevice, error) {
+	var devices []models.KioskDevice
+	if err := s.db.Order("created_at DESC").Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// RegisterDevice creates a device waiting to be paired with the returned PIN
+func (s *KioskService) RegisterDevice(req models.CreateKioskDeviceRequest, createdBy uint) (*models.KioskPairingResponse, error) {
+	device := &models.KioskDevice{
+		Name:      req.Name,
+		CreatedBy: &createdBy,
+	}
+	if err := s.db.Create(device).Error; err != nil {
+		return nil, err
+	}
+
+	return s.StartPairing(device.ID)
+}
+
+// StartPairing issues a new PIN for a device; its current token stops working
+func (s *KioskService) StartPairing(id uint) (*models.KioskPairingResponse, error) {
+	var device models.KioskDevice
+	if err := s.db.First(&device, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("device not found")
+		}
+		return nil, err
+	}
+
+	pin, err := generatePin()
+	if err != nil {
+		return nil, err
+	}
+
+	pinHash := hashToken(pin)
+	expiresAt := time.Now().Add(kioskPinTTL)
+	updates := map[string]interface{}{
+		"pin_hash":       pinHash,
+		"pin_expires_at": expiresAt,
+		"token_hash":     nil,
+		"paired_at":      nil,
+		"revoked_at":     nil,
+	}
+	if err := s.db.Model(&device).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	device.PinExpiresAt = &expiresAt
+	device.PairedAt = nil
+	device.RevokedAt = nil
+
+	return &models.KioskPairingResponse{Device: device, Pin: pin}, nil
+}
+
+// Pair exchanges a valid PIN for the device's kiosk token
+func (s *KioskService) Pair(pin string) (*models.KioskTokenResponse, error) {
+	var device models.KioskDevice
+	err := s.db.Where("pin_hash = ? AND pin_expires_at > ? AND revoked_at IS NULL", hashToken(pin), time.Now()).
+		First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired PIN")
+		}
+		return nil, err
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(bytes)
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"token_hash":     hashToken(token),
+		"pin_hash":       nil,
+		"pin_expires_at": nil,
+		"paired_at":      now,
+	}
+	if err := s.db.Model(&device).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	device.PinExpiresAt = nil
+	device.PairedAt = &now
+
+	return &models.KioskTokenResponse{Device: device, Token: token}, nil
+}
+
+// RevokeDevice unpairs a device, its token is rejected immediately
+func (s *KioskService) RevokeDevice(id uint) error {
+	updates := map[string]interface{}{
+		"revoked_at":     time.Now(),
+		"token_hash":     nil,
+		"pin_hash":       nil,
+		"pin_expires_at": nil,
+	}
+	result := s.db.Model(&models.KioskDevice{}).Where("id = ? AND revoked_at IS NULL", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("device not found")
+	}
+	return nil
+}
+
+// Authenticate returns the paired device matching a kiosk token and records its activity
+func (s *KioskService) Authenticate(token string) (*models.KioskDevice, error) {
+	var device models.KioskDevice
+	if err := s.db.Where("token_hash = ? AND revoked_at IS NULL", hashToken(token)).First(&device).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid token")
+		}
+		return nil, err
+	}
+
+	s.db.Model(&device).UpdateColumn("last_seen_at", time.Now())
+	return &device, nil
+}
+
+// GetPlayers lists the players to pick from on the kiosk, by name
+func (s *KioskService) GetPlayers() ([]models.KioskPlayer, error) {
+	var players []models.KioskPlayer
+	if err := s.db.Model(&models.Player{}).
+		Select("id", "username", "display_name", "elo_rating").
+		Scopes(models.NotArchived()).
+		Order("COALESCE(display_name, username) ASC").
+		Scan(&players).Error; err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// CreateMatch records a pending match on behalf of a kiosk device
+func (s *KioskService) CreateMatch(deviceID uint, req models.CreateKioskMatchRequest) (*models.Match, error) {
+	if err := s.matchService.CheckDailyQuota(req.Player1ID, req.Player2ID); err != nil {
+		return nil, err
+	}
+
+	match, err := s.matchService.CreateMatch(models.CreateMatchRequest{
+		Player1ID:  req.Player1ID,
+		Player2ID:  req.Player2ID,
+		WinnerID:   req.WinnerID,
+		ClientUUID: req.ClientUUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(match).Update("kiosk_device_id", deviceID).Error; err != nil {
+		return nil, err
+	}
+	match.KioskDeviceID = &deviceID
+
+	return match, nil
+}
+
+// FindReplayedMatch returns the match already recorded with the client UUID of req, nil when the UUID is new
+func (s *KioskService) FindReplayedMatch(req models.CreateKioskMatchRequest) (*models.Match, error) {
+	return s.matchService.FindReplayedMatch(models.CreateMatchRequest{
+		Player1ID:  req.Player1ID,
+		Player2ID:  req.Player2ID,
+		WinnerID:   req.WinnerID,
+		ClientUUID: req.ClientUUID,
+	})
+}
+
+// ConfirmMatch confirms, at the table, a pending match recorded on the same device. Both players stand
+// at the kiosk, so in two-player confirmation mode it records the confirmation of each of them.
+func (s *KioskService) ConfirmMatch(deviceID, matchID uint) (*models.Match, error) {
+	var match models.Match
+	if err := s.db.First(&match, matchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("match not found")
+		}
+		return nil, err
+	}
+
+	if match.KioskDeviceID == nil || *match.KioskDeviceID != deviceID {
+		return nil, errors.New("match was not recorded on this device")
+	}
+	if match.Status != "pending" {
+		return nil, errors.New("match is not pending")
+	}
+	if !match.RequiresBothConfirmations {
+		return s.matchService.ConfirmMatch(matchID)
+	}
+
+	if _, err := s.matchService.ConfirmByPlayer(matchID, match.Player1ID); err != nil {
+		return nil, err
+	}
+	return s.matchService.ConfirmByPlayer(matchID, match.Player2ID)
+}
+
+// hashCardUID normalizes a card UID and returns its HMAC keyed by CARD_UID_SECRET.
+// Card UIDs are short, the key keeps stored hashes from being reversed by brute force,
+// so cards are refused rather than hashed without a key.
+func hashCardUID(uid string) (string, error) {
+	secret := os.Getenv("CARD_UID_SECRET")
+	if secret == "" {
+		return "", errors.New("card login is not configured")
+	}
+
+	normalized := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("0123456789ABCDEF", r) {
+			return r
+		}
+		if strings.ContainsRune(" :-", r) {
+			return -1
+		}
+		return '?'
+	}, strings.ToUpper(strings.TrimSpace(uid)))
+	if normalized == "" || strings.ContainsRune(normalized, '?') {
+		return "", errors.New("invalid card UID")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SetPlayerCard associates a card with a player, replacing their previous card
+func (s *KioskService) SetPlayerCard(playerID uint, uid string) error {
+	cardHash, err := hashCardUID(uid)
+	if err != nil {
+		return err
+	}
+
+	var player models.Player
+	if err := s.db.First(&player, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("player not found")
+		}
+		return err
+	}
+
+	var owner models.Player
+	err = s.db.Where("card_uid_hash = ? AND id <> ?", cardHash, playerID).First(&owner).Error
+	if err == nil {
+		return errors.New("card is already associated with another player")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.db.Model(&player).Update("card_uid_hash", cardHash).Error
+}
+
+// RemovePlayerCard removes the card of a player
+func (s *KioskService) RemovePlayerCard(playerID uint) error {
+	result := s.db.Model(&models.Player{}).
+		Where("id = ? AND card_uid_hash IS NOT NULL", playerID).
+		Update("card_uid_hash", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("player has no card")
+	}
+	return nil
+}
+
+// ResolveCard returns the player a scanned card belongs to
+func (s *KioskService) ResolveCard(uid string) (*models.KioskPlayer, error) {
+	cardHash, err := hashCardUID(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	var player models.KioskPlayer
+	result := s.db.Model(&models.Player{}).
+		Select("id", "username", "display_name", "elo_rating").
+		Where("card_uid_hash = ?", cardHash).
+		Limit(1).
+		Scan(&player)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("unknown card")
+	}
+	return &player, nil
+}
+
+// generatePin returns a random 6 digit PIN
+func generatePin() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
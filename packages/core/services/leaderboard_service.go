@@ -0,0 +1,155 @@
+package services
+
+import (
+	"core/models"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+type LeaderboardService struct {
+	db *gorm.DB
+
+	mu          sync.Mutex
+	refreshedAt *time.Time
+
+	// Coalesces the concurrent reads of the same page and the concurrent refreshes
+	group singleflight.Group
+}
+
+func NewLeaderboardService(db *gorm.DB) *LeaderboardService {
+	return &LeaderboardService{
+		db: db,
+	}
+}
+
+// GetLeaderboard reads a page of the leaderboard view, ordered by ELO, of a campus if not empty.
+// Archived players are left out.
+// Concurrent requests of the same page share a single read.
+func (s *LeaderboardService) GetLeaderboard(page, pageSize int, campus string) (*models.LeaderboardResponse, error) {
+	value, err, _ := s.group.Do(fmt.Sprintf("page:%d:%d:%s", page, pageSize, campus), func() (interface{}, error) {
+		return s.readLeaderboard(page, pageSize, campus)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := *value.(*models.LeaderboardResponse)
+	return &response, nil
+}
+
+// GetTopPlayers reads the best players of the leaderboard view, of a campus if not empty, followed by
+// the current user when given and not among them. Archived players are left out.
+func (s *LeaderboardService) GetTopPlayers(limit int, currentUserID *uint, campus string) ([]models.LeaderboardEntry, error) {
+	top, err := s.GetLeaderboard(1, limit, campus)
+	if err != nil {
+		return nil, err
+	}
+	entries := top.Data
+	if entries == nil {
+		entries = []models.LeaderboardEntry{}
+	}
+
+	if currentUserID == nil || slices.ContainsFunc(entries, func(e models.LeaderboardEntry) bool { return e.PlayerID == *currentUserID }) {
+		return entries, nil
+	}
+
+	var current []models.LeaderboardEntry
+	if err := s.db.Select("leaderboard.*, players.display_name, titles.name AS flair").
+		Joins("LEFT JOIN players ON players.id = leaderboard.player_id").
+		Joins("LEFT JOIN titles ON titles.id = players.flair_title_id").
+		Scopes(models.NotArchived()).
+		Where("leaderboard.player_id = ?", *currentUserID).
+		Find(&current).Error; err != nil {
+		return nil, err
+	}
+	return append(entries, current...), nil
+}
+
+func (s *LeaderboardService) readLeaderboard(page, pageSize int, campus string) (*models.LeaderboardResponse, error) {
+	var entries []models.LeaderboardEntry
+	var total int64
+
+	if err := s.db.Model(&models.LeaderboardEntry{}).
+		Joins("LEFT JOIN players ON players.id = leaderboard.player_id").
+		Scopes(models.OnCampus(campus), models.NotArchived()).
+		Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// The display name, flair and campus are joined here rather than in the view so that a change shows up immediately
+	columns := "leaderboard.*, players.display_name, titles.name AS flair"
+	if campus != "" {
+		columns += ", RANK() OVER (ORDER BY leaderboard.elo_rating DESC) AS campus_rank"
+	}
+	if err := s.db.Select(columns).
+		Joins("LEFT JOIN players ON players.id = leaderboard.player_id").
+		Joins("LEFT JOIN titles ON titles.id = players.flair_title_id").
+		Scopes(models.OnCampus(campus), models.NotArchived()).
+		Order("leaderboard.elo_rating DESC, leaderboard.player_id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	refreshedAt := s.refreshedAt
+	s.mu.Unlock()
+
+	return &models.LeaderboardResponse{
+		Data:        entries,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+		TotalPages:  int((total + int64(pageSize) - 1) / int64(pageSize)),
+		RefreshedAt: refreshedAt,
+	}, nil
+}
+
+// RefreshIfStale refreshes the view when players or matches changed since the last refresh
+// (confirmations, cancellations, rating corrections). Returns whether a refresh happened.
+func (s *LeaderboardService) RefreshIfStale() (bool, error) {
+	var lastChange *time.Time
+	if err := s.db.Raw(`
+		SELECT GREATEST(
+			(SELECT MAX(updated_at) FROM players),
+			(SELECT MAX(updated_at) FROM matches)
+		)`).Scan(&lastChange).Error; err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	refreshedAt := s.refreshedAt
+	s.mu.Unlock()
+
+	if refreshedAt != nil && (lastChange == nil || lastChange.Before(*refreshedAt)) {
+		return false, nil
+	}
+
+	return true, s.Refresh()
+}
+
+// Refresh recomputes the view without blocking the reads, a refresh requested while one runs joins it
+func (s *LeaderboardService) Refresh() error {
+	_, err, _ := s.group.Do("refresh", func() (interface{}, error) {
+		return nil, s.refresh()
+	})
+	return err
+}
+
+func (s *LeaderboardService) refresh() error {
+	startedAt := time.Now()
+	if err := s.db.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY leaderboard").Error; err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.refreshedAt = &startedAt
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// fixturesDB opens the database given by BENCH_DB_DSN, a database loaded with `make fixtures`,
+// and skips the benchmark or test without one
+func fixturesDB(tb testing.TB) *gorm.DB {
+	tb.Helper()
+	dsn := os.Getenv("BENCH_DB_DSN")
+	if dsn == "" {
+		tb.Skip("BENCH_DB_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+func BenchmarkGetLeaderboard(b *testing.B) {
+	leaderboardService := NewLeaderboardService(fixturesDB(b))
+	if err := leaderboardService.Refresh(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := leaderboardService.readLeaderboard(1, 20, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetTopPlayers(b *testing.B) {
+	leaderboardService := NewLeaderboardService(fixturesDB(b))
+	if err := leaderboardService.Refresh(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := leaderboardService.GetTopPlayers(10, nil, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
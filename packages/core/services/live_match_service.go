@@ -0,0 +1,391 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LiveMatchService struct {
+	db           *gorm.DB
+	matchService *MatchService
+}
+
+func NewLiveMatchService(db *gorm.DB, matchService *MatchService) *LiveMatchService {
+	return &LiveMatchService{
+		db:           db,
+		matchService: matchService,
+	}
+}
+
+// StartLiveMatch starts a live match between two players, scores start at 0-0
+func (s *LiveMatchService) StartLiveMatch(req models.StartLiveMatchRequest, startedBy uint) (*models.LiveMatch, error) {
+	if req.Player1ID == req.Player2ID {
+		return nil, errors.New("player1 and player2 must be different")
+	}
+
+	var player1, player2 models.Player
+	if err := s.db.First(&player1, req.Player1ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player1 not found")
+		}
+		return nil, err
+	}
+	if err := s.db.First(&player2, req.Player2ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player2 not found")
+		}
+		return nil, err
+	}
+	if player1.ArchivedAt != nil || player2.ArchivedAt != nil {
+		return nil, errors.New("player is archived")
+	}
+
+	var tournament models.Tournament
+	if req.TournamentID != nil {
+		if err := s.db.First(&tournament, *req.TournamentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("tournament not found")
+			}
+			return nil, err
+		}
+		if tournament.Status != "ongoing" {
+			return nil, errors.New("tournament is not ongoing")
+		}
+	}
+
+	// A player can only be on one table at a time
+	var busy int64
+	if err := s.db.Model(&models.LiveMatch{}).
+		Where("status = ?", models.LiveMatchInProgress).
+		Where("player1_id IN ? OR player2_id IN ?", []uint{req.Player1ID, req.Player2ID}, []uint{req.Player1ID, req.Player2ID}).
+		Count(&busy).Error; err != nil {
+		return nil, err
+	}
+	if busy > 0 {
+		return nil, errors.New("player already in a live match")
+	}
+
+	liveMatch := models.LiveMatch{
+		Player1ID:    req.Player1ID,
+		Player2ID:    req.Player2ID,
+		Status:       models.LiveMatchInProgress,
+		TournamentID: req.TournamentID,
+		StartedAt:    time.Now(),
+		// Tournament rules are frozen for the duration of the match
+		TimeLimitSeconds: tournament.MatchTimeLimitSeconds,
+		GoldenGoal:       tournament.GoldenGoal,
+	}
+	if startedBy != 0 {
+		liveMatch.StartedBy = &startedBy
+	}
+
+	if err := s.db.Create(&liveMatch).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetLiveMatch(liveMatch.ID)
+}
+
+// GetLiveMatches returns the live matches currently in progress
+func (s *LiveMatchService) GetLiveMatches() ([]models.LiveMatch, error) {
+	var liveMatches []models.LiveMatch
+
+	result := s.db.Where("status = ?", models.LiveMatchInProgress).
+		Order("started_at ASC").
+		Preload("Player1").
+		Preload("Player2").
+		Find(&liveMatches)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return liveMatches, nil
+}
+
+// GetLiveMatch returns a live match with its goal timeline
+func (s *LiveMatchService) GetLiveMatch(id uint) (*models.LiveMatch, error) {
+	var liveMatch models.LiveMatch
+
+	err := s.db.Preload("Player1").
+		Preload("Player2").
+		Preload("Goals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scored_at ASC, id ASC")
+		}).
+		First(&liveMatch, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("live match not found")
+		}
+		return nil, err
+	}
+
+	return &liveMatch, nil
+}
+
+// RecordGoal adds a goal for the scorer and appends it to the timeline
+func (s *LiveMatchService) RecordGoal(id uint, scorerID uint) (*models.LiveMatch, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var liveMatch models.LiveMatch
+		if err := tx.First(&liveMatch, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("live match not found")
+			}
+			return err
+		}
+
+		switch liveMatch.Timer(time.Now()).Phase {
+		case models.TimerPaused:
+			return errors.New("live match is paused")
+		case models.TimerTimeOver:
+			return errors.New("live match time is over")
+		}
+
+		column := ""
+		switch scorerID {
+		case liveMatch.Player1ID:
+			column = "player1_score"
+		case liveMatch.Player2ID:
+			column = "player2_score"
+		default:
+			return errors.New("scorer must be either player1 or player2")
+		}
+
+		// Increment atomically: the row stays locked until commit so concurrent goals are serialized
+		result := tx.Model(&models.LiveMatch{}).
+			Where("id = ? AND status = ?", id, models.LiveMatchInProgress).
+			UpdateColumn(column, gorm.Expr(column+" + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("live match is not in progress")
+		}
+
+		if err := tx.First(&liveMatch, id).Error; err != nil {
+			return err
+		}
+
+		goal := models.MatchGoal{
+			LiveMatchID:  id,
+			ScorerID:     scorerID,
+			Player1Score: liveMatch.Player1Score,
+			Player2Score: liveMatch.Player2Score,
+			ScoredAt:     time.Now(),
+		}
+		return tx.Create(&goal).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLiveMatch(id)
+}
+
+// UndoLastGoal removes the last goal of the timeline (scoring mistakes on the table)
+func (s *LiveMatchService) UndoLastGoal(id uint) (*models.LiveMatch, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var liveMatch models.LiveMatch
+		if err := tx.First(&liveMatch, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("live match not found")
+			}
+			return err
+		}
+		if liveMatch.Status != models.LiveMatchInProgress {
+			return errors.New("live match is not in progress")
+		}
+
+		var lastGoal models.MatchGoal
+		if err := tx.Where("live_match_id = ?", id).Order("scored_at DESC, id DESC").First(&lastGoal).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("no goal to undo")
+			}
+			return err
+		}
+
+		if err := tx.Delete(&lastGoal).Error; err != nil {
+			return err
+		}
+
+		// Restore the score as it was before the removed goal
+		var previous models.MatchGoal
+		player1Score, player2Score := 0, 0
+		if err := tx.Where("live_match_id = ?", id).Order("scored_at DESC, id DESC").First(&previous).Error; err == nil {
+			player1Score, player2Score = previous.Player1Score, previous.Player2Score
+		}
+
+		return tx.Model(&liveMatch).Updates(map[string]interface{}{
+			"player1_score": player1Score,
+			"player2_score": player2Score,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLiveMatch(id)
+}
+
+// FinalizeLiveMatch turns a live match into a regular pending match and attaches the timeline to it
+func (s *LiveMatchService) FinalizeLiveMatch(id uint) (*models.LiveMatch, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Claim the live match first, a concurrent finalization or abandon then finds it no longer in progress
+		now := time.Now()
+		result := tx.Model(&models.LiveMatch{}).
+			Where("id = ? AND status = ?", id, models.LiveMatchInProgress).
+			Updates(map[string]interface{}{
+				"status":      models.LiveMatchFinished,
+				"finished_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.First(&models.LiveMatch{}, id).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("live match not found")
+				}
+				return err
+			}
+			return errors.New("live match is not in progress")
+		}
+
+		// Read the score once claimed, no goal can be added any more
+		var liveMatch models.LiveMatch
+		if err := tx.First(&liveMatch, id).Error; err != nil {
+			return err
+		}
+		if liveMatch.Player1Score == liveMatch.Player2Score {
+			return errors.New("live match cannot end in a draw")
+		}
+
+		winnerID := liveMatch.Player1ID
+		if liveMatch.Player2Score > liveMatch.Player1Score {
+			winnerID = liveMatch.Player2ID
+		}
+
+		match, err := s.matchService.createMatchInTransaction(tx, models.CreateMatchRequest{
+			Player1ID:    liveMatch.Player1ID,
+			Player2ID:    liveMatch.Player2ID,
+			WinnerID:     winnerID,
+			TournamentID: liveMatch.TournamentID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.LiveMatch{}).Where("id = ?", id).Update("match_id", match.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.MatchGoal{}).Where("live_match_id = ?", id).Update("match_id", match.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLiveMatch(id)
+}
+
+// AbandonLiveMatch stops a live match without creating a match
+func (s *LiveMatchService) AbandonLiveMatch(id uint) (*models.LiveMatch, error) {
+	now := time.Now()
+	result := s.db.Model(&models.LiveMatch{}).
+		Where("id = ? AND status = ?", id, models.LiveMatchInProgress).
+		Updates(map[string]interface{}{
+			"status":      models.LiveMatchAbandoned,
+			"finished_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	liveMatch, err := s.GetLiveMatch(id)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("live match is not in progress")
+	}
+
+	return liveMatch, nil
+}
+
+// GetTimer returns the synchronized timer state of a live match
+func (s *LiveMatchService) GetTimer(id uint) (*models.LiveMatchTimer, error) {
+	var liveMatch models.LiveMatch
+	if err := s.db.First(&liveMatch, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("live match not found")
+		}
+		return nil, err
+	}
+
+	timer := liveMatch.Timer(time.Now())
+	return &timer, nil
+}
+
+// PauseTimer stops the clock of a live match (injury, ball out of the table...)
+func (s *LiveMatchService) PauseTimer(id uint) (*models.LiveMatchTimer, error) {
+	result := s.db.Model(&models.LiveMatch{}).
+		Where("id = ? AND status = ? AND paused_at IS NULL", id, models.LiveMatchInProgress).
+		Update("paused_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	timer, err := s.GetTimer(id)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("live match timer is not running")
+	}
+
+	return timer, nil
+}
+
+// ResumeTimer restarts the clock of a paused live match
+func (s *LiveMatchService) ResumeTimer(id uint) (*models.LiveMatchTimer, error) {
+	var liveMatch models.LiveMatch
+	if err := s.db.First(&liveMatch, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("live match not found")
+		}
+		return nil, err
+	}
+	if liveMatch.Status != models.LiveMatchInProgress || liveMatch.PausedAt == nil {
+		return nil, errors.New("live match timer is not paused")
+	}
+
+	pausedFor := int(time.Since(*liveMatch.PausedAt).Seconds())
+	if err := s.db.Model(&liveMatch).Updates(map[string]interface{}{
+		"paused_at":      nil,
+		"paused_seconds": gorm.Expr("paused_seconds + ?", pausedFor),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetTimer(id)
+}
+
+// GetMatchTimeline returns the goal-by-goal timeline of a finalized match
+func (s *LiveMatchService) GetMatchTimeline(matchID uint) ([]models.MatchGoal, error) {
+	var match models.Match
+	if err := s.db.First(&match, matchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("match not found")
+		}
+		return nil, err
+	}
+
+	var goals []models.MatchGoal
+	if err := s.db.Where("match_id = ?", matchID).Order("scored_at ASC, id ASC").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+
+	return goals, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MatchFeedService struct {
+	db *gorm.DB
+}
+
+func NewMatchFeedService(db *gorm.DB) *MatchFeedService {
+	return &MatchFeedService{
+		db: db,
+	}
+}
+
+type MatchFeedFilters struct {
+	Kind         *string    `json:"kind,omitempty"` // solo or team, both when nil
+	PlayerID     *uint      `json:"player_id,omitempty"`
+	Outcome      string     `json:"outcome,omitempty"` // Restricts the matches of PlayerID to its "wins" or "losses"
+	Status       *string    `json:"status,omitempty"`
+	TournamentID *uint      `json:"tournament_id,omitempty"`
+	DateFrom     *time.Time `json:"date_from,omitempty"`
+	DateTo       *time.Time `json:"date_to,omitempty"`
+	Page         int        `json:"page"`
+	PerPage      int        `json:"per_page"`
+}
+
+// feedRef is a row of the feed before its match is loaded
+type feedRef struct {
+	MatchKind string
+	ID        uint
+	CreatedAt time.Time
+}
+
+// filtered applies the filters shared by both kinds of matches
+func (f MatchFeedFilters) filtered(query *gorm.DB) *gorm.DB {
+	if f.Status != nil {
+		query = query.Where("status = ?", *f.Status)
+	}
+	if f.TournamentID != nil {
+		query = query.Where("tournament_id = ?", *f.TournamentID)
+	}
+	if f.DateFrom != nil {
+		query = query.Where("created_at >= ?", *f.DateFrom)
+	}
+	if f.DateTo != nil {
+		// Add 24 hours to include the entire day
+		query = query.Where("created_at < ?", f.DateTo.Add(24*time.Hour))
+	}
+	return query
+}
+
+func (s *MatchFeedService) soloRefs(filters MatchFeedFilters) *gorm.DB {
+	query := s.db.Model(&models.Match{}).Select("'" + models.MatchKindSolo + "' AS match_kind, id, created_at")
+	if filters.PlayerID != nil {
+		playerID := *filters.PlayerID
+		query = query.Where("player1_id = ? OR player2_id = ?", playerID, playerID)
+		switch filters.Outcome {
+		case "wins":
+			query = query.Where("winner_id = ?", playerID)
+		case "losses":
+			query = query.Where("winner_id != ?", playerID)
+		}
+	}
+	return filters.filtered(query)
+}
+
+func (s *MatchFeedService) teamRefs(filters MatchFeedFilters) *gorm.DB {
+	query := s.db.Model(&models.TeamMatch{}).Select("'" + models.MatchKindTeam + "' AS match_kind, id, created_at")
+	if filters.PlayerID != nil {
+		playerTeams := s.db.Unscoped().Model(&models.Team{}).Select("id").
+			Where("player1_id = ? OR player2_id = ?", *filters.PlayerID, *filters.PlayerID)
+		query = query.Where("team1_id IN (?) OR team2_id IN (?)", playerTeams, playerTeams)
+		switch filters.Outcome {
+		case "wins":
+			query = query.Where("winner_team_id IN (?)", playerTeams)
+		case "losses":
+			query = query.Where("winner_team_id NOT IN (?)", playerTeams)
+		}
+	}
+	return filters.filtered(query)
+}
+
+// GetFeed returns solo and team matches in a single stream, latest first
+func (s *MatchFeedService) GetFeed(filters MatchFeedFilters) (*models.PaginatedMatchFeedResponse, error) {
+	var feed *gorm.DB
+	switch {
+	case filters.Kind != nil && *filters.Kind == models.MatchKindSolo:
+		feed = s.db.Table("(?) AS feed", s.soloRefs(filters))
+	case filters.Kind != nil && *filters.Kind == models.MatchKindTeam:
+		feed = s.db.Table("(?) AS feed", s.teamRefs(filters))
+	default:
+		feed = s.db.Table("(? UNION ALL ?) AS feed", s.soloRefs(filters), s.teamRefs(filters))
+	}
+
+	var total int64
+	if err := feed.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var refs []feedRef
+	if err := feed.Session(&gorm.Session{}).
+		Order("created_at DESC, match_kind ASC, id DESC").
+		Offset((filters.Page - 1) * filters.PerPage).
+		Limit(filters.PerPage).
+		Scan(&refs).Error; err != nil {
+		return nil, err
+	}
+
+	items, err := s.loadItems(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedMatchFeedResponse{
+		Data:       items,
+		Total:      total,
+		Page:       filters.Page,
+		PageSize:   filters.PerPage,
+		TotalPages: int((total + int64(filters.PerPage) - 1) / int64(filters.PerPage)),
+	}, nil
+}
+
+// GetPlayerHistory returns the solo matches of a player and the matches of their teams, latest first
+func (s *MatchFeedService) GetPlayerHistory(playerID uint, outcome string, page, pageSize int) (*models.PaginatedMatchFeedResponse, error) {
+	if err := s.db.First(&models.Player{}, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+
+	return s.GetFeed(MatchFeedFilters{
+		PlayerID: &playerID,
+		Outcome:  outcome,
+		Page:     page,
+		PerPage:  pageSize,
+	})
+}
+
+// loadItems loads the matches of a page of the feed, a few queries per kind rather than one per match
+func (s *MatchFeedService) loadItems(refs []feedRef) ([]models.MatchFeedItem, error) {
+	var soloIDs, teamIDs []uint
+	for _, ref := range refs {
+		if ref.MatchKind == models.MatchKindSolo {
+			soloIDs = append(soloIDs, ref.ID)
+		} else {
+			teamIDs = append(teamIDs, ref.ID)
+		}
+	}
+
+	soloMatches := make(map[uint]*models.Match, len(soloIDs))
+	if len(soloIDs) > 0 {
+		var matches []models.Match
+		if err := s.db.Preload("Player1").Preload("Player2").Preload("Winner").
+			Where("id IN ?", soloIDs).Find(&matches).Error; err != nil {
+			return nil, err
+		}
+		for i := range matches {
+			soloMatches[matches[i].ID] = &matches[i]
+		}
+	}
+
+	teamMatches := make(map[uint]*models.TeamMatch, len(teamIDs))
+	if len(teamIDs) > 0 {
+		var matches []models.TeamMatch
+		if err := s.db.Where("id IN ?", teamIDs).Find(&matches).Error; err != nil {
+			return nil, err
+		}
+		if err := loadTeamMatchRelations(s.db, matches); err != nil {
+			return nil, err
+		}
+		for i := range matches {
+			teamMatches[matches[i].ID] = &matches[i]
+		}
+	}
+
+	items := make([]models.MatchFeedItem, 0, len(refs))
+	for _, ref := range refs {
+		item := models.MatchFeedItem{MatchKind: ref.MatchKind, ID: ref.ID, CreatedAt: ref.CreatedAt}
+		if ref.MatchKind == models.MatchKindSolo {
+			item.Match = soloMatches[ref.ID]
+		} else {
+			item.TeamMatch = teamMatches[ref.ID]
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
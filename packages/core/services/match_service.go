@@ -1,41 +1,190 @@
 package services
 
 import (
+	"core/clock"
 	"core/models"
+	"core/repositories"
 	"core/utils"
 	"errors"
+	"log"
+	"os"
+	"slices"
+	"strconv"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// defaultDailyMatchLimit applies when MATCH_DAILY_LIMIT is not set: no quota, so that upgrading
+// a deployment does not start refusing matches
+const defaultDailyMatchLimit = 0
+
+// exportBatchSize is the number of rows loaded at once when a list is streamed as CSV
+const exportBatchSize = 500
+
 type MatchService struct {
-	db            *gorm.DB
-	playerService *PlayerService
+	db                 *gorm.DB
+	matches            repositories.MatchRepo
+	playerService      *PlayerService
+	dailyMatchLimit    int
+	calibrationMatches int
+	floorMode          string
+	photoPolicy        string
+	clock              clock.Clock
 }
 
 func NewMatchService(db *gorm.DB) *MatchService {
+	return NewMatchServiceWithRepos(db, repositories.NewPlayerRepo(db), repositories.NewMatchRepo(db))
+}
+
+// NewMatchServiceWithRepos builds the service on the given repositories (fakes, replica, cache).
+// Confirmation, ELO and replay transactions still go through db.
+func NewMatchServiceWithRepos(db *gorm.DB, players repositories.PlayerRepo, matches repositories.MatchRepo) *MatchService {
+	floorMode := floorModeFromEnv()
 	return &MatchService{
-		db:            db,
-		playerService: NewPlayerService(db),
+		db:                 db,
+		matches:            matches,
+		playerService:      NewPlayerServiceWithRepos(db, players, matches),
+		dailyMatchLimit:    dailyMatchLimitFromEnv(),
+		calibrationMatches: calibrationMatchesFromEnv(floorMode),
+		floorMode:          floorMode,
+		photoPolicy:        photoPolicyFromEnv(),
+		clock:              clock.Real,
 	}
 }
 
-func (s *MatchService) GetRecentMatches(limit int) ([]models.Match, error) {
-	var matches []models.Match
+// SetClock replaces the wall clock used for creation, confirmation and quota dates
+func (s *MatchService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// dailyMatchLimitFromEnv reads MATCH_DAILY_LIMIT; 0 (default) disables the quota
+func dailyMatchLimitFromEnv() int {
+	value := os.Getenv("MATCH_DAILY_LIMIT")
+	if value == "" {
+		return defaultDailyMatchLimit
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Printf("Invalid MATCH_DAILY_LIMIT %q, using %d", value, defaultDailyMatchLimit)
+		return defaultDailyMatchLimit
+	}
+	return limit
+}
+
+// calibrationMatchesFromEnv reads ELO_CALIBRATION_MATCHES, the number of first confirmed matches of a
+// player that the ELO floor does not apply to; by default 0 (disabled), or utils.ZeroSumCalibrationMatches
+// in zero_sum mode where ratings could not move off the floor without it
+func calibrationMatchesFromEnv(floorMode string) int {
+	defaultMatches := 0
+	if floorMode == utils.FloorModeZeroSum {
+		defaultMatches = utils.ZeroSumCalibrationMatches
+	}
+
+	value := os.Getenv("ELO_CALIBRATION_MATCHES")
+	if value == "" {
+		return defaultMatches
+	}
+	matches, err := strconv.Atoi(value)
+	if err != nil || matches < 0 {
+		log.Printf("Invalid ELO_CALIBRATION_MATCHES %q, using %d", value, defaultMatches)
+		return defaultMatches
+	}
+	return matches
+}
+
+// floorModeFromEnv reads ELO_FLOOR_MODE, one of utils.FloorModes (default clamp)
+func floorModeFromEnv() string {
+	value := os.Getenv("ELO_FLOOR_MODE")
+	if value == "" {
+		return utils.FloorModeClamp
+	}
+	if !slices.Contains(utils.FloorModes, value) {
+		log.Printf("Invalid ELO_FLOOR_MODE %q, using %s", value, utils.FloorModeClamp)
+		return utils.FloorModeClamp
+	}
+	return value
+}
+
+// eloChanges computes the ELO changes of a confirmed match, weighted by its tournament and by the rating
+// overrides of the players, and whether each player was exempt from the floor
+func (s *MatchService) eloChanges(tx *gorm.DB, match *models.Match, player1, player2 *models.Player, confirmedAt time.Time) (float64, float64, bool, bool) {
+	player1Exempt := s.floorExempt(tx, match, match.Player1ID, confirmedAt)
+	player2Exempt := s.floorExempt(tx, match, match.Player2ID, confirmedAt)
+	weight := tournamentEloWeight(tx, match.TournamentID)
+	player1Change, player2Change := utils.CalculateWeightedEloChange(
+		player1.EloRating,
+		player2.EloRating,
+		match.WinnerID,
+		match.Player1ID,
+		weight*ratingKMultiplier(tx, match.Player1ID, confirmedAt),
+		weight*ratingKMultiplier(tx, match.Player2ID, confirmedAt),
+		player1Exempt,
+		player2Exempt,
+	)
+	if s.floorMode == utils.FloorModeZeroSum {
+		player1Change, player2Change = utils.ZeroSumEloChange(player1Change, player2Change)
+	}
+	return player1Change, player2Change, player1Exempt, player2Exempt
+}
+
+// floorExempt reports whether the ELO floor does not apply to a player on a match: the floor is
+// disabled, the match was flagged by an admin, or the player had fewer confirmed matches than the
+// calibration before it
+func (s *MatchService) floorExempt(tx *gorm.DB, match *models.Match, playerID uint, confirmedAt time.Time) bool {
+	if s.floorMode == utils.FloorModeNone || match.FloorExempt {
+		return true
+	}
+	if s.calibrationMatches == 0 {
+		return false
+	}
+
+	var played int64
+	if err := tx.Model(&models.Match{}).
+		Where("status = 'confirmed' AND id <> ? AND confirmed_at < ?", match.ID, confirmedAt).
+		Where("player1_id = ? OR player2_id = ?", playerID, playerID).
+		Count(&played).Error; err != nil {
+		log.Printf("Error counting the matches of player %d for calibration: %v", playerID, err)
+		return false
+	}
+	return played < int64(s.calibrationMatches)
+}
+
+// FloorMode returns how the ELO floor treats the solo and team matches, one of utils.FloorModes
+func (s *MatchService) FloorMode() string {
+	return s.floorMode
+}
 
-	result := s.db.Order("created_at DESC").
-		Limit(limit).
-		Preload("Player1").
-		Preload("Player2").
-		Preload("Winner").
-		Find(&matches)
+// DailyMatchLimit returns the number of ranked matches a player can submit per day (0 = unlimited)
+func (s *MatchService) DailyMatchLimit() int {
+	return s.dailyMatchLimit
+}
+
+// CheckDailyQuota returns an error when one of the players already reached the daily cap of ranked matches.
+// Tournament matches are scheduled and do not count; rejected and cancelled matches neither.
+func (s *MatchService) CheckDailyQuota(playerIDs ...uint) error {
+	if s.dailyMatchLimit == 0 {
+		return nil
+	}
 
-	if result.Error != nil {
-		return nil, result.Error
+	now := s.clock.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for _, playerID := range playerIDs {
+		count, err := s.matches.CountRankedSince(playerID, startOfDay)
+		if err != nil {
+			return err
+		}
+		if count >= int64(s.dailyMatchLimit) {
+			return errors.New("daily match limit reached")
+		}
 	}
+	return nil
+}
 
-	return matches, nil
+func (s *MatchService) GetRecentMatches(limit int) ([]models.Match, error) {
+	return s.matches.Recent(limit)
 }
 
 type MatchFilters struct {
@@ -47,52 +196,30 @@ type MatchFilters struct {
 	PerPage  int        `json:"per_page"`
 }
 
-func (s *MatchService) GetMatches(filters MatchFilters) (*models.PaginatedMatchResponse, error) {
-	var matches []models.Match
-	var total int64
-
-	// Build query
-	query := s.db.Model(&models.Match{})
-
-	// Apply filters
-	if filters.PlayerID != nil {
-		query = query.Where("player1_id = ? OR player2_id = ?", *filters.PlayerID, *filters.PlayerID)
-	}
-
-	if filters.Status != nil {
-		query = query.Where("status = ?", *filters.Status)
-	}
-
-	if filters.DateFrom != nil {
-		query = query.Where("created_at >= ?", *filters.DateFrom)
+// matchQuery converts the list filters into a repository query
+func matchQuery(filters MatchFilters) repositories.MatchQuery {
+	query := repositories.MatchQuery{
+		PlayerID:    filters.PlayerID,
+		Status:      filters.Status,
+		CreatedFrom: filters.DateFrom,
 	}
 
 	if filters.DateTo != nil {
 		// Add 24 hours to include the entire day
 		dateTo := filters.DateTo.Add(24 * time.Hour)
-		query = query.Where("created_at < ?", dateTo)
-	}
-
-	// Get total count
-	if err := query.Count(&total).Error; err != nil {
-		return nil, err
+		query.CreatedBefore = &dateTo
 	}
+	return query
+}
 
+func (s *MatchService) GetMatches(filters MatchFilters) (*models.PaginatedMatchResponse, error) {
 	// Calculate offset
 	offset := (filters.Page - 1) * filters.PerPage
 
-	// Get paginated results
-	result := query.
-		Offset(offset).
-		Limit(filters.PerPage).
-		Order("created_at DESC").
-		Preload("Player1").
-		Preload("Player2").
-		Preload("Winner").
-		Find(&matches)
-
-	if result.Error != nil {
-		return nil, result.Error
+	// Get paginated results with the total count
+	matches, total, err := s.matches.List(matchQuery(filters), offset, filters.PerPage)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate total pages
@@ -107,17 +234,60 @@ func (s *MatchService) GetMatches(filters MatchFilters) (*models.PaginatedMatchR
 	}, nil
 }
 
+// EachMatch passes every match of the filters to fn in batches, latest first, ignoring the pagination (CSV exports)
+func (s *MatchService) EachMatch(filters MatchFilters, fn func([]models.Match) error) error {
+	return s.matches.Each(matchQuery(filters), exportBatchSize, fn)
+}
+
+// FindReplayedMatch returns the match already created with the client UUID of req, nil when the UUID is new
+// or not given. A UUID used for a different or deleted match is an error.
+func (s *MatchService) FindReplayedMatch(req models.CreateMatchRequest) (*models.Match, error) {
+	if req.ClientUUID == nil {
+		return nil, nil
+	}
+
+	var match models.Match
+	if err := s.db.Unscoped().Where("client_uuid = ?", *req.ClientUUID).First(&match).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if match.DeletedAt.Valid || match.Player1ID != req.Player1ID || match.Player2ID != req.Player2ID || match.WinnerID != req.WinnerID {
+		return nil, errors.New("client_uuid already used")
+	}
+
+	return s.matches.FindByIDWithPlayers(match.ID)
+}
+
 func (s *MatchService) CreateMatch(req models.CreateMatchRequest) (*models.Match, error) {
+	var match *models.Match
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		match, err = s.createMatchInTransaction(tx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the created match with relationships
+	return s.matches.FindByIDWithPlayers(match.ID)
+}
+
+// createMatchInTransaction validates and creates a pending match, so that callers can create it
+// together with their own changes
+func (s *MatchService) createMatchInTransaction(tx *gorm.DB, req models.CreateMatchRequest) (*models.Match, error) {
 	// Validate that players exist
 	var player1, player2 models.Player
-	if err := s.db.First(&player1, req.Player1ID).Error; err != nil {
+	if err := tx.First(&player1, req.Player1ID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("player1 not found")
 		}
 		return nil, err
 	}
 
-	if err := s.db.First(&player2, req.Player2ID).Error; err != nil {
+	if err := tx.First(&player2, req.Player2ID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("player2 not found")
 		}
@@ -129,23 +299,20 @@ func (s *MatchService) CreateMatch(req models.CreateMatchRequest) (*models.Match
 		return nil, errors.New("player1 and player2 must be different")
 	}
 
+	// Archived players left the school, their profile is read-only
+	if player1.ArchivedAt != nil || player2.ArchivedAt != nil {
+		return nil, errors.New("player is archived")
+	}
+
 	// Validate that winner is one of the players
 	if req.WinnerID != req.Player1ID && req.WinnerID != req.Player2ID {
 		return nil, errors.New("winner must be either player1 or player2")
 	}
 
-	// Start transaction
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
 	// Validate tournament if provided
 	if req.TournamentID != nil {
 		var tournament models.Tournament
-		if err := s.db.First(&tournament, *req.TournamentID).Error; err != nil {
+		if err := tx.First(&tournament, *req.TournamentID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("tournament not found")
 			}
@@ -157,7 +324,12 @@ func (s *MatchService) CreateMatch(req models.CreateMatchRequest) (*models.Match
 	}
 
 	// Create the match in pending status
-	now := time.Now()
+	now := s.clock.Now()
+	setting, err := seasonSettingOf(tx, models.SeasonOf(now))
+	if err != nil {
+		return nil, err
+	}
+
 	match := models.Match{
 		Player1ID:    req.Player1ID,
 		Player2ID:    req.Player2ID,
@@ -165,43 +337,48 @@ func (s *MatchService) CreateMatch(req models.CreateMatchRequest) (*models.Match
 		TournamentID: req.TournamentID,
 		Status:       "pending",
 		CreatedAt:    now,
+		ClientUUID:   req.ClientUUID,
 		// ConfirmedAt will be set when confirmed
+		RequiresBothConfirmations: setting.ConfirmationMode == models.ConfirmationModeBoth,
 	}
 
 	if err := tx.Create(&match).Error; err != nil {
-		tx.Rollback()
+		// Only client_uuid is unique, the same request was sent twice at once
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("client_uuid already used")
+		}
 		return nil, err
 	}
 
 	// No ELO calculations or stats updates for pending matches
 	// These will be done when the match is confirmed
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
+	return &match, nil
+}
 
-	// Load the created match with relationships
-	if err := s.db.Preload("Player1").Preload("Player2").Preload("Winner").First(&match, match.ID).Error; err != nil {
+func (s *MatchService) UpdateMatchStatus(matchID uint, req models.UpdateMatchStatusRequest) (*models.Match, error) {
+	var match *models.Match
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		match, err = s.updateMatchStatusInTransaction(tx, matchID, req)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &match, nil
-}
+	s.statusUpdated(match)
 
-func (s *MatchService) UpdateMatchStatus(matchID uint, req models.UpdateMatchStatusRequest) (*models.Match, error) {
-	// Start transaction
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	// Load the updated match with relationships
+	return s.matches.FindByIDWithPlayers(match.ID)
+}
 
-	// Get the match
+// updateMatchStatusInTransaction applies a status update, and the ELO of a confirmation, so that callers
+// can apply it together with their own changes. statusUpdated must be called once committed.
+func (s *MatchService) updateMatchStatusInTransaction(tx *gorm.DB, matchID uint, req models.UpdateMatchStatusRequest) (*models.Match, error) {
+	// Get the match, locked so that a concurrent confirmation waits and then finds it no longer pending
 	var match models.Match
-	if err := tx.First(&match, matchID).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&match, matchID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("match not found")
 		}
@@ -210,22 +387,43 @@ func (s *MatchService) UpdateMatchStatus(matchID uint, req models.UpdateMatchSta
 
 	// Check if match is still pending
 	if match.Status != "pending" {
-		tx.Rollback()
 		return nil, errors.New("match is not pending")
 	}
 
+	// A match held by the anomaly review is released or rejected by an admin first
+	if match.OnHold && req.Status != nil && *req.Status == "confirmed" {
+		return nil, errors.New("match is on hold for review")
+	}
+
 	// Update winner_id if provided
 	if req.WinnerID != nil {
 		// Validate that winner is one of the players
 		if *req.WinnerID != match.Player1ID && *req.WinnerID != match.Player2ID {
-			tx.Rollback()
 			return nil, errors.New("winner must be either player1 or player2")
 		}
+		if *req.WinnerID != match.WinnerID {
+			match.Disputed = true
+			// A corrected result has to be confirmed again by both players
+			if match.RequiresBothConfirmations {
+				match.Player1ConfirmedAt = nil
+				match.Player2ConfirmedAt = nil
+			}
+		}
 		match.WinnerID = *req.WinnerID
 	}
 
+	if req.PhotoURL != nil {
+		match.PhotoURL = req.PhotoURL
+	}
+
+	// Under a strict photo policy, the scoreboard photo comes before the confirmation
+	if req.Status != nil && *req.Status == "confirmed" && match.PhotoURL == nil &&
+		photoRequired(tx, s.photoPolicy, match.TournamentID, match.RefereeID, match.Disputed) {
+		return nil, errors.New("scoreboard photo required")
+	}
+
 	// Update status if provided
-	now := time.Now()
+	now := s.clock.Now()
 	if req.Status != nil {
 		match.Status = *req.Status
 		if *req.Status == "confirmed" {
@@ -234,7 +432,6 @@ func (s *MatchService) UpdateMatchStatus(matchID uint, req models.UpdateMatchSta
 	}
 
 	if err := tx.Save(&match).Error; err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
@@ -243,65 +440,59 @@ func (s *MatchService) UpdateMatchStatus(matchID uint, req models.UpdateMatchSta
 		// Get current player ELO ratings
 		var player1, player2 models.Player
 		if err := tx.First(&player1, match.Player1ID).Error; err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 		if err := tx.First(&player2, match.Player2ID).Error; err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 
 		// Calculate ELO changes
-		player1Change, player2Change := utils.CalculateEloChange(
-			player1.EloRating,
-			player2.EloRating,
-			match.WinnerID,
-			match.Player1ID,
-		)
+		player1Change, player2Change, player1Exempt, player2Exempt := s.eloChanges(tx, &match, &player1, &player2, now)
 
 		// Create ELO history entries
 		eloHistory1 := models.EloHistory{
-			PlayerID:   match.Player1ID,
-			MatchID:    match.ID,
-			EloBefore:  player1.EloRating,
-			EloAfter:   player1.EloRating + player1Change,
-			EloChange:  player1Change,
-			OpponentID: &match.Player2ID,
-			CreatedAt:  now,
+			PlayerID:    match.Player1ID,
+			MatchID:     &match.ID,
+			Kind:        models.EloHistoryKindMatch,
+			EloBefore:   player1.EloRating,
+			EloAfter:    player1.EloRating + player1Change,
+			EloChange:   player1Change,
+			OpponentID:  &match.Player2ID,
+			FloorExempt: player1Exempt,
+			CreatedAt:   now,
 		}
 
 		eloHistory2 := models.EloHistory{
-			PlayerID:   match.Player2ID,
-			MatchID:    match.ID,
-			EloBefore:  player2.EloRating,
-			EloAfter:   player2.EloRating + player2Change,
-			EloChange:  player2Change,
-			OpponentID: &match.Player1ID,
-			CreatedAt:  now,
+			PlayerID:    match.Player2ID,
+			MatchID:     &match.ID,
+			Kind:        models.EloHistoryKindMatch,
+			EloBefore:   player2.EloRating,
+			EloAfter:    player2.EloRating + player2Change,
+			EloChange:   player2Change,
+			OpponentID:  &match.Player1ID,
+			FloorExempt: player2Exempt,
+			CreatedAt:   now,
 		}
 
 		if err := tx.Create(&eloHistory1).Error; err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 
 		if err := tx.Create(&eloHistory2).Error; err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 
 		// Update player stats and ELO ratings
 		if err := s.updatePlayerStatsInTransaction(tx, match.Player1ID, match.Player2ID, match.WinnerID, player1Change, player2Change); err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
+	return &match, nil
+}
 
+// statusUpdated refreshes what depends on a committed status update
+func (s *MatchService) statusUpdated(match *models.Match) {
 	// If match was confirmed, recalculate all player ranks
 	if match.Status == "confirmed" {
 		if err := s.playerService.RecalculateAllRanks(); err != nil {
@@ -309,13 +500,6 @@ func (s *MatchService) UpdateMatchStatus(matchID uint, req models.UpdateMatchSta
 			// In production, you might want to use a proper logger
 		}
 	}
-
-	// Load the updated match with relationships
-	if err := s.db.Preload("Player1").Preload("Player2").Preload("Winner").First(&match, match.ID).Error; err != nil {
-		return nil, err
-	}
-
-	return &match, nil
 }
 
 func (s *MatchService) updatePlayerStatsInTransaction(tx *gorm.DB, player1ID, player2ID, winnerID uint, player1Change, player2Change float64) error {
@@ -400,32 +584,31 @@ func (s *MatchService) recalculateSubsequentMatchesInTransaction(tx *gorm.DB, pl
 		}
 
 		// Calculate new ELO changes based on current ratings
-		player1Change, player2Change := utils.CalculateEloChange(
-			player1.EloRating,
-			player2.EloRating,
-			subsequentMatch.WinnerID,
-			subsequentMatch.Player1ID,
-		)
+		player1Change, player2Change, player1Exempt, player2Exempt := s.eloChanges(tx, &subsequentMatch, &player1, &player2, *subsequentMatch.ConfirmedAt)
 
 		// Create new ELO history entries
 		eloHistory1 := models.EloHistory{
-			PlayerID:   subsequentMatch.Player1ID,
-			MatchID:    subsequentMatch.ID,
-			EloBefore:  player1.EloRating,
-			EloAfter:   player1.EloRating + player1Change,
-			EloChange:  player1Change,
-			OpponentID: &subsequentMatch.Player2ID,
-			CreatedAt:  *subsequentMatch.ConfirmedAt,
+			PlayerID:    subsequentMatch.Player1ID,
+			MatchID:     &subsequentMatch.ID,
+			Kind:        models.EloHistoryKindMatch,
+			EloBefore:   player1.EloRating,
+			EloAfter:    player1.EloRating + player1Change,
+			EloChange:   player1Change,
+			OpponentID:  &subsequentMatch.Player2ID,
+			FloorExempt: player1Exempt,
+			CreatedAt:   *subsequentMatch.ConfirmedAt,
 		}
 
 		eloHistory2 := models.EloHistory{
-			PlayerID:   subsequentMatch.Player2ID,
-			MatchID:    subsequentMatch.ID,
-			EloBefore:  player2.EloRating,
-			EloAfter:   player2.EloRating + player2Change,
-			EloChange:  player2Change,
-			OpponentID: &subsequentMatch.Player1ID,
-			CreatedAt:  *subsequentMatch.ConfirmedAt,
+			PlayerID:    subsequentMatch.Player2ID,
+			MatchID:     &subsequentMatch.ID,
+			Kind:        models.EloHistoryKindMatch,
+			EloBefore:   player2.EloRating,
+			EloAfter:    player2.EloRating + player2Change,
+			EloChange:   player2Change,
+			OpponentID:  &subsequentMatch.Player1ID,
+			FloorExempt: player2Exempt,
+			CreatedAt:   *subsequentMatch.ConfirmedAt,
 		}
 
 		if err := tx.Create(&eloHistory1).Error; err != nil {
@@ -465,28 +648,116 @@ func (s *MatchService) ConfirmMatch(matchID uint) (*models.Match, error) {
 	return s.UpdateMatchStatus(matchID, confirmRequest)
 }
 
+// UpdateMatchStatusBy applies a status update made by a user. In two-player confirmation mode,
+// a confirmation made by one of the participants only counts for that participant.
+func (s *MatchService) UpdateMatchStatusBy(matchID, userID uint, req models.UpdateMatchStatusRequest) (*models.Match, error) {
+	match, err := s.matches.FindByID(matchID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errors.New("match not found")
+		}
+		return nil, err
+	}
+
+	isParticipant := userID == match.Player1ID || userID == match.Player2ID
+	if !match.RequiresBothConfirmations || !isParticipant || req.Status == nil || *req.Status != "confirmed" {
+		return s.UpdateMatchStatus(matchID, req)
+	}
+
+	if req.WinnerID != nil || req.PhotoURL != nil {
+		if _, err := s.UpdateMatchStatus(matchID, models.UpdateMatchStatusRequest{WinnerID: req.WinnerID, PhotoURL: req.PhotoURL}); err != nil {
+			return nil, err
+		}
+	}
+	return s.ConfirmByPlayer(matchID, userID)
+}
+
+// ConfirmByPlayer records the confirmation of one participant. In two-player confirmation mode
+// the match is confirmed, and ELO applied, once both participants have confirmed.
+func (s *MatchService) ConfirmByPlayer(matchID, playerID uint) (*models.Match, error) {
+	match, err := s.matches.FindByID(matchID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errors.New("match not found")
+		}
+		return nil, err
+	}
+
+	if match.Status != "pending" {
+		return nil, errors.New("match is not pending")
+	}
+	if !match.RequiresBothConfirmations {
+		return s.ConfirmMatch(matchID)
+	}
+
+	var column string
+	switch playerID {
+	case match.Player1ID:
+		column = "player1_confirmed_at"
+	case match.Player2ID:
+		column = "player2_confirmed_at"
+	default:
+		return nil, errors.New("player is not in the match")
+	}
+
+	if err := s.matches.Update(matchID, map[string]interface{}{column: s.clock.Now()}); err != nil {
+		return nil, err
+	}
+
+	// Read the confirmations back, the other participant may have confirmed concurrently
+	match, err = s.matches.FindByIDWithPlayers(matchID)
+	if err != nil {
+		return nil, err
+	}
+	if match.Player1ConfirmedAt != nil && match.Player2ConfirmedAt != nil {
+		confirmed, err := s.ConfirmMatch(matchID)
+		if err != nil && err.Error() == "match is not pending" {
+			// Both confirmations saw the other one, the first to lock the match applied the ELO
+			return s.matches.FindByIDWithPlayers(matchID)
+		}
+		return confirmed, err
+	}
+	return match, nil
+}
+
 func (s *MatchService) CancelMatch(matchID uint) (*models.Match, error) {
 	// Get the match
-	var match models.Match
-	if err := s.db.First(&match, matchID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	if _, err := s.matches.FindByID(matchID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
 			return nil, errors.New("match not found")
 		}
 		return nil, err
 	}
 
 	// Update status to cancelled
-	match.Status = "cancelled"
-	if err := s.db.Save(&match).Error; err != nil {
+	if err := s.matches.Update(matchID, map[string]interface{}{"status": "cancelled"}); err != nil {
 		return nil, err
 	}
 
 	// Load the updated match with relationships
-	if err := s.db.Preload("Player1").Preload("Player2").Preload("Winner").First(&match, match.ID).Error; err != nil {
+	return s.matches.FindByIDWithPlayers(matchID)
+}
+
+// SetFloorExemption flags a solo match so that the ELO floor does not hold its players, or clears the flag.
+// The ELO of a confirmed match is already applied, so only pending matches can change.
+// Team matches have no exemption.
+func (s *MatchService) SetFloorExemption(matchID uint, exempt bool) (*models.Match, error) {
+	match, err := s.matches.FindByID(matchID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, errors.New("match not found")
+		}
 		return nil, err
 	}
+	if match.Status != "pending" {
+		return nil, errors.New("match is not pending")
+	}
 
-	return &match, nil
+	if err := s.matches.Update(matchID, map[string]interface{}{"floor_exempt": exempt}); err != nil {
+		return nil, err
+	}
+
+	return s.matches.FindByIDWithPlayers(matchID)
 }
 
 func (s *MatchService) DeleteMatch(matchID uint) (*models.Match, error) {
@@ -510,6 +781,14 @@ func (s *MatchService) DeleteMatch(matchID uint) (*models.Match, error) {
 
 	// If match was confirmed, reverse the stats and ELO changes
 	if match.Status == "confirmed" {
+		// The history of this match and of the ones replayed after it may have been archived
+		if match.ConfirmedAt != nil {
+			if err := restoreArchivedEloHistory(tx, *match.ConfirmedAt); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
 		// Get ELO history entries for this match to reverse changes
 		var eloHistories []models.EloHistory
 		if err := tx.Where("match_id = ?", match.ID).Find(&eloHistories).Error; err != nil {
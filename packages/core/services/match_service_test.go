@@ -0,0 +1,257 @@
+package services
+
+import (
+	"core/clock"
+	"core/models"
+	"core/repositories"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMatchRepo counts the ranked matches of each player from a map and keeps a single match in memory,
+// the other methods are not used
+type fakeMatchRepo struct {
+	repositories.MatchRepo
+	ranked map[uint]int64
+	since  time.Time
+	match  *models.Match
+}
+
+func (r *fakeMatchRepo) CountRankedSince(playerID uint, since time.Time) (int64, error) {
+	r.since = since
+	return r.ranked[playerID], nil
+}
+
+func (r *fakeMatchRepo) FindByID(id uint) (*models.Match, error) {
+	if r.match == nil || r.match.ID != id {
+		return nil, repositories.ErrNotFound
+	}
+	match := *r.match
+	return &match, nil
+}
+
+func (r *fakeMatchRepo) FindByIDWithPlayers(id uint) (*models.Match, error) {
+	return r.FindByID(id)
+}
+
+func (r *fakeMatchRepo) Update(id uint, fields map[string]interface{}) error {
+	for column, value := range fields {
+		confirmedAt := value.(time.Time)
+		switch column {
+		case "player1_confirmed_at":
+			r.match.Player1ConfirmedAt = &confirmedAt
+		case "player2_confirmed_at":
+			r.match.Player2ConfirmedAt = &confirmedAt
+		}
+	}
+	return nil
+}
+
+func TestCheckDailyQuota(t *testing.T) {
+	t.Setenv("MATCH_DAILY_LIMIT", "2")
+
+	tests := []struct {
+		name    string
+		ranked  map[uint]int64
+		wantErr bool
+	}{
+		{"no match today", map[uint]int64{}, false},
+		{"under the limit", map[uint]int64{1: 1, 2: 1}, false},
+		{"player1 at the limit", map[uint]int64{1: 2}, true},
+		{"player2 at the limit", map[uint]int64{2: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := &fakeMatchRepo{ranked: tt.ranked}
+			matchService := NewMatchServiceWithRepos(nil, nil, matches)
+			matchService.SetClock(clock.NewFake(time.Date(2025, 10, 1, 18, 30, 0, 0, time.UTC)))
+
+			err := matchService.CheckDailyQuota(1, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if want := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC); !matches.since.Equal(want) {
+				t.Errorf("counted since %v, want the start of the day %v", matches.since, want)
+			}
+		})
+	}
+}
+
+func TestDailyMatchLimitFromEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"not set, no quota", "", 0},
+		{"limit", "20", 20},
+		{"explicitly disabled", "0", 0},
+		{"not a number", "twenty", 0},
+		{"negative", "-1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MATCH_DAILY_LIMIT", tt.value)
+			if got := dailyMatchLimitFromEnv(); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDailyQuotaDisabledByDefault(t *testing.T) {
+	t.Setenv("MATCH_DAILY_LIMIT", "")
+
+	matches := &fakeMatchRepo{ranked: map[uint]int64{1: 100, 2: 100}}
+	matchService := NewMatchServiceWithRepos(nil, nil, matches)
+
+	if err := matchService.CheckDailyQuota(1, 2); err != nil {
+		t.Fatalf("got error %v, want no quota", err)
+	}
+	if !matches.since.IsZero() {
+		t.Error("the matches of the day were counted without a quota")
+	}
+}
+
+func TestConfirmByPlayer(t *testing.T) {
+	now := time.Date(2025, 10, 1, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		status         string
+		playerID       uint
+		wantErr        string
+		wantConfirmed1 bool
+		wantConfirmed2 bool
+	}{
+		{"player1 confirms", "pending", 1, "", true, false},
+		{"player2 confirms", "pending", 2, "", false, true},
+		{"not a participant", "pending", 3, "player is not in the match", false, false},
+		{"already confirmed", "confirmed", 1, "match is not pending", false, false},
+		{"unknown match", "", 1, "match not found", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := &fakeMatchRepo{}
+			if tt.status != "" {
+				matches.match = &models.Match{ID: 7, Player1ID: 1, Player2ID: 2, WinnerID: 1, Status: tt.status, RequiresBothConfirmations: true}
+			}
+			matchService := NewMatchServiceWithRepos(nil, nil, matches)
+			matchService.SetClock(clock.NewFake(now))
+
+			match, err := matchService.ConfirmByPlayer(7, tt.playerID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if match.Status != "pending" {
+				t.Errorf("got status %q, the match waits for the other confirmation", match.Status)
+			}
+			if (match.Player1ConfirmedAt != nil) != tt.wantConfirmed1 || (match.Player2ConfirmedAt != nil) != tt.wantConfirmed2 {
+				t.Errorf("got confirmations (%v, %v), want (%v, %v)", match.Player1ConfirmedAt, match.Player2ConfirmedAt, tt.wantConfirmed1, tt.wantConfirmed2)
+			}
+		})
+	}
+}
+
+// TestConcurrentConfirmationsApplyEloOnce confirms a two-player match from both players at once,
+// on the fixtures database: only one of them may apply the ELO
+func TestConcurrentConfirmationsApplyEloOnce(t *testing.T) {
+	db := fixturesDB(t)
+	matchService := NewMatchService(db)
+
+	var playerIDs []uint
+	if err := db.Model(&models.Player{}).Scopes(models.NotArchived()).Order("id").Limit(2).Pluck("id", &playerIDs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(playerIDs) < 2 {
+		t.Skip("at least 2 players are required, run `make fixtures` first")
+	}
+
+	for i := 0; i < 10; i++ {
+		match, err := matchService.CreateMatch(models.CreateMatchRequest{
+			Player1ID: playerIDs[0],
+			Player2ID: playerIDs[1],
+			WinnerID:  playerIDs[0],
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Model(match).Update("requires_both_confirmations", true).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, len(playerIDs))
+		for j, playerID := range playerIDs {
+			wg.Add(1)
+			go func(j int, playerID uint) {
+				defer wg.Done()
+				_, errs[j] = matchService.ConfirmByPlayer(match.ID, playerID)
+			}(j, playerID)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var entries int64
+		if err := db.Model(&models.EloHistory{}).Where("match_id = ?", match.ID).Count(&entries).Error; err != nil {
+			t.Fatal(err)
+		}
+		if entries != 2 {
+			t.Fatalf("match %d has %d ELO history entries, want 2", match.ID, entries)
+		}
+	}
+}
+
+func BenchmarkGetMatches(b *testing.B) {
+	matchService := NewMatchService(fixturesDB(b))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := matchService.GetMatches(MatchFilters{Page: 1, PerPage: 20}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCreateAndConfirmMatch creates and confirms real matches between the first players
+func BenchmarkCreateAndConfirmMatch(b *testing.B) {
+	db := fixturesDB(b)
+	matchService := NewMatchService(db)
+
+	var playerIDs []uint
+	if err := db.Model(&models.Player{}).Scopes(models.NotArchived()).Order("id").Limit(10).Pluck("id", &playerIDs).Error; err != nil {
+		b.Fatal(err)
+	}
+	if len(playerIDs) < 2 {
+		b.Skip("at least 2 players are required, run `make fixtures` first")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		player1, player2 := playerIDs[i%len(playerIDs)], playerIDs[(i+1)%len(playerIDs)]
+		match, err := matchService.CreateMatch(models.CreateMatchRequest{
+			Player1ID: player1,
+			Player2ID: player2,
+			WinnerID:  player1,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := matchService.ConfirmMatch(match.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MonthlyAwardService struct {
+	db                  *gorm.DB
+	notificationService *NotificationService
+	announcer           *Announcer
+}
+
+func NewMonthlyAwardService(db *gorm.DB, notificationService *NotificationService, announcer *Announcer) *MonthlyAwardService {
+	return &MonthlyAwardService{
+		db:                  db,
+		notificationService: notificationService,
+		announcer:           announcer,
+	}
+}
+
+// monthlyAwardWinner is the best player of the month for an award
+type monthlyAwardWinner struct {
+	PlayerID uint
+	Name     string // Display name, username if none
+	Value    float64
+}
+
+// GetAwards lists the monthly awards, most recent month first, optionally for a single month
+func (s *MonthlyAwardService) GetAwards(month *string) ([]models.MonthlyAward, error) {
+	query := s.db.Preload("Player").Preload("Trophy")
+	if month != nil {
+		query = query.Where("month = ?", *month)
+	}
+
+	var awards []models.MonthlyAward
+	if err := query.Order("month DESC, id ASC").Find(&awards).Error; err != nil {
+		return nil, err
+	}
+	return awards, nil
+}
+
+// AwardPreviousMonth gives the awards of the month before now that were not given yet. Running it
+// again is harmless, so the job can run daily and catch up after a downtime on the first of the month.
+func (s *MonthlyAwardService) AwardPreviousMonth(now time.Time) ([]models.MonthlyAward, error) {
+	return s.AwardMonth(time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location()))
+}
+
+// AwardMonth computes the awards of the month containing month that were not given yet, writes them
+// with their trophy, then tells the winners and announces them. Awards without a candidate (no match,
+// no upset...) are skipped.
+func (s *MonthlyAwardService) AwardMonth(month time.Time) ([]models.MonthlyAward, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	end := start.AddDate(0, 1, 0)
+	if end.After(time.Now()) {
+		return nil, errors.New("month not over")
+	}
+	monthKey := start.Format(models.MonthlyAwardMonthLayout)
+
+	var given []string
+	if err := s.db.Model(&models.MonthlyAward{}).Where("month = ?", monthKey).Pluck("kind", &given).Error; err != nil {
+		return nil, err
+	}
+
+	var awarded []models.MonthlyAward
+	for _, kind := range models.MonthlyAwardKinds {
+		if slices.Contains(given, kind) {
+			continue
+		}
+
+		winner, err := s.findWinner(kind, start, end)
+		if err != nil {
+			return awarded, err
+		}
+		if winner == nil {
+			continue
+		}
+
+		name := models.MonthlyAwardName(kind, start)
+		award := models.MonthlyAward{Month: monthKey, Kind: kind, PlayerID: winner.PlayerID, Value: winner.Value}
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			trophy := models.Trophy{
+				Name:           name,
+				Season:         models.SeasonOf(start),
+				WinnerPlayerID: &winner.PlayerID,
+				AwardedAt:      time.Now(),
+			}
+			if err := tx.Create(&trophy).Error; err != nil {
+				return err
+			}
+			award.TrophyID = &trophy.ID
+			return tx.Create(&award).Error
+		})
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			// Given meanwhile by another instance
+			continue
+		}
+		if err != nil {
+			return awarded, err
+		}
+
+		s.notificationService.NotifyMonthlyAward(&award, name)
+		if err := s.announcer.Announce(monthlyAwardAnnouncement(kind, name, winner)); err != nil {
+			log.Printf("Error announcing the %s award of %s: %v", kind, monthKey, err)
+		}
+		awarded = append(awarded, award)
+	}
+
+	return awarded, nil
+}
+
+// findWinner returns the best active player of the month for an award, ties going to the lowest ID,
+// or nil when nobody qualifies
+func (s *MonthlyAwardService) findWinner(kind string, start, end time.Time) (*monthlyAwardWinner, error) {
+	var query string
+	switch kind {
+	case models.MonthlyAwardMostImproved:
+		query = `
+			SELECT players.id AS player_id, COALESCE(players.display_name, players.username) AS name, SUM(elo_history.elo_change) AS value
+			FROM elo_history
+			JOIN players ON players.id = elo_history.player_id AND players.deleted_at IS NULL
+			WHERE elo_history.kind = 'match' AND elo_history.deleted_at IS NULL
+				AND elo_history.created_at >= @start AND elo_history.created_at < @end
+			GROUP BY players.id
+			HAVING SUM(elo_history.elo_change) > 0
+			ORDER BY value DESC, players.id ASC
+			LIMIT 1`
+	case models.MonthlyAwardMostActive:
+		query = `
+			SELECT players.id AS player_id, COALESCE(players.display_name, players.username) AS name, COUNT(*) AS value
+			FROM (
+				SELECT unnest(ARRAY[player1_id, player2_id]) AS player_id
+				FROM matches
+				WHERE status = 'confirmed' AND deleted_at IS NULL
+					AND confirmed_at >= @start AND confirmed_at < @end
+				UNION ALL
+				SELECT unnest(ARRAY[teams.player1_id, teams.player2_id])
+				FROM team_matches
+				JOIN teams ON teams.id IN (team_matches.team1_id, team_matches.team2_id)
+				WHERE team_matches.status = 'confirmed' AND team_matches.deleted_at IS NULL
+					AND team_matches.confirmed_at >= @start AND team_matches.confirmed_at < @end
+			) played
+			JOIN players ON players.id = played.player_id AND players.deleted_at IS NULL
+			GROUP BY players.id
+			ORDER BY value DESC, players.id ASC
+			LIMIT 1`
+	case models.MonthlyAwardGiantKiller:
+		query = `
+			SELECT players.id AS player_id, COALESCE(players.display_name, players.username) AS name, loser.elo_before - winner.elo_before AS value
+			FROM elo_history winner
+			JOIN elo_history loser ON loser.match_id = winner.match_id AND loser.player_id = winner.opponent_id
+				AND loser.deleted_at IS NULL
+			JOIN players ON players.id = winner.player_id AND players.deleted_at IS NULL
+			WHERE winner.kind = 'match' AND winner.deleted_at IS NULL AND winner.elo_change > 0
+				AND loser.elo_before > winner.elo_before
+				AND winner.created_at >= @start AND winner.created_at < @end
+			ORDER BY value DESC, players.id ASC
+			LIMIT 1`
+	default:
+		return nil, fmt.Errorf("unknown monthly award %s", kind)
+	}
+
+	var winners []monthlyAwardWinner
+	if err := s.db.Raw(query, map[string]interface{}{"start": start, "end": end}).Scan(&winners).Error; err != nil {
+		return nil, err
+	}
+	if len(winners) == 0 {
+		return nil, nil
+	}
+	return &winners[0], nil
+}
+
+// monthlyAwardAnnouncement is the message posted for an award
+func monthlyAwardAnnouncement(kind, name string, winner *monthlyAwardWinner) string {
+	switch kind {
+	case models.MonthlyAwardMostImproved:
+		return fmt.Sprintf("🏆 %s : %s (+%.0f ELO)", name, winner.Name, winner.Value)
+	case models.MonthlyAwardMostActive:
+		return fmt.Sprintf("🏆 %s : %s (%.0f matchs)", name, winner.Name, winner.Value)
+	default:
+		return fmt.Sprintf("🏆 %s : %s (victoire contre un adversaire classé %.0f ELO plus haut)", name, winner.Name, winner.Value)
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"core/events"
+	"core/models"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	authModels "auth/models"
+	authServices "auth/services"
+
+	"gorm.io/gorm"
+)
+
+// maxNotificationDelay caps how long batching can hold a notification back (quiet hours excepted)
+const maxNotificationDelay = time.Hour
+
+type NotificationService struct {
+	db           *gorm.DB
+	emailService authServices.EmailService
+	events       *events.Bus
+}
+
+func NewNotificationService(db *gorm.DB, emailService authServices.EmailService, bus *events.Bus) *NotificationService {
+	return &NotificationService{
+		db:           db,
+		emailService: emailService,
+		events:       bus,
+	}
+}
+
+// Notify queues a notification, it will be delivered by the dispatcher
+func (s *NotificationService) Notify(notification models.Notification) {
+	notification.Status = models.NotificationStatusPending
+	if err := s.db.Create(&notification).Error; err != nil {
+		log.Printf("Error queuing %s notification for user %d: %v", notification.Type, notification.UserID, err)
+	}
+}
+
+// NotifyMatchCreated asks every player of a new match, except its creator, to confirm it
+func (s *NotificationService) NotifyMatchCreated(match *models.Match, creatorID uint) {
+	for _, playerID := range []uint{match.Player1ID, match.Player2ID} {
+		if playerID == creatorID {
+			continue
+		}
+		s.Notify(models.Notification{
+			UserID:  playerID,
+			Type:    models.NotificationMatchPending,
+			Message: fmt.Sprintf("Le match #%d est en attente de votre confirmation", match.ID),
+			MatchID: &match.ID,
+		})
+	}
+}
+
+// NotifyTeamMatchCreated asks every player of a new team match, except its creator, to confirm it
+func (s *NotificationService) NotifyTeamMatchCreated(match *models.TeamMatch, creatorID uint) {
+	playerIDs, err := s.teamMatchPlayerIDs(match)
+	if err != nil {
+		log.Printf("Error loading players of team match %d: %v", match.ID, err)
+		return
+	}
+
+	for _, playerID := range playerIDs {
+		if playerID == creatorID {
+			continue
+		}
+		s.Notify(models.Notification{
+			UserID:      playerID,
+			Type:        models.NotificationMatchPending,
+			Message:     fmt.Sprintf("Le match en équipe #%d est en attente de votre confirmation", match.ID),
+			TeamMatchID: &match.ID,
+		})
+	}
+}
+
+// NotifyMatchAutoValidated tells both players that a match was confirmed by the auto-validation job
+func (s *NotificationService) NotifyMatchAutoValidated(match *models.Match) {
+	for _, playerID := range []uint{match.Player1ID, match.Player2ID} {
+		s.Notify(models.Notification{
+			UserID:  playerID,
+			Type:    models.NotificationMatchAutoValidated,
+			Message: fmt.Sprintf("Le match #%d a été validé automatiquement", match.ID),
+			MatchID: &match.ID,
+		})
+	}
+}
+
+// NotifyTeamMatchAutoValidated tells every player that a team match was confirmed by the auto-validation job
+func (s *NotificationService) NotifyTeamMatchAutoValidated(match *models.TeamMatch) {
+	playerIDs, err := s.teamMatchPlayerIDs(match)
+	if err != nil {
+		log.Printf("Error loading players of team match %d: %v", match.ID, err)
+		return
+	}
+
+	for _, playerID := range playerIDs {
+		s.Notify(models.Notification{
+			UserID:      playerID,
+			Type:        models.NotificationMatchAutoValidated,
+			Message:     fmt.Sprintf("Le match en équipe #%d a été validé automatiquement", match.ID),
+			TeamMatchID: &match.ID,
+		})
+	}
+}
+
+// NotifyTournamentEditionCreated tells the subscribers of a recurring tournament that registrations are open
+func (s *NotificationService) NotifyTournamentEditionCreated(tournament *models.Tournament, subscriberIDs []uint) {
+	for _, playerID := range subscriberIDs {
+		s.Notify(models.Notification{
+			UserID:       playerID,
+			Type:         models.NotificationTournamentEdition,
+			Message:      fmt.Sprintf("Les inscriptions au tournoi %s sont ouvertes", tournament.Name),
+			TournamentID: &tournament.ID,
+		})
+	}
+}
+
+// NotifyWaitlistPromoted tells both players of a team that it moved from the waitlist to the tournament
+func (s *NotificationService) NotifyWaitlistPromoted(tournamentTeam *models.TournamentTeam, tournamentName string) {
+	for _, playerID := range []uint{tournamentTeam.Team.Player1ID, tournamentTeam.Team.Player2ID} {
+		s.Notify(models.Notification{
+			UserID:       playerID,
+			Type:         models.NotificationWaitlistPromoted,
+			Message:      fmt.Sprintf("Une place s'est libérée : votre équipe %s est inscrite au tournoi %s", tournamentTeam.Team.Name, tournamentName),
+			TournamentID: &tournamentTeam.TournamentID,
+		})
+	}
+}
+
+// NotifyMonthlyAward congratulates the winner of a monthly award
+func (s *NotificationService) NotifyMonthlyAward(award *models.MonthlyAward, name string) {
+	s.Notify(models.Notification{
+		UserID:  award.PlayerID,
+		Type:    models.NotificationMonthlyAward,
+		Message: fmt.Sprintf("Bravo, vous remportez le trophée %s !", name),
+	})
+}
+
+func (s *NotificationService) teamMatchPlayerIDs(match *models.TeamMatch) ([]uint, error) {
+	var teams []models.Team
+	if err := s.db.Where("id IN ?", []uint{match.Team1ID, match.Team2ID}).Find(&teams).Error; err != nil {
+		return nil, err
+	}
+
+	var playerIDs []uint
+	for _, team := range teams {
+		playerIDs = append(playerIDs, team.Player1ID, team.Player2ID)
+	}
+	return playerIDs, nil
+}
+
+// GetPreferences returns the player's notification preferences, or the defaults if none were saved
+func (s *NotificationService) GetPreferences(userID uint) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := s.db.First(&preference, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preference = models.DefaultNotificationPreference(userID)
+		return &preference, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// UpdatePreferences applies the provided fields and saves the player's preferences
+func (s *NotificationService) UpdatePreferences(userID uint, req models.UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error) {
+	preference, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return nil, errors.New("invalid timezone")
+		}
+		preference.Timezone = *req.Timezone
+	}
+	if req.QuietHoursEnabled != nil {
+		preference.QuietHoursEnabled = *req.QuietHoursEnabled
+	}
+	if req.QuietHoursStart != nil {
+		preference.QuietHoursStart = *req.QuietHoursStart
+	}
+	if req.QuietHoursEnd != nil {
+		preference.QuietHoursEnd = *req.QuietHoursEnd
+	}
+	if req.BatchWindowMinutes != nil {
+		preference.BatchWindowMinutes = *req.BatchWindowMinutes
+	}
+
+	// Save upserts on the primary key, which keeps defaults for players without a row yet
+	if err := s.db.Save(preference).Error; err != nil {
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+// DispatchPending delivers pending notifications, one digest email per player.
+// A player's notifications are held while they are in quiet hours, and while new ones keep
+// arriving within their batch window (up to maxNotificationDelay), so that several pending
+// matches end up in a single email. Returns the number of notifications delivered.
+func (s *NotificationService) DispatchPending(now time.Time) (int, error) {
+	var pending []models.Notification
+	if err := s.db.Where("status = ?", models.NotificationStatusPending).
+		Order("created_at ASC").
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+
+	byUser := make(map[uint][]models.Notification)
+	var userIDs []uint
+	for _, notification := range pending {
+		if _, ok := byUser[notification.UserID]; !ok {
+			userIDs = append(userIDs, notification.UserID)
+		}
+		byUser[notification.UserID] = append(byUser[notification.UserID], notification)
+	}
+
+	delivered := 0
+	for _, userID := range userIDs {
+		notifications := byUser[userID]
+
+		preference, err := s.GetPreferences(userID)
+		if err != nil {
+			log.Printf("Error loading notification preferences for user %d: %v", userID, err)
+			continue
+		}
+
+		if preference.InQuietHours(now) {
+			continue
+		}
+
+		oldest := notifications[0].CreatedAt
+		newest := notifications[len(notifications)-1].CreatedAt
+		window := time.Duration(preference.BatchWindowMinutes) * time.Minute
+		if now.Sub(newest) < window && now.Sub(oldest) < maxNotificationDelay {
+			continue
+		}
+
+		if err := s.deliver(userID, notifications, now); err != nil {
+			log.Printf("Error delivering notifications to user %d: %v", userID, err)
+			s.events.Publish(events.TypeDeliveryFailed, map[string]interface{}{
+				"channel":       "notification_digest",
+				"user_id":       userID,
+				"notifications": len(notifications),
+				"error":         err.Error(),
+			})
+			continue
+		}
+		delivered += len(notifications)
+	}
+
+	return delivered, nil
+}
+
+func (s *NotificationService) deliver(userID uint, notifications []models.Notification, now time.Time) error {
+	var user authModels.User
+	if err := s.db.Select("id", "email").First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	if err := s.emailService.SendNotificationDigest(user.Email, digestLines(notifications)); err != nil &&
+		!errors.Is(err, authServices.ErrEmailSuppressed) {
+		return err
+	}
+
+	ids := make([]uint, 0, len(notifications))
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+
+	return s.db.Model(&models.Notification{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": models.NotificationStatusSent, "sent_at": now}).Error
+}
+
+// digestLines combines notifications into email lines; pending match requests are merged into one line
+func digestLines(notifications []models.Notification) []string {
+	var lines []string
+	var pendingMatches []models.Notification
+
+	for _, notification := range notifications {
+		if notification.Type == models.NotificationMatchPending {
+			pendingMatches = append(pendingMatches, notification)
+			continue
+		}
+		lines = append(lines, notification.Message)
+	}
+
+	switch len(pendingMatches) {
+	case 0:
+	case 1:
+		lines = append([]string{pendingMatches[0].Message}, lines...)
+	default:
+		lines = append([]string{fmt.Sprintf("%d matchs sont en attente de votre confirmation", len(pendingMatches))}, lines...)
+	}
+
+	return lines
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"core/models"
+	"log"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+// photoPolicyFromEnv reads MATCH_PHOTO_POLICY, the scoreboard photo policy of the matches played outside tournaments
+func photoPolicyFromEnv() string {
+	switch value := os.Getenv("MATCH_PHOTO_POLICY"); value {
+	case "", models.PhotoPolicyNone:
+		return models.PhotoPolicyNone
+	case models.PhotoPolicyDisputed, models.PhotoPolicyAlways:
+		return value
+	default:
+		log.Printf("Invalid MATCH_PHOTO_POLICY %q, using %s", value, models.PhotoPolicyNone)
+		return models.PhotoPolicyNone
+	}
+}
+
+// photoRequired reports whether confirming a match needs a scoreboard photo, under the policy of its
+// tournament, or casualPolicy outside tournaments. Matches with a referee are exempt: the referee
+// attests the result.
+func photoRequired(db *gorm.DB, casualPolicy string, tournamentID, refereeID *uint, disputed bool) bool {
+	if refereeID != nil {
+		return false
+	}
+
+	policy := casualPolicy
+	if tournamentID != nil {
+		var tournamentPolicy string
+		if err := db.Model(&models.Tournament{}).Where("id = ?", *tournamentID).Pluck("photo_policy", &tournamentPolicy).Error; err == nil && tournamentPolicy != "" {
+			policy = tournamentPolicy
+		}
+	}
+
+	switch policy {
+	case models.PhotoPolicyAlways:
+		return true
+	case models.PhotoPolicyDisputed:
+		return disputed
+	}
+	return false
+}
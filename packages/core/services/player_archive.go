@@ -0,0 +1,114 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"time"
+)
+
+// lastActivitySQL computes the last activity of each active player: latest solo or team match they
+// took part in, whatever its status, or last login, and their creation date when they have neither
+const lastActivitySQL = `
+	WITH activity AS (
+		SELECT player1_id AS player_id, created_at FROM matches WHERE deleted_at IS NULL
+		UNION ALL
+		SELECT player2_id, created_at FROM matches WHERE deleted_at IS NULL
+		UNION ALL
+		SELECT teams.player1_id, team_matches.created_at FROM team_matches
+		JOIN teams ON teams.id IN (team_matches.team1_id, team_matches.team2_id)
+		WHERE team_matches.deleted_at IS NULL
+		UNION ALL
+		SELECT teams.player2_id, team_matches.created_at FROM team_matches
+		JOIN teams ON teams.id IN (team_matches.team1_id, team_matches.team2_id)
+		WHERE team_matches.deleted_at IS NULL
+	), last_matches AS (
+		SELECT player_id, MAX(created_at) AS last_match_at FROM activity GROUP BY player_id
+	)
+	SELECT players.id AS player_id, players.username,
+		GREATEST(last_matches.last_match_at, users.last_login) AS last_activity_at
+	FROM players
+	LEFT JOIN last_matches ON last_matches.player_id = players.id
+	LEFT JOIN users ON users.id = players.id
+	WHERE players.deleted_at IS NULL AND players.archived_at IS NULL
+		AND COALESCE(GREATEST(last_matches.last_match_at, users.last_login), players.created_at) < ?
+	ORDER BY last_activity_at ASC NULLS FIRST, players.id ASC`
+
+// editablePlayer loads a player whose profile can be changed, archived profiles are read-only
+func (s *PlayerService) editablePlayer(id uint) (*models.Player, error) {
+	player, err := s.GetPlayerByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if player.ArchivedAt != nil {
+		return nil, errors.New("player is archived")
+	}
+	return player, nil
+}
+
+// ArchivePlayer archives a player who left the school: they leave the leaderboards and cannot play,
+// their profile, history and trophies stay visible
+func (s *PlayerService) ArchivePlayer(id uint, now time.Time) (*models.Player, error) {
+	player, err := s.GetPlayerByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if player.ArchivedAt != nil {
+		return nil, errors.New("player already archived")
+	}
+
+	// An archived player has no rank, the others move up
+	if err := s.players.Update(id, map[string]interface{}{"archived_at": now, "rank": 0}); err != nil {
+		return nil, err
+	}
+	if err := s.RecalculateAllRanks(); err != nil {
+		return nil, err
+	}
+	return s.GetPlayerByID(id)
+}
+
+// UnarchivePlayer brings an archived player back with the rating they left with
+func (s *PlayerService) UnarchivePlayer(id uint) (*models.Player, error) {
+	player, err := s.GetPlayerByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if player.ArchivedAt == nil {
+		return nil, errors.New("player is not archived")
+	}
+
+	if err := s.players.Update(id, map[string]interface{}{"archived_at": nil}); err != nil {
+		return nil, err
+	}
+	if err := s.RecalculateAllRanks(); err != nil {
+		return nil, err
+	}
+	return s.GetPlayerByID(id)
+}
+
+// ArchiveInactivePlayers archives the players without any match nor login since the given date,
+// or only lists them on a dry run
+func (s *PlayerService) ArchiveInactivePlayers(inactiveSince time.Time, dryRun bool, now time.Time) (*models.ArchiveInactivePlayersResponse, error) {
+	players := []models.ArchivedPlayer{}
+	if err := s.db.Raw(lastActivitySQL, inactiveSince).Scan(&players).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.ArchiveInactivePlayersResponse{DryRun: dryRun, Count: len(players), Players: players}
+	if dryRun || len(players) == 0 {
+		return response, nil
+	}
+
+	ids := make([]uint, len(players))
+	for i, player := range players {
+		ids[i] = player.PlayerID
+	}
+	if err := s.db.Model(&models.Player{}).
+		Where("id IN ? AND archived_at IS NULL", ids).
+		Updates(map[string]interface{}{"archived_at": now, "rank": 0}).Error; err != nil {
+		return nil, err
+	}
+	if err := s.RecalculateAllRanks(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
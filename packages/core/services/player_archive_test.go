@@ -0,0 +1,83 @@
+package services
+
+import (
+	"cmp"
+	"core/models"
+	"core/repositories"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakePlayerRepo keeps players in memory, ranked by AllByElo like the database, the other methods are not used
+type fakePlayerRepo struct {
+	repositories.PlayerRepo
+	players map[uint]*models.Player
+}
+
+func (r *fakePlayerRepo) FindByID(id uint) (*models.Player, error) {
+	player, ok := r.players[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	copied := *player
+	return &copied, nil
+}
+
+func (r *fakePlayerRepo) AllByElo() ([]models.Player, error) {
+	var players []models.Player
+	for _, player := range r.players {
+		if player.ArchivedAt == nil {
+			players = append(players, *player)
+		}
+	}
+	slices.SortFunc(players, func(a, b models.Player) int {
+		return cmp.Or(cmp.Compare(b.EloRating, a.EloRating), cmp.Compare(a.ID, b.ID))
+	})
+	return players, nil
+}
+
+func (r *fakePlayerRepo) Update(id uint, fields map[string]interface{}) error {
+	player := r.players[id]
+	for column, value := range fields {
+		switch column {
+		case "rank":
+			player.Rank = value.(int)
+		case "archived_at":
+			if at, ok := value.(time.Time); ok {
+				player.ArchivedAt = &at
+			} else {
+				player.ArchivedAt = nil
+			}
+		}
+	}
+	return nil
+}
+
+func TestArchivePlayerClearsRank(t *testing.T) {
+	players := &fakePlayerRepo{players: map[uint]*models.Player{
+		1: {ID: 1, EloRating: 1400, Rank: 1},
+		2: {ID: 2, EloRating: 1300, Rank: 2},
+		3: {ID: 3, EloRating: 1200, Rank: 3},
+	}}
+	playerService := NewPlayerServiceWithRepos(nil, players, nil)
+
+	archived, err := playerService.ArchivePlayer(1, time.Date(2025, 10, 1, 18, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived.Rank != 0 {
+		t.Errorf("archived player has rank %d, want 0", archived.Rank)
+	}
+	if players.players[2].Rank != 1 || players.players[3].Rank != 2 {
+		t.Errorf("got ranks (%d, %d) for the others, want (1, 2)", players.players[2].Rank, players.players[3].Rank)
+	}
+
+	unarchived, err := playerService.UnarchivePlayer(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unarchived.Rank != 1 || players.players[2].Rank != 2 {
+		t.Errorf("got ranks (%d, %d) after the return, want (1, 2)", unarchived.Rank, players.players[2].Rank)
+	}
+}
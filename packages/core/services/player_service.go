@@ -1,34 +1,137 @@
 package services
 
 import (
+	"core/events"
 	"core/models"
+	"core/repositories"
 	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type PlayerService struct {
-	db *gorm.DB
+	db      *gorm.DB
+	players repositories.PlayerRepo
+	matches repositories.MatchRepo
+
+	displayNameChecks []DisplayNameCheck
+	events            *events.Bus // Display name changes, for review on the admin console
 }
 
 func NewPlayerService(db *gorm.DB) *PlayerService {
-	return &PlayerService{
-		db: db,
+	return NewPlayerServiceWithRepos(db, repositories.NewPlayerRepo(db), repositories.NewMatchRepo(db))
+}
+
+// NewPlayerServiceWithRepos builds the service on the given repositories (fakes, replica, cache)
+func NewPlayerServiceWithRepos(db *gorm.DB, players repositories.PlayerRepo, matches repositories.MatchRepo) *PlayerService {
+	s := &PlayerService{
+		db:      db,
+		players: players,
+		matches: matches,
 	}
+	s.displayNameChecks = []DisplayNameCheck{checkDisplayNameCharacters, newDisplayNameBlocklist(), s.checkDisplayNameImpersonation}
+	return s
 }
 
-func (s *PlayerService) GetPlayerByID(id uint) (*models.Player, error) {
-	var player models.Player
+// SetEvents publishes the display name changes on the admin console feed
+func (s *PlayerService) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
 
-	result := s.db.First(&player, id)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+func (s *PlayerService) GetPlayerByID(id uint) (*models.Player, error) {
+	player, err := s.players.FindByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
 			return nil, errors.New("player not found")
 		}
-		return nil, result.Error
+		return nil, err
 	}
 
-	return &player, nil
+	return player, nil
+}
+
+// SetAway sets the away window of a player; they keep their rating and get an away badge meanwhile
+func (s *PlayerService) SetAway(id uint, from, until time.Time) (*models.Player, error) {
+	if !until.After(from) {
+		return nil, errors.New("away_until must be after away_from")
+	}
+	if !until.After(time.Now()) {
+		return nil, errors.New("away window is already over")
+	}
+
+	if _, err := s.editablePlayer(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.players.Update(id, map[string]interface{}{
+		"away_from":  from,
+		"away_until": until,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetPlayerByID(id)
+}
+
+// ClearAway ends the away window of a player
+func (s *PlayerService) ClearAway(id uint) (*models.Player, error) {
+	if _, err := s.editablePlayer(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.players.Update(id, map[string]interface{}{
+		"away_from":  nil,
+		"away_until": nil,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetPlayerByID(id)
+}
+
+// SetCampus records the campus and department of a player, nil or empty clears them
+func (s *PlayerService) SetCampus(id uint, campus, department *string) (*models.Player, error) {
+	if _, err := s.editablePlayer(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.SetCampusWithTx(s.db, id, campus, department); err != nil {
+		return nil, err
+	}
+
+	return s.GetPlayerByID(id)
+}
+
+// SetCampusWithTx records the campus and department of a player within a transaction
+func (s *PlayerService) SetCampusWithTx(tx *gorm.DB, id uint, campus, department *string) error {
+	if department != nil {
+		trimmed := strings.TrimSpace(*department)
+		department = &trimmed
+		if trimmed == "" {
+			department = nil
+		}
+	}
+
+	return tx.Model(&models.Player{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"campus":     models.NormalizeCampus(campus),
+		"department": department,
+	}).Error
+}
+
+// GetCampuses lists the campuses with their number of players, largest first
+func (s *PlayerService) GetCampuses() ([]models.CampusCount, error) {
+	var campuses []models.CampusCount
+	if err := s.db.Model(&models.Player{}).
+		Select("campus, COUNT(*) AS players").
+		Where("campus IS NOT NULL").
+		Group("campus").
+		Order("players DESC, campus ASC").
+		Scan(&campuses).Error; err != nil {
+		return nil, err
+	}
+	return campuses, nil
 }
 
 func (s *PlayerService) CreatePlayer(userID uint, username string) (*models.Player, error) {
@@ -41,9 +144,8 @@ func (s *PlayerService) CreatePlayer(userID uint, username string) (*models.Play
 		Losses:       0,
 	}
 
-	result := s.db.Create(player)
-	if result.Error != nil {
-		return nil, result.Error
+	if err := s.players.Create(player); err != nil {
+		return nil, err
 	}
 
 	return player, nil
@@ -69,7 +171,9 @@ func (s *PlayerService) CreatePlayerWithTx(tx *gorm.DB, userID uint, username st
 func (s *PlayerService) GetEloHistoryByPlayerID(playerID uint) ([]models.EloHistory, error) {
 	var eloHistory []models.EloHistory
 
-	result := s.db.Where("player_id = ?", playerID).
+	// Read through the view so that archived entries are part of the history
+	result := s.db.Table(models.EloHistoryAllView).
+		Where("player_id = ?", playerID).
 		Order("id ASC").
 		Preload("Match").
 		Preload("Opponent").
@@ -98,49 +202,10 @@ func (s *PlayerService) GetTeamEloHistoryByPlayerID(playerID uint) ([]models.Tea
 	return eloHistory, nil
 }
 
-func (s *PlayerService) GetTopPlayersByElo(limit int, currentUserID *uint) ([]models.Player, error) {
-	var players []models.Player
-
-	result := s.db.Order("elo_rating DESC").
-		Limit(limit).
-		Find(&players)
-
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	// Si currentUserID est fourni et que l'utilisateur n'est pas dans le top, l'ajouter
-	if currentUserID != nil {
-		// Vérifier si l'utilisateur connecté est déjà dans la liste
-		userInTop := false
-		for _, player := range players {
-			if player.ID == *currentUserID {
-				userInTop = true
-				break
-			}
-		}
-
-		// Si l'utilisateur n'est pas dans le top, le récupérer et l'ajouter
-		if !userInTop {
-			var currentUser models.Player
-			if err := s.db.First(&currentUser, *currentUserID).Error; err == nil {
-				players = append(players, currentUser)
-			}
-		}
-	}
-
-	return players, nil
-}
-
-func (s *PlayerService) GetTopPlayersByTeamElo(limit int, currentUserID *uint) ([]models.Player, error) {
-	var players []models.Player
-
-	result := s.db.Order("team_elo_rating DESC").
-		Limit(limit).
-		Find(&players)
-
-	if result.Error != nil {
-		return nil, result.Error
+func (s *PlayerService) GetTopPlayersByTeamElo(limit int, currentUserID *uint, campus string) ([]models.Player, error) {
+	players, err := s.players.Top("team_elo_rating", limit, campus)
+	if err != nil {
+		return nil, err
 	}
 
 	// Si currentUserID est fourni et que l'utilisateur n'est pas dans le top, l'ajouter
@@ -156,9 +221,8 @@ func (s *PlayerService) GetTopPlayersByTeamElo(limit int, currentUserID *uint) (
 
 		// Si l'utilisateur n'est pas dans le top, le récupérer et l'ajouter
 		if !userInTop {
-			var currentUser models.Player
-			if err := s.db.First(&currentUser, *currentUserID).Error; err == nil {
-				players = append(players, currentUser)
+			if currentUser, err := s.players.FindByID(*currentUserID); err == nil {
+				players = append(players, *currentUser)
 			}
 		}
 	}
@@ -167,35 +231,12 @@ func (s *PlayerService) GetTopPlayersByTeamElo(limit int, currentUserID *uint) (
 }
 
 func (s *PlayerService) GetPlayerMatches(playerID uint, filter string, page int, pageSize int) (*models.PaginatedMatchResponse, error) {
-	var matches []models.Match
-	var total int64
-
-	baseQuery := s.db.Model(&models.Match{}).Where("player1_id = ? OR player2_id = ?", playerID, playerID)
-
-	switch filter {
-	case "wins":
-		baseQuery = baseQuery.Where("winner_id = ?", playerID)
-	case "losses":
-		baseQuery = baseQuery.Where("winner_id != ? AND (player1_id = ? OR player2_id = ?)", playerID, playerID, playerID)
-	}
-
-	// Count total records
-	if err := baseQuery.Count(&total).Error; err != nil {
-		return nil, err
-	}
-
 	// Calculate offset
 	offset := (page - 1) * pageSize
 
-	// Get paginated matches
-	query := baseQuery.Order("created_at DESC").
-		Preload("Player1").
-		Preload("Player2").
-		Preload("Winner").
-		Offset(offset).
-		Limit(pageSize)
-
-	if err := query.Find(&matches).Error; err != nil {
+	// Get paginated matches, filter is "wins", "losses" or empty
+	matches, total, err := s.matches.List(repositories.MatchQuery{PlayerID: &playerID, Outcome: filter}, offset, pageSize)
+	if err != nil {
 		return nil, err
 	}
 
@@ -211,15 +252,14 @@ func (s *PlayerService) GetPlayerMatches(playerID uint, filter string, page int,
 	}, nil
 }
 
-func (s *PlayerService) GetAllPlayers(orderBy string, direction string, page int, pageSize int) (*models.PaginatedPlayersResponse, error) {
-	var players []models.Player
-	var total int64
-
-	// Validate order by field
+// playerOrderClause validates the requested ordering, unknown values fall back to the newest players first
+func playerOrderClause(orderBy string, direction string) string {
 	allowedOrderBy := map[string]bool{
-		"created_at": true,
-		"elo_rating": true,
-		"username":   true,
+		"created_at":    true,
+		"elo_rating":    true,
+		"username":      true,
+		"win_rate":      true,
+		"team_win_rate": true,
 	}
 
 	if !allowedOrderBy[orderBy] {
@@ -231,22 +271,16 @@ func (s *PlayerService) GetAllPlayers(orderBy string, direction string, page int
 		direction = "DESC"
 	}
 
-	// Count total records
-	if err := s.db.Model(&models.Player{}).Count(&total).Error; err != nil {
-		return nil, err
-	}
+	return orderBy + " " + direction
+}
 
+func (s *PlayerService) GetAllPlayers(orderBy string, direction string, page int, pageSize int) (*models.PaginatedPlayersResponse, error) {
 	// Calculate offset
 	offset := (page - 1) * pageSize
 
-	// Build order clause
-	orderClause := orderBy + " " + direction
-
-	// Get paginated players
-	if err := s.db.Order(orderClause).
-		Offset(offset).
-		Limit(pageSize).
-		Find(&players).Error; err != nil {
+	// Get paginated players with the total count
+	players, total, err := s.players.List(playerOrderClause(orderBy, direction), offset, pageSize)
+	if err != nil {
 		return nil, err
 	}
 
@@ -262,12 +296,17 @@ func (s *PlayerService) GetAllPlayers(orderBy string, direction string, page int
 	}, nil
 }
 
+// EachPlayer passes every player to fn in batches, with the ordering of GetAllPlayers (CSV exports)
+func (s *PlayerService) EachPlayer(orderBy string, direction string, fn func([]models.Player) error) error {
+	return s.players.Each(playerOrderClause(orderBy, direction), exportBatchSize, fn)
+}
+
 // RecalculateAllRanks recalcule les rangs de tous les joueurs basés sur leur ELO
 // Gère les égalités : joueurs avec même ELO ont le même rang
 func (s *PlayerService) RecalculateAllRanks() error {
 	// Récupérer tous les joueurs triés par ELO décroissant
-	var players []models.Player
-	if err := s.db.Order("elo_rating DESC, id ASC").Find(&players).Error; err != nil {
+	players, err := s.players.AllByElo()
+	if err != nil {
 		return err
 	}
 
@@ -282,7 +321,7 @@ func (s *PlayerService) RecalculateAllRanks() error {
 		}
 
 		// Mettre à jour le rang du joueur
-		if err := s.db.Model(&player).Update("rank", currentRank).Error; err != nil {
+		if err := s.players.Update(player.ID, map[string]interface{}{"rank": currentRank}); err != nil {
 			return err
 		}
 
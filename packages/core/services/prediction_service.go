@@ -0,0 +1,123 @@
+package services
+
+import (
+	"core/models"
+	"core/utils"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// headToHeadPriorMatches is the weight of the rating engine in the adjusted odds, in matches: the
+// head-to-head record takes over as the two sides play more matches against each other
+const headToHeadPriorMatches = 10.0
+
+type PredictionService struct {
+	db *gorm.DB
+}
+
+func NewPredictionService(db *gorm.DB) *PredictionService {
+	return &PredictionService{
+		db: db,
+	}
+}
+
+// PredictPlayers predicts a solo match between two players
+func (s *PredictionService) PredictPlayers(player1ID, player2ID uint) (*models.MatchupPrediction, error) {
+	if player1ID == player2ID {
+		return nil, errors.New("players must be different")
+	}
+
+	var players []models.Player
+	if err := s.db.Where("id IN ?", []uint{player1ID, player2ID}).Find(&players).Error; err != nil {
+		return nil, err
+	}
+	if len(players) != 2 {
+		return nil, errors.New("player not found")
+	}
+	player1, player2 := players[0], players[1]
+	if player1.ID != player1ID {
+		player1, player2 = player2, player1
+	}
+
+	var record struct {
+		Matches     int
+		Player1Wins int
+	}
+	if err := s.db.Model(&models.Match{}).
+		Select("COUNT(*) AS matches, COUNT(*) FILTER (WHERE winner_id = ?) AS player1_wins", player1ID).
+		Where("status = ?", "confirmed").
+		Where("(player1_id = ? AND player2_id = ?) OR (player1_id = ? AND player2_id = ?)", player1ID, player2ID, player2ID, player1ID).
+		Scan(&record).Error; err != nil {
+		return nil, err
+	}
+
+	return predict(models.MatchKindSolo,
+		models.PredictionSide{ID: player1.ID, Name: player1.Name(), EloRating: player1.EloRating},
+		models.PredictionSide{ID: player2.ID, Name: player2.Name(), EloRating: player2.EloRating},
+		models.HeadToHead{Matches: record.Matches, Side1Wins: record.Player1Wins, Side2Wins: record.Matches - record.Player1Wins}), nil
+}
+
+// PredictTeams predicts a match between two teams, rated like the team matches: the average of the
+// team ratings of their players
+func (s *PredictionService) PredictTeams(team1ID, team2ID uint) (*models.MatchupPrediction, error) {
+	if team1ID == team2ID {
+		return nil, errors.New("teams must be different")
+	}
+
+	var teams []models.Team
+	if err := s.db.Preload("Player1").Preload("Player2").
+		Where("id IN ?", []uint{team1ID, team2ID}).Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	if len(teams) != 2 {
+		return nil, errors.New("team not found")
+	}
+	team1, team2 := teams[0], teams[1]
+	if team1.ID != team1ID {
+		team1, team2 = team2, team1
+	}
+
+	var record struct {
+		Matches   int
+		Team1Wins int
+	}
+	if err := s.db.Model(&models.TeamMatch{}).
+		Select("COUNT(*) AS matches, COUNT(*) FILTER (WHERE winner_team_id = ?) AS team1_wins", team1ID).
+		Where("status = ?", "confirmed").
+		Where("(team1_id = ? AND team2_id = ?) OR (team1_id = ? AND team2_id = ?)", team1ID, team2ID, team2ID, team1ID).
+		Scan(&record).Error; err != nil {
+		return nil, err
+	}
+
+	return predict(models.MatchKindTeam,
+		models.PredictionSide{
+			ID:        team1.ID,
+			Name:      team1.Name,
+			EloRating: utils.CalculateTeamAverageElo(team1.Player1.TeamEloRating, team1.Player2.TeamEloRating),
+		},
+		models.PredictionSide{
+			ID:        team2.ID,
+			Name:      team2.Name,
+			EloRating: utils.CalculateTeamAverageElo(team2.Player1.TeamEloRating, team2.Player2.TeamEloRating),
+		},
+		models.HeadToHead{Matches: record.Matches, Side1Wins: record.Team1Wins, Side2Wins: record.Matches - record.Team1Wins}), nil
+}
+
+// predict computes the odds of both sides. The adjusted odds treat the rating engine as
+// headToHeadPriorMatches virtual matches, added to the real head-to-head record.
+func predict(kind string, side1, side2 models.PredictionSide, record models.HeadToHead) *models.MatchupPrediction {
+	side1.WinProbability = utils.ExpectedScore(side1.EloRating, side2.EloRating)
+	side2.WinProbability = 1 - side1.WinProbability
+
+	side1.AdjustedWinProbability = (side1.WinProbability*headToHeadPriorMatches + float64(record.Side1Wins)) /
+		(headToHeadPriorMatches + float64(record.Matches))
+	side2.AdjustedWinProbability = 1 - side1.AdjustedWinProbability
+
+	return &models.MatchupPrediction{
+		MatchKind:  kind,
+		Side1:      side1,
+		Side2:      side2,
+		HeadToHead: record,
+	}
+}
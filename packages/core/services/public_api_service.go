@@ -0,0 +1,203 @@
+package services
+
+import (
+	"core/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	publicAPITokenPrefix = "bab_"
+	// maxPublicResults caps the number of results returned per sync request
+	maxPublicResults = 500
+)
+
+type PublicAPIService struct {
+	db *gorm.DB
+}
+
+func NewPublicAPIService(db *gorm.DB) *PublicAPIService {
+	return &PublicAPIService{
+		db: db,
+	}
+}
+
+// hashToken returns the SHA-256 of a token, only hashes of tokens given to clients are stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken generates a new scoped token; the clear token is only returned here
+func (s *PublicAPIService) CreateToken(req models.CreatePublicAPITokenRequest, createdBy uint) (*models.PublicAPITokenCreatedResponse, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+	token := publicAPITokenPrefix + hex.EncodeToString(bytes)
+
+	apiToken := models.PublicAPIToken{
+		Name:      req.Name,
+		TokenHash: hashToken(token),
+		Prefix:    token[:len(publicAPITokenPrefix)+8],
+		Scopes:    strings.Join(req.Scopes, ","),
+		CreatedBy: &createdBy,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.db.Create(&apiToken).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.PublicAPITokenCreatedResponse{
+		PublicAPIToken: apiToken,
+		Token:          token,
+	}, nil
+}
+
+func (s *PublicAPIService) GetTokens() ([]models.PublicAPIToken, error) {
+	var tokens []models.PublicAPIToken
+	if err := s.db.Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeToken disables a token, it is kept for the record
+func (s *PublicAPIService) RevokeToken(id uint) error {
+	result := s.db.Model(&models.PublicAPIToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
+
+// Authenticate returns the active token matching a clear token and records its use
+func (s *PublicAPIService) Authenticate(token string) (*models.PublicAPIToken, error) {
+	var apiToken models.PublicAPIToken
+	if err := s.db.Where("token_hash = ?", hashToken(token)).First(&apiToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid token")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !apiToken.IsActive(now) {
+		return nil, errors.New("invalid token")
+	}
+
+	s.db.Model(&apiToken).UpdateColumn("last_used_at", now)
+	return &apiToken, nil
+}
+
+// SetConsent records whether a player agrees to be exposed through the public API
+func (s *PublicAPIService) SetConsent(playerID uint, consent bool) (*models.Player, error) {
+	var player models.Player
+	if err := s.db.First(&player, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"public_api_consent":    consent,
+		"public_api_consent_at": now,
+	}
+	if err := s.db.Model(&player).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	player.PublicAPIConsent = consent
+	player.PublicAPIConsentAt = &now
+
+	return &player, nil
+}
+
+// GetPlayers lists the consenting players by ELO
+func (s *PublicAPIService) GetPlayers(page, pageSize int) (*models.PaginatedPublicPlayersResponse, error) {
+	query := s.db.Model(&models.Player{}).Where("public_api_consent = ?", true)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var players []models.Player
+	if err := query.Order("elo_rating DESC, id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&players).Error; err != nil {
+		return nil, err
+	}
+
+	data := make([]models.PublicPlayer, len(players))
+	for i := range players {
+		data[i] = models.NewPublicPlayer(&players[i])
+	}
+
+	return &models.PaginatedPublicPlayersResponse{
+		Data:       data,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// GetPlayer returns a consenting player, non-consenting players are reported as not found
+func (s *PublicAPIService) GetPlayer(id uint) (*models.PublicPlayer, error) {
+	var player models.Player
+	if err := s.db.Where("public_api_consent = ?", true).First(&player, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+
+	publicPlayer := models.NewPublicPlayer(&player)
+	return &publicPlayer, nil
+}
+
+// GetResults returns the confirmed solo matches between two consenting players,
+// confirmed after `since`, oldest first
+func (s *PublicAPIService) GetResults(since *time.Time, limit int) (*models.PublicResultsResponse, error) {
+	if limit <= 0 || limit > maxPublicResults {
+		limit = maxPublicResults
+	}
+
+	consenting := s.db.Model(&models.Player{}).Select("id").Where("public_api_consent = ?", true)
+	query := s.db.Where("status = ? AND confirmed_at IS NOT NULL", "confirmed").
+		Where("player1_id IN (?) AND player2_id IN (?)", consenting, consenting)
+	if since != nil {
+		query = query.Where("confirmed_at > ?", *since)
+	}
+
+	var matches []models.Match
+	if err := query.Order("confirmed_at ASC, id ASC").Limit(limit).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.PublicResultsResponse{
+		Data: make([]models.PublicMatch, len(matches)),
+	}
+	for i := range matches {
+		response.Data[i] = models.NewPublicMatch(&matches[i])
+	}
+	if len(matches) == limit {
+		response.Next = matches[len(matches)-1].ConfirmedAt
+	}
+
+	return response, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"core/clock"
+	"core/models"
+	"core/utils"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RatingOverrideService struct {
+	db            *gorm.DB
+	playerService *PlayerService
+	clock         clock.Clock
+}
+
+func NewRatingOverrideService(db *gorm.DB) *RatingOverrideService {
+	return &RatingOverrideService{
+		db:            db,
+		playerService: NewPlayerService(db),
+		clock:         clock.Real,
+	}
+}
+
+// SetClock replaces the wall clock used for the override and expiry dates
+func (s *RatingOverrideService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// ratingKMultiplier returns the K-factor multiplier of a player at a given time,
+// from their most recent override active at that time (1 without override)
+func ratingKMultiplier(db *gorm.DB, playerID uint, at time.Time) float64 {
+	var multipliers []float64
+	if err := db.Model(&models.RatingOverride{}).
+		Where("player_id = ? AND created_at <= ? AND expires_at > ?", playerID, at, at).
+		Where("revoked_at IS NULL OR revoked_at > ?", at).
+		Order("created_at DESC").
+		Limit(1).
+		Pluck("k_multiplier", &multipliers).Error; err != nil || len(multipliers) == 0 || multipliers[0] <= 0 {
+		return 1
+	}
+	return multipliers[0]
+}
+
+// GetPlayerOverrides lists the overrides of a player, most recent first
+func (s *RatingOverrideService) GetPlayerOverrides(playerID uint) ([]models.RatingOverride, error) {
+	var overrides []models.RatingOverride
+	if err := s.db.Where("player_id = ?", playerID).Order("created_at DESC").Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// CreateOverride records an override and applies its one-off adjustment to the player's ELO
+func (s *RatingOverrideService) CreateOverride(playerID, adminID uint, req models.CreateRatingOverrideRequest) (*models.RatingOverride, error) {
+	if req.KMultiplier == nil && req.Adjustment == nil {
+		return nil, errors.New("k_multiplier or adjustment is required")
+	}
+	if !req.ExpiresAt.After(s.clock.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	if _, err := s.playerService.GetPlayerByID(playerID); err != nil {
+		return nil, err
+	}
+
+	override := models.RatingOverride{
+		PlayerID:    playerID,
+		KMultiplier: 1,
+		Reason:      req.Reason,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   &adminID,
+	}
+	if req.KMultiplier != nil {
+		override.KMultiplier = *req.KMultiplier
+	}
+	if req.Adjustment != nil {
+		override.Adjustment = *req.Adjustment
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&override).Error; err != nil {
+			return err
+		}
+		if override.Adjustment == 0 {
+			return nil
+		}
+		// The adjustment never takes a player below the ELO floor
+		return tx.Model(&models.Player{}).Where("id = ?", playerID).
+			Update("elo_rating", gorm.Expr("GREATEST(elo_rating + ?, ?)", override.Adjustment, utils.EloFloor)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if override.Adjustment != 0 {
+		if err := s.playerService.RecalculateAllRanks(); err != nil {
+			log.Printf("Error recalculating ranks after rating override %d: %v", override.ID, err)
+		}
+	}
+
+	return &override, nil
+}
+
+// RevokeOverride ends an override early; an adjustment already applied is kept
+func (s *RatingOverrideService) RevokeOverride(playerID, id uint) (*models.RatingOverride, error) {
+	var override models.RatingOverride
+	if err := s.db.Where("player_id = ?", playerID).First(&override, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("rating override not found")
+		}
+		return nil, err
+	}
+	if override.RevokedAt != nil {
+		return nil, errors.New("rating override already revoked")
+	}
+
+	now := s.clock.Now()
+	if err := s.db.Model(&override).Update("revoked_at", now).Error; err != nil {
+		return nil, err
+	}
+	override.RevokedAt = &now
+
+	return &override, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RatingResetService struct {
+	db            *gorm.DB
+	playerService *PlayerService
+}
+
+func NewRatingResetService(db *gorm.DB) *RatingResetService {
+	return &RatingResetService{
+		db:            db,
+		playerService: NewPlayerService(db),
+	}
+}
+
+// CreateRequest records a player's request to reset their rating, one pending request at a time
+func (s *RatingResetService) CreateRequest(playerID uint, reason string) (*models.RatingResetRequest, error) {
+	if _, err := s.playerService.GetPlayerByID(playerID); err != nil {
+		return nil, err
+	}
+
+	var pending int64
+	if err := s.db.Model(&models.RatingResetRequest{}).
+		Where("player_id = ? AND status = ?", playerID, models.RatingResetStatusPending).
+		Count(&pending).Error; err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, errors.New("a rating reset request is already pending")
+	}
+
+	request := models.RatingResetRequest{
+		PlayerID: playerID,
+		Reason:   reason,
+		Status:   models.RatingResetStatusPending,
+	}
+	if err := s.db.Create(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetRequests lists the requests with a status, oldest first so they are handled in order
+func (s *RatingResetService) GetRequests(status string) ([]models.RatingResetRequest, error) {
+	var requests []models.RatingResetRequest
+	if err := s.db.Preload("Player").
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ReviewRequest approves or rejects a request. On approval the player's solo rating goes back
+// to the base rating; their history is kept and a reset entry is added to it.
+func (s *RatingResetService) ReviewRequest(id, reviewerID uint, status string) (*models.RatingResetRequest, error) {
+	var request models.RatingResetRequest
+	if err := s.db.First(&request, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("rating reset request not found")
+		}
+		return nil, err
+	}
+	if request.Status != models.RatingResetStatusPending {
+		return nil, errors.New("rating reset request already reviewed")
+	}
+
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&request).Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if status != models.RatingResetStatusApproved {
+			return nil
+		}
+
+		var player models.Player
+		if err := tx.First(&player, request.PlayerID).Error; err != nil {
+			return err
+		}
+
+		marker := models.EloHistory{
+			PlayerID:  player.ID,
+			Kind:      models.EloHistoryKindReset,
+			EloBefore: player.EloRating,
+			EloAfter:  models.BaseEloRating,
+			EloChange: models.BaseEloRating - player.EloRating,
+			CreatedAt: now,
+		}
+		if err := tx.Create(&marker).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Player{}).Where("id = ?", player.ID).Update("elo_rating", models.BaseEloRating).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.RatingResetStatusApproved {
+		if err := s.playerService.RecalculateAllRanks(); err != nil {
+			log.Printf("Error recalculating ranks after rating reset of player %d: %v", request.PlayerID, err)
+		}
+	}
+
+	if err := s.db.Preload("Player").First(&request, id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
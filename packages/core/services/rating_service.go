@@ -0,0 +1,150 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RatingService struct {
+	db *gorm.DB
+}
+
+func NewRatingService(db *gorm.DB) *RatingService {
+	return &RatingService{
+		db: db,
+	}
+}
+
+// ranked returns the ratings of the players with their rank on their ladder, ranked among the
+// players of a campus if not empty. Archived players are ranked apart (rank 0) and flagged archived.
+func (s *RatingService) ranked(campus string) *gorm.DB {
+	return s.db.Table("(?) AS ratings", s.db.Model(&models.Rating{}).
+		Select(`ratings.*, players.archived_at IS NOT NULL AS archived,
+			CASE WHEN players.archived_at IS NULL
+				THEN RANK() OVER (PARTITION BY ratings.ladder, players.archived_at IS NULL ORDER BY ratings.elo_rating DESC)
+				ELSE 0 END AS rank`).
+		Joins("JOIN players ON players.id = ratings.player_id AND players.deleted_at IS NULL").
+		Scopes(models.OnCampus(campus)))
+}
+
+// GetPlayerRatings returns the standing of a player on every ladder they played, in ladder order
+func (s *RatingService) GetPlayerRatings(playerID uint) ([]models.Rating, error) {
+	var ratings []models.Rating
+	if err := s.ranked("").Where("player_id = ?", playerID).Find(&ratings).Error; err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(ratings, func(a, b models.Rating) int {
+		return slices.Index(models.Ladders, a.Ladder) - slices.Index(models.Ladders, b.Ladder)
+	})
+	return ratings, nil
+}
+
+// GetLadder returns the best players of a ladder, of a campus if not empty
+func (s *RatingService) GetLadder(ladder string, limit int, campus string) ([]models.Rating, error) {
+	if !slices.Contains(models.Ladders, ladder) {
+		return nil, errors.New("ladder not found")
+	}
+
+	var ratings []models.Rating
+	if err := s.ranked(campus).
+		Preload("Player").
+		Where("ladder = ? AND NOT archived", ladder).
+		Order("elo_rating DESC, player_id ASC").
+		Limit(limit).
+		Find(&ratings).Error; err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// CurrentElo returns the rating of a player on a ladder, the base rating if they never played it
+func (s *RatingService) CurrentElo(tx *gorm.DB, playerID uint, ladder string) (float64, error) {
+	var ratings []float64
+	if err := tx.Model(&models.Rating{}).
+		Where("player_id = ? AND ladder = ?", playerID, ladder).
+		Pluck("elo_rating", &ratings).Error; err != nil {
+		return 0, err
+	}
+	if len(ratings) == 0 {
+		return models.BaseEloRating, nil
+	}
+	return ratings[0], nil
+}
+
+// ApplyResult records a match result on a ladder that is not mirrored from the Player columns,
+// creating the rating of a player on their first match
+func (s *RatingService) ApplyResult(tx *gorm.DB, playerID uint, ladder string, eloChange float64, won bool) error {
+	if !slices.Contains(models.Ladders, ladder) {
+		return errors.New("ladder not found")
+	}
+	if models.IsMirroredLadder(ladder) {
+		return errors.New("ladder is written through the player ratings")
+	}
+
+	wins, losses := 0, 1
+	if won {
+		wins, losses = 1, 0
+	}
+	return tx.Exec(`
+		INSERT INTO ratings (player_id, ladder, elo_rating, total_matches, wins, losses)
+		VALUES (?, ?, ?, 1, ?, ?)
+		ON CONFLICT (player_id, ladder) DO UPDATE SET
+			elo_rating = ratings.elo_rating + ?,
+			total_matches = ratings.total_matches + 1,
+			wins = ratings.wins + EXCLUDED.wins,
+			losses = ratings.losses + EXCLUDED.losses,
+			updated_at = CURRENT_TIMESTAMP`,
+		playerID, ladder, models.BaseEloRating+eloChange, wins, losses, eloChange).Error
+}
+
+// saveRating writes the standing of a player on a ladder, creating it on their first match
+func saveRating(tx *gorm.DB, rating *models.Rating) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "player_id"}, {Name: "ladder"}},
+		DoUpdates: clause.AssignmentColumns([]string{"elo_rating", "total_matches", "wins", "losses", "updated_at"}),
+	}).Create(rating).Error
+}
+
+// TeamLadderDrift lists the players whose team columns and team rating differ, or who have no team
+// rating. Both are kept in sync by the database, a drift means a backfill is needed.
+func (s *RatingService) TeamLadderDrift() ([]models.RatingDrift, error) {
+	var drift []models.RatingDrift
+	err := s.db.Raw(`
+		SELECT players.id AS player_id, players.username,
+			players.team_elo_rating, players.team_total_matches, players.team_wins, players.team_losses,
+			ratings.elo_rating AS rating_elo_rating, ratings.total_matches AS rating_total_matches,
+			ratings.wins AS rating_wins, ratings.losses AS rating_losses
+		FROM players
+		LEFT JOIN ratings ON ratings.player_id = players.id AND ratings.ladder = ?
+		WHERE ratings.id IS NULL
+			OR (players.team_elo_rating, players.team_total_matches, players.team_wins, players.team_losses)
+				IS DISTINCT FROM (ratings.elo_rating, ratings.total_matches, ratings.wins, ratings.losses)
+		ORDER BY players.id`, models.LadderTeam).Scan(&drift).Error
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// BackfillTeamLadder copies the team columns of the players into their team rating where it is
+// missing or differs, and returns the number of ratings written
+func (s *RatingService) BackfillTeamLadder() (int64, error) {
+	result := s.db.Exec(`
+		INSERT INTO ratings (player_id, ladder, elo_rating, total_matches, wins, losses)
+		SELECT id, ?, team_elo_rating, team_total_matches, team_wins, team_losses FROM players
+		ON CONFLICT (player_id, ladder) DO UPDATE SET
+			elo_rating = EXCLUDED.elo_rating,
+			total_matches = EXCLUDED.total_matches,
+			wins = EXCLUDED.wins,
+			losses = EXCLUDED.losses,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE (ratings.elo_rating, ratings.total_matches, ratings.wins, ratings.losses)
+			IS DISTINCT FROM (EXCLUDED.elo_rating, EXCLUDED.total_matches, EXCLUDED.wins, EXCLUDED.losses)`,
+		models.LadderTeam)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"time"
+
+	authModels "auth/models"
+
+	"gorm.io/gorm"
+)
+
+type RefereeService struct {
+	db               *gorm.DB
+	matchService     *MatchService
+	teamMatchService *TeamMatchService
+}
+
+func NewRefereeService(db *gorm.DB, matchService *MatchService, teamMatchService *TeamMatchService) *RefereeService {
+	return &RefereeService{
+		db:               db,
+		matchService:     matchService,
+		teamMatchService: teamMatchService,
+	}
+}
+
+// IsReferee checks that the user has the referee role
+func (s *RefereeService) IsReferee(userID uint) (bool, error) {
+	var user authModels.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.HasRole(authModels.RoleReferee), nil
+}
+
+func (s *RefereeService) checkReferee(refereeID uint, playerIDs ...uint) error {
+	isReferee, err := s.IsReferee(refereeID)
+	if err != nil {
+		return err
+	}
+	if !isReferee {
+		return errors.New("user is not a referee")
+	}
+
+	for _, playerID := range playerIDs {
+		if playerID == refereeID {
+			return errors.New("referee cannot play in the match")
+		}
+	}
+	return nil
+}
+
+// AssignMatchReferee assigns a referee to a tournament match
+func (s *RefereeService) AssignMatchReferee(matchID, refereeID uint) (*models.Match, error) {
+	var match models.Match
+	if err := s.db.First(&match, matchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("match not found")
+		}
+		return nil, err
+	}
+
+	if match.TournamentID == nil {
+		return nil, errors.New("match is not a tournament match")
+	}
+	if err := s.checkReferee(refereeID, match.Player1ID, match.Player2ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&match).Update("referee_id", refereeID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Player1").Preload("Player2").Preload("Winner").First(&match, matchID).Error; err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// AssignTeamMatchReferee assigns a referee to a tournament team match
+func (s *RefereeService) AssignTeamMatchReferee(matchID, refereeID uint) (*models.TeamMatch, error) {
+	var match models.TeamMatch
+	if err := s.db.Preload("Team1").Preload("Team2").First(&match, matchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("team match not found")
+		}
+		return nil, err
+	}
+
+	if match.TournamentID == nil {
+		return nil, errors.New("match is not a tournament match")
+	}
+	if err := s.checkReferee(refereeID, match.Team1.Player1ID, match.Team1.Player2ID, match.Team2.Player1ID, match.Team2.Player2ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&models.TeamMatch{}).Where("id = ?", matchID).Update("referee_id", refereeID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Team1").Preload("Team1.Player1").Preload("Team1.Player2").
+		Preload("Team2").Preload("Team2.Player1").Preload("Team2.Player2").
+		Preload("WinnerTeam").First(&match, matchID).Error; err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// RecordRefereedMatch creates a tournament match entered by a referee and confirms it right away,
+// without waiting for the opponent or the 24h auto-validation
+func (s *RefereeService) RecordRefereedMatch(req models.CreateMatchRequest, refereeID uint) (*models.Match, error) {
+	if req.TournamentID == nil {
+		return nil, errors.New("match is not a tournament match")
+	}
+	if err := s.checkReferee(refereeID, req.Player1ID, req.Player2ID); err != nil {
+		return nil, err
+	}
+
+	// Sent again with the same client UUID: already recorded and confirmed
+	if replayed, err := s.matchService.FindReplayedMatch(req); err != nil || replayed != nil {
+		return replayed, err
+	}
+
+	// Created, assigned and confirmed at once, a failure leaves no unrefereed or pending match behind
+	status := "confirmed"
+	var match *models.Match
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		created, err := s.matchService.createMatchInTransaction(tx, req)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Match{}).Where("id = ?", created.ID).Update("referee_id", refereeID).Error; err != nil {
+			return err
+		}
+		match, err = s.matchService.updateMatchStatusInTransaction(tx, created.ID, models.UpdateMatchStatusRequest{Status: &status})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.matchService.statusUpdated(match)
+	return s.matchService.matches.FindByIDWithPlayers(match.ID)
+}
+
+// RecordRefereedTeamMatch creates a tournament team match entered by a referee and confirms it right away
+func (s *RefereeService) RecordRefereedTeamMatch(req models.CreateTeamMatchRequest, refereeID uint) (*models.TeamMatch, error) {
+	if req.TournamentID == nil {
+		return nil, errors.New("match is not a tournament match")
+	}
+
+	var teams []models.Team
+	if err := s.db.Where("id IN ?", []uint{req.Team1ID, req.Team2ID}).Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	var playerIDs []uint
+	for _, team := range teams {
+		playerIDs = append(playerIDs, team.Player1ID, team.Player2ID)
+	}
+	if err := s.checkReferee(refereeID, playerIDs...); err != nil {
+		return nil, err
+	}
+
+	// Sent again with the same client UUID: already recorded and confirmed
+	if replayed, err := s.teamMatchService.FindReplayedTeamMatch(req); err != nil || replayed != nil {
+		return replayed, err
+	}
+
+	// Created, assigned and confirmed at once, a failure leaves no unrefereed or pending match behind
+	status := "confirmed"
+	var match *models.TeamMatch
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		created, err := s.teamMatchService.createTeamMatchInTransaction(tx, req)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TeamMatch{}).Where("id = ?", created.ID).Update("referee_id", refereeID).Error; err != nil {
+			return err
+		}
+		match, err = s.teamMatchService.updateTeamMatchStatusInTransaction(tx, created.ID, models.UpdateTeamMatchStatusRequest{Status: &status})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.teamMatchService.statusUpdated(match)
+	if err := s.db.First(match, match.ID).Error; err != nil {
+		return nil, err
+	}
+	return loadTeamMatch(s.db, *match)
+}
+
+// GetRefereeStats returns how many matches a referee has officiated
+func (s *RefereeService) GetRefereeStats(refereeID uint) (*models.RefereeStats, error) {
+	var user authModels.User
+	if err := s.db.First(&user, refereeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("referee not found")
+		}
+		return nil, err
+	}
+
+	stats := &models.RefereeStats{
+		RefereeID: refereeID,
+		Username:  user.Username,
+	}
+
+	if err := s.db.Model(&models.Match{}).Where("referee_id = ?", refereeID).Count(&stats.MatchesRefereed).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&models.TeamMatch{}).Where("referee_id = ?", refereeID).Count(&stats.TeamMatchesRefereed).Error; err != nil {
+		return nil, err
+	}
+
+	var confirmedSolo, confirmedTeam int64
+	if err := s.db.Model(&models.Match{}).Where("referee_id = ? AND status = ?", refereeID, "confirmed").Count(&confirmedSolo).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&models.TeamMatch{}).Where("referee_id = ? AND status = ?", refereeID, "confirmed").Count(&confirmedTeam).Error; err != nil {
+		return nil, err
+	}
+	stats.ConfirmedMatches = confirmedSolo + confirmedTeam
+
+	if err := s.db.Raw(`
+		SELECT COUNT(DISTINCT tournament_id) FROM (
+			SELECT tournament_id FROM matches WHERE referee_id = ? AND deleted_at IS NULL
+			UNION
+			SELECT tournament_id FROM team_matches WHERE referee_id = ? AND deleted_at IS NULL
+		) refereed
+	`, refereeID, refereeID).Scan(&stats.TournamentsRefereed).Error; err != nil {
+		return nil, err
+	}
+
+	var lastRefereedAt *time.Time
+	if err := s.db.Raw(`
+		SELECT MAX(created_at) FROM (
+			SELECT created_at FROM matches WHERE referee_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT created_at FROM team_matches WHERE referee_id = ? AND deleted_at IS NULL
+		) refereed
+	`, refereeID, refereeID).Scan(&lastRefereedAt).Error; err != nil {
+		return nil, err
+	}
+	stats.LastRefereedAt = lastRefereedAt
+
+	return stats, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeasonService struct {
+	db *gorm.DB
+}
+
+func NewSeasonService(db *gorm.DB) *SeasonService {
+	return &SeasonService{
+		db: db,
+	}
+}
+
+// seasonSettingOf returns the rules of a season, the defaults when it was never configured
+func seasonSettingOf(db *gorm.DB, season string) (models.SeasonSetting, error) {
+	var setting models.SeasonSetting
+	if err := db.First(&setting, "season = ?", season).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DefaultSeasonSetting(season), nil
+		}
+		return setting, err
+	}
+	return setting, nil
+}
+
+func (s *SeasonService) GetSetting(season string) (*models.SeasonSetting, error) {
+	if !models.IsValidSeason(season) {
+		return nil, errors.New("invalid season")
+	}
+
+	setting, err := seasonSettingOf(s.db, season)
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// UpdateSetting selects the rules of a season. Matches already recorded keep the mode they were created with.
+func (s *SeasonService) UpdateSetting(season string, req models.UpdateSeasonSettingRequest) (*models.SeasonSetting, error) {
+	if !models.IsValidSeason(season) {
+		return nil, errors.New("invalid season")
+	}
+
+	setting := models.SeasonSetting{
+		Season:           season,
+		ConfirmationMode: req.ConfirmationMode,
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "season"}},
+		DoUpdates: clause.AssignmentColumns([]string{"confirmation_mode", "updated_at"}),
+	}).Create(&setting).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetSetting(season)
+}
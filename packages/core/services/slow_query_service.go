@@ -0,0 +1,214 @@
+package services
+
+import (
+	"core/events"
+	"core/models"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSlowQueryThreshold = 200 * time.Millisecond
+	defaultSlowQueryAlert     = time.Second
+
+	// slowQueryRetention is how long recorded queries are kept
+	slowQueryRetention = 30 * 24 * time.Hour
+
+	slowQueryStartKey = "slow_query:start"
+)
+
+// Packages whose functions are the entry point of a query: route handlers and scheduled jobs
+var slowQueryEntryPackages = []string{"core/handlers.", "auth/handlers.", "core/cron.", "core/middleware.", "auth/middleware."}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\$\d+`)
+	inListPattern      = regexp.MustCompile(`\(\?(\s*,\s*\?)+\)`)
+	spacesPattern      = regexp.MustCompile(`\s+`)
+)
+
+type SlowQueryService struct {
+	db        *gorm.DB
+	events    *events.Bus
+	threshold time.Duration
+	alert     time.Duration
+	queue     chan models.SlowQuery
+}
+
+// NewSlowQueryService reads SLOW_QUERY_THRESHOLD_MS (default 200, 0 disables the recording)
+// and SLOW_QUERY_ALERT_MS, the budget above which a query is reported to the admin console (default 1000)
+func NewSlowQueryService(db *gorm.DB, bus *events.Bus) *SlowQueryService {
+	return &SlowQueryService{
+		db:        db,
+		events:    bus,
+		threshold: envMilliseconds("SLOW_QUERY_THRESHOLD_MS", defaultSlowQueryThreshold),
+		alert:     envMilliseconds("SLOW_QUERY_ALERT_MS", defaultSlowQueryAlert),
+		queue:     make(chan models.SlowQuery, 256),
+	}
+}
+
+func envMilliseconds(name string, fallback time.Duration) time.Duration {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value >= 0 {
+		return time.Duration(value) * time.Millisecond
+	}
+	return fallback
+}
+
+// Register hooks the query timer into every GORM operation and starts the writer.
+// Slow queries are written in the background so that recording never slows requests further.
+func (s *SlowQueryService) Register() error {
+	if s.threshold == 0 {
+		log.Println("Slow query logging disabled")
+		return nil
+	}
+
+	type registrar interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+
+	callbacks := s.db.Callback()
+	hooks := []struct {
+		name      string
+		registrar registrar
+		fn        func(*gorm.DB)
+	}{
+		{"slow_query:before_create", callbacks.Create().Before("gorm:create"), s.before},
+		{"slow_query:after_create", callbacks.Create().After("gorm:create"), s.after},
+		{"slow_query:before_query", callbacks.Query().Before("gorm:query"), s.before},
+		{"slow_query:after_query", callbacks.Query().After("gorm:query"), s.after},
+		{"slow_query:before_update", callbacks.Update().Before("gorm:update"), s.before},
+		{"slow_query:after_update", callbacks.Update().After("gorm:update"), s.after},
+		{"slow_query:before_delete", callbacks.Delete().Before("gorm:delete"), s.before},
+		{"slow_query:after_delete", callbacks.Delete().After("gorm:delete"), s.after},
+		{"slow_query:before_row", callbacks.Row().Before("gorm:row"), s.before},
+		{"slow_query:after_row", callbacks.Row().After("gorm:row"), s.after},
+		{"slow_query:before_raw", callbacks.Raw().Before("gorm:raw"), s.before},
+		{"slow_query:after_raw", callbacks.Raw().After("gorm:raw"), s.after},
+	}
+	for _, hook := range hooks {
+		if err := hook.registrar.Register(hook.name, hook.fn); err != nil {
+			return err
+		}
+	}
+
+	go s.write()
+	return nil
+}
+
+func (s *SlowQueryService) before(db *gorm.DB) {
+	db.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+func (s *SlowQueryService) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(slowQueryStartKey)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(value.(time.Time))
+	if elapsed < s.threshold || db.Statement.Table == "slow_queries" {
+		return
+	}
+
+	source, handler := queryCallers()
+	query := models.SlowQuery{
+		Fingerprint:  fingerprintSQL(db.Statement.SQL.String()),
+		Source:       source,
+		Handler:      handler,
+		DurationMs:   float64(elapsed.Microseconds()) / 1000,
+		RowsAffected: db.RowsAffected,
+	}
+
+	if s.alert > 0 && elapsed >= s.alert {
+		s.events.Publish(events.TypeSlowQuery, map[string]interface{}{
+			"fingerprint": query.Fingerprint,
+			"source":      query.Source,
+			"handler":     query.Handler,
+			"duration_ms": query.DurationMs,
+			"budget_ms":   s.alert.Milliseconds(),
+		})
+	}
+
+	select {
+	case s.queue <- query:
+	default:
+		log.Printf("Slow query queue full, dropping %s (%.0fms)", query.Source, query.DurationMs)
+	}
+}
+
+// write stores the queued slow queries and purges the old ones once an hour
+func (s *SlowQueryService) write() {
+	purge := time.NewTicker(time.Hour)
+	defer purge.Stop()
+
+	for {
+		select {
+		case query := <-s.queue:
+			if err := s.db.Create(&query).Error; err != nil {
+				log.Printf("Error recording slow query: %v", err)
+			}
+		case now := <-purge.C:
+			if err := s.db.Where("created_at < ?", now.Add(-slowQueryRetention)).Delete(&models.SlowQuery{}).Error; err != nil {
+				log.Printf("Error purging slow queries: %v", err)
+			}
+		}
+	}
+}
+
+// queryCallers walks the stack of a query: the source is the first caller outside GORM,
+// the handler the route handler or scheduled job it runs for
+func queryCallers() (source, handler string) {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if source == "" && !strings.Contains(frame.File, "gorm.io/") && !strings.HasSuffix(frame.File, "slow_query_service.go") {
+			source = filepath.Base(filepath.Dir(frame.File)) + "/" + filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		for _, entry := range slowQueryEntryPackages {
+			if strings.HasPrefix(frame.Function, entry) {
+				return source, strings.TrimSuffix(frame.Function, "-fm")
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	if handler == "" {
+		handler = "unknown"
+	}
+	return source, handler
+}
+
+// fingerprintSQL groups queries that only differ by their values or the length of their IN lists
+func fingerprintSQL(sql string) string {
+	sql = placeholderPattern.ReplaceAllString(sql, "?")
+	sql = inListPattern.ReplaceAllString(sql, "(?...)")
+	return strings.TrimSpace(spacesPattern.ReplaceAllString(sql, " "))
+}
+
+// GetWorstOffenders aggregates the slow queries recorded since a date, by total time spent
+func (s *SlowQueryService) GetWorstOffenders(since time.Time, limit int) ([]models.SlowQueryOffender, error) {
+	var offenders []models.SlowQueryOffender
+	if err := s.db.Model(&models.SlowQuery{}).
+		Select(`fingerprint, handler, MAX(source) AS source, COUNT(*) AS count,
+			AVG(duration_ms) AS avg_duration_ms, MAX(duration_ms) AS max_duration_ms,
+			SUM(duration_ms) AS total_ms, MAX(created_at) AS last_seen`).
+		Where("created_at >= ?", since).
+		Group("fingerprint, handler").
+		Order("total_ms DESC").
+		Limit(limit).
+		Scan(&offenders).Error; err != nil {
+		return nil, err
+	}
+	return offenders, nil
+}
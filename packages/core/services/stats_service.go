@@ -4,11 +4,15 @@ import (
 	"core/models"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
 type StatsService struct {
 	db *gorm.DB
+
+	// Coalesces the concurrent computations of the public stats into one
+	group singleflight.Group
 }
 
 func NewStatsService(db *gorm.DB) *StatsService {
@@ -17,7 +21,45 @@ func NewStatsService(db *gorm.DB) *StatsService {
 	}
 }
 
-func (s *StatsService) GetStats() (*models.Stats, error) {
+// GetStats computes the global stats, or those of a campus if not empty. Concurrent callers share
+// the result of a single computation.
+func (s *StatsService) GetStats(campus string) (*models.Stats, error) {
+	value, err, _ := s.group.Do("stats:"+campus, func() (interface{}, error) {
+		return s.computeStats(campus)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Each caller gets its own copy
+	stats := *value.(*models.Stats)
+	return &stats, nil
+}
+
+// campusScopes filters the players of a campus, the matches and teams where one of them plays (both
+// have player1_id and player2_id), and the matches of those teams. Nothing is filtered when the campus
+// is empty.
+func (s *StatsService) campusScopes(campus string) (players, withPlayer, teamMatches func(*gorm.DB) *gorm.DB) {
+	if campus == "" {
+		all := func(db *gorm.DB) *gorm.DB { return db }
+		return all, all, all
+	}
+
+	campusPlayers := s.db.Model(&models.Player{}).Select("id").Scopes(models.OnCampus(campus))
+	campusTeams := s.db.Model(&models.Team{}).Select("id").
+		Where("player1_id IN (?) OR player2_id IN (?)", campusPlayers, campusPlayers)
+
+	players = models.OnCampus(campus)
+	withPlayer = func(db *gorm.DB) *gorm.DB {
+		return db.Where("player1_id IN (?) OR player2_id IN (?)", campusPlayers, campusPlayers)
+	}
+	teamMatches = func(db *gorm.DB) *gorm.DB {
+		return db.Where("team1_id IN (?) OR team2_id IN (?)", campusTeams, campusTeams)
+	}
+	return players, withPlayer, teamMatches
+}
+
+func (s *StatsService) computeStats(campus string) (*models.Stats, error) {
 	var totalPlayers int64
 	var totalMatches int64
 	var matchesLast7Days int64
@@ -27,23 +69,25 @@ func (s *StatsService) GetStats() (*models.Stats, error) {
 	var teamMatchesLast7Days int64
 	var teamMatchesPrevious7Days int64
 
+	onCampus, withCampusPlayer, teamMatchesOnCampus := s.campusScopes(campus)
+
 	// Count total players
-	if err := s.db.Model(&models.Player{}).Count(&totalPlayers).Error; err != nil {
+	if err := s.db.Model(&models.Player{}).Scopes(onCampus).Count(&totalPlayers).Error; err != nil {
 		return nil, err
 	}
 
 	// Count total matches (solo)
-	if err := s.db.Model(&models.Match{}).Count(&totalMatches).Error; err != nil {
+	if err := s.db.Model(&models.Match{}).Scopes(withCampusPlayer).Count(&totalMatches).Error; err != nil {
 		return nil, err
 	}
 
 	// Count total teams
-	if err := s.db.Model(&models.Team{}).Count(&totalTeams).Error; err != nil {
+	if err := s.db.Model(&models.Team{}).Scopes(withCampusPlayer).Count(&totalTeams).Error; err != nil {
 		return nil, err
 	}
 
 	// Count total team matches
-	if err := s.db.Model(&models.TeamMatch{}).Count(&totalTeamMatches).Error; err != nil {
+	if err := s.db.Model(&models.TeamMatch{}).Scopes(teamMatchesOnCampus).Count(&totalTeamMatches).Error; err != nil {
 		return nil, err
 	}
 
@@ -54,28 +98,28 @@ func (s *StatsService) GetStats() (*models.Stats, error) {
 	previous7DaysEnd := last7DaysStart
 
 	// Count solo matches in the last 7 days
-	if err := s.db.Model(&models.Match{}).
+	if err := s.db.Model(&models.Match{}).Scopes(withCampusPlayer).
 		Where("created_at >= ?", last7DaysStart).
 		Count(&matchesLast7Days).Error; err != nil {
 		return nil, err
 	}
 
 	// Count solo matches in the previous 7 days (7-14 days ago)
-	if err := s.db.Model(&models.Match{}).
+	if err := s.db.Model(&models.Match{}).Scopes(withCampusPlayer).
 		Where("created_at >= ? AND created_at < ?", previous7DaysStart, previous7DaysEnd).
 		Count(&matchesPrevious7Days).Error; err != nil {
 		return nil, err
 	}
 
 	// Count team matches in the last 7 days
-	if err := s.db.Model(&models.TeamMatch{}).
+	if err := s.db.Model(&models.TeamMatch{}).Scopes(teamMatchesOnCampus).
 		Where("created_at >= ?", last7DaysStart).
 		Count(&teamMatchesLast7Days).Error; err != nil {
 		return nil, err
 	}
 
 	// Count team matches in the previous 7 days (7-14 days ago)
-	if err := s.db.Model(&models.TeamMatch{}).
+	if err := s.db.Model(&models.TeamMatch{}).Scopes(teamMatchesOnCampus).
 		Where("created_at >= ? AND created_at < ?", previous7DaysStart, previous7DaysEnd).
 		Count(&teamMatchesPrevious7Days).Error; err != nil {
 		return nil, err
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"core/models"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// syncOverlap moves the returned checkpoint back so that writes committed while a sync runs, or stamped
+// by an instance with a slightly late clock, are sent again next time rather than missed
+const syncOverlap = 5 * time.Second
+
+// syncPageSize is the most entities of each kind returned by a sync, the rest comes with the next one
+const syncPageSize = 500
+
+// syncCursorPrefix versions the cursor format
+const syncCursorPrefix = "v1:"
+
+const (
+	// offlineMatchMaxAge is how long after being played an offline match can still be submitted
+	offlineMatchMaxAge = 7 * 24 * time.Hour
+	// offlineConflictWindow is how close to an offline match a match with the same players and winner must be
+	// to be taken for the same game entered twice (e.g. on a phone while the kiosk was offline)
+	offlineConflictWindow = 15 * time.Minute
+)
+
+const offlineConflictMessage = "a match between these players was recorded around the same time"
+
+// offlineRejections are the match creation errors that will fail again if the match is resubmitted
+var offlineRejections = map[string]bool{
+	"player1 not found":                        true,
+	"player2 not found":                        true,
+	"player1 and player2 must be different":    true,
+	"winner must be either player1 or player2": true,
+	"daily match limit reached":                true,
+	"client_uuid already used":                 true,
+	"player is archived":                       true,
+}
+
+type SyncService struct {
+	db           *gorm.DB
+	kioskService *KioskService
+}
+
+func NewSyncService(db *gorm.DB, kioskService *KioskService) *SyncService {
+	return &SyncService{db: db, kioskService: kioskService}
+}
+
+// ParseSince reads a checkpoint given as a cursor returned by a previous sync or as an RFC 3339 timestamp
+func ParseSince(since string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		return at, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(since)
+	if err != nil || !strings.HasPrefix(string(decoded), syncCursorPrefix) {
+		return time.Time{}, errors.New("invalid since")
+	}
+	nanos, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), syncCursorPrefix), 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("invalid since")
+	}
+	return time.Unix(0, nanos), nil
+}
+
+func encodeSyncCursor(at time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncCursorPrefix + strconv.FormatInt(at.UnixNano(), 10)))
+}
+
+// GetChanges returns the players, matches and teams created, updated or deleted after since, or all of
+// them when since is nil, by pages of at most syncPageSize entities of each kind: HasMore tells the client to
+// sync again right away from the returned cursor. Entities changed right around the checkpoint may be sent
+// twice, clients apply the changes by ID so that replaying them is harmless.
+func (s *SyncService) GetChanges(since *time.Time) (*models.SyncResponse, error) {
+	// Taken before reading so that nothing written during the reads falls before the next checkpoint
+	now := time.Now()
+
+	response := &models.SyncResponse{
+		Players:    models.SyncPlayers{Created: []models.Player{}, Updated: []models.Player{}, Deleted: []uint{}},
+		Matches:    models.SyncMatches{Created: []models.Match{}, Updated: []models.Match{}, Deleted: []uint{}},
+		Teams:      models.SyncTeams{Created: []models.Team{}, Updated: []models.Team{}, Deleted: []uint{}},
+		Full:       since == nil,
+		ServerTime: now,
+	}
+	// The next sync resumes at the earliest end of a truncated page, a complete one at now
+	cursor := now.Add(-syncOverlap)
+	truncatedAt := func(last *time.Time) {
+		if last != nil && (!response.HasMore || last.Before(cursor)) {
+			cursor, response.HasMore = *last, true
+		}
+	}
+
+	players, last, err := syncPage(s.db.Model(&models.Player{}), "updated_at", since,
+		func(p models.Player) (time.Time, uint) { return p.UpdatedAt, p.ID })
+	if err != nil {
+		return nil, err
+	}
+	truncatedAt(last)
+	for _, player := range players {
+		if since == nil || player.CreatedAt.After(*since) {
+			response.Players.Created = append(response.Players.Created, player)
+		} else {
+			response.Players.Updated = append(response.Players.Updated, player)
+		}
+	}
+
+	matches, last, err := syncPage(s.db.Model(&models.Match{}).Preload("Player1").Preload("Player2").Preload("Winner"), "updated_at", since,
+		func(m models.Match) (time.Time, uint) { return m.UpdatedAt, m.ID })
+	if err != nil {
+		return nil, err
+	}
+	truncatedAt(last)
+	for _, match := range matches {
+		if since == nil || match.CreatedAt.After(*since) {
+			response.Matches.Created = append(response.Matches.Created, match)
+		} else {
+			response.Matches.Updated = append(response.Matches.Updated, match)
+		}
+	}
+
+	teams, last, err := syncPage(s.db.Model(&models.Team{}).Preload("Player1").Preload("Player2"), "updated_at", since,
+		func(t models.Team) (time.Time, uint) { return t.UpdatedAt, t.ID })
+	if err != nil {
+		return nil, err
+	}
+	truncatedAt(last)
+	for _, team := range teams {
+		if since == nil || team.CreatedAt.After(*since) {
+			response.Teams.Created = append(response.Teams.Created, team)
+		} else {
+			response.Teams.Updated = append(response.Teams.Updated, team)
+		}
+	}
+
+	// A full sync has nothing deleted to report
+	if since != nil {
+		for _, deleted := range []struct {
+			model interface{}
+			ids   *[]uint
+		}{
+			{&models.Player{}, &response.Players.Deleted},
+			{&models.Match{}, &response.Matches.Deleted},
+			{&models.Team{}, &response.Teams.Deleted},
+		} {
+			deletions, last, err := syncPage(s.db.Unscoped().Model(deleted.model).Select("id", "deleted_at"), "deleted_at", since,
+				func(d syncDeletion) (time.Time, uint) { return d.DeletedAt, d.ID })
+			if err != nil {
+				return nil, err
+			}
+			truncatedAt(last)
+			for _, deletion := range deletions {
+				*deleted.ids = append(*deleted.ids, deletion.ID)
+			}
+		}
+	}
+
+	response.Cursor = encodeSyncCursor(cursor)
+	return response, nil
+}
+
+// syncDeletion is a soft-deleted row as read by GetChanges
+type syncDeletion struct {
+	ID        uint
+	DeletedAt time.Time
+}
+
+// syncPage reads the rows of query changed after since, by the time column, oldest first and at most
+// syncPageSize of them. A page is never cut between rows changed at the same time: a full page also holds
+// the rows changed at the time of its last one, and that time is returned so the next page starts after it.
+// The returned time is nil when every row changed after since was read.
+func syncPage[T any](query *gorm.DB, column string, since *time.Time, changedAt func(T) (time.Time, uint)) ([]T, *time.Time, error) {
+	page := query.Session(&gorm.Session{})
+	if since != nil {
+		page = page.Where(column+" > ?", *since)
+	}
+
+	var rows []T
+	if err := page.Order(column + " ASC, id ASC").Limit(syncPageSize).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(rows) < syncPageSize {
+		return rows, nil, nil
+	}
+
+	last, lastID := changedAt(rows[len(rows)-1])
+	var sameTime []T
+	if err := query.Session(&gorm.Session{}).Where(column+" = ? AND id > ?", last, lastID).Order("id ASC").Find(&sameTime).Error; err != nil {
+		return nil, nil, err
+	}
+	return append(rows, sameTime...), &last, nil
+}
+
+// SubmitMatches creates the matches a kiosk queued while offline and returns one result per item, in order,
+// with the matches created. Items are deduplicated by their client UUID: a batch sent again returns the
+// same matches instead of creating them twice.
+func (s *SyncService) SubmitMatches(deviceID uint, items []models.SyncMatchItem) ([]models.SyncMatchResult, []*models.Match) {
+	results := make([]models.SyncMatchResult, 0, len(items))
+	var created []*models.Match
+
+	for _, item := range items {
+		result, match := s.submitMatch(deviceID, item)
+		results = append(results, result)
+		if match != nil {
+			created = append(created, match)
+		}
+	}
+	return results, created
+}
+
+func (s *SyncService) submitMatch(deviceID uint, item models.SyncMatchItem) (models.SyncMatchResult, *models.Match) {
+	result := models.SyncMatchResult{ClientUUID: item.ClientUUID}
+	reject := func(status, message string) (models.SyncMatchResult, *models.Match) {
+		result.Status, result.Error = status, message
+		return result, nil
+	}
+
+	now := time.Now()
+	playedAt := now
+	if item.PlayedAt != nil {
+		playedAt = *item.PlayedAt
+	}
+	if playedAt.After(now.Add(syncOverlap)) {
+		return reject(models.SyncMatchRejected, "played_at is in the future")
+	}
+	if playedAt.Before(now.Add(-offlineMatchMaxAge)) {
+		return reject(models.SyncMatchRejected, "played_at is too old")
+	}
+
+	// Claim the UUID first, so that two requests carrying the same batch cannot both create the match
+	submission := models.OfflineMatchSubmission{
+		ClientUUID:    item.ClientUUID,
+		KioskDeviceID: &deviceID,
+		Player1ID:     item.Player1ID,
+		Player2ID:     item.Player2ID,
+		WinnerID:      item.WinnerID,
+		PlayedAt:      playedAt,
+		Status:        models.OfflineSubmissionProcessing,
+	}
+	claim := s.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "client_uuid"}}, DoNothing: true}).Create(&submission)
+	if claim.Error != nil {
+		return reject(models.SyncMatchError, "Failed to create match")
+	}
+
+	if claim.RowsAffected == 0 {
+		var existing models.OfflineMatchSubmission
+		if err := s.db.Where("client_uuid = ?", item.ClientUUID).First(&existing).Error; err != nil {
+			return reject(models.SyncMatchError, "Failed to create match")
+		}
+		if existing.Player1ID != item.Player1ID || existing.Player2ID != item.Player2ID || existing.WinnerID != item.WinnerID {
+			return reject(models.SyncMatchRejected, "client_uuid already used for another match")
+		}
+
+		switch existing.Status {
+		case models.OfflineSubmissionAccepted:
+			result.Status, result.MatchID = models.SyncMatchDuplicate, existing.MatchID
+			return result, nil
+		case models.OfflineSubmissionProcessing:
+			return reject(models.SyncMatchError, "match is being submitted by another request")
+		}
+
+		// A conflict is only reported again, unless the client now forces the match
+		if !item.Force || !s.reclaim(existing.ID) {
+			result.Status, result.MatchID = models.SyncMatchConflict, existing.MatchID
+			result.Error = offlineConflictMessage
+			return result, nil
+		}
+		submission = existing
+	}
+
+	kioskReq := models.CreateKioskMatchRequest{
+		Player1ID:  item.Player1ID,
+		Player2ID:  item.Player2ID,
+		WinnerID:   item.WinnerID,
+		ClientUUID: &item.ClientUUID,
+	}
+
+	// Recorded online with the same UUID before the connection dropped, the kiosk did not get the answer
+	replayed, err := s.kioskService.FindReplayedMatch(kioskReq)
+	if err != nil {
+		s.db.Delete(&submission)
+		if err.Error() == "client_uuid already used" {
+			return reject(models.SyncMatchRejected, "client_uuid already used for another match")
+		}
+		return reject(models.SyncMatchError, "Failed to create match")
+	}
+	if replayed != nil {
+		s.db.Model(&submission).Updates(map[string]interface{}{
+			"status":   models.OfflineSubmissionAccepted,
+			"match_id": replayed.ID,
+		})
+		result.Status, result.MatchID = models.SyncMatchDuplicate, &replayed.ID
+		return result, nil
+	}
+
+	if !item.Force {
+		similar, err := s.findSimilarMatch(item, playedAt)
+		if err != nil {
+			s.db.Delete(&submission)
+			return reject(models.SyncMatchError, "Failed to create match")
+		}
+		if similar != nil {
+			s.db.Model(&submission).Updates(map[string]interface{}{
+				"status":   models.OfflineSubmissionConflict,
+				"match_id": similar.ID,
+			})
+			result.Status, result.MatchID = models.SyncMatchConflict, &similar.ID
+			result.Error = offlineConflictMessage
+			return result, nil
+		}
+	}
+
+	match, err := s.kioskService.CreateMatch(deviceID, kioskReq)
+	if err != nil {
+		// Release the UUID, the client may resubmit once the problem is solved
+		s.db.Delete(&submission)
+		if offlineRejections[err.Error()] {
+			return reject(models.SyncMatchRejected, err.Error())
+		}
+		return reject(models.SyncMatchError, "Failed to create match")
+	}
+
+	if err := s.db.Model(&submission).Updates(map[string]interface{}{
+		"status":   models.OfflineSubmissionAccepted,
+		"match_id": match.ID,
+	}).Error; err != nil {
+		log.Printf("Offline match %s created as match %d but not marked as accepted: %v", item.ClientUUID, match.ID, err)
+	}
+
+	result.Status, result.MatchID = models.SyncMatchAccepted, &match.ID
+	return result, match
+}
+
+// reclaim takes back a submission reported as a conflict to create it, false if another request did first
+func (s *SyncService) reclaim(submissionID uint) bool {
+	update := s.db.Model(&models.OfflineMatchSubmission{}).
+		Where("id = ? AND status = ?", submissionID, models.OfflineSubmissionConflict).
+		Update("status", models.OfflineSubmissionProcessing)
+	return update.Error == nil && update.RowsAffected == 1
+}
+
+// findSimilarMatch looks for a match with the same players and winner recorded online close to when the
+// offline one was played. Matches from other offline submissions are rematches, the client told them apart.
+func (s *SyncService) findSimilarMatch(item models.SyncMatchItem, playedAt time.Time) (*models.Match, error) {
+	var match models.Match
+	err := s.db.
+		Where("(player1_id = ? AND player2_id = ?) OR (player1_id = ? AND player2_id = ?)",
+			item.Player1ID, item.Player2ID, item.Player2ID, item.Player1ID).
+		Where("winner_id = ? AND status NOT IN ?", item.WinnerID, []string{"rejected", "cancelled"}).
+		Where("id NOT IN (?)", s.db.Model(&models.OfflineMatchSubmission{}).Select("match_id").Where("status = ?", models.OfflineSubmissionAccepted)).
+		Where("created_at BETWEEN ? AND ?", playedAt.Add(-offlineConflictWindow), playedAt.Add(offlineConflictWindow)).
+		Order("created_at ASC").
+		First(&match).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"core/clock"
 	"core/models"
 	"core/utils"
 	"errors"
@@ -14,40 +15,119 @@ type TeamMatchService struct {
 	teamService       *TeamService
 	playerService     *PlayerService
 	tournamentService *TournamentService
+	photoPolicy       string
+	floorMode         string
+	// Team matches a player plays before the floor holds their team rating, see calibrationMatchesFromEnv
+	calibrationMatches int
+	clock              clock.Clock
 }
 
 func NewTeamMatchService(db *gorm.DB) *TeamMatchService {
+	floorMode := floorModeFromEnv()
 	return &TeamMatchService{
-		db:                db,
-		teamService:       NewTeamService(db),
-		playerService:     NewPlayerService(db),
-		tournamentService: NewTournamentService(db),
+		db:                 db,
+		teamService:        NewTeamService(db),
+		playerService:      NewPlayerService(db),
+		tournamentService:  NewTournamentService(db),
+		photoPolicy:        photoPolicyFromEnv(),
+		floorMode:          floorMode,
+		calibrationMatches: calibrationMatchesFromEnv(floorMode),
+		clock:              clock.Real,
 	}
 }
 
+// SetClock replaces the wall clock used for creation and confirmation dates
+func (s *TeamMatchService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 func (s *TeamMatchService) GetRecentTeamMatches(limit int) ([]models.TeamMatch, error) {
 	var matches []models.TeamMatch
 
 	result := s.db.Order("created_at DESC").
 		Limit(limit).
-		Preload("Team1").
-		Preload("Team1.Player1").
-		Preload("Team1.Player2").
-		Preload("Team2").
-		Preload("Team2.Player1").
-		Preload("Team2.Player2").
-		Preload("WinnerTeam").
-		Preload("WinnerTeam.Player1").
-		Preload("WinnerTeam.Player2").
 		Find(&matches)
 
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	if err := loadTeamMatchRelations(s.db, matches); err != nil {
+		return nil, err
+	}
+
 	return matches, nil
 }
 
+// loadTeamMatchRelations loads the teams of the matches (team1, team2, winner) and their players
+// in two IN queries instead of one preload query per association
+func loadTeamMatchRelations(db *gorm.DB, matches []models.TeamMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	teamIDs := make([]uint, 0, len(matches)*2)
+	seenTeams := make(map[uint]bool)
+	for _, match := range matches {
+		for _, teamID := range []uint{match.Team1ID, match.Team2ID, match.WinnerTeamID} {
+			if !seenTeams[teamID] {
+				seenTeams[teamID] = true
+				teamIDs = append(teamIDs, teamID)
+			}
+		}
+	}
+
+	var teams []models.Team
+	if err := db.Where("id IN ?", teamIDs).Find(&teams).Error; err != nil {
+		return err
+	}
+
+	playerIDs := make([]uint, 0, len(teams)*2)
+	seenPlayers := make(map[uint]bool)
+	for _, team := range teams {
+		for _, playerID := range []uint{team.Player1ID, team.Player2ID} {
+			if !seenPlayers[playerID] {
+				seenPlayers[playerID] = true
+				playerIDs = append(playerIDs, playerID)
+			}
+		}
+	}
+
+	var players []models.Player
+	if len(playerIDs) > 0 {
+		if err := db.Where("id IN ?", playerIDs).Find(&players).Error; err != nil {
+			return err
+		}
+	}
+
+	playersByID := make(map[uint]models.Player, len(players))
+	for _, player := range players {
+		playersByID[player.ID] = player
+	}
+	teamsByID := make(map[uint]models.Team, len(teams))
+	for _, team := range teams {
+		team.Player1 = playersByID[team.Player1ID]
+		team.Player2 = playersByID[team.Player2ID]
+		teamsByID[team.ID] = team
+	}
+
+	for i := range matches {
+		matches[i].Team1 = teamsByID[matches[i].Team1ID]
+		matches[i].Team2 = teamsByID[matches[i].Team2ID]
+		matches[i].WinnerTeam = teamsByID[matches[i].WinnerTeamID]
+	}
+	return nil
+}
+
+// loadTeamMatch loads the relations of a single team match
+func loadTeamMatch(db *gorm.DB, match models.TeamMatch) (*models.TeamMatch, error) {
+	matches := []models.TeamMatch{match}
+	if err := loadTeamMatchRelations(db, matches); err != nil {
+		return nil, err
+	}
+	return &matches[0], nil
+}
+
 type TeamMatchFilters struct {
 	TeamID       *uint      `json:"team_id,omitempty"`
 	PlayerID     *uint      `json:"player_id,omitempty"`
@@ -126,21 +206,16 @@ func (s *TeamMatchService) GetTeamMatches(filters TeamMatchFilters) (*models.Pag
 		Offset(offset).
 		Limit(filters.PerPage).
 		Order("created_at DESC").
-		Preload("Team1").
-		Preload("Team1.Player1").
-		Preload("Team1.Player2").
-		Preload("Team2").
-		Preload("Team2.Player1").
-		Preload("Team2.Player2").
-		Preload("WinnerTeam").
-		Preload("WinnerTeam.Player1").
-		Preload("WinnerTeam.Player2").
 		Find(&matches)
 
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	if err := loadTeamMatchRelations(s.db, matches); err != nil {
+		return nil, err
+	}
+
 	// Calculate total pages
 	totalPages := int((total + int64(filters.PerPage) - 1) / int64(filters.PerPage))
 
@@ -153,7 +228,49 @@ func (s *TeamMatchService) GetTeamMatches(filters TeamMatchFilters) (*models.Pag
 	}, nil
 }
 
+// FindReplayedTeamMatch returns the team match already created with the client UUID of req, nil when the
+// UUID is new or not given. A UUID used for a different or deleted match is an error.
+func (s *TeamMatchService) FindReplayedTeamMatch(req models.CreateTeamMatchRequest) (*models.TeamMatch, error) {
+	if req.ClientUUID == nil {
+		return nil, nil
+	}
+
+	var match models.TeamMatch
+	if err := s.db.Unscoped().Where("client_uuid = ?", *req.ClientUUID).First(&match).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if match.DeletedAt.Valid || match.Team1ID != req.Team1ID || match.Team2ID != req.Team2ID || match.WinnerTeamID != req.WinnerTeamID {
+		return nil, errors.New("client_uuid already used")
+	}
+
+	return loadTeamMatch(s.db, match)
+}
+
 func (s *TeamMatchService) CreateTeamMatch(req models.CreateTeamMatchRequest) (*models.TeamMatch, error) {
+	var match *models.TeamMatch
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		match, err = s.createTeamMatchInTransaction(tx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the created match with relationships
+	if err := s.db.First(match, match.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return loadTeamMatch(s.db, *match)
+}
+
+// createTeamMatchInTransaction validates and creates a pending team match, so that callers can create it
+// together with their own changes
+func (s *TeamMatchService) createTeamMatchInTransaction(tx *gorm.DB, req models.CreateTeamMatchRequest) (*models.TeamMatch, error) {
 	// Validate that teams exist
 	team1, err := s.teamService.GetTeamByID(req.Team1ID)
 	if err != nil {
@@ -181,10 +298,17 @@ func (s *TeamMatchService) CreateTeamMatch(req models.CreateTeamMatchRequest) (*
 		return nil, errors.New("teams cannot share players")
 	}
 
+	// Archived players left the school, their profile is read-only
+	if team1.Player1.ArchivedAt != nil || team1.Player2.ArchivedAt != nil ||
+		team2.Player1.ArchivedAt != nil || team2.Player2.ArchivedAt != nil {
+		return nil, errors.New("player is archived")
+	}
+
 	// Validate tournament if provided
+	var stage *string
 	if req.TournamentID != nil {
 		var tournament models.Tournament
-		if err := s.db.First(&tournament, *req.TournamentID).Error; err != nil {
+		if err := tx.First(&tournament, *req.TournamentID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("tournament not found")
 			}
@@ -193,63 +317,67 @@ func (s *TeamMatchService) CreateTeamMatch(req models.CreateTeamMatchRequest) (*
 		if tournament.Status != "ongoing" {
 			return nil, errors.New("tournament is not ongoing")
 		}
-	}
 
-	// Start transaction
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+		// Multi-stage tournaments: group matches within a pool, knockout matches from the bracket
+		stage, err = s.tournamentService.ResolveTeamMatchStage(&tournament, req.Team1ID, req.Team2ID)
+		if err != nil {
+			return nil, err
 		}
-	}()
+	}
 
 	// Create the team match in pending status
-	now := time.Now()
+	now := s.clock.Now()
 	match := models.TeamMatch{
 		Team1ID:      req.Team1ID,
 		Team2ID:      req.Team2ID,
 		WinnerTeamID: req.WinnerTeamID,
 		TournamentID: req.TournamentID,
+		Stage:        stage,
 		Status:       "pending",
 		CreatedAt:    now,
+		ClientUUID:   req.ClientUUID,
 	}
 
 	if err := tx.Create(&match).Error; err != nil {
-		tx.Rollback()
+		// Only client_uuid is unique, the same request was sent twice at once
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("client_uuid already used")
+		}
 		return nil, err
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
+	return &match, nil
+}
+
+func (s *TeamMatchService) UpdateTeamMatchStatus(matchID uint, req models.UpdateTeamMatchStatusRequest) (*models.TeamMatch, error) {
+	var match *models.TeamMatch
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		match, err = s.updateTeamMatchStatusInTransaction(tx, matchID, req)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Load the created match with relationships
-	if err := s.db.Preload("Team1").Preload("Team1.Player1").Preload("Team1.Player2").
-		Preload("Team2").Preload("Team2.Player1").Preload("Team2.Player2").
-		Preload("WinnerTeam").Preload("WinnerTeam.Player1").Preload("WinnerTeam.Player2").
-		First(&match, match.ID).Error; err != nil {
+	s.statusUpdated(match)
+
+	// Load the updated match with relationships
+	if err := s.db.First(match, match.ID).Error; err != nil {
 		return nil, err
 	}
 
-	return &match, nil
+	return loadTeamMatch(s.db, *match)
 }
 
-func (s *TeamMatchService) UpdateTeamMatchStatus(matchID uint, req models.UpdateTeamMatchStatusRequest) (*models.TeamMatch, error) {
-	// Start transaction
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
+// updateTeamMatchStatusInTransaction applies a status update, and the ELO of a confirmation, so that callers
+// can apply it together with their own changes. statusUpdated must be called once committed.
+func (s *TeamMatchService) updateTeamMatchStatusInTransaction(tx *gorm.DB, matchID uint, req models.UpdateTeamMatchStatusRequest) (*models.TeamMatch, error) {
 	// Get the match
 	var match models.TeamMatch
 	if err := tx.Preload("Team1").Preload("Team1.Player1").Preload("Team1.Player2").
 		Preload("Team2").Preload("Team2.Player1").Preload("Team2.Player2").
 		First(&match, matchID).Error; err != nil {
-		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("team match not found")
 		}
@@ -258,21 +386,32 @@ func (s *TeamMatchService) UpdateTeamMatchStatus(matchID uint, req models.Update
 
 	// Check if match is still pending
 	if match.Status != "pending" {
-		tx.Rollback()
 		return nil, errors.New("team match is not pending")
 	}
 
 	// Update winner_team_id if provided
 	if req.WinnerTeamID != nil {
 		if *req.WinnerTeamID != match.Team1ID && *req.WinnerTeamID != match.Team2ID {
-			tx.Rollback()
 			return nil, errors.New("winner must be either team1 or team2")
 		}
+		if *req.WinnerTeamID != match.WinnerTeamID {
+			match.Disputed = true
+		}
 		match.WinnerTeamID = *req.WinnerTeamID
 	}
 
+	if req.PhotoURL != nil {
+		match.PhotoURL = req.PhotoURL
+	}
+
+	// Under a strict photo policy, the scoreboard photo comes before the confirmation
+	if req.Status != nil && *req.Status == "confirmed" && match.PhotoURL == nil &&
+		photoRequired(tx, s.photoPolicy, match.TournamentID, match.RefereeID, match.Disputed) {
+		return nil, errors.New("scoreboard photo required")
+	}
+
 	// Update status if provided
-	now := time.Now()
+	now := s.clock.Now()
 	if req.Status != nil {
 		match.Status = *req.Status
 		if *req.Status == "confirmed" {
@@ -281,23 +420,21 @@ func (s *TeamMatchService) UpdateTeamMatchStatus(matchID uint, req models.Update
 	}
 
 	if err := tx.Save(&match).Error; err != nil {
-		tx.Rollback()
 		return nil, err
 	}
 
 	// If confirmed, calculate team ELO and update stats
 	if match.Status == "confirmed" {
 		if err := s.updateTeamEloAndStats(tx, &match, now); err != nil {
-			tx.Rollback()
 			return nil, err
 		}
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
+	return &match, nil
+}
 
+// statusUpdated refreshes the ranks and tournament stats that depend on a committed status update
+func (s *TeamMatchService) statusUpdated(match *models.TeamMatch) {
 	// If match was confirmed, recalculate team ranks
 	if match.Status == "confirmed" {
 		if err := s.recalculateTeamRanks(); err != nil {
@@ -316,18 +453,11 @@ func (s *TeamMatchService) UpdateTeamMatchStatus(matchID uint, req models.Update
 			if err := s.tournamentService.UpdateTournamentTeamStats(*match.TournamentID, match.Team2ID, !isTeam1Winner); err != nil {
 				// Log error but don't fail the request
 			}
+			if err := s.tournamentService.RecordKnockoutResult(match); err != nil {
+				// Log error but don't fail the request
+			}
 		}
 	}
-
-	// Load the updated match with relationships
-	if err := s.db.Preload("Team1").Preload("Team1.Player1").Preload("Team1.Player2").
-		Preload("Team2").Preload("Team2.Player1").Preload("Team2.Player2").
-		Preload("WinnerTeam").Preload("WinnerTeam.Player1").Preload("WinnerTeam.Player2").
-		First(&match, match.ID).Error; err != nil {
-		return nil, err
-	}
-
-	return &match, nil
 }
 
 func (s *TeamMatchService) updateTeamEloAndStats(tx *gorm.DB, match *models.TeamMatch, now time.Time) error {
@@ -337,11 +467,21 @@ func (s *TeamMatchService) updateTeamEloAndStats(tx *gorm.DB, match *models.Team
 
 	isTeam1Winner := match.WinnerTeamID == match.Team1ID
 
-	// Calculate ELO changes for each player
-	team1Player1Change := utils.CalculateTeamEloChange(match.Team1.Player1.TeamEloRating, team2AvgElo, isTeam1Winner)
-	team1Player2Change := utils.CalculateTeamEloChange(match.Team1.Player2.TeamEloRating, team2AvgElo, isTeam1Winner)
-	team2Player1Change := utils.CalculateTeamEloChange(match.Team2.Player1.TeamEloRating, team1AvgElo, !isTeam1Winner)
-	team2Player2Change := utils.CalculateTeamEloChange(match.Team2.Player2.TeamEloRating, team1AvgElo, !isTeam1Winner)
+	// Tournament matches can weigh more (or less) than friendly matches
+	weight := tournamentEloWeight(tx, match.TournamentID)
+
+	// Calculate ELO changes for each player, under the same floor mode and calibration as the solo matches
+	team1Player1Change := utils.CalculateWeightedTeamEloChange(match.Team1.Player1.TeamEloRating, team2AvgElo, isTeam1Winner, weight, s.floorExempt(&match.Team1.Player1))
+	team1Player2Change := utils.CalculateWeightedTeamEloChange(match.Team1.Player2.TeamEloRating, team2AvgElo, isTeam1Winner, weight, s.floorExempt(&match.Team1.Player2))
+	team2Player1Change := utils.CalculateWeightedTeamEloChange(match.Team2.Player1.TeamEloRating, team1AvgElo, !isTeam1Winner, weight, s.floorExempt(&match.Team2.Player1))
+	team2Player2Change := utils.CalculateWeightedTeamEloChange(match.Team2.Player2.TeamEloRating, team1AvgElo, !isTeam1Winner, weight, s.floorExempt(&match.Team2.Player2))
+	if s.floorMode == utils.FloorModeZeroSum {
+		if isTeam1Winner {
+			team1Player1Change, team1Player2Change, team2Player1Change, team2Player2Change = utils.ZeroSumTeamEloChange(team1Player1Change, team1Player2Change, team2Player1Change, team2Player2Change)
+		} else {
+			team2Player1Change, team2Player2Change, team1Player1Change, team1Player2Change = utils.ZeroSumTeamEloChange(team2Player1Change, team2Player2Change, team1Player1Change, team1Player2Change)
+		}
+	}
 
 	// Calculate team ELO changes (average of the two players' changes)
 	team1EloChange := (team1Player1Change + team1Player2Change) / 2.0
@@ -393,58 +533,54 @@ func (s *TeamMatchService) updateTeamEloAndStats(tx *gorm.DB, match *models.Team
 		}
 	}
 
-	// Update player team ELO ratings and stats
-	playerUpdates := map[uint]map[string]interface{}{
-		match.Team1.Player1.ID: {
-			"team_elo_rating":    match.Team1.Player1.TeamEloRating + team1Player1Change,
-			"team_total_matches": match.Team1.Player1.TeamTotalMatches + 1,
-		},
-		match.Team1.Player2.ID: {
-			"team_elo_rating":    match.Team1.Player2.TeamEloRating + team1Player2Change,
-			"team_total_matches": match.Team1.Player2.TeamTotalMatches + 1,
-		},
-		match.Team2.Player1.ID: {
-			"team_elo_rating":    match.Team2.Player1.TeamEloRating + team2Player1Change,
-			"team_total_matches": match.Team2.Player1.TeamTotalMatches + 1,
-		},
-		match.Team2.Player2.ID: {
-			"team_elo_rating":    match.Team2.Player2.TeamEloRating + team2Player2Change,
-			"team_total_matches": match.Team2.Player2.TeamTotalMatches + 1,
-		},
-	}
-
-	// Update wins for winners
-	if isTeam1Winner {
-		playerUpdates[match.Team1.Player1.ID]["team_wins"] = match.Team1.Player1.TeamWins + 1
-		playerUpdates[match.Team1.Player2.ID]["team_wins"] = match.Team1.Player2.TeamWins + 1
-		playerUpdates[match.Team2.Player1.ID]["team_losses"] = match.Team2.Player1.TeamLosses + 1
-		playerUpdates[match.Team2.Player2.ID]["team_losses"] = match.Team2.Player2.TeamLosses + 1
-	} else {
-		playerUpdates[match.Team2.Player1.ID]["team_wins"] = match.Team2.Player1.TeamWins + 1
-		playerUpdates[match.Team2.Player2.ID]["team_wins"] = match.Team2.Player2.TeamWins + 1
-		playerUpdates[match.Team1.Player1.ID]["team_losses"] = match.Team1.Player1.TeamLosses + 1
-		playerUpdates[match.Team1.Player2.ID]["team_losses"] = match.Team1.Player2.TeamLosses + 1
-	}
-
-	// Apply all updates
-	for playerID, updates := range playerUpdates {
-		if err := tx.Model(&models.Player{}).Where("id = ?", playerID).Updates(updates).Error; err != nil {
+	// Update the team ladder of the players, copied back to their team columns by the database
+	results := []struct {
+		player models.Player
+		change float64
+		won    bool
+	}{
+		{match.Team1.Player1, team1Player1Change, isTeam1Winner},
+		{match.Team1.Player2, team1Player2Change, isTeam1Winner},
+		{match.Team2.Player1, team2Player1Change, !isTeam1Winner},
+		{match.Team2.Player2, team2Player2Change, !isTeam1Winner},
+	}
+	for _, result := range results {
+		rating := models.Rating{
+			PlayerID:     result.player.ID,
+			Ladder:       models.LadderTeam,
+			EloRating:    result.player.TeamEloRating + result.change,
+			TotalMatches: result.player.TeamTotalMatches + 1,
+			Wins:         result.player.TeamWins,
+			Losses:       result.player.TeamLosses,
+		}
+		if result.won {
+			rating.Wins++
+		} else {
+			rating.Losses++
+		}
+		if err := saveRating(tx, &rating); err != nil {
 			return err
 		}
 	}
 
 	// Update team statistics
-	if err := s.teamService.UpdateTeamStats(match.Team1ID, isTeam1Winner, team1EloChange); err != nil {
+	if err := s.teamService.UpdateTeamStats(tx, match.Team1ID, match.ID, match.Team2ID, isTeam1Winner, team1EloChange, now); err != nil {
 		return err
 	}
 
-	if err := s.teamService.UpdateTeamStats(match.Team2ID, !isTeam1Winner, team2EloChange); err != nil {
+	if err := s.teamService.UpdateTeamStats(tx, match.Team2ID, match.ID, match.Team1ID, !isTeam1Winner, team2EloChange, now); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// floorExempt reports whether the ELO floor does not hold the team rating of a player: the floor is
+// disabled or the player had fewer team matches than the calibration
+func (s *TeamMatchService) floorExempt(player *models.Player) bool {
+	return s.floorMode == utils.FloorModeNone || player.TeamTotalMatches < s.calibrationMatches
+}
+
 func (s *TeamMatchService) recalculateTeamRanks() error {
 	// Get all players sorted by team ELO rating descending
 	var players []models.Player
@@ -502,12 +638,9 @@ func (s *TeamMatchService) CancelTeamMatch(matchID uint) (*models.TeamMatch, err
 	}
 
 	// Load with relationships
-	if err := s.db.Preload("Team1").Preload("Team1.Player1").Preload("Team1.Player2").
-		Preload("Team2").Preload("Team2.Player1").Preload("Team2.Player2").
-		Preload("WinnerTeam").Preload("WinnerTeam.Player1").Preload("WinnerTeam.Player2").
-		First(&match, match.ID).Error; err != nil {
+	if err := s.db.First(&match, match.ID).Error; err != nil {
 		return nil, err
 	}
 
-	return &match, nil
+	return loadTeamMatch(s.db, match)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -138,10 +139,27 @@ func (s *TeamService) DeleteTeam(id uint) error {
 	return nil
 }
 
-func (s *TeamService) GetAllTeams(page int, pageSize int) (*models.PaginatedTeamsResponse, error) {
+func (s *TeamService) GetAllTeams(orderBy string, direction string, page int, pageSize int) (*models.PaginatedTeamsResponse, error) {
 	var teams []models.Team
 	var total int64
 
+	// Validate order by field
+	allowedOrderBy := map[string]bool{
+		"created_at": true,
+		"elo_rating": true,
+		"name":       true,
+		"win_rate":   true,
+	}
+
+	if !allowedOrderBy[orderBy] {
+		orderBy = "created_at"
+	}
+
+	// Validate direction
+	if direction != "ASC" && direction != "DESC" {
+		direction = "DESC"
+	}
+
 	// Count total records
 	if err := s.db.Model(&models.Team{}).Count(&total).Error; err != nil {
 		return nil, err
@@ -152,7 +170,7 @@ func (s *TeamService) GetAllTeams(page int, pageSize int) (*models.PaginatedTeam
 
 	// Get paginated teams
 	if err := s.db.Preload("Player1").Preload("Player2").
-		Order("created_at DESC").
+		Order(orderBy + " " + direction).
 		Offset(offset).
 		Limit(pageSize).
 		Find(&teams).Error; err != nil {
@@ -230,9 +248,11 @@ func (s *TeamService) GetTeamAverageElo(teamID uint) (float64, error) {
 	return (team.Player1.TeamEloRating + team.Player2.TeamEloRating) / 2.0, nil
 }
 
-func (s *TeamService) UpdateTeamStats(teamID uint, won bool, eloChange float64) error {
+// UpdateTeamStats applies the result of a confirmed team match to the record and rating of a team,
+// and writes the rating change to its history
+func (s *TeamService) UpdateTeamStats(tx *gorm.DB, teamID, teamMatchID, opponentTeamID uint, won bool, eloChange float64, at time.Time) error {
 	var team models.Team
-	if err := s.db.First(&team, teamID).Error; err != nil {
+	if err := tx.First(&team, teamID).Error; err != nil {
 		return err
 	}
 
@@ -247,7 +267,19 @@ func (s *TeamService) UpdateTeamStats(teamID uint, won bool, eloChange float64)
 		updates["losses"] = team.Losses + 1
 	}
 
-	return s.db.Model(team).Updates(updates).Error
+	if err := tx.Model(team).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.TeamRatingHistory{
+		TeamID:         team.ID,
+		TeamMatchID:    teamMatchID,
+		EloBefore:      team.EloRating,
+		EloAfter:       team.EloRating + eloChange,
+		EloChange:      eloChange,
+		OpponentTeamID: &opponentTeamID,
+		CreatedAt:      at,
+	}).Error
 }
 
 func (s *TeamService) GetTopTeamsByElo(limit int) ([]models.Team, error) {
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TitleService struct {
+	db *gorm.DB
+}
+
+func NewTitleService(db *gorm.DB) *TitleService {
+	return &TitleService{
+		db: db,
+	}
+}
+
+// GetTitles lists the titles by name
+func (s *TitleService) GetTitles() ([]models.Title, error) {
+	var titles []models.Title
+	if err := s.db.Order("name ASC").Find(&titles).Error; err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+func (s *TitleService) GetTitleByID(id uint) (*models.Title, error) {
+	var title models.Title
+	if err := s.db.First(&title, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("title not found")
+		}
+		return nil, err
+	}
+	return &title, nil
+}
+
+func (s *TitleService) CreateTitle(req models.CreateTitleRequest) (*models.Title, error) {
+	title := &models.Title{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := s.db.Create(title).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("title name already used")
+		}
+		return nil, err
+	}
+	return title, nil
+}
+
+// UpdateTitle renames a title or changes its description, for every player holding it
+func (s *TitleService) UpdateTitle(id uint, req models.UpdateTitleRequest) (*models.Title, error) {
+	if _, err := s.GetTitleByID(id); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&models.Title{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return nil, errors.New("title name already used")
+			}
+			return nil, err
+		}
+	}
+
+	return s.GetTitleByID(id)
+}
+
+// DeleteTitle removes a title from every player holding it, and from their flair
+func (s *TitleService) DeleteTitle(id uint) error {
+	result := s.db.Delete(&models.Title{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("title not found")
+	}
+	return nil
+}
+
+// GetPlayerTitles returns the titles held by a player, most recent first
+func (s *TitleService) GetPlayerTitles(playerID uint) ([]models.PlayerTitle, error) {
+	var titles []models.PlayerTitle
+	if err := s.db.Preload("Title").
+		Where("player_id = ?", playerID).
+		Order("awarded_at DESC").
+		Find(&titles).Error; err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+// AssignTitle gives a title to a player
+func (s *TitleService) AssignTitle(playerID, titleID uint) (*models.PlayerTitle, error) {
+	if err := s.db.First(&models.Player{}, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+	title, err := s.GetTitleByID(titleID)
+	if err != nil {
+		return nil, err
+	}
+
+	playerTitle := &models.PlayerTitle{
+		PlayerID:  playerID,
+		TitleID:   titleID,
+		AwardedAt: time.Now(),
+	}
+	if err := s.db.Create(playerTitle).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("title already held")
+		}
+		return nil, err
+	}
+
+	playerTitle.Title = title
+	return playerTitle, nil
+}
+
+// RevokeTitle takes a title back from a player, clearing their flair if they were showing it
+func (s *TitleService) RevokeTitle(playerID, titleID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("player_id = ? AND title_id = ?", playerID, titleID).Delete(&models.PlayerTitle{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("player title not found")
+		}
+
+		return tx.Model(&models.Player{}).
+			Where("id = ? AND flair_title_id = ?", playerID, titleID).
+			Update("flair_title_id", nil).Error
+	})
+}
+
+// SetFlair selects the title shown next to the player's name, among the ones they hold; nil shows none
+func (s *TitleService) SetFlair(playerID uint, titleID *uint) (*models.Player, error) {
+	var player models.Player
+	if err := s.db.First(&player, playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("player not found")
+		}
+		return nil, err
+	}
+	if player.ArchivedAt != nil {
+		return nil, errors.New("player is archived")
+	}
+
+	if titleID != nil {
+		var held int64
+		if err := s.db.Model(&models.PlayerTitle{}).
+			Where("player_id = ? AND title_id = ?", playerID, *titleID).
+			Count(&held).Error; err != nil {
+			return nil, err
+		}
+		if held == 0 {
+			return nil, errors.New("title not held")
+		}
+	}
+
+	if err := s.db.Model(&player).Update("flair_title_id", titleID).Error; err != nil {
+		return nil, err
+	}
+	player.FlairTitleID = titleID
+	return &player, nil
+}
+
+// awardChampionTitle gives the champion title of a finished tournament to both players of the
+// winning team. The title is shared with a title of the same name an admin created beforehand.
+func awardChampionTitle(tx *gorm.DB, tournament models.Tournament, winnerTeamID uint) error {
+	var team models.Team
+	if err := tx.Unscoped().First(&team, winnerTeamID).Error; err != nil {
+		return err
+	}
+
+	name := []rune("Champion " + tournament.Name)
+	if len(name) > 255 {
+		name = name[:255]
+	}
+
+	title := models.Title{Name: string(name)}
+	if err := tx.Where(models.Title{Name: title.Name}).
+		Attrs(models.Title{TournamentID: &tournament.ID}).
+		FirstOrCreate(&title).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, playerID := range []uint{team.Player1ID, team.Player2ID} {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.PlayerTitle{
+			PlayerID:  playerID,
+			TitleID:   title.ID,
+			AwardedAt: now,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TournamentRecurrenceService struct {
+	db                  *gorm.DB
+	tournamentService   *TournamentService
+	notificationService *NotificationService
+}
+
+func NewTournamentRecurrenceService(db *gorm.DB, notificationService *NotificationService) *TournamentRecurrenceService {
+	return &TournamentRecurrenceService{
+		db:                  db,
+		tournamentService:   NewTournamentService(db),
+		notificationService: notificationService,
+	}
+}
+
+func (s *TournamentRecurrenceService) GetRecurrences() ([]models.TournamentRecurrence, error) {
+	var recurrences []models.TournamentRecurrence
+	if err := s.db.Preload("Tournament").Order("next_run_at ASC").Find(&recurrences).Error; err != nil {
+		return nil, err
+	}
+	return recurrences, nil
+}
+
+func (s *TournamentRecurrenceService) GetRecurrenceByID(id uint) (*models.TournamentRecurrence, error) {
+	var recurrence models.TournamentRecurrence
+	if err := s.db.Preload("Tournament").First(&recurrence, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recurrence not found")
+		}
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+// CreateRecurrence makes an existing tournament the first edition of a recurring tournament
+func (s *TournamentRecurrenceService) CreateRecurrence(req models.CreateTournamentRecurrenceRequest) (*models.TournamentRecurrence, error) {
+	var tournament models.Tournament
+	if err := s.db.First(&tournament, req.TournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tournament not found")
+		}
+		return nil, err
+	}
+	if tournament.RecurrenceID != nil {
+		return nil, errors.New("tournament is already recurring")
+	}
+
+	recurrence := &models.TournamentRecurrence{
+		Name:         tournament.Name,
+		TournamentID: &tournament.ID,
+		Frequency:    models.RecurrenceWeekly,
+		Weekday:      *req.Weekday,
+		Hour:         18,
+		Timezone:     models.DefaultNotificationZone,
+		Edition:      1,
+		Active:       true,
+	}
+	if req.Frequency != "" {
+		recurrence.Frequency = req.Frequency
+	}
+	if req.Hour != nil {
+		recurrence.Hour = *req.Hour
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return nil, errors.New("invalid timezone")
+		}
+		recurrence.Timezone = req.Timezone
+	}
+	recurrence.NextRunAt = recurrence.NextRunAfter(time.Now())
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(recurrence).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Tournament{}).Where("id = ?", tournament.ID).
+			Updates(map[string]interface{}{"recurrence_id": recurrence.ID, "edition": 1}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRecurrenceByID(recurrence.ID)
+}
+
+func (s *TournamentRecurrenceService) UpdateRecurrence(id uint, req models.UpdateTournamentRecurrenceRequest) (*models.TournamentRecurrence, error) {
+	recurrence, err := s.GetRecurrenceByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		recurrence.Name = *req.Name
+	}
+	if req.Active != nil {
+		recurrence.Active = *req.Active
+	}
+
+	rescheduled := false
+	if req.Frequency != nil {
+		recurrence.Frequency = *req.Frequency
+		rescheduled = true
+	}
+	if req.Weekday != nil {
+		recurrence.Weekday = *req.Weekday
+		rescheduled = true
+	}
+	if req.Hour != nil {
+		recurrence.Hour = *req.Hour
+		rescheduled = true
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return nil, errors.New("invalid timezone")
+		}
+		recurrence.Timezone = *req.Timezone
+		rescheduled = true
+	}
+	if rescheduled {
+		recurrence.NextRunAt = time.Time{}
+		recurrence.NextRunAt = recurrence.NextRunAfter(time.Now())
+	}
+
+	if err := s.db.Model(&models.TournamentRecurrence{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":        recurrence.Name,
+		"active":      recurrence.Active,
+		"frequency":   recurrence.Frequency,
+		"weekday":     recurrence.Weekday,
+		"hour":        recurrence.Hour,
+		"timezone":    recurrence.Timezone,
+		"next_run_at": recurrence.NextRunAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetRecurrenceByID(id)
+}
+
+// DeleteRecurrence stops a recurring tournament, existing editions are kept
+func (s *TournamentRecurrenceService) DeleteRecurrence(id uint) error {
+	result := s.db.Delete(&models.TournamentRecurrence{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("recurrence not found")
+	}
+
+	return nil
+}
+
+// Subscribe registers a player to be notified of every new edition
+func (s *TournamentRecurrenceService) Subscribe(recurrenceID, playerID uint) error {
+	if _, err := s.GetRecurrenceByID(recurrenceID); err != nil {
+		return err
+	}
+
+	var existing int64
+	if err := s.db.Model(&models.TournamentRecurrenceSubscription{}).
+		Where("recurrence_id = ? AND player_id = ?", recurrenceID, playerID).
+		Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	return s.db.Create(&models.TournamentRecurrenceSubscription{
+		RecurrenceID: recurrenceID,
+		PlayerID:     playerID,
+	}).Error
+}
+
+func (s *TournamentRecurrenceService) Unsubscribe(recurrenceID, playerID uint) error {
+	return s.db.Where("recurrence_id = ? AND player_id = ?", recurrenceID, playerID).
+		Delete(&models.TournamentRecurrenceSubscription{}).Error
+}
+
+// CreateDueEditions creates the next edition of every active recurrence whose run time has passed
+func (s *TournamentRecurrenceService) CreateDueEditions(now time.Time) (int, error) {
+	var recurrences []models.TournamentRecurrence
+	if err := s.db.Where("active = ? AND next_run_at <= ?", true, now).Find(&recurrences).Error; err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for i := range recurrences {
+		if _, err := s.createNextEdition(&recurrences[i], now); err != nil {
+			log.Printf("Error creating next edition of recurring tournament %d: %v", recurrences[i].ID, err)
+			continue
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// CreateNextEdition creates the next edition right away, without changing the schedule
+func (s *TournamentRecurrenceService) CreateNextEdition(id uint) (*models.Tournament, error) {
+	recurrence, err := s.GetRecurrenceByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createNextEdition(recurrence, time.Time{})
+}
+
+// createNextEdition copies the settings of the latest edition into a new opened tournament.
+// When now is set, the recurrence is also moved to its next run.
+func (s *TournamentRecurrenceService) createNextEdition(recurrence *models.TournamentRecurrence, now time.Time) (*models.Tournament, error) {
+	if recurrence.TournamentID == nil {
+		return nil, errors.New("latest edition was deleted")
+	}
+
+	var previous models.Tournament
+	if err := s.db.First(&previous, *recurrence.TournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("latest edition was deleted")
+		}
+		return nil, err
+	}
+
+	edition := recurrence.Edition + 1
+	tournament, err := s.tournamentService.CreateTournament(models.CreateTournamentRequest{
+		Name:                  fmt.Sprintf("%s #%d", recurrence.Name, edition),
+		Type:                  previous.Type,
+		Description:           previous.Description,
+		MatchTimeLimitSeconds: previous.MatchTimeLimitSeconds,
+		GoldenGoal:            previous.GoldenGoal,
+		Format:                previous.Format,
+		PoolCount:             previous.PoolCount,
+		QualifiersPerPool:     previous.QualifiersPerPool,
+		MaxParticipants:       previous.MaxParticipants,
+		EloWeight:             previous.EloWeight,
+		EntryFeeCents:         previous.EntryFeeCents,
+		Currency:              previous.Currency,
+		TrophyImageURL:        previous.TrophyImageURL,
+		PhotoPolicy:           previous.PhotoPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"tournament_id": tournament.ID,
+		"edition":       edition,
+	}
+	if !now.IsZero() {
+		updates["next_run_at"] = recurrence.NextRunAfter(now)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Tournament{}).Where("id = ?", tournament.ID).Updates(map[string]interface{}{
+			"recurrence_id": recurrence.ID,
+			"edition":       edition,
+			"template_id":   previous.TemplateID,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TournamentRecurrence{}).Where("id = ?", recurrence.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tournament.RecurrenceID = &recurrence.ID
+	tournament.Edition = &edition
+	tournament.TemplateID = previous.TemplateID
+
+	var subscriberIDs []uint
+	if err := s.db.Model(&models.TournamentRecurrenceSubscription{}).
+		Where("recurrence_id = ?", recurrence.ID).
+		Pluck("player_id", &subscriberIDs).Error; err != nil {
+		log.Printf("Error loading subscribers of recurring tournament %d: %v", recurrence.ID, err)
+	}
+	s.notificationService.NotifyTournamentEditionCreated(tournament, subscriberIDs)
+
+	return tournament, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -21,18 +22,65 @@ func NewTournamentService(db *gorm.DB) *TournamentService {
 }
 
 func (s *TournamentService) CreateTournament(req models.CreateTournamentRequest) (*models.Tournament, error) {
-	slug := s.generateUniqueSlug(req.Name)
+	tournament, err := s.buildTournament(req)
+	if err != nil {
+		return nil, err
+	}
+	tournament.Slug = s.generateUniqueSlug(req.Name)
+
+	if err := s.db.Create(tournament).Error; err != nil {
+		return nil, err
+	}
 
+	return tournament, nil
+}
+
+// buildTournament validates the tournament configuration and applies the defaults
+func (s *TournamentService) buildTournament(req models.CreateTournamentRequest) (*models.Tournament, error) {
 	tournament := &models.Tournament{
 		Name:        req.Name,
-		Slug:        slug,
 		Type:        req.Type,
 		Status:      "opened",
 		Description: req.Description,
+		GoldenGoal:  req.GoldenGoal,
+	}
+	if req.MatchTimeLimitSeconds != nil && *req.MatchTimeLimitSeconds > 0 {
+		tournament.MatchTimeLimitSeconds = req.MatchTimeLimitSeconds
 	}
 
-	if err := s.db.Create(tournament).Error; err != nil {
-		return nil, err
+	tournament.Format = models.TournamentFormatSingle
+	if req.Format == models.TournamentFormatGroupsKnockout {
+		if req.Type != "team" {
+			return nil, errors.New("group stage is only available for team tournaments")
+		}
+		if req.PoolCount < 1 || req.QualifiersPerPool < 1 {
+			return nil, errors.New("pool_count and qualifiers_per_pool are required for a groups_knockout tournament")
+		}
+		tournament.Format = models.TournamentFormatGroupsKnockout
+		tournament.PoolCount = req.PoolCount
+		tournament.QualifiersPerPool = req.QualifiersPerPool
+	}
+
+	tournament.EloWeight = 1
+	if req.EloWeight > 0 {
+		tournament.EloWeight = req.EloWeight
+	}
+	if req.MaxParticipants != nil && *req.MaxParticipants > 0 {
+		tournament.MaxParticipants = req.MaxParticipants
+	}
+	tournament.RegistrationDeadline = req.RegistrationDeadline
+
+	if req.EntryFeeCents != nil && *req.EntryFeeCents > 0 {
+		tournament.EntryFeeCents = req.EntryFeeCents
+	}
+	tournament.Currency = "EUR"
+	if req.Currency != "" {
+		tournament.Currency = strings.ToUpper(req.Currency)
+	}
+	tournament.TrophyImageURL = req.TrophyImageURL
+	tournament.PhotoPolicy = models.PhotoPolicyNone
+	if req.PhotoPolicy != "" {
+		tournament.PhotoPolicy = req.PhotoPolicy
 	}
 
 	return tournament, nil
@@ -120,6 +168,63 @@ func (s *TournamentService) UpdateTournament(id uint, req models.UpdateTournamen
 	if req.Description != nil {
 		updates["description"] = *req.Description
 	}
+	if req.MatchTimeLimitSeconds != nil {
+		if *req.MatchTimeLimitSeconds > 0 {
+			updates["match_time_limit_seconds"] = *req.MatchTimeLimitSeconds
+		} else {
+			updates["match_time_limit_seconds"] = nil
+		}
+	}
+	if req.GoldenGoal != nil {
+		updates["golden_goal"] = *req.GoldenGoal
+	}
+	if req.MaxParticipants != nil {
+		if *req.MaxParticipants > 0 {
+			updates["max_participants"] = *req.MaxParticipants
+		} else {
+			updates["max_participants"] = nil
+		}
+	}
+	if req.EloWeight != nil {
+		updates["elo_weight"] = *req.EloWeight
+	}
+	if req.EntryFeeCents != nil {
+		if *req.EntryFeeCents > 0 {
+			updates["entry_fee_cents"] = *req.EntryFeeCents
+		} else {
+			updates["entry_fee_cents"] = nil
+		}
+	}
+	if req.Currency != nil {
+		updates["currency"] = strings.ToUpper(*req.Currency)
+	}
+	if req.PhotoPolicy != nil {
+		updates["photo_policy"] = *req.PhotoPolicy
+	}
+	if req.TrophyImageURL != nil {
+		updates["trophy_image_url"] = *req.TrophyImageURL
+	}
+	if req.RegistrationDeadline != nil {
+		if req.RegistrationDeadline.IsZero() {
+			updates["registration_deadline"] = nil
+		} else {
+			updates["registration_deadline"] = *req.RegistrationDeadline
+		}
+	}
+	if req.PoolCount != nil || req.QualifiersPerPool != nil {
+		if tournament.Format != models.TournamentFormatGroupsKnockout {
+			return nil, errors.New("tournament has no group stage")
+		}
+		if tournament.Stage != nil {
+			return nil, errors.New("pools are already assigned")
+		}
+		if req.PoolCount != nil {
+			updates["pool_count"] = *req.PoolCount
+		}
+		if req.QualifiersPerPool != nil {
+			updates["qualifiers_per_pool"] = *req.QualifiersPerPool
+		}
+	}
 	if req.Status != nil {
 		validTransitions := map[string]string{
 			"opened":  "ongoing",
@@ -133,7 +238,16 @@ func (s *TournamentService) UpdateTournament(id uint, req models.UpdateTournamen
 	}
 
 	if len(updates) > 0 {
-		if err := s.db.Model(&models.Tournament{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.Tournament{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+				return err
+			}
+			if updates["status"] == "finished" {
+				return s.awardTournamentTrophy(tx, id)
+			}
+			return nil
+		})
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -141,8 +255,58 @@ func (s *TournamentService) UpdateTournament(id uint, req models.UpdateTournamen
 	return s.GetTournamentByID(id)
 }
 
-func (s *TournamentService) JoinTournament(tournamentID, teamID, userID uint) (*models.TournamentTeam, error) {
-	// 1. Tournament must exist and be opened
+// awardTournamentTrophy writes the trophy of a finished tournament for its winning team, and gives
+// its players the champion title. The winner is the knockout champion, or the team with the best
+// record in a single stage tournament; nothing is awarded if there is no clear winner.
+func (s *TournamentService) awardTournamentTrophy(tx *gorm.DB, tournamentID uint) error {
+	var tournament models.Tournament
+	if err := tx.First(&tournament, tournamentID).Error; err != nil {
+		return err
+	}
+
+	var winnerTeamID *uint
+	if tournament.Format == models.TournamentFormatGroupsKnockout {
+		var final models.TournamentBracketMatch
+		err := tx.Where("tournament_id = ?", tournamentID).Order("round DESC").First(&final).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		winnerTeamID = final.WinnerTeamID
+	} else {
+		var leaders []models.TournamentTeam
+		if err := tx.Where("tournament_id = ?", tournamentID).
+			Order("wins DESC, losses ASC").
+			Limit(2).
+			Find(&leaders).Error; err != nil {
+			return err
+		}
+		if len(leaders) == 1 || (len(leaders) == 2 &&
+			(leaders[0].Wins != leaders[1].Wins || leaders[0].Losses != leaders[1].Losses)) {
+			winnerTeamID = &leaders[0].TeamID
+		}
+	}
+
+	if winnerTeamID == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if err := tx.Create(&models.Trophy{
+		Name:         tournament.Name,
+		ImageURL:     tournament.TrophyImageURL,
+		Season:       models.SeasonOf(now),
+		TournamentID: &tournament.ID,
+		WinnerTeamID: winnerTeamID,
+		AwardedAt:    now,
+	}).Error; err != nil {
+		return err
+	}
+
+	return awardChampionTitle(tx, tournament, *winnerTeamID)
+}
+
+// getOpenTournament returns a tournament that still accepts registrations
+func (s *TournamentService) getOpenTournament(tournamentID uint) (*models.Tournament, error) {
 	var tournament models.Tournament
 	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -155,27 +319,41 @@ func (s *TournamentService) JoinTournament(tournamentID, teamID, userID uint) (*
 		return nil, errors.New("tournament is not open for registration")
 	}
 
-	// 2. Team must exist
+	if tournament.RegistrationDeadline != nil && time.Now().After(*tournament.RegistrationDeadline) {
+		return nil, errors.New("registration deadline has passed")
+	}
+
+	return &tournament, nil
+}
+
+// checkRegistration validates that a team can register (or join the waitlist) for a tournament
+func (s *TournamentService) checkRegistration(tournamentID, teamID, userID uint) error {
+	// Team must exist
 	var team models.Team
 	if err := s.db.First(&team, teamID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("team not found")
+			return errors.New("team not found")
 		}
-		return nil, err
+		return err
 	}
 
-	// 3. User must be a member of the team
+	// User must be a member of the team
 	if team.Player1ID != userID && team.Player2ID != userID {
-		return nil, errors.New("you must be a member of the team to join a tournament")
+		return errors.New("you must be a member of the team to join a tournament")
 	}
 
-	// 4. Team must not already be registered
+	// Team must not already be registered or waiting
 	var existingEntry models.TournamentTeam
 	if err := s.db.Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).First(&existingEntry).Error; err == nil {
-		return nil, errors.New("team is already registered in this tournament")
+		return errors.New("team is already registered in this tournament")
+	}
+
+	var existingWaitlistEntry models.TournamentWaitlistEntry
+	if err := s.db.Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).First(&existingWaitlistEntry).Error; err == nil {
+		return errors.New("team is already registered on the waitlist")
 	}
 
-	// 5. No player from this team should already be in the tournament via another team
+	// No player from this team should already be in the tournament (or its waitlist) via another team
 	var existingTeam models.Team
 	err := s.db.Model(&models.Team{}).
 		Joins("JOIN tournament_teams ON tournament_teams.team_id = teams.id").
@@ -184,7 +362,34 @@ func (s *TournamentService) JoinTournament(tournamentID, teamID, userID uint) (*
 		First(&existingTeam).Error
 
 	if err == nil {
-		return nil, fmt.Errorf("already registered in this tournament with team %s", existingTeam.Name)
+		return fmt.Errorf("already registered in this tournament with team %s", existingTeam.Name)
+	}
+
+	err = s.db.Model(&models.Team{}).
+		Joins("JOIN tournament_waitlist_entries ON tournament_waitlist_entries.team_id = teams.id").
+		Where("tournament_waitlist_entries.tournament_id = ? AND (teams.player1_id IN (?, ?) OR teams.player2_id IN (?, ?))",
+			tournamentID, team.Player1ID, team.Player2ID, team.Player1ID, team.Player2ID).
+		First(&existingTeam).Error
+
+	if err == nil {
+		return fmt.Errorf("already registered on the waitlist with team %s", existingTeam.Name)
+	}
+
+	return nil
+}
+
+func (s *TournamentService) JoinTournament(tournamentID, teamID, userID uint) (*models.TournamentTeam, error) {
+	tournament, err := s.getOpenTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRegistration(tournamentID, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if tournament.MaxParticipants != nil && tournament.NbParticipants >= *tournament.MaxParticipants {
+		return nil, errors.New("tournament is full")
 	}
 
 	tournamentTeam := &models.TournamentTeam{
@@ -212,49 +417,170 @@ func (s *TournamentService) JoinTournament(tournamentID, teamID, userID uint) (*
 	return tournamentTeam, nil
 }
 
-func (s *TournamentService) LeaveTournament(tournamentID, teamID, userID uint) error {
+// JoinWaitlist puts a team at the end of the waitlist of a full tournament
+func (s *TournamentService) JoinWaitlist(tournamentID, teamID, userID uint) (*models.TournamentWaitlistItem, error) {
+	tournament, err := s.getOpenTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRegistration(tournamentID, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if tournament.MaxParticipants == nil || tournament.NbParticipants < *tournament.MaxParticipants {
+		return nil, errors.New("tournament is not full")
+	}
+
+	entry := &models.TournamentWaitlistEntry{
+		TournamentID: tournamentID,
+		TeamID:       teamID,
+	}
+	if err := s.db.Create(entry).Error; err != nil {
+		return nil, err
+	}
+
+	waitlist, err := s.GetWaitlist(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range waitlist {
+		if item.TeamID == teamID {
+			return &item, nil
+		}
+	}
+
+	return nil, errors.New("team is not on the waitlist")
+}
+
+// GetWaitlist returns the waitlist of a tournament in promotion order
+func (s *TournamentService) GetWaitlist(tournamentID uint) ([]models.TournamentWaitlistItem, error) {
+	var entries []models.TournamentWaitlistEntry
+	if err := s.db.Where("tournament_id = ?", tournamentID).
+		Preload("Team").
+		Preload("Team.Player1").
+		Preload("Team.Player2").
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]models.TournamentWaitlistItem, len(entries))
+	for i, entry := range entries {
+		items[i] = models.TournamentWaitlistItem{
+			Position:  i + 1,
+			TeamID:    entry.TeamID,
+			Team:      entry.Team,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	return items, nil
+}
+
+// LeaveTournament removes a team from a tournament or from its waitlist. When a registered team
+// withdraws, the first team of the waitlist takes its place and is returned.
+func (s *TournamentService) LeaveTournament(tournamentID, teamID, userID uint) (*models.TournamentTeam, error) {
 	// Tournament must exist and be opened
 	var tournament models.Tournament
 	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("tournament not found")
+			return nil, errors.New("tournament not found")
 		}
-		return err
+		return nil, err
 	}
 
 	if tournament.Status != "opened" {
-		return errors.New("tournament is not open for registration")
+		return nil, errors.New("tournament is not open for registration")
 	}
 
 	// Team must exist
 	var team models.Team
 	if err := s.db.First(&team, teamID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("team not found")
+			return nil, errors.New("team not found")
 		}
-		return err
+		return nil, err
 	}
 
 	// User must be a member of the team
 	if team.Player1ID != userID && team.Player2ID != userID {
-		return errors.New("you must be a member of the team to leave a tournament")
+		return nil, errors.New("you must be a member of the team to leave a tournament")
+	}
+
+	// Leaving the waitlist does not free a spot
+	waitlistResult := s.db.Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).Delete(&models.TournamentWaitlistEntry{})
+	if waitlistResult.Error != nil {
+		return nil, waitlistResult.Error
+	}
+	if waitlistResult.RowsAffected > 0 {
+		return nil, nil
 	}
 
 	// Find and delete the registration
 	result := s.db.Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).Delete(&models.TournamentTeam{})
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("team is not registered in this tournament")
+		return nil, errors.New("team is not registered in this tournament")
 	}
 
 	// Decrement nb_participants
 	s.db.Model(&models.Tournament{}).Where("id = ? AND nb_participants > 0", tournamentID).
 		Update("nb_participants", gorm.Expr("nb_participants - 1"))
 
-	return nil
+	// Promote the first team of the waitlist, unless registrations are closed
+	if tournament.RegistrationDeadline != nil && time.Now().After(*tournament.RegistrationDeadline) {
+		return nil, nil
+	}
+
+	return s.promoteFromWaitlist(tournamentID)
+}
+
+// promoteFromWaitlist registers the first team of the waitlist, if any
+func (s *TournamentService) promoteFromWaitlist(tournamentID uint) (*models.TournamentTeam, error) {
+	var promoted *models.TournamentTeam
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var entry models.TournamentWaitlistEntry
+		if err := tx.Where("tournament_id = ?", tournamentID).
+			Order("created_at ASC, id ASC").
+			First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		tournamentTeam := &models.TournamentTeam{
+			TournamentID: tournamentID,
+			TeamID:       entry.TeamID,
+		}
+		if err := tx.Create(tournamentTeam).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entry).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Tournament{}).Where("id = ?", tournamentID).
+			Update("nb_participants", gorm.Expr("nb_participants + 1")).Error; err != nil {
+			return err
+		}
+
+		promoted = tournamentTeam
+		return nil
+	})
+	if err != nil || promoted == nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Team").First(promoted, promoted.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
 }
 
 func (s *TournamentService) GetTournamentTeams(tournamentID uint, page, pageSize int) (*models.PaginatedTournamentTeamsResponse, error) {
@@ -283,11 +609,13 @@ func (s *TournamentService) GetTournamentTeams(tournamentID uint, page, pageSize
 	items := make([]models.TournamentTeamItem, len(tournamentTeams))
 	for i, tt := range tournamentTeams {
 		items[i] = models.TournamentTeamItem{
-			ID:     tt.ID,
-			TeamID: tt.TeamID,
-			Wins:   tt.Wins,
-			Losses: tt.Losses,
-			Team:   tt.Team,
+			ID:            tt.ID,
+			TeamID:        tt.TeamID,
+			Wins:          tt.Wins,
+			Losses:        tt.Losses,
+			Pool:          tt.Pool,
+			PaymentStatus: tt.PaymentStatus,
+			Team:          tt.Team,
 		}
 	}
 
@@ -302,6 +630,109 @@ func (s *TournamentService) GetTournamentTeams(tournamentID uint, page, pageSize
 	}, nil
 }
 
+// UpdateEntryPayment sets the payment status of a team's entry fee
+func (s *TournamentService) UpdateEntryPayment(tournamentID, teamID uint, req models.UpdateEntryPaymentRequest, actorID uint) (*models.TournamentTeam, error) {
+	var tournamentTeam models.TournamentTeam
+	if err := s.db.Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).First(&tournamentTeam).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("team is not registered in this tournament")
+		}
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"payment_status":     req.Status,
+		"payment_updated_by": actorID,
+	}
+	if req.Status == models.PaymentStatusPaid {
+		if tournamentTeam.PaidAt == nil {
+			updates["paid_at"] = time.Now()
+		}
+	} else {
+		updates["paid_at"] = nil
+	}
+	if req.Note != nil {
+		updates["payment_note"] = *req.Note
+	}
+
+	if err := s.db.Model(&tournamentTeam).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Team").First(&tournamentTeam, tournamentTeam.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return &tournamentTeam, nil
+}
+
+// GetFeesSummary returns the collected and outstanding entry fees of a tournament
+func (s *TournamentService) GetFeesSummary(tournamentID uint) (*models.TournamentFeesSummary, error) {
+	var tournament models.Tournament
+	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tournament not found")
+		}
+		return nil, err
+	}
+
+	var tournamentTeams []models.TournamentTeam
+	if err := s.db.Where("tournament_id = ?", tournamentID).
+		Preload("Team").
+		Order("created_at ASC").
+		Find(&tournamentTeams).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &models.TournamentFeesSummary{
+		TournamentID: tournamentID,
+		Currency:     tournament.Currency,
+		NbEntries:    len(tournamentTeams),
+		Entries:      make([]models.EntryPaymentItem, len(tournamentTeams)),
+	}
+	if tournament.EntryFeeCents != nil {
+		summary.EntryFeeCents = *tournament.EntryFeeCents
+	}
+
+	for i, tt := range tournamentTeams {
+		switch tt.PaymentStatus {
+		case models.PaymentStatusPaid:
+			summary.NbPaid++
+		case models.PaymentStatusWaived:
+			summary.NbWaived++
+		default:
+			summary.NbUnpaid++
+		}
+
+		summary.Entries[i] = models.EntryPaymentItem{
+			TeamID:        tt.TeamID,
+			TeamName:      tt.Team.Name,
+			PaymentStatus: tt.PaymentStatus,
+			PaidAt:        tt.PaidAt,
+			PaymentNote:   tt.PaymentNote,
+		}
+	}
+
+	summary.ExpectedCents = (summary.NbPaid + summary.NbUnpaid) * summary.EntryFeeCents
+	summary.CollectedCents = summary.NbPaid * summary.EntryFeeCents
+	summary.OutstandingCents = summary.NbUnpaid * summary.EntryFeeCents
+
+	return summary, nil
+}
+
+// tournamentEloWeight returns the ELO multiplier of a match's tournament (1 outside tournaments)
+func tournamentEloWeight(db *gorm.DB, tournamentID *uint) float64 {
+	if tournamentID == nil {
+		return 1
+	}
+
+	var weight float64
+	if err := db.Model(&models.Tournament{}).Where("id = ?", *tournamentID).Pluck("elo_weight", &weight).Error; err != nil || weight <= 0 {
+		return 1
+	}
+	return weight
+}
+
 // UpdateTournamentTeamStats updates wins/losses for a team in a tournament
 func (s *TournamentService) UpdateTournamentTeamStats(tournamentID, teamID uint, won bool) error {
 	updates := map[string]interface{}{}
@@ -337,15 +768,6 @@ func (s *TournamentService) GetTournamentMatches(tournamentID uint, page, pageSi
 	offset := (page - 1) * pageSize
 
 	if err := s.db.Where("tournament_id = ?", tournamentID).
-		Preload("Team1").
-		Preload("Team1.Player1").
-		Preload("Team1.Player2").
-		Preload("Team2").
-		Preload("Team2.Player1").
-		Preload("Team2.Player2").
-		Preload("WinnerTeam").
-		Preload("WinnerTeam.Player1").
-		Preload("WinnerTeam.Player2").
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(pageSize).
@@ -353,6 +775,10 @@ func (s *TournamentService) GetTournamentMatches(tournamentID uint, page, pageSi
 		return nil, err
 	}
 
+	if err := loadTeamMatchRelations(s.db, matches); err != nil {
+		return nil, err
+	}
+
 	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
 
 	return &models.PaginatedTeamMatchResponse{
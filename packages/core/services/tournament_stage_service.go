@@ -0,0 +1,470 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// poolNames returns the names of the first count pools (A, B, C...)
+func poolNames(count int) []string {
+	names := make([]string, count)
+	for i := range names {
+		names[i] = string(rune('A' + i))
+	}
+	return names
+}
+
+func (s *TournamentService) getMultiStageTournament(tournamentID uint) (*models.Tournament, error) {
+	var tournament models.Tournament
+	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tournament not found")
+		}
+		return nil, err
+	}
+
+	if tournament.Format != models.TournamentFormatGroupsKnockout {
+		return nil, errors.New("tournament has no group stage")
+	}
+
+	return &tournament, nil
+}
+
+// AssignPools assigns the registered teams to the group stage pools, either from the
+// given assignments or automatically by team ELO, and opens the group stage
+func (s *TournamentService) AssignPools(tournamentID uint, req models.AssignPoolsRequest) (*models.TournamentStandingsResponse, error) {
+	tournament, err := s.getMultiStageTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Stage != nil && *tournament.Stage == models.TournamentStageKnockout {
+		return nil, errors.New("knockout stage has already started")
+	}
+
+	var groupMatches int64
+	if err := s.db.Model(&models.TeamMatch{}).
+		Where("tournament_id = ? AND stage = ? AND status IN ?", tournamentID, models.TournamentStageGroup, []string{"pending", "confirmed"}).
+		Count(&groupMatches).Error; err != nil {
+		return nil, err
+	}
+	if groupMatches > 0 {
+		return nil, errors.New("group stage has already started")
+	}
+
+	var tournamentTeams []models.TournamentTeam
+	if err := s.db.Where("tournament_id = ?", tournamentID).Preload("Team").Find(&tournamentTeams).Error; err != nil {
+		return nil, err
+	}
+	if len(tournamentTeams) < 2*tournament.PoolCount {
+		return nil, errors.New("not enough teams to fill the pools")
+	}
+
+	names := poolNames(tournament.PoolCount)
+	pools := make(map[uint]string, len(tournamentTeams))
+
+	if len(req.Assignments) > 0 {
+		validPools := make(map[string]bool, len(names))
+		for _, name := range names {
+			validPools[name] = true
+		}
+		registered := make(map[uint]bool, len(tournamentTeams))
+		for _, tt := range tournamentTeams {
+			registered[tt.TeamID] = true
+		}
+
+		for _, assignment := range req.Assignments {
+			if !registered[assignment.TeamID] {
+				return nil, fmt.Errorf("team %d is not registered in this tournament", assignment.TeamID)
+			}
+			if !validPools[assignment.Pool] {
+				return nil, fmt.Errorf("unknown pool %s", assignment.Pool)
+			}
+			pools[assignment.TeamID] = assignment.Pool
+		}
+		if len(pools) != len(tournamentTeams) {
+			return nil, errors.New("all registered teams must be assigned to a pool")
+		}
+	} else {
+		// Snake seeding: A B C C B A A B C... so that pools have a similar level
+		sort.SliceStable(tournamentTeams, func(i, j int) bool {
+			return tournamentTeams[i].Team.EloRating > tournamentTeams[j].Team.EloRating
+		})
+		for i, tt := range tournamentTeams {
+			index := i % len(names)
+			if (i/len(names))%2 == 1 {
+				index = len(names) - 1 - index
+			}
+			pools[tt.TeamID] = names[index]
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for teamID, pool := range pools {
+			if err := tx.Model(&models.TournamentTeam{}).
+				Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).
+				Update("pool", pool).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Tournament{}).Where("id = ?", tournamentID).
+			Update("stage", models.TournamentStageGroup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStandings(tournamentID)
+}
+
+// ResolveTeamMatchStage checks that a tournament team match fits the current stage and returns
+// that stage (nil for single stage tournaments)
+func (s *TournamentService) ResolveTeamMatchStage(tournament *models.Tournament, team1ID, team2ID uint) (*string, error) {
+	if tournament.Format != models.TournamentFormatGroupsKnockout {
+		return nil, nil
+	}
+	if tournament.Stage == nil {
+		return nil, errors.New("pools are not assigned yet")
+	}
+
+	stage := *tournament.Stage
+	switch stage {
+	case models.TournamentStageGroup:
+		var tournamentTeams []models.TournamentTeam
+		if err := s.db.Where("tournament_id = ? AND team_id IN ?", tournament.ID, []uint{team1ID, team2ID}).
+			Find(&tournamentTeams).Error; err != nil {
+			return nil, err
+		}
+		if len(tournamentTeams) != 2 || tournamentTeams[0].Pool == nil || tournamentTeams[1].Pool == nil ||
+			*tournamentTeams[0].Pool != *tournamentTeams[1].Pool {
+			return nil, errors.New("teams are not in the same pool")
+		}
+	case models.TournamentStageKnockout:
+		if _, err := s.findOpenBracketMatch(s.db, tournament.ID, team1ID, team2ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &stage, nil
+}
+
+func (s *TournamentService) findOpenBracketMatch(db *gorm.DB, tournamentID, team1ID, team2ID uint) (*models.TournamentBracketMatch, error) {
+	var bracketMatch models.TournamentBracketMatch
+	err := db.Where("tournament_id = ? AND winner_team_id IS NULL AND ((team1_id = ? AND team2_id = ?) OR (team1_id = ? AND team2_id = ?))",
+		tournamentID, team1ID, team2ID, team2ID, team1ID).
+		First(&bracketMatch).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no knockout match to play between these teams")
+		}
+		return nil, err
+	}
+	return &bracketMatch, nil
+}
+
+// RecordKnockoutResult moves the winner of a confirmed knockout match to the next round
+func (s *TournamentService) RecordKnockoutResult(match *models.TeamMatch) error {
+	if match.TournamentID == nil || match.Stage == nil || *match.Stage != models.TournamentStageKnockout {
+		return nil
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		bracketMatch, err := s.findOpenBracketMatch(tx, *match.TournamentID, match.Team1ID, match.Team2ID)
+		if err != nil {
+			return err
+		}
+
+		winnerID := match.WinnerTeamID
+		bracketMatch.WinnerTeamID = &winnerID
+		bracketMatch.TeamMatchID = &match.ID
+		if err := tx.Save(bracketMatch).Error; err != nil {
+			return err
+		}
+
+		return s.advanceWinner(tx, bracketMatch)
+	})
+}
+
+// advanceWinner places the winner of a bracket match in its slot of the next round
+func (s *TournamentService) advanceWinner(tx *gorm.DB, bracketMatch *models.TournamentBracketMatch) error {
+	column := "team1_id"
+	if bracketMatch.Position%2 == 1 {
+		column = "team2_id"
+	}
+
+	// No next round slot: this was the final
+	return tx.Model(&models.TournamentBracketMatch{}).
+		Where("tournament_id = ? AND round = ? AND position = ?", bracketMatch.TournamentID, bracketMatch.Round+1, bracketMatch.Position/2).
+		Update(column, *bracketMatch.WinnerTeamID).Error
+}
+
+// bracketSeedOrder returns the seed placed in each slot of a bracket of the given size,
+// so that the best seeds can only meet in the last rounds (1, 8, 4, 5, 2, 7, 3, 6 for 8 slots)
+func bracketSeedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// StartKnockout closes the group stage and builds the knockout bracket from the pool qualifiers.
+// Qualifiers are seeded by pool rank first (all pool winners, then all runners-up...), and the
+// best seeds get a bye when the number of qualifiers is not a power of two.
+func (s *TournamentService) StartKnockout(tournamentID uint) (*models.TournamentStandingsResponse, error) {
+	tournament, err := s.getMultiStageTournament(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tournament.Stage == nil || *tournament.Stage != models.TournamentStageGroup {
+		return nil, errors.New("tournament is not in group stage")
+	}
+
+	var pendingMatches int64
+	if err := s.db.Model(&models.TeamMatch{}).
+		Where("tournament_id = ? AND stage = ? AND status = ?", tournamentID, models.TournamentStageGroup, "pending").
+		Count(&pendingMatches).Error; err != nil {
+		return nil, err
+	}
+	if pendingMatches > 0 {
+		return nil, errors.New("group stage still has pending matches")
+	}
+
+	pools, err := s.getPoolStandings(tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	var qualifiers []uint
+	for rank := 0; rank < tournament.QualifiersPerPool; rank++ {
+		for _, pool := range pools {
+			if rank < len(pool.Standings) {
+				qualifiers = append(qualifiers, pool.Standings[rank].TeamID)
+			}
+		}
+	}
+	if len(qualifiers) < 2 {
+		return nil, errors.New("not enough qualified teams for a knockout stage")
+	}
+
+	size := 2
+	for size < len(qualifiers) {
+		size *= 2
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tournament_id = ?", tournamentID).Delete(&models.TournamentBracketMatch{}).Error; err != nil {
+			return err
+		}
+
+		// Empty slots for every round, filled as winners advance
+		round := 1
+		for slots := size / 2; slots >= 1; slots /= 2 {
+			for position := 0; position < slots; position++ {
+				if err := tx.Create(&models.TournamentBracketMatch{
+					TournamentID: tournamentID,
+					Round:        round,
+					Position:     position,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			round++
+		}
+
+		seedOrder := bracketSeedOrder(size)
+		seedTeam := func(seed int) *uint {
+			if seed > len(qualifiers) {
+				return nil
+			}
+			return &qualifiers[seed-1]
+		}
+
+		for position := 0; position < size/2; position++ {
+			team1ID := seedTeam(seedOrder[2*position])
+			team2ID := seedTeam(seedOrder[2*position+1])
+
+			var bracketMatch models.TournamentBracketMatch
+			if err := tx.Where("tournament_id = ? AND round = 1 AND position = ?", tournamentID, position).
+				First(&bracketMatch).Error; err != nil {
+				return err
+			}
+			bracketMatch.Team1ID = team1ID
+			bracketMatch.Team2ID = team2ID
+
+			// Bye: the seeded team goes straight to the next round
+			if team2ID == nil {
+				bracketMatch.WinnerTeamID = team1ID
+			}
+
+			if err := tx.Save(&bracketMatch).Error; err != nil {
+				return err
+			}
+			if bracketMatch.WinnerTeamID != nil {
+				if err := s.advanceWinner(tx, &bracketMatch); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Model(&models.Tournament{}).Where("id = ?", tournamentID).
+			Update("stage", models.TournamentStageKnockout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStandings(tournamentID)
+}
+
+// getPoolStandings ranks the teams of each pool on confirmed group stage matches
+// (wins, then fewer losses, then team ELO)
+func (s *TournamentService) getPoolStandings(tournament *models.Tournament) ([]models.TournamentPool, error) {
+	var tournamentTeams []models.TournamentTeam
+	if err := s.db.Where("tournament_id = ? AND pool IS NOT NULL", tournament.ID).
+		Preload("Team").
+		Find(&tournamentTeams).Error; err != nil {
+		return nil, err
+	}
+
+	var matches []models.TeamMatch
+	if err := s.db.Where("tournament_id = ? AND stage = ? AND status = ?", tournament.ID, models.TournamentStageGroup, "confirmed").
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	standings := make(map[uint]*models.PoolStanding, len(tournamentTeams))
+	for _, tt := range tournamentTeams {
+		standings[tt.TeamID] = &models.PoolStanding{
+			TeamID:    tt.TeamID,
+			TeamName:  tt.Team.Name,
+			EloRating: tt.Team.EloRating,
+		}
+	}
+
+	for _, match := range matches {
+		for _, teamID := range []uint{match.Team1ID, match.Team2ID} {
+			standing, ok := standings[teamID]
+			if !ok {
+				continue
+			}
+			standing.Played++
+			if match.WinnerTeamID == teamID {
+				standing.Wins++
+			} else {
+				standing.Losses++
+			}
+		}
+	}
+
+	pools := make([]models.TournamentPool, 0, tournament.PoolCount)
+	for _, name := range poolNames(tournament.PoolCount) {
+		pool := models.TournamentPool{Name: name, Standings: []models.PoolStanding{}}
+		for _, tt := range tournamentTeams {
+			if *tt.Pool == name {
+				pool.Standings = append(pool.Standings, *standings[tt.TeamID])
+			}
+		}
+
+		sort.SliceStable(pool.Standings, func(i, j int) bool {
+			a, b := pool.Standings[i], pool.Standings[j]
+			if a.Wins != b.Wins {
+				return a.Wins > b.Wins
+			}
+			if a.Losses != b.Losses {
+				return a.Losses < b.Losses
+			}
+			return a.EloRating > b.EloRating
+		})
+		for i := range pool.Standings {
+			pool.Standings[i].Rank = i + 1
+			pool.Standings[i].Qualified = i < tournament.QualifiersPerPool
+		}
+
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+func bracketRoundName(slots int) string {
+	switch slots {
+	case 1:
+		return "final"
+	case 2:
+		return "semi_final"
+	case 4:
+		return "quarter_final"
+	}
+	return fmt.Sprintf("round_of_%d", slots*2)
+}
+
+// GetStandings returns the pool standings and the knockout bracket of a tournament
+func (s *TournamentService) GetStandings(tournamentID uint) (*models.TournamentStandingsResponse, error) {
+	var tournament models.Tournament
+	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tournament not found")
+		}
+		return nil, err
+	}
+
+	response := &models.TournamentStandingsResponse{
+		TournamentID:      tournament.ID,
+		Format:            tournament.Format,
+		Stage:             tournament.Stage,
+		QualifiersPerPool: tournament.QualifiersPerPool,
+		Pools:             []models.TournamentPool{},
+		Bracket:           []models.BracketRound{},
+	}
+
+	if tournament.Format != models.TournamentFormatGroupsKnockout || tournament.Stage == nil {
+		return response, nil
+	}
+
+	pools, err := s.getPoolStandings(&tournament)
+	if err != nil {
+		return nil, err
+	}
+	response.Pools = pools
+
+	var bracketMatches []models.TournamentBracketMatch
+	if err := s.db.Where("tournament_id = ?", tournamentID).
+		Preload("Team1").
+		Preload("Team2").
+		Preload("WinnerTeam").
+		Order("round ASC, position ASC").
+		Find(&bracketMatches).Error; err != nil {
+		return nil, err
+	}
+
+	for _, bracketMatch := range bracketMatches {
+		last := len(response.Bracket) - 1
+		if last < 0 || response.Bracket[last].Round != bracketMatch.Round {
+			response.Bracket = append(response.Bracket, models.BracketRound{Round: bracketMatch.Round})
+			last++
+		}
+		response.Bracket[last].Matches = append(response.Bracket[last].Matches, bracketMatch)
+	}
+	for i := range response.Bracket {
+		response.Bracket[i].Name = bracketRoundName(len(response.Bracket[i].Matches))
+	}
+
+	if len(response.Bracket) > 0 {
+		final := response.Bracket[len(response.Bracket)-1]
+		if len(final.Matches) == 1 {
+			response.ChampionTeamID = final.Matches[0].WinnerTeamID
+		}
+	}
+
+	return response, nil
+}
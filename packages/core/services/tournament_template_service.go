@@ -0,0 +1,155 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type TournamentTemplateService struct {
+	db                *gorm.DB
+	tournamentService *TournamentService
+}
+
+func NewTournamentTemplateService(db *gorm.DB) *TournamentTemplateService {
+	return &TournamentTemplateService{
+		db:                db,
+		tournamentService: NewTournamentService(db),
+	}
+}
+
+func (s *TournamentTemplateService) GetTemplates() ([]models.TournamentTemplate, error) {
+	var templates []models.TournamentTemplate
+	if err := s.db.Order("name ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *TournamentTemplateService) GetTemplateByID(id uint) (*models.TournamentTemplate, error) {
+	var template models.TournamentTemplate
+	if err := s.db.First(&template, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (s *TournamentTemplateService) CreateTemplate(req models.CreateTournamentTemplateRequest) (*models.TournamentTemplate, error) {
+	// Validate the configuration the same way a tournament would be
+	tournament, err := s.tournamentService.buildTournament(models.CreateTournamentRequest{
+		Name:                  req.Name,
+		Type:                  req.Type,
+		Description:           req.Description,
+		MatchTimeLimitSeconds: req.MatchTimeLimitSeconds,
+		GoldenGoal:            req.GoldenGoal,
+		Format:                req.Format,
+		PoolCount:             req.PoolCount,
+		QualifiersPerPool:     req.QualifiersPerPool,
+		MaxParticipants:       req.MaxParticipants,
+		EloWeight:             req.EloWeight,
+		PhotoPolicy:           req.PhotoPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	template := templateFromTournament(tournament, req.Name, req.Description)
+	if err := s.db.Create(template).Error; err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func templateFromTournament(tournament *models.Tournament, name, description string) *models.TournamentTemplate {
+	return &models.TournamentTemplate{
+		Name:                  name,
+		Description:           description,
+		Type:                  tournament.Type,
+		Format:                tournament.Format,
+		PoolCount:             tournament.PoolCount,
+		QualifiersPerPool:     tournament.QualifiersPerPool,
+		MaxParticipants:       tournament.MaxParticipants,
+		MatchTimeLimitSeconds: tournament.MatchTimeLimitSeconds,
+		GoldenGoal:            tournament.GoldenGoal,
+		EloWeight:             tournament.EloWeight,
+		PhotoPolicy:           tournament.PhotoPolicy,
+	}
+}
+
+// SaveTournamentAsTemplate copies the configuration of an existing tournament into a new template
+func (s *TournamentTemplateService) SaveTournamentAsTemplate(tournamentID uint, req models.SaveTournamentAsTemplateRequest) (*models.TournamentTemplate, error) {
+	var tournament models.Tournament
+	if err := s.db.First(&tournament, tournamentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tournament not found")
+		}
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = tournament.Description
+	}
+
+	template := templateFromTournament(&tournament, req.Name, description)
+
+	if err := s.db.Create(template).Error; err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TournamentTemplateService) DeleteTemplate(id uint) error {
+	result := s.db.Delete(&models.TournamentTemplate{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("template not found")
+	}
+
+	return nil
+}
+
+// CreateTournamentFromTemplate creates a new opened tournament with the template configuration
+func (s *TournamentTemplateService) CreateTournamentFromTemplate(templateID uint, req models.CreateTournamentFromTemplateRequest) (*models.Tournament, error) {
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = template.Description
+	}
+
+	tournament, err := s.tournamentService.CreateTournament(models.CreateTournamentRequest{
+		Name:                  req.Name,
+		Type:                  template.Type,
+		Description:           description,
+		MatchTimeLimitSeconds: template.MatchTimeLimitSeconds,
+		GoldenGoal:            template.GoldenGoal,
+		Format:                template.Format,
+		PoolCount:             template.PoolCount,
+		QualifiersPerPool:     template.QualifiersPerPool,
+		MaxParticipants:       template.MaxParticipants,
+		EloWeight:             template.EloWeight,
+		PhotoPolicy:           template.PhotoPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(tournament).Update("template_id", template.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return tournament, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"core/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TrophyService struct {
+	db *gorm.DB
+}
+
+func NewTrophyService(db *gorm.DB) *TrophyService {
+	return &TrophyService{
+		db: db,
+	}
+}
+
+func (s *TrophyService) preloaded() *gorm.DB {
+	return s.db.Preload("Tournament").
+		Preload("WinnerTeam").
+		Preload("WinnerPlayer")
+}
+
+// GetTrophies lists the trophies, most recent first, optionally for a single season
+func (s *TrophyService) GetTrophies(season *string) ([]models.Trophy, error) {
+	query := s.preloaded()
+	if season != nil {
+		query = query.Where("season = ?", *season)
+	}
+
+	var trophies []models.Trophy
+	if err := query.Order("awarded_at DESC").Find(&trophies).Error; err != nil {
+		return nil, err
+	}
+	return trophies, nil
+}
+
+func (s *TrophyService) GetTrophyByID(id uint) (*models.Trophy, error) {
+	var trophy models.Trophy
+	if err := s.preloaded().First(&trophy, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("trophy not found")
+		}
+		return nil, err
+	}
+	return &trophy, nil
+}
+
+// GetPlayerTrophies returns the trophies won by a player, alone or with one of their teams
+func (s *TrophyService) GetPlayerTrophies(playerID uint) ([]models.Trophy, error) {
+	var trophies []models.Trophy
+	err := s.preloaded().
+		Where("winner_player_id = ? OR winner_team_id IN (?)", playerID,
+			s.db.Model(&models.Team{}).Select("id").Where("player1_id = ? OR player2_id = ?", playerID, playerID)).
+		Order("awarded_at DESC").
+		Find(&trophies).Error
+	if err != nil {
+		return nil, err
+	}
+	return trophies, nil
+}
+
+// GetTeamTrophies returns the trophies won by a team
+func (s *TrophyService) GetTeamTrophies(teamID uint) ([]models.Trophy, error) {
+	var trophies []models.Trophy
+	if err := s.preloaded().
+		Where("winner_team_id = ?", teamID).
+		Order("awarded_at DESC").
+		Find(&trophies).Error; err != nil {
+		return nil, err
+	}
+	return trophies, nil
+}
+
+// CreateTrophy records a trophy awarded by hand, typically a season award
+func (s *TrophyService) CreateTrophy(req models.CreateTrophyRequest) (*models.Trophy, error) {
+	if err := s.checkWinner(req.WinnerTeamID, req.WinnerPlayerID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	trophy := &models.Trophy{
+		Name:           req.Name,
+		ImageURL:       req.ImageURL,
+		Season:         req.Season,
+		WinnerTeamID:   req.WinnerTeamID,
+		WinnerPlayerID: req.WinnerPlayerID,
+		AwardedAt:      now,
+	}
+	if trophy.Season == "" {
+		trophy.Season = models.SeasonOf(now)
+	}
+
+	if err := s.db.Create(trophy).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetTrophyByID(trophy.ID)
+}
+
+// UpdateTrophy renames a trophy, changes its image or corrects its winner
+func (s *TrophyService) UpdateTrophy(id uint, req models.UpdateTrophyRequest) (*models.Trophy, error) {
+	trophy, err := s.GetTrophyByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.ImageURL != nil {
+		updates["image_url"] = *req.ImageURL
+	}
+	if req.WinnerTeamID != nil || req.WinnerPlayerID != nil {
+		if err := s.checkWinner(req.WinnerTeamID, req.WinnerPlayerID); err != nil {
+			return nil, err
+		}
+		updates["winner_team_id"] = req.WinnerTeamID
+		updates["winner_player_id"] = req.WinnerPlayerID
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&models.Trophy{}).Where("id = ?", trophy.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetTrophyByID(id)
+}
+
+func (s *TrophyService) DeleteTrophy(id uint) error {
+	result := s.db.Delete(&models.Trophy{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("trophy not found")
+	}
+	return nil
+}
+
+// checkWinner validates that a trophy goes to exactly one existing team or player
+func (s *TrophyService) checkWinner(teamID, playerID *uint) error {
+	if (teamID == nil) == (playerID == nil) {
+		return errors.New("a trophy must have either a winner team or a winner player")
+	}
+
+	if teamID != nil {
+		if err := s.db.First(&models.Team{}, *teamID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("team not found")
+			}
+			return err
+		}
+		return nil
+	}
+
+	if err := s.db.First(&models.Player{}, *playerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("player not found")
+		}
+		return err
+	}
+	return nil
+}
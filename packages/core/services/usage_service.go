@@ -0,0 +1,179 @@
+package services
+
+import (
+	"core/models"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// usageFlushInterval is how often the counters kept in memory are added to api_usage
+	usageFlushInterval = time.Minute
+
+	defaultUsageRetentionDays = 180
+)
+
+type usageKey struct {
+	day    time.Time
+	userID uint
+	group  string
+}
+
+type usageCounter struct {
+	requests     int64
+	lastActivity time.Time
+}
+
+// UsageService counts the requests per user and endpoint group. Requests only increment counters in
+// memory, they are written to api_usage in one statement every minute.
+type UsageService struct {
+	db        *gorm.DB
+	retention time.Duration
+
+	mu      sync.Mutex
+	pending map[usageKey]*usageCounter
+}
+
+// NewUsageService reads USAGE_RETENTION_DAYS, how long the daily counters are kept (default 180, 0 keeps them)
+func NewUsageService(db *gorm.DB) *UsageService {
+	retentionDays := defaultUsageRetentionDays
+	if value, err := strconv.Atoi(os.Getenv("USAGE_RETENTION_DAYS")); err == nil && value >= 0 {
+		retentionDays = value
+	}
+
+	return &UsageService{
+		db:        db,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+		pending:   make(map[usageKey]*usageCounter),
+	}
+}
+
+// Start writes the counters every minute and purges the old days once a day
+func (s *UsageService) Start() {
+	go func() {
+		flush := time.NewTicker(usageFlushInterval)
+		defer flush.Stop()
+		purge := time.NewTicker(24 * time.Hour)
+		defer purge.Stop()
+
+		for {
+			select {
+			case <-flush.C:
+				if err := s.Flush(); err != nil {
+					log.Printf("Error writing API usage: %v", err)
+				}
+			case now := <-purge.C:
+				if s.retention == 0 {
+					continue
+				}
+				if err := s.db.Where("day < ?", now.Add(-s.retention)).Delete(&models.APIUsage{}).Error; err != nil {
+					log.Printf("Error purging API usage: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Record counts a request of a user (0 when anonymous) to an endpoint group
+func (s *UsageService) Record(userID uint, group string, at time.Time) {
+	key := usageKey{
+		day:    time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC),
+		userID: userID,
+		group:  group,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.pending[key]
+	if !ok {
+		counter = &usageCounter{}
+		s.pending[key] = counter
+	}
+	counter.requests++
+	if at.After(counter.lastActivity) {
+		counter.lastActivity = at
+	}
+}
+
+// Flush adds the counters kept in memory to api_usage. On failure they are kept for the next flush.
+func (s *UsageService) Flush() error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]*usageCounter)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	rows := make([]models.APIUsage, 0, len(pending))
+	for key, counter := range pending {
+		rows = append(rows, models.APIUsage{
+			Day:            key.day,
+			UserID:         key.userID,
+			EndpointGroup:  key.group,
+			RequestCount:   counter.requests,
+			LastActivityAt: counter.lastActivity,
+		})
+	}
+
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "user_id"}, {Name: "endpoint_group"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"request_count":    gorm.Expr("api_usage.request_count + EXCLUDED.request_count"),
+			"last_activity_at": gorm.Expr("GREATEST(api_usage.last_activity_at, EXCLUDED.last_activity_at)"),
+		}),
+	}).Create(&rows).Error
+	if err != nil {
+		s.restore(pending)
+	}
+	return err
+}
+
+// restore puts back counters that could not be written
+func (s *UsageService) restore(pending map[usageKey]*usageCounter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, counter := range pending {
+		current, ok := s.pending[key]
+		if !ok {
+			s.pending[key] = counter
+			continue
+		}
+		current.requests += counter.requests
+		if counter.lastActivity.After(current.lastActivity) {
+			current.lastActivity = counter.lastActivity
+		}
+	}
+}
+
+// GetUsage aggregates the use of each endpoint group since a date, for every user or for one,
+// the most requested groups first
+func (s *UsageService) GetUsage(from time.Time, userID *uint) (*models.APIUsageReport, error) {
+	query := s.db.Model(&models.APIUsage{}).
+		Select(`endpoint_group,
+			SUM(request_count) AS requests,
+			COALESCE(SUM(request_count) FILTER (WHERE user_id = 0), 0) AS anonymous_requests,
+			COUNT(DISTINCT user_id) FILTER (WHERE user_id <> 0) AS users,
+			MAX(last_activity_at) AS last_activity_at`).
+		Where("day >= ?", from.Format("2006-01-02"))
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	report := &models.APIUsageReport{From: from, UserID: userID, Groups: []models.APIUsageGroup{}}
+	if err := query.Group("endpoint_group").
+		Order("requests DESC, endpoint_group").
+		Scan(&report.Groups).Error; err != nil {
+		return nil, err
+	}
+	return report, nil
+}
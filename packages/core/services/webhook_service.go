@@ -0,0 +1,396 @@
+package services
+
+import (
+	"bytes"
+	"core/events"
+	"core/models"
+	"core/webhooks"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookSecretPrefix       = "whsec_"
+	defaultWebhookSecretGrace = 24 * time.Hour
+	webhookBatchSize          = 100
+)
+
+// webhookRetryDelays spaces the attempts of a delivery, it fails after the last one
+var webhookRetryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour}
+
+var webhookHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// WebhookService sends signed events to the integrators' endpoints. Results are queued from the same
+// source as GET /public/v1/results, so an endpoint receives exactly what the public API exposes.
+type WebhookService struct {
+	db        *gorm.DB
+	publicAPI *PublicAPIService
+	events    *events.Bus
+}
+
+func NewWebhookService(db *gorm.DB, publicAPI *PublicAPIService, bus *events.Bus) *WebhookService {
+	return &WebhookService{
+		db:        db,
+		publicAPI: publicAPI,
+		events:    bus,
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(bytes), nil
+}
+
+func newDeliveryID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "dlv_" + hex.EncodeToString(bytes), nil
+}
+
+// CreateEndpoint registers an endpoint; it receives the results confirmed from now on. The clear
+// secret is only returned here and on rotation.
+func (s *WebhookService) CreateEndpoint(req models.CreateWebhookEndpointRequest, createdBy uint) (*models.WebhookSecretResponse, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	endpoint := models.WebhookEndpoint{
+		Name:      req.Name,
+		URL:       req.URL,
+		Events:    strings.Join(req.Events, ","),
+		Secret:    secret,
+		Cursor:    &now,
+		CreatedBy: &createdBy,
+	}
+	if err := s.db.Create(&endpoint).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookSecretResponse{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+func (s *WebhookService) GetEndpoints() ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.Order("created_at DESC").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (s *WebhookService) getEndpoint(id uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := s.db.First(&endpoint, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook endpoint not found")
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// DeleteEndpoint removes an endpoint with its deliveries
+func (s *WebhookService) DeleteEndpoint(id uint) error {
+	result := s.db.Delete(&models.WebhookEndpoint{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("webhook endpoint not found")
+	}
+	return nil
+}
+
+// RotateSecret gives an endpoint a new secret. The previous one keeps signing the deliveries during the
+// grace period (24 hours by default), next to the new one, so that the integrator can switch without
+// losing deliveries.
+func (s *WebhookService) RotateSecret(id uint, graceHours *int) (*models.WebhookSecretResponse, error) {
+	grace := defaultWebhookSecretGrace
+	if graceHours != nil {
+		grace = time.Duration(*graceHours) * time.Hour
+	}
+
+	endpoint, err := s.getEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"secret": secret, "previous_secret": nil, "previous_secret_expires_at": nil}
+	if grace > 0 {
+		expiresAt := time.Now().Add(grace)
+		updates["previous_secret"] = endpoint.Secret
+		updates["previous_secret_expires_at"] = expiresAt
+	}
+	if err := s.db.Model(endpoint).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	endpoint, err = s.getEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookSecretResponse{WebhookEndpoint: *endpoint, Secret: secret}, nil
+}
+
+// Ping queues a ping for an endpoint and sends it at once
+func (s *WebhookService) Ping(id uint) (*models.WebhookDelivery, error) {
+	endpoint, err := s.getEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.enqueue(s.db, endpoint, models.WebhookEventPing, map[string]interface{}{"endpoint_id": endpoint.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempt(endpoint, delivery, time.Now()); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// GetDeliveries lists the last deliveries of an endpoint, most recent first
+func (s *WebhookService) GetDeliveries(endpointID uint, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.getEndpoint(endpointID); err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := s.db.Where("endpoint_id = ?", endpointID).Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// enqueue stores a delivery with its final body
+func (s *WebhookService) enqueue(tx *gorm.DB, endpoint *models.WebhookEndpoint, event string, data interface{}) (*models.WebhookDelivery, error) {
+	id, err := newDeliveryID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(models.WebhookPayload{ID: id, Event: event, CreatedAt: now, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := models.WebhookDelivery{
+		DeliveryID:    id,
+		EndpointID:    endpoint.ID,
+		Event:         event,
+		Payload:       string(payload),
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := tx.Create(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// Dispatch queues the results confirmed since the cursor of each endpoint, then sends the deliveries
+// that are due, and returns how many were delivered
+func (s *WebhookService) Dispatch(now time.Time) (int, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.Find(&endpoints).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range endpoints {
+		if endpoints[i].Subscribes(models.WebhookEventResultConfirmed) {
+			if err := s.queueResults(&endpoints[i]); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var due []models.WebhookDelivery
+	if err := s.db.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("id ASC").
+		Limit(webhookBatchSize).
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	byID := make(map[uint]*models.WebhookEndpoint, len(endpoints))
+	for i := range endpoints {
+		byID[endpoints[i].ID] = &endpoints[i]
+	}
+
+	delivered := 0
+	for i := range due {
+		endpoint, ok := byID[due[i].EndpointID]
+		if !ok {
+			continue // Endpoint deleted meanwhile
+		}
+		if err := s.attempt(endpoint, &due[i], now); err != nil {
+			return delivered, err
+		}
+		if due[i].Status == models.WebhookDeliveryDelivered {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// queueResults queues a delivery per result confirmed after the cursor of the endpoint
+func (s *WebhookService) queueResults(endpoint *models.WebhookEndpoint) error {
+	for {
+		results, err := s.publicAPI.GetResults(endpoint.Cursor, webhookBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(results.Data) == 0 {
+			return nil
+		}
+
+		cursor := results.Data[len(results.Data)-1].ConfirmedAt
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			for _, result := range results.Data {
+				if _, err := s.enqueue(tx, endpoint, models.WebhookEventResultConfirmed, result); err != nil {
+					return err
+				}
+			}
+			return tx.Model(endpoint).Update("cursor", cursor).Error
+		}); err != nil {
+			return err
+		}
+		endpoint.Cursor = &cursor
+
+		if results.Next == nil {
+			return nil
+		}
+	}
+}
+
+// attempt sends a delivery signed with the active secrets of its endpoint and records the outcome,
+// scheduling a retry on failure. Only a storage error is returned.
+func (s *WebhookService) attempt(endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery, now time.Time) error {
+	status, sendErr := s.send(endpoint, delivery, now)
+
+	delivery.Attempts++
+	updates := map[string]interface{}{"attempts": delivery.Attempts}
+	if status != 0 {
+		delivery.ResponseStatus = &status
+		updates["response_status"] = status
+	}
+
+	if sendErr == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		updates["status"] = delivery.Status
+		updates["delivered_at"] = now
+		updates["next_attempt_at"] = nil
+		updates["last_error"] = nil
+	} else {
+		message := sendErr.Error()
+		delivery.LastError = &message
+		updates["last_error"] = message
+
+		if delivery.Attempts > len(webhookRetryDelays) {
+			delivery.Status = models.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+			updates["status"] = delivery.Status
+			updates["next_attempt_at"] = nil
+
+			s.events.Publish(events.TypeDeliveryFailed, map[string]interface{}{
+				"channel":     "webhook",
+				"endpoint_id": endpoint.ID,
+				"delivery_id": delivery.DeliveryID,
+				"event":       delivery.Event,
+				"error":       message,
+			})
+		} else {
+			next := now.Add(webhookRetryDelays[delivery.Attempts-1])
+			delivery.NextAttemptAt = &next
+			updates["next_attempt_at"] = next
+		}
+	}
+
+	return s.db.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error
+}
+
+// send posts a delivery and returns the response status, a non 2xx answer is an error
+func (s *WebhookService) send(endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.HeaderID, delivery.DeliveryID)
+	req.Header.Set(webhooks.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhooks.HeaderSignature, webhooks.SignatureHeader(endpoint.Secrets(now), delivery.DeliveryID, timestamp, body))
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// VerifyDelivery checks a delivery as the integrator received it, with the secrets of its endpoint:
+// signature, replay window and known delivery ID. It backs the verification helper for integrators.
+func (s *WebhookService) VerifyDelivery(id, timestamp, signature string, body []byte, now time.Time) (*models.VerifyWebhookResponse, error) {
+	response := &models.VerifyWebhookResponse{
+		DeliveryID:          id,
+		ReplayWindowSeconds: int(webhooks.DefaultTolerance.Seconds()),
+	}
+
+	var secrets []string
+	if id != "" {
+		var delivery models.WebhookDelivery
+		err := s.db.Where("delivery_id = ?", id).First(&delivery).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.Reason = "unknown delivery id"
+			return response, nil
+		case err != nil:
+			return nil, err
+		}
+
+		endpoint, err := s.getEndpoint(delivery.EndpointID)
+		if err != nil {
+			return nil, err
+		}
+		secrets = endpoint.Secrets(now)
+	}
+
+	if err := webhooks.NewVerifier(secrets...).Check(id, timestamp, signature, body, now); err != nil {
+		response.Reason = err.Error()
+		return response, nil
+	}
+	response.Valid = true
+	return response, nil
+}
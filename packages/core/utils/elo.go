@@ -2,15 +2,46 @@ package utils
 
 import "math"
 
+// EloFloor is the minimum ELO rating, no result or adjustment takes a player below it
+const EloFloor = 1200.0
+
+// Floor modes, how the ELO floor treats a loss that would take a player below it
+const (
+	FloorModeClamp   = "clamp"    // The loss is cut at the floor, the winner keeps their full gain (default)
+	FloorModeZeroSum = "zero_sum" // The loss is cut at the floor and the winner only gains what the loser lost
+	FloorModeNone    = "none"     // No floor, the loss is applied in full
+)
+
+// FloorModes lists the floor modes
+var FloorModes = []string{FloorModeClamp, FloorModeZeroSum, FloorModeNone}
+
+// ExpectedScore is the probability that a player rated rating beats an opponent rated opponentRating
+func ExpectedScore(rating, opponentRating float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10, (opponentRating-rating)/400))
+}
+
 // CalculateEloChange calculates ELO rating changes using the standard ELO formula
 // Returns (player1Change, player2Change)
 // Ensures that no player can go below 1200 ELO
 func CalculateEloChange(player1Elo, player2Elo float64, winnerID, player1ID uint) (float64, float64) {
-	const K = 32.0        // ELO K-factor
-	const MinElo = 1200.0 // Minimum ELO rating
+	return CalculateEloChangeWithExemptions(player1Elo, player2Elo, winnerID, player1ID, false, false)
+}
+
+// CalculateEloChangeWithExemptions is CalculateEloChange where an exempt player is not held at the
+// ELO floor: their change is the plain ELO formula, even if it takes them below 1200
+func CalculateEloChangeWithExemptions(player1Elo, player2Elo float64, winnerID, player1ID uint, player1Exempt, player2Exempt bool) (float64, float64) {
+	return CalculateWeightedEloChange(player1Elo, player2Elo, winnerID, player1ID, 1, 1, player1Exempt, player2Exempt)
+}
+
+// CalculateWeightedEloChange is CalculateEloChangeWithExemptions where the K-factor of each player is
+// multiplied (tournament weight, rating override), before the floor so that a weighted loss is still
+// held at 1200 and before any zero-sum adjustment
+func CalculateWeightedEloChange(player1Elo, player2Elo float64, winnerID, player1ID uint, player1KMultiplier, player2KMultiplier float64, player1Exempt, player2Exempt bool) (float64, float64) {
+	const K = 32.0          // ELO K-factor
+	const MinElo = EloFloor // Minimum ELO rating
 
 	// Expected scores
-	expectedScore1 := 1.0 / (1.0 + math.Pow(10, (player2Elo-player1Elo)/400))
+	expectedScore1 := ExpectedScore(player1Elo, player2Elo)
 	expectedScore2 := 1.0 - expectedScore1
 
 	// Actual scores
@@ -24,29 +55,78 @@ func CalculateEloChange(player1Elo, player2Elo float64, winnerID, player1ID uint
 	}
 
 	// Calculate changes
-	change1 := K * (actualScore1 - expectedScore1)
-	change2 := K * (actualScore2 - expectedScore2)
+	change1 := K * player1KMultiplier * (actualScore1 - expectedScore1)
+	change2 := K * player2KMultiplier * (actualScore2 - expectedScore2)
 
-	// Apply minimum ELO constraint
-	if player1Elo+change1 < MinElo {
+	// Apply minimum ELO constraint, a loss never takes a player through it, one left below it by calibration follows the formula
+	if !player1Exempt && player1Elo >= MinElo && player1Elo+change1 < MinElo {
 		change1 = MinElo - player1Elo
 	}
-	if player2Elo+change2 < MinElo {
+	if !player2Exempt && player2Elo >= MinElo && player2Elo+change2 < MinElo {
 		change2 = MinElo - player2Elo
 	}
 
 	return math.Round(change1), math.Round(change2)
 }
 
+// ZeroSumEloChange cuts the gain of the winner of an exchange to the points the loser actually lost,
+// so that a loss clamped at the floor does not create rating out of nothing
+func ZeroSumEloChange(change1, change2 float64) (float64, float64) {
+	if change1 > -change2 && change2 <= 0 {
+		change1 = math.Max(-change2, 0)
+	}
+	if change2 > -change1 && change1 <= 0 {
+		change2 = math.Max(-change1, 0)
+	}
+	return change1, change2
+}
+
+// ZeroSumTeamEloChange is ZeroSumEloChange for a team match. Each player is rated against the average
+// of the other team, so the two sides rarely match even without the floor: the larger side is cut, in
+// proportion, to the smaller one, and the four changes add up to zero
+func ZeroSumTeamEloChange(winner1Change, winner2Change, loser1Change, loser2Change float64) (float64, float64, float64, float64) {
+	gained := winner1Change + winner2Change
+	lost := -(loser1Change + loser2Change)
+	switch {
+	case gained > lost:
+		winner1Change, winner2Change = scaleEloChanges(winner1Change, winner2Change, math.Max(lost, 0))
+	case lost > gained:
+		loser1Change, loser2Change = scaleEloChanges(loser1Change, loser2Change, -math.Max(gained, 0))
+	}
+	return winner1Change, winner2Change, loser1Change, loser2Change
+}
+
+// scaleEloChanges cuts two changes of the same sign, in proportion, so that they add up to total.
+// The second takes the rest so that the rounded changes still add up exactly.
+func scaleEloChanges(change1, change2, total float64) (float64, float64) {
+	sum := change1 + change2
+	if sum == 0 || total == 0 {
+		return 0, 0
+	}
+	change1 = math.Round(change1 * total / sum)
+	return change1, total - change1
+}
+
+// ZeroSumCalibrationMatches is the calibration of the zero_sum mode when ELO_CALIBRATION_MATCHES is not
+// set. Players start at the floor, so if the floor held them from their first match a conserved total
+// would leave everyone at 1200: the floor only applies once a player played that many matches.
+const ZeroSumCalibrationMatches = 10
+
 // CalculateTeamEloChange calculates ELO rating changes for team matches
 // Each player's ELO is calculated individually against the average ELO of the opposing team
 // Ensures that no player can go below 1200 ELO
 func CalculateTeamEloChange(playerElo, opponentTeamAvgElo float64, isWinner bool) float64 {
-	const K = 32.0        // ELO K-factor
-	const MinElo = 1200.0 // Minimum ELO rating
+	return CalculateWeightedTeamEloChange(playerElo, opponentTeamAvgElo, isWinner, 1, false)
+}
+
+// CalculateWeightedTeamEloChange is CalculateTeamEloChange with the K-factor multiplied (tournament weight),
+// before the floor so that a weighted loss is still held at 1200; an exempt player is not held at the floor
+func CalculateWeightedTeamEloChange(playerElo, opponentTeamAvgElo float64, isWinner bool, kMultiplier float64, exempt bool) float64 {
+	const K = 32.0          // ELO K-factor
+	const MinElo = EloFloor // Minimum ELO rating
 
 	// Expected score for this player against the opposing team's average
-	expectedScore := 1.0 / (1.0 + math.Pow(10, (opponentTeamAvgElo-playerElo)/400))
+	expectedScore := ExpectedScore(playerElo, opponentTeamAvgElo)
 
 	// Actual score
 	var actualScore float64
@@ -57,10 +137,10 @@ func CalculateTeamEloChange(playerElo, opponentTeamAvgElo float64, isWinner bool
 	}
 
 	// Calculate change
-	change := K * (actualScore - expectedScore)
+	change := K * kMultiplier * (actualScore - expectedScore)
 
-	// Apply minimum ELO constraint
-	if playerElo+change < MinElo {
+	// Apply minimum ELO constraint, a loss never takes a player through it, one left below it by calibration follows the formula
+	if !exempt && playerElo >= MinElo && playerElo+change < MinElo {
 		change = MinElo - playerElo
 	}
 
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCalculateWeightedEloChange(t *testing.T) {
+	tests := []struct {
+		name                                   string
+		player1Elo, player2Elo                 float64
+		winnerID                               uint
+		player1KMultiplier, player2KMultiplier float64
+		player1Exempt, player2Exempt           bool
+		want1, want2                           float64
+	}{
+		{"even match", 1500, 1500, 1, 1, 1, false, false, 16, -16},
+		{"loss clamped at the floor", 1500, 1203, 1, 1, 1, false, false, 5, -3},
+		{"loss at the floor", 1300, 1200, 1, 1, 1, false, false, 12, 0},
+		{"exempt loser goes below the floor", 1210, 1210, 2, 1, 1, true, false, -16, 16},
+		{"tournament weight before the floor", 1250, 1250, 2, 5, 5, false, false, -50, 80},
+		{"weighted exempt loser", 1250, 1250, 2, 5, 5, true, false, -80, 80},
+		{"override multiplier per player", 1500, 1500, 1, 2, 1, false, false, 32, -16},
+		{"override multiplier before the floor", 1500, 1220, 1, 1, 4, false, false, 5, -20},
+		{"loser left below the floor by calibration", 1300, 1180, 1, 1, 1, false, false, 11, -11},
+		{"winner below the floor", 1180, 1300, 1, 1, 1, false, false, 21, -21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2 := CalculateWeightedEloChange(tt.player1Elo, tt.player2Elo, tt.winnerID, 1,
+				tt.player1KMultiplier, tt.player2KMultiplier, tt.player1Exempt, tt.player2Exempt)
+			if got1 != tt.want1 || got2 != tt.want2 {
+				t.Errorf("got (%v, %v), want (%v, %v)", got1, got2, tt.want1, tt.want2)
+			}
+			if !tt.player1Exempt && tt.player1Elo >= EloFloor && tt.player1Elo+got1 < EloFloor ||
+				!tt.player2Exempt && tt.player2Elo >= EloFloor && tt.player2Elo+got2 < EloFloor {
+				t.Errorf("a player went below the floor: (%v, %v)", tt.player1Elo+got1, tt.player2Elo+got2)
+			}
+		})
+	}
+}
+
+func TestCalculateEloChangeWithExemptions(t *testing.T) {
+	tests := []struct {
+		name                         string
+		player1Elo, player2Elo       float64
+		winnerID                     uint
+		player1Exempt, player2Exempt bool
+		want1, want2                 float64
+	}{
+		{"player1 wins", 1500, 1500, 1, false, false, 16, -16},
+		{"player2 wins", 1500, 1500, 2, false, false, -16, 16},
+		{"favourite wins", 1700, 1300, 1, false, false, 3, -3},
+		{"underdog wins", 1300, 1700, 1, false, false, 29, -29},
+		{"loser held at the floor", 1500, 1203, 1, false, false, 5, -3},
+		{"exempt loser", 1500, 1203, 1, false, true, 5, -5},
+		{"both exempt", 1210, 1210, 2, true, true, -16, 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2 := CalculateEloChangeWithExemptions(tt.player1Elo, tt.player2Elo, tt.winnerID, 1, tt.player1Exempt, tt.player2Exempt)
+			if got1 != tt.want1 || got2 != tt.want2 {
+				t.Errorf("got (%v, %v), want (%v, %v)", got1, got2, tt.want1, tt.want2)
+			}
+		})
+	}
+}
+
+func TestZeroSumEloChange(t *testing.T) {
+	tests := []struct {
+		name                     string
+		change1, change2         float64
+		wantChange1, wantChange2 float64
+	}{
+		{"already zero-sum", 16, -16, 16, -16},
+		{"player1 gain cut to the clamped loss", 16, -10, 10, -10},
+		{"player2 gain cut to the clamped loss", -10, 16, -10, 10},
+		{"loser at the floor gives nothing", 12, 0, 0, 0},
+		{"exempt loss larger than the gain", 5, -7, 5, -7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2 := ZeroSumEloChange(tt.change1, tt.change2)
+			if got1 != tt.wantChange1 || got2 != tt.wantChange2 {
+				t.Errorf("got (%v, %v), want (%v, %v)", got1, got2, tt.wantChange1, tt.wantChange2)
+			}
+		})
+	}
+}
+
+func TestZeroSumAfterWeightedFloor(t *testing.T) {
+	// A weighted loss clamped at the floor must still leave a zero-sum exchange
+	change1, change2 := CalculateWeightedEloChange(1250, 1250, 2, 1, 5, 5, false, false)
+	change1, change2 = ZeroSumEloChange(change1, change2)
+	if change1+change2 != 0 {
+		t.Errorf("got (%v, %v), want a zero sum", change1, change2)
+	}
+	if 1250+change1 < EloFloor {
+		t.Errorf("loser went below the floor: %v", 1250+change1)
+	}
+}
+
+func TestCalculateWeightedTeamEloChange(t *testing.T) {
+	tests := []struct {
+		name                      string
+		playerElo, opponentAvgElo float64
+		isWinner                  bool
+		kMultiplier               float64
+		exempt                    bool
+		want                      float64
+	}{
+		{"even win", 1500, 1500, true, 1, false, 16},
+		{"even loss", 1500, 1500, false, 1, false, -16},
+		{"loss clamped at the floor", 1210, 1210, false, 1, false, -10},
+		{"tournament weight before the floor", 1250, 1250, false, 5, false, -50},
+		{"exempt loss", 1250, 1250, false, 5, true, -80},
+		{"loss below the floor", 1190, 1250, false, 1, false, -13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateWeightedTeamEloChange(tt.playerElo, tt.opponentAvgElo, tt.isWinner, tt.kMultiplier, tt.exempt); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZeroSumTeamEloChange(t *testing.T) {
+	tests := []struct {
+		name                     string
+		winner1, winner2         float64
+		loser1, loser2           float64
+		wantWinner1, wantWinner2 float64
+		wantLoser1, wantLoser2   float64
+	}{
+		{"already zero-sum", 16, 16, -16, -16, 16, 16, -16, -16},
+		{"gains cut in proportion", 20, 10, -5, -10, 10, 5, -5, -10},
+		{"rounded gains add up to the loss", 16, 16, -10, -15, 13, 12, -10, -15},
+		{"losers at the floor give nothing", 16, 16, 0, 0, 0, 0, 0, 0},
+		{"losses cut to the gains", 10, 10, -16, -16, 10, 10, -10, -10},
+		{"rounded losses add up to the gains", 5, 10, -16, -8, 5, 10, -10, -5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1, got2, got3, got4 := ZeroSumTeamEloChange(tt.winner1, tt.winner2, tt.loser1, tt.loser2)
+			if got1 != tt.wantWinner1 || got2 != tt.wantWinner2 || got3 != tt.wantLoser1 || got4 != tt.wantLoser2 {
+				t.Errorf("got (%v, %v, %v, %v), want (%v, %v, %v, %v)", got1, got2, got3, got4,
+					tt.wantWinner1, tt.wantWinner2, tt.wantLoser1, tt.wantLoser2)
+			}
+		})
+	}
+}
+
+// simulatedPlayer is a player of the long zero-sum sequences, winning according to a hidden strength
+type simulatedPlayer struct {
+	strength float64
+	rating   float64
+	played   int
+}
+
+func newSimulatedPlayers(count int) []*simulatedPlayer {
+	players := make([]*simulatedPlayer, count)
+	for i := range players {
+		players[i] = &simulatedPlayer{strength: 1000 + float64(i)*40, rating: EloFloor}
+	}
+	return players
+}
+
+// exempt applies the zero_sum default calibration, like the match services
+func (p *simulatedPlayer) exempt() bool {
+	return p.played < ZeroSumCalibrationMatches
+}
+
+func checkZeroSumSequence(t *testing.T, players []*simulatedPlayer, match int) {
+	t.Helper()
+	total := 0.0
+	for _, p := range players {
+		total += p.rating
+	}
+	if want := EloFloor * float64(len(players)); total != want {
+		t.Fatalf("after match %d the ratings add up to %v, want %v", match, total, want)
+	}
+}
+
+func checkRatingsMoved(t *testing.T, players []*simulatedPlayer) {
+	t.Helper()
+	weakest, strongest := players[0], players[len(players)-1]
+	if strongest.rating < EloFloor+100 {
+		t.Errorf("the strongest player is still at %v", strongest.rating)
+	}
+	if weakest.rating >= strongest.rating {
+		t.Errorf("the weakest player (%v) is not below the strongest (%v)", weakest.rating, strongest.rating)
+	}
+}
+
+func TestZeroSumLongSoloSequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	players := newSimulatedPlayers(20)
+
+	for match := 1; match <= 5000; match++ {
+		i, j := rng.Intn(len(players)), rng.Intn(len(players)-1)
+		if j >= i {
+			j++
+		}
+		player1, player2 := players[i], players[j]
+		winnerID := uint(2)
+		if rng.Float64() < ExpectedScore(player1.strength, player2.strength) {
+			winnerID = 1
+		}
+
+		change1, change2 := CalculateEloChangeWithExemptions(player1.rating, player2.rating, winnerID, 1, player1.exempt(), player2.exempt())
+		change1, change2 = ZeroSumEloChange(change1, change2)
+		player1.rating += change1
+		player2.rating += change2
+		player1.played++
+		player2.played++
+
+		checkZeroSumSequence(t, players, match)
+	}
+	checkRatingsMoved(t, players)
+}
+
+func TestZeroSumLongTeamSequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	players := newSimulatedPlayers(16)
+
+	for match := 1; match <= 5000; match++ {
+		order := rng.Perm(len(players))
+		winners := []*simulatedPlayer{players[order[0]], players[order[1]]}
+		losers := []*simulatedPlayer{players[order[2]], players[order[3]]}
+		if rng.Float64() >= ExpectedScore(winners[0].strength+winners[1].strength, losers[0].strength+losers[1].strength) {
+			winners, losers = losers, winners
+		}
+
+		winnersAvg := CalculateTeamAverageElo(winners[0].rating, winners[1].rating)
+		losersAvg := CalculateTeamAverageElo(losers[0].rating, losers[1].rating)
+		changes := make([]float64, 4)
+		for k, p := range append(winners, losers...) {
+			if k < 2 {
+				changes[k] = CalculateWeightedTeamEloChange(p.rating, losersAvg, true, 1, p.exempt())
+			} else {
+				changes[k] = CalculateWeightedTeamEloChange(p.rating, winnersAvg, false, 1, p.exempt())
+			}
+		}
+		changes[0], changes[1], changes[2], changes[3] = ZeroSumTeamEloChange(changes[0], changes[1], changes[2], changes[3])
+		for k, p := range append(winners, losers...) {
+			p.rating += changes[k]
+			p.played++
+		}
+
+		checkZeroSumSequence(t, players, match)
+	}
+	checkRatingsMoved(t, players)
+}
+
+func BenchmarkCalculateEloChange(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CalculateEloChange(1250, 1310, 1, 1)
+	}
+}
+
+func BenchmarkCalculateWeightedEloChange(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CalculateWeightedEloChange(1250, 1310, 1, 1, 2, 1.5, false, false)
+	}
+}
+
+func BenchmarkCalculateTeamEloChange(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CalculateTeamEloChange(1250, CalculateTeamAverageElo(1290, 1330), true)
+	}
+}
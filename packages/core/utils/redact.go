@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces personal data and secrets in captured bodies
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeys are JSON fields whose value is never kept, matched case-insensitively
+// anywhere in the key (e.g. new_password, refreshToken)
+var sensitiveKeys = []string{
+	"password", "token", "secret", "email", "phone", "card_uid", "cardUid",
+	"first_name", "last_name", "firstname", "lastname", "username", "authorization", "api_key", "apikey",
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// RedactBody returns a JSON body with its sensitive fields and email addresses masked, cut to maxLen bytes.
+// Bodies that are not JSON are not kept since they cannot be sanitized reliably.
+func RedactBody(body []byte, maxLen int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "[non-JSON body omitted]"
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[unserializable body omitted]"
+	}
+
+	if len(redacted) > maxLen {
+		return string(redacted[:maxLen]) + "...[truncated]"
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	case string:
+		return emailPattern.ReplaceAllString(v, RedactedValue)
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, strings.ToLower(sensitive)) {
+			return true
+		}
+	}
+	return false
+}
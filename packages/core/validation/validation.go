@@ -0,0 +1,176 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is a rule a field of the request body does not satisfy
+type FieldError struct {
+	Field   string `json:"field" example:"email"`   // JSON path of the field, e.g. assignments[0].team_id
+	Rule    string `json:"rule" example:"required"` // Failed binding rule, or "type" when the value has the wrong type
+	Message string `json:"message" example:"is required"`
+}
+
+// ErrorResponse is returned with 400 when the request body cannot be bound
+type ErrorResponse struct {
+	Error  string       `json:"error" example:"Invalid request body"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+func init() {
+	// Name the fields after their JSON key rather than the Go struct field
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// BindError translates the error of ShouldBindJSON into a response listing the invalid fields, instead
+// of the raw validator message naming the Go structs
+func BindError(err error) ErrorResponse {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fields = append(fields, FieldError{Field: fieldPath(fe), Rule: fe.Tag(), Message: message(fe)})
+		}
+		return ErrorResponse{Error: "Invalid request body", Fields: fields}
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return ErrorResponse{Error: "Invalid request body", Fields: []FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: "must be " + article(typeError.Type.Kind()),
+		}}}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return ErrorResponse{Error: "Request body is empty"}
+	}
+	return ErrorResponse{Error: "Invalid JSON body"}
+}
+
+// fieldPath drops the name of the request struct from the namespace of a field
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+// message describes the rule a field failed, for the binding tags used by the request DTOs
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return "is required when " + snakeCase(param) + " is missing"
+	case "email":
+		return "must be a valid email address"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "numeric":
+		return "must contain only digits"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "datetime":
+		return "must be a date formatted as " + param
+	case "startswith":
+		return fmt.Sprintf("must start with %q", param)
+	case "len":
+		return "must be exactly " + size(fe.Kind(), param)
+	case "min":
+		return "must be at least " + size(fe.Kind(), param)
+	case "max":
+		return "must be at most " + size(fe.Kind(), param)
+	case "gt":
+		return "must be greater than " + size(fe.Kind(), param)
+	case "gte":
+		return "must be at least " + size(fe.Kind(), param)
+	case "lt":
+		return "must be less than " + size(fe.Kind(), param)
+	case "lte":
+		return "must be at most " + size(fe.Kind(), param)
+	case "gtfield":
+		return "must be after " + snakeCase(param)
+	default:
+		return "does not satisfy " + fe.Tag()
+	}
+}
+
+// size reads a length rule for the kind of the field: characters, items or a plain value
+func size(kind reflect.Kind, param string) string {
+	plural := "s"
+	if param == "1" {
+		plural = ""
+	}
+	switch kind {
+	case reflect.String:
+		return param + " character" + plural
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return param + " item" + plural
+	default:
+		return param
+	}
+}
+
+// snakeCase turns the Go field named by a cross-field rule into its JSON key, e.g. StartsAt into starts_at
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// article names the JSON type expected for a Go kind
+func article(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	default:
+		return "of another type"
+	}
+}
@@ -0,0 +1,123 @@
+// Package webhooks signs the deliveries sent to the integrators' endpoints and verifies them. Verifier
+// is the reference implementation of the checks an integrator should run on each delivery.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of a delivery
+const (
+	HeaderID        = "X-Webhook-Id"        // Delivery ID, the same on every retry
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix time of the attempt
+	HeaderSignature = "X-Webhook-Signature" // v1=<hex>, one per active secret, comma separated
+)
+
+// DefaultTolerance is the replay window: a delivery whose timestamp is further from now is refused
+const DefaultTolerance = 5 * time.Minute
+
+const signatureVersion = "v1="
+
+var (
+	ErrMissingHeaders    = errors.New("missing webhook headers")
+	ErrInvalidTimestamp  = errors.New("invalid timestamp")
+	ErrOutsideWindow     = errors.New("timestamp outside the replay window")
+	ErrInvalidSignature  = errors.New("no signature matches")
+	ErrAlreadyDelivered  = errors.New("delivery already received")
+	ErrNoSecretAvailable = errors.New("no secret to verify with")
+)
+
+// Sign returns the signature of a delivery for a secret: the hex HMAC-SHA256 of "id.timestamp.body"
+func Sign(secret, id string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + "." + strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader signs a delivery with every active secret, so that the endpoint accepts it with either
+// the new or the previous secret while a rotation is in progress
+func SignatureHeader(secrets []string, id string, timestamp int64, body []byte) string {
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		signatures = append(signatures, Sign(secret, id, timestamp, body))
+	}
+	return strings.Join(signatures, ",")
+}
+
+// Verifier checks the deliveries received by an endpoint: signature by one of the secrets, timestamp
+// within the replay window, and delivery ID not seen within that window
+type Verifier struct {
+	Secrets   []string
+	Tolerance time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewVerifier verifies with the given secrets, the current one and, during a rotation, the previous one
+func NewVerifier(secrets ...string) *Verifier {
+	return &Verifier{Secrets: secrets, Tolerance: DefaultTolerance, seen: make(map[string]time.Time)}
+}
+
+// Check verifies the signature and the replay window of a delivery without recording it
+func (v *Verifier) Check(id, timestamp, signature string, body []byte, now time.Time) error {
+	if id == "" || timestamp == "" || signature == "" {
+		return ErrMissingHeaders
+	}
+	if len(v.Secrets) == 0 {
+		return ErrNoSecretAvailable
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if delta := now.Sub(time.Unix(unix, 0)); delta > v.Tolerance || delta < -v.Tolerance {
+		return ErrOutsideWindow
+	}
+
+	for _, secret := range v.Secrets {
+		expected := Sign(secret, id, unix, body)
+		for _, provided := range strings.Split(signature, ",") {
+			if hmac.Equal([]byte(strings.TrimSpace(provided)), []byte(expected)) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Verify checks a delivery and records its ID, so that the same delivery replayed within the window
+// is refused. A retry of a delivery that was not acknowledged keeps its ID: only verify it once it is
+// processed, or treat ErrAlreadyDelivered as a success without processing it again.
+func (v *Verifier) Verify(id, timestamp, signature string, body []byte, now time.Time) error {
+	if err := v.Check(id, timestamp, signature, body, now); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.seen == nil {
+		v.seen = make(map[string]time.Time)
+	}
+	// IDs older than the window cannot come back, their timestamp would be refused
+	for seenID, at := range v.seen {
+		if now.Sub(at) > 2*v.Tolerance {
+			delete(v.seen, seenID)
+		}
+	}
+	if _, found := v.seen[id]; found {
+		return ErrAlreadyDelivered
+	}
+	v.seen[id] = now
+	return nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+
+	"auth"
+	"core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Every route must have an explicit auth policy: either a middleware that authenticates the request,
+// or an entry in publicRoutes. The inventory walks the registered routes and reads the middleware chain
+// of each one, so that a handler mounted without its middleware stops the boot and fails `go test` and `make check-routes`
+// instead of being served to anyone.
+
+// Route policies, derived from the middleware chain
+const (
+	policyRole          = "role"          // JWT and a role (admin, referee)
+	policyAuthenticated = "authenticated" // JWT, ownership checked by the handler when needed
+	policyKiosk         = "kiosk"         // Paired kiosk token
+	policyKioskOrJWT    = "kiosk_or_jwt"  // Paired kiosk token or JWT
+	policyPublicAPI     = "public_api"    // Scoped public API token
+	policyPublic        = "public"        // Declared in publicRoutes
+)
+
+// authMiddlewares maps the function name of the middlewares that authenticate a request to the policy
+// they enforce, the strictest first
+var authMiddlewares = []struct {
+	function string
+	policy   string
+}{
+	{"auth/middleware.RequireRole.", policyRole},
+	{"auth/middleware.RequireAnyRole.", policyRole},
+	{"auth/middleware.JWTMiddleware.", policyAuthenticated},
+	{"core/middleware.RequireKiosk.", policyKiosk},
+	{"core/middleware.RequireKioskOrJWT.", policyKioskOrJWT},
+	{"core/middleware.(*PublicAPI).RequireScope.", policyPublicAPI},
+}
+
+// publicRoutes are the routes served without authentication on purpose. Routes with an optional JWT
+// are public too. A new public route must be added here, a removed one taken out.
+var publicRoutes = []string{
+	// Server
+	"GET /health",
+	"GET /readyz",
+	"GET /swagger/*any",
+	"GET /openapi.json",
+
+	// Account: sign-up, login and links sent by email
+	"POST /auth/register",
+	"POST /auth/login",
+	"POST /auth/refresh",
+	"POST /auth/logout",
+	"POST /auth/reset-password/send-link",
+	"POST /auth/reset-password/confirm",
+	"POST /auth/verify-email/confirm",
+
+	// Mail provider webhook, authenticated by its shared secret in the handler
+	"POST /webhooks/email/events",
+	// Verification helper for webhook integrators, rate limited, it reveals no secret
+	"POST /webhooks/verify",
+
+	// Export files, authenticated by the signature of the download URL in the handler
+	"GET /exports/:id/download",
+
+	// Kiosk pairing, authenticated by the PIN in the handler
+	"POST /kiosk/pair",
+
+	// Admin page shell, it calls the admin endpoints with the JWT of the admin
+	"GET /admin/ui",
+
+	// App configuration
+	"GET /client-config",
+
+	// Players, the top lists take an optional JWT
+	"GET /players",
+	"GET /players/top",
+	"GET /players/top-teams",
+	"GET /players/:id",
+	"GET /players/:id/teams",
+	"GET /players/:id/trophies",
+	"GET /players/:id/titles",
+	"GET /players/:id/external-ids",
+	"GET /players/:id/external-matches",
+	"GET /players/:id/elo-history",
+	"GET /players/:id/team-elo-history",
+	"GET /players/:id/ratings",
+	"GET /players/:id/matches",
+	"GET /players/:id/history",
+
+	// Matches and rankings
+	"GET /matches",
+	"GET /matches/recent",
+	"GET /matches/all",
+	"GET /matches/:id/timeline",
+	"GET /live-matches",
+	"GET /live-matches/:id",
+	"GET /live-matches/:id/timer",
+	"GET /team-matches",
+	"GET /team-matches/recent",
+	"GET /elo-history/recent",
+	"GET /team-elo-history/recent",
+	"GET /leaderboard",
+	"GET /ladders/:ladder",
+	"GET /campuses",
+	"GET /predict",
+	"GET /predict/teams",
+	"GET /stats",
+	"GET /hall-of-fame",
+	"GET /referees/:id/stats",
+	"GET /seasons/:season/settings",
+	"GET /event-leaderboards",
+	"GET /event-leaderboards/:id/standings",
+
+	// Teams
+	"GET /teams",
+	"GET /teams/players/:playerId",
+	"GET /teams/:id",
+	"GET /teams/:id/trophies",
+	"GET /teams/:id/elo-history",
+
+	// Tournaments
+	"GET /tournaments",
+	"GET /tournaments/:id",
+	"GET /tournaments/:id/teams",
+	"GET /tournaments/:id/matches",
+	"GET /tournaments/:id/standings",
+	"GET /tournaments/:id/waitlist",
+	"GET /tournament-templates",
+	"GET /tournament-templates/:id",
+	"GET /tournament-recurrences",
+	"GET /tournament-recurrences/:id",
+
+	// Trophies and titles
+	"GET /trophies",
+	"GET /trophies/:id",
+	"GET /monthly-awards",
+	"GET /titles",
+}
+
+// routeProbeKey marks the in-process requests of the inventory, it cannot be set from the network
+type routeProbeKey struct{}
+
+// routeProbe records the handler chain of a probe request and stops it before any other middleware,
+// so probing never runs a handler. It must be the first middleware of the engine.
+func routeProbe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if chain, ok := c.Request.Context().Value(routeProbeKey{}).(*[]string); ok {
+			*chain = c.HandlerNames()
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// routePolicy is a registered route with the policy read from its middleware chain
+type routePolicy struct {
+	route  string // "METHOD /path"
+	policy string // Empty when the route has no auth middleware and is not declared public
+}
+
+// routeInventory lists the routes of the engine with their policy, in registration order
+func routeInventory(r *gin.Engine) ([]routePolicy, error) {
+	var inventory []routePolicy
+	for _, info := range r.Routes() {
+		route := info.Method + " " + info.Path
+
+		var chain []string
+		req := httptest.NewRequest(info.Method, probePath(info.Path), nil)
+		req = req.WithContext(context.WithValue(req.Context(), routeProbeKey{}, &chain))
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if chain == nil {
+			return nil, fmt.Errorf("%s could not be probed, is routeProbe the first middleware?", route)
+		}
+
+		inventory = append(inventory, routePolicy{route: route, policy: policyOf(route, chain)})
+	}
+	return inventory, nil
+}
+
+// probePath fills the parameters of a route path so that the router matches it
+func probePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "0"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// policyOf returns the strictest policy enforced by a middleware chain, public if the route is declared
+// so, empty otherwise
+func policyOf(route string, chain []string) string {
+	for _, middleware := range authMiddlewares {
+		if slices.ContainsFunc(chain, func(name string) bool { return strings.HasPrefix(name, middleware.function) }) {
+			return middleware.policy
+		}
+	}
+	if slices.Contains(publicRoutes, route) {
+		return policyPublic
+	}
+	return ""
+}
+
+// checkRoutePolicies fails on the routes without a policy, and on the public declarations that no
+// longer match a route or that a middleware now protects
+func checkRoutePolicies(r *gin.Engine) error {
+	inventory, err := routeInventory(r)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	policies := make(map[string]string, len(inventory))
+	for _, entry := range inventory {
+		policies[entry.route] = entry.policy
+		if entry.policy == "" {
+			problems = append(problems, entry.route+" has no auth middleware and is not declared public")
+		}
+	}
+	for _, route := range publicRoutes {
+		switch policy, registered := policies[route]; {
+		case !registered:
+			problems = append(problems, route+" is declared public but not registered")
+		case policy != policyPublic:
+			problems = append(problems, route+" is declared public but requires "+policy)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%d route policy problem(s):\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// routeChecks verifies the policy of every route once they are all registered
+func routeChecks(r *gin.Engine) []startupCheck {
+	return []startupCheck{
+		{name: "route_policies", critical: true, run: func() error { return checkRoutePolicies(r) }},
+	}
+}
+
+// isCheckRoutes reports whether the server was started with --check-routes
+func isCheckRoutes() bool {
+	return slices.Contains(os.Args[1:], "--check-routes")
+}
+
+// runCheckRoutes prints the route inventory and checks it without a database, for CI
+// (`make check-routes`), and returns the exit code
+func runCheckRoutes() int {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(routeProbe())
+	registerRoutes(r, auth.NewModule(nil), core.NewModule(nil))
+
+	inventory, err := routeInventory(r)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	counts := make(map[string]int)
+	for _, entry := range inventory {
+		policy := entry.policy
+		if policy == "" {
+			policy = "MISSING"
+		}
+		counts[policy]++
+		fmt.Printf("%-14s %s\n", policy, entry.route)
+	}
+	fmt.Printf("\n%d route(s): %d role, %d authenticated, %d kiosk, %d kiosk_or_jwt, %d public_api, %d public\n", len(inventory),
+		counts[policyRole], counts[policyAuthenticated], counts[policyKiosk], counts[policyKioskOrJWT], counts[policyPublicAPI], counts[policyPublic])
+
+	if err := checkRoutePolicies(r); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Println("✅ Every route has an explicit auth policy")
+	return 0
+}
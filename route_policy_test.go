@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"auth"
+	"core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRouteTestEngine registers the routes of the server without a database, like `make check-routes`
+func newRouteTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(routeProbe())
+	registerRoutes(r, auth.NewModule(nil), core.NewModule(nil))
+	return r
+}
+
+func TestEveryRouteHasAnAuthPolicy(t *testing.T) {
+	if err := checkRoutePolicies(newRouteTestEngine()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRouteWithoutPolicyIsReported(t *testing.T) {
+	r := newRouteTestEngine()
+	r.GET("/undeclared", func(c *gin.Context) {})
+
+	err := checkRoutePolicies(r)
+	if err == nil {
+		t.Fatal("expected a route policy problem for GET /undeclared")
+	}
+	if !strings.Contains(err.Error(), "GET /undeclared has no auth middleware") {
+		t.Fatalf("unexpected problems: %v", err)
+	}
+}
+
+func TestSyncRequiresKioskOrJWT(t *testing.T) {
+	inventory, err := routeInventory(newRouteTestEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range inventory {
+		if entry.route == "GET /sync" {
+			if entry.policy != policyKioskOrJWT {
+				t.Fatalf("GET /sync has policy %q, want %q", entry.policy, policyKioskOrJWT)
+			}
+			return
+		}
+	}
+	t.Fatal("GET /sync is not registered")
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	authModels "auth/models"
+	"bab-insa-api/config"
+	"bab-insa-api/fixtures"
+	"bab-insa-api/migrations"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+// The sandbox runs the API on a disposable database loaded with the fixtures, for frontend development
+// (`make sandbox`), with nothing to install. The schema relies on PostgreSQL (triggers, materialized views,
+// window functions, generated columns) and so do many service queries, so the sandbox embeds a PostgreSQL
+// server rather than SQLite: the binary starts it as a child process on a temporary data directory and
+// stops it on shutdown, or when the server fails to start (see fatalf). The PostgreSQL binaries are not part
+// of the API binary: they are downloaded on the first run and cached in ~/.embedded-postgres-go.
+
+// sandboxDatabase points the sandbox at its embedded server, whatever the .env says,
+// so that a sandbox never touches a real database
+var sandboxDatabase = map[string]string{
+	"DB_HOST":     "localhost",
+	"DB_PORT":     "55432",
+	"DB_USER":     "postgres",
+	"DB_PASSWORD": "sandbox",
+	"DB_NAME":     "bab_insa_sandbox",
+	"DB_SSLMODE":  "disable",
+}
+
+// sandboxDefaults apply to the variables that are not set
+var sandboxDefaults = map[string]string{
+	"JWT_SECRET":        "sandbox-secret-never-use-it-in-production",
+	"MAIL_PROVIDER":     "log",
+	"MATCH_DAILY_LIMIT": "0",
+}
+
+// sandboxAdminID is the fixture user promoted to admin, to try the admin screens
+const sandboxAdminID = 1
+
+// isSandbox reports whether the server was started with --sandbox
+func isSandbox() bool {
+	return slices.Contains(os.Args[1:], "--sandbox")
+}
+
+// applySandboxEnv configures the sandbox before anything reads the environment
+func applySandboxEnv() {
+	for name, value := range sandboxDatabase {
+		_ = os.Setenv(name, value)
+	}
+	for name, value := range sandboxDefaults {
+		if os.Getenv(name) == "" {
+			_ = os.Setenv(name, value)
+		}
+	}
+}
+
+// startSandbox starts the embedded PostgreSQL server of the sandbox, connects to it and loads the fixtures.
+// It returns the function that stops the server and removes its data; on an error nothing is left running.
+func startSandbox() (func(), error) {
+	stop, err := startSandboxDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.OpenDatabase(); err != nil {
+		stop()
+		return nil, err
+	}
+	if err := prepareSandbox(); err != nil {
+		config.CloseDatabase()
+		stop()
+		return nil, err
+	}
+
+	return func() {
+		config.CloseDatabase()
+		stop()
+	}, nil
+}
+
+// startSandboxDatabase starts the embedded PostgreSQL server of the sandbox on a fresh data directory
+// and returns the function that stops it and removes its data
+func startSandboxDatabase() (func(), error) {
+	port, err := strconv.ParseUint(sandboxDatabase["DB_PORT"], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sandbox database port: %w", err)
+	}
+	runtimePath, err := os.MkdirTemp("", "bab-insa-sandbox-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the sandbox database directory: %w", err)
+	}
+
+	log.Println("🧪 Starting the sandbox database (PostgreSQL binaries are downloaded on the first run)...")
+	database := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V16).
+		Port(uint32(port)).
+		Username(sandboxDatabase["DB_USER"]).
+		Password(sandboxDatabase["DB_PASSWORD"]).
+		Database(sandboxDatabase["DB_NAME"]).
+		RuntimePath(runtimePath).
+		DataPath(filepath.Join(runtimePath, "data")).
+		Logger(io.Discard))
+	if err := database.Start(); err != nil {
+		_ = os.RemoveAll(runtimePath)
+		return nil, fmt.Errorf("failed to start the sandbox database: %w", err)
+	}
+
+	return func() {
+		if err := database.Stop(); err != nil {
+			log.Printf("Failed to stop the sandbox database: %v", err)
+		}
+		_ = os.RemoveAll(runtimePath)
+	}, nil
+}
+
+// prepareSandbox migrates the sandbox database and loads the fixtures
+func prepareSandbox() error {
+	if err := migrations.NewAppMigrator(config.DB).Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate the sandbox database: %w", err)
+	}
+
+	if err := fixtures.NewFixtures(config.DB).GenerateTestData(); err != nil {
+		return fmt.Errorf("failed to load the sandbox fixtures: %w", err)
+	}
+	if err := config.DB.Model(&authModels.User{}).Where("id = ?", sandboxAdminID).
+		Update("roles", authModels.Roles{authModels.RoleUser, authModels.RoleAdmin}).Error; err != nil {
+		return fmt.Errorf("failed to create the sandbox admin: %w", err)
+	}
+
+	log.Println("🧪 SANDBOX MODE: disposable database, log in as <name>@bab-insa.fr / password123 (alexandre@bab-insa.fr is admin)")
+	return nil
+}
+
+// stopSandbox stops the sandbox database, if any; set once the sandbox is started
+var stopSandbox = func() {}
+
+// fatalf logs like log.Fatalf once the sandbox database is stopped, so that the embedded server and its
+// data directory never outlive the process
+func fatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+	stopSandbox()
+	os.Exit(1)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	authServices "auth/services"
+	authUtils "auth/utils"
+	"bab-insa-api/config"
+	"bab-insa-api/migrations"
+	"core"
+)
+
+// startupCheckTimeout bounds each network check so that a dead dependency does not hang the boot
+const startupCheckTimeout = 5 * time.Second
+
+// startupCheck is a dependency verified on boot. A failing critical check stops the server
+// unless STARTUP_CHECK_MODE=degraded, a failing optional check is only logged.
+type startupCheck struct {
+	name     string
+	critical bool
+	run      func() error
+}
+
+// runStartupChecks logs the outcome of each check and exits when a critical one fails in strict mode
+func runStartupChecks(stage string, checks []startupCheck) {
+	degraded := os.Getenv("STARTUP_CHECK_MODE") == "degraded"
+
+	var failures []string
+	for _, check := range checks {
+		if err := check.run(); err != nil {
+			if check.critical {
+				failures = append(failures, check.name)
+				log.Printf("❌ Startup check %s failed: %v", check.name, err)
+			} else {
+				log.Printf("⚠️  Startup check %s failed (optional): %v", check.name, err)
+			}
+			continue
+		}
+		log.Printf("✅ Startup check %s passed", check.name)
+	}
+
+	if len(failures) == 0 {
+		return
+	}
+	if !degraded {
+		fatalf("Refusing to start, %s checks failed: %s (set STARTUP_CHECK_MODE=degraded to start anyway)",
+			stage, strings.Join(failures, ", "))
+	}
+	log.Printf("⚠️  STARTING DEGRADED: %s checks failed: %s", stage, strings.Join(failures, ", "))
+}
+
+// dependencyChecks verifies what the server needs before it accepts requests
+func dependencyChecks() []startupCheck {
+	return []startupCheck{
+		{name: "database", critical: true, run: checkDatabase},
+		{name: "migrations", critical: true, run: checkPendingMigrations(migrations.PhasePreDeploy, "make migrate-pre")},
+		{name: "post_deploy_migrations", critical: false, run: checkPendingMigrations(migrations.PhasePostDeploy, "make migrate-post")},
+		{name: "jwt_secret", critical: true, run: authUtils.CheckJWTSecret},
+		{name: "smtp", critical: os.Getenv("STARTUP_CHECK_SMTP") == "required", run: checkSMTP},
+		{name: "card_uid_secret", critical: false, run: checkCardUIDSecret},
+	}
+}
+
+// schedulerChecks verifies the background jobs once the scheduler started. It is optional: a scheduler
+// that failed to start keeps retrying while the API serves in degraded mode.
+func schedulerChecks(coreModule *core.Module) []startupCheck {
+	return []startupCheck{
+		{name: "scheduler", critical: false, run: func() error {
+			if coreModule.Scheduler.JobCount() == 0 {
+				return fmt.Errorf("no job registered")
+			}
+			return nil
+		}},
+	}
+}
+
+func checkDatabase() error {
+	sqlDB, err := config.DB.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), startupCheckTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// checkPendingMigrations reports the migrations of a phase not run yet, post-deploy ones are expected
+// to be pending until the new binary is live
+func checkPendingMigrations(phase migrations.MigrationPhase, command string) func() error {
+	return func() error {
+		pending := migrations.NewAppMigrator(config.DB).Pending(phase)
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migration(s), first is %s, run `%s`", len(pending), pending[0], command)
+		}
+		return nil
+	}
+}
+
+// checkCardUIDSecret reports that NFC/student card login is off, the card endpoints answer 503 without a key
+func checkCardUIDSecret() error {
+	if os.Getenv("CARD_UID_SECRET") == "" {
+		return fmt.Errorf("CARD_UID_SECRET not set, card login disabled")
+	}
+	return nil
+}
+
+// checkSMTP dials the SMTP server when emails go through SMTP, other transports are not checked
+func checkSMTP() error {
+	provider := strings.ToLower(os.Getenv("MAIL_PROVIDER"))
+	if provider != "smtp" && (provider != "" || os.Getenv("MAIL_DSN") == "") {
+		return nil
+	}
+
+	transport, err := authServices.NewSMTPTransport()
+	if err != nil {
+		return err
+	}
+	return transport.Ping(startupCheckTimeout)
+}